@@ -3,17 +3,32 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/caiolandgraf/pam/internal/config"
 	"github.com/caiolandgraf/pam/internal/db"
 	"github.com/caiolandgraf/pam/internal/editor"
+	"github.com/caiolandgraf/pam/internal/params"
 	"github.com/caiolandgraf/pam/internal/styles"
+	"github.com/caiolandgraf/pam/internal/workspace"
 )
 
 func (a *App) handleAdd() {
-	if len(os.Args) < 3 {
-		printError("Usage: pam add <run-name> [query]")
+	project := false
+	args := []string{}
+	meta, rest := parseQueryMetaFlags(os.Args[2:])
+	for _, arg := range rest {
+		if arg == "--project" {
+			project = true
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	if len(args) < 1 {
+		printError("Usage: pam add [folder/]<run-name> [query] [--description <text>] [--tag <tag>]... [--folder <path>] [--assert <expr>] [--project]")
 	}
 
 	if a.config.CurrentConnection == "" {
@@ -26,11 +41,14 @@ func (a *App) handleAdd() {
 	}
 	queries := a.config.Connections[a.config.CurrentConnection].Queries
 
-	queryName := os.Args[2]
+	folder, queryName := db.SplitQueryPath(args[0])
+	if queryName == "" {
+		printError("Invalid query name '%s'", args[0])
+	}
 	var querySQL string
 
-	if len(os.Args) >= 4 {
-		querySQL = os.Args[3]
+	if len(args) >= 2 {
+		querySQL = args[1]
 	} else {
 		header := fmt.Sprintf("-- Creating new run:  %s\n", queryName)
 		header += fmt.Sprintf("-- Connection: %s (%s)\n",
@@ -51,12 +69,21 @@ func (a *App) handleAdd() {
 		}
 	}
 
-	queries[queryName] = db.Query{
-		Name: queryName,
-		SQL:  querySQL,
-		Id:   db.GetNextQueryId(queries),
+	query := db.Query{
+		Name:   queryName,
+		SQL:    querySQL,
+		Folder: folder,
+	}
+	meta.apply(&query)
+
+	if project {
+		a.addProjectQuery(query)
+		return
 	}
 
+	query.Id = db.GetNextQueryId(queries)
+	queries[queryName] = query
+
 	err := a.config.Save()
 	if err != nil {
 		printError("Could not save configuration file: %v", err)
@@ -65,13 +92,49 @@ func (a *App) handleAdd() {
 	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Added query '%s' with ID %d", queryName, queries[queryName].Id)))
 }
 
+// addProjectQuery writes the query to the project's .pam directory, creating
+// one in the working directory when none was found.
+func (a *App) addProjectQuery(query db.Query) {
+	ws := config.Project
+	if ws == nil {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printError("Could not get working directory: %v", err)
+		}
+		ws = &workspace.Workspace{Dir: filepath.Join(cwd, workspace.DirName)}
+		config.Project = ws
+	}
+	if _, exists := ws.Lookup(a.config.CurrentConnection, query.Name); exists {
+		printError("Query '%s' already exists in the project", query.Name)
+	}
+
+	q := workspace.Query{
+		Query:      query,
+		Connection: a.config.CurrentConnection,
+	}
+	names := []string{}
+	for name := range params.ExtractParameters(query.SQL) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		q.Params = append(q.Params, workspace.Param{Name: name})
+	}
+
+	saved, err := ws.Save(q)
+	if err != nil {
+		printError("Could not save project query: %v", err)
+	}
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Added query '%s' to %s", query.Name, ws.Rel(saved.Path))))
+}
+
 func removeCommentLines(content string) string {
 	lines := strings.Split(content, "\n")
 	var result strings.Builder
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
-		if !strings.HasPrefix(trimmed, "--") {
+		if !strings.HasPrefix(trimmed, "--") || params.IsLookupLine(trimmed) {
 			result.WriteString(line)
 			result.WriteString("\n")
 		}
@@ -60,16 +60,40 @@ func (a *App) Run() {
 		a.handleStatus()
 	case "history":
 		a.handleHistory()
+	case "export":
+		a.handleExport()
+	case "import":
+		a.handleImport()
 	case "tables", "t", "explore":
 		a.handleTables()
 	case "table-view", "tv":
 		a.handleTableView()
+	case "table":
+		a.handleTable()
 	case "disconnect", "clear", "unset":
 		a.handleDisconnect()
 	case "config":
 		a.handleConfig()
 	case "explain":
 		a.handleExplain()
+	case "plan":
+		a.handlePlan()
+	case "erd":
+		a.handleErd()
+	case "profile":
+		a.handleProfile()
+	case "seed":
+		a.handleSeed()
+	case "migrate":
+		a.handleMigrate()
+	case "check":
+		a.handleCheck()
+	case "federate":
+		a.handleFederate()
+	case "open":
+		a.handleOpen()
+	case "group":
+		a.handleGroup()
 	case "help":
 		a.handleHelp()
 	case "__complete":
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/check"
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+type checkFlags struct {
+	junitFile       string
+	jsonFile        string
+	updateSnapshots bool
+}
+
+func parseCheckFlags() (checkFlags, []string) {
+	flags := checkFlags{}
+	remainingArgs := []string{}
+	args := os.Args[2:]
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--junit":
+			if i+1 >= len(args) {
+				printError("--junit requires a value")
+			}
+			flags.junitFile = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--junit="):
+			flags.junitFile = strings.TrimPrefix(arg, "--junit=")
+		case arg == "--json":
+			if i+1 >= len(args) {
+				printError("--json requires a value")
+			}
+			flags.jsonFile = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--json="):
+			flags.jsonFile = strings.TrimPrefix(arg, "--json=")
+		case arg == "--update-snapshots":
+			flags.updateSnapshots = true
+		case !strings.HasPrefix(arg, "-"):
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+	return flags, remainingArgs
+}
+
+// snapshotPath is where a check's expected result is kept: in the project's
+// .pam directory for project queries, in the config directory otherwise
+func snapshotPath(connName string, q db.Query) string {
+	file := q.Name + ".json"
+	if q.Source != "" && config.Project != nil {
+		return filepath.Join(config.Project.Dir, "snapshots", file)
+	}
+	return filepath.Join(config.CfgPath, "snapshots", connName, file)
+}
+
+// handleCheck runs the current connection's queries tagged "check", or the
+// named ones, and exits with status 1 when any of them fails:
+//
+//	pam check [query...] [--junit file] [--json file] [--update-snapshots]
+func (a *App) handleCheck() {
+	if a.config.CurrentConnection == "" {
+		printError(
+			"No active connection. Use 'pam switch <connection>' or 'pam init' first",
+		)
+	}
+	flags, args := parseCheckFlags()
+	connName := a.config.CurrentConnection
+	queries := a.config.Queries(connName)
+
+	var checks []db.Query
+	if len(args) > 0 {
+		for _, selector := range args {
+			q, ok := db.FindQueryWithSelector(queries, selector)
+			if !ok {
+				printError("Query '%s' not found", selector)
+			}
+			checks = append(checks, q)
+		}
+	} else {
+		for _, q := range queries {
+			if check.IsCheck(q) {
+				checks = append(checks, q)
+			}
+		}
+		sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	}
+	if len(checks) == 0 {
+		fmt.Println(styles.Faint.Render(
+			"No checks for " + connName + ". Tag a query with 'pam edit <query> --tag check --assert \"rows = 0\"'",
+		))
+		return
+	}
+
+	conn := config.FromConnectionYaml(a.config.Connections[connName])
+	if err := conn.Open(); err != nil {
+		printError("Could not open connection to %s: %v", connName, err)
+	}
+	defer conn.Close()
+
+	// A report on stdout keeps it clean of the progress lines
+	out := io.Writer(os.Stdout)
+	if flags.junitFile == "-" || flags.jsonFile == "-" {
+		out = os.Stderr
+	}
+
+	results := make([]check.Result, 0, len(checks))
+	for _, q := range checks {
+		c := check.Check{
+			Name:         q.Name,
+			Assert:       q.Assert,
+			SnapshotPath: snapshotPath(connName, q),
+		}
+		var err error
+		c.SQL, c.Args, _, err = a.processParameters(q.SQL, conn, map[string]string{}, nil, true)
+		var result check.Result
+		if err != nil {
+			result = check.Result{Name: q.Name, SQL: q.SQL, Assert: q.Assert, Err: err}
+		} else {
+			result = check.Run(conn, c, flags.updateSnapshots)
+		}
+		printCheckResult(out, result)
+		results = append(results, result)
+	}
+
+	summary := check.Summarize(results)
+	fmt.Fprintln(out)
+	line := fmt.Sprintf(
+		"%d check(s): %d passed, %d failed, %d error(s) in %s",
+		summary.Total, summary.Passed, summary.Failed, summary.Errors,
+		summary.Duration.Round(time.Millisecond),
+	)
+	if summary.Passed == summary.Total {
+		fmt.Fprintln(out, styles.Success.Render(line))
+	} else {
+		fmt.Fprintln(out, styles.Error.Render(line))
+	}
+
+	if flags.junitFile != "" {
+		if err := writeCheckReport(flags.junitFile, connName, results, check.WriteJUnit); err != nil {
+			printError("Could not write JUnit report: %v", err)
+		}
+	}
+	if flags.jsonFile != "" {
+		if err := writeCheckReport(flags.jsonFile, connName, results, check.WriteJSON); err != nil {
+			printError("Could not write JSON report: %v", err)
+		}
+	}
+	if summary.Passed != summary.Total {
+		os.Exit(1)
+	}
+}
+
+func printCheckResult(out io.Writer, r check.Result) {
+	elapsed := styles.Faint.Render(r.Duration.Round(time.Millisecond).String())
+	switch r.Status() {
+	case check.Passed:
+		fmt.Fprintf(out, "%s %s %s\n", styles.Success.Render("✓"), r.Name, elapsed)
+	case check.Errored:
+		fmt.Fprintf(out, "%s %s %s\n", styles.Error.Render("✗"), r.Name, styles.Error.Render(r.Err.Error()))
+	case check.Failed:
+		fmt.Fprintf(out, "%s %s %s\n", styles.Error.Render("✗"), r.Name, elapsed)
+		// Show a few offending rows; the reports carry the rest
+		preview := make([]check.Failure, len(r.Failures))
+		for i, f := range r.Failures {
+			preview[i] = f
+			if len(f.Rows) > 5 {
+				preview[i].Rows = f.Rows[:5]
+			}
+		}
+		for _, line := range strings.Split(strings.TrimRight(check.FormatFailures(preview), "\n"), "\n") {
+			fmt.Fprintln(out, "    "+styles.Faint.Render(line))
+		}
+	}
+}
+
+// writeCheckReport writes a report to path, or to stdout for "-"
+func writeCheckReport(path, connName string, results []check.Result, write func(io.Writer, string, []check.Result) error) error {
+	if path == "-" {
+		return write(os.Stdout, connName, results)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f, connName, results)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
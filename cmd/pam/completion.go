@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/caiolandgraf/pam/internal/completion"
 	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/params"
 )
 
 func (a *App) handleComplete() {
@@ -69,15 +72,124 @@ func getCompletions(args []string, cfg *config.Config) []string {
 		// Check if completing after --format/-f
 		for i, arg := range args {
 			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
-				return []string{"csv", "json", "tsv", "html", "sql", "markdown"}
+				return []string{"csv", "json", "tsv", "html", "sql", "markdown", "svg"}
+			}
+			if arg == "--chart" && i == len(args)-1 {
+				return []string{"line", "bar", "sparkline"}
+			}
+			if arg == "--group" && i == len(args)-1 {
+				return getAllGroups(cfg)
+			}
+		}
+		if len(args) >= 3 {
+			if values, ok := getParamLookupValues(cfg, args[1], args[len(args)-1]); ok {
+				return values
 			}
 		}
 		result := getCurrentConnectionQueries(cfg)
-		result = append(result, "--format", "-f")
+		if len(args) >= 2 {
+			result = append(result, getQueryParamFlags(cfg, args[1])...)
+		}
+		result = append(result, "--format", "-f", "--chart", "--file-mode", "--group", "--parallel", "--yes")
 		return result
+	case "plan":
+		for i, arg := range args {
+			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
+				return []string{"text", "json"}
+			}
+		}
+		result := getCurrentConnectionQueries(cfg)
+		result = append(result, "--analyze", "--no-analyze", "--format")
+		return result
+	case "explain", "erd":
+		for i, arg := range args {
+			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
+				return graphFormats
+			}
+		}
+		return []string{"--format", "--depth", "--output"}
+	case "profile":
+		for i, arg := range args {
+			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
+				return []string{"json", "markdown"}
+			}
+		}
+		return []string{"--format", "--top", "--buckets", "--columns"}
+	case "seed":
+		for i, arg := range args {
+			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
+				return []string{"sql", "csv"}
+			}
+		}
+		return []string{"--rows", "--seed", "--output", "--format", "--batch-size"}
+	case "migrate":
+		if len(args) == 1 {
+			return []string{"new", "up", "down", "status"}
+		}
+		return []string{"--dir", "--steps", "--dry-run"}
+	case "check":
+		return append(
+			[]string{"--junit", "--json", "--update-snapshots"},
+			getCurrentConnectionQueries(cfg)...,
+		)
+	case "group":
+		if len(args) == 1 {
+			return []string{"list", "add", "remove"}
+		}
+		if len(args) == 2 {
+			return getAllGroups(cfg)
+		}
+		return getAllConnections(cfg)
+	case "export":
+		if len(args) > 0 && args[len(args)-1] == "--target-dialect" {
+			return sqlDialects
+		}
+		return []string{
+			"--table", "--output", "--no-create", "--drop", "--no-data",
+			"--data-only", "--target-dialect", "--dir", "--jobs", "--gzip",
+			"--zstd", "--resume", "--no-mask",
+		}
+	case "import":
+		if len(args) > 0 && args[len(args)-1] == "--source-dialect" {
+			return sqlDialects
+		}
+		return []string{
+			"--file", "--continue-on-error", "--dry-run", "--source-dialect",
+			"--single-transaction", "--batch-size", "--error-report",
+		}
+	case "table":
+		if len(args) == 1 {
+			return []string{"create", "rename", "truncate", "drop", "clone"}
+		}
+		return []string{"--yes", "--schema-only"}
+	case "open":
+		for i, arg := range args {
+			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
+				return []string{"csv", "json", "tsv", "html", "sql", "markdown"}
+			}
+		}
+		return []string{"--format"}
+	case "federate":
+		for i, arg := range args {
+			if (arg == "--format" || arg == "-f") && i == len(args)-1 {
+				return []string{"csv", "json", "tsv", "html", "sql", "markdown"}
+			}
+		}
+		return []string{"--format", "--materialize"}
+	case "add", "save":
+		if len(args) >= 2 && args[len(args)-1] == "--folder" {
+			return getQueryFolders(cfg)
+		}
+		if len(args) >= 2 && (args[len(args)-1] == "--tag" || args[len(args)-1] == "-t") {
+			return getQueryTags(cfg)
+		}
+		return []string{"--project", "--description", "--tag", "--folder", "--assert"}
 	case "switch", "use":
 		return getAllConnections(cfg)
 	case "list", "ls":
+		if len(args) >= 2 && (args[len(args)-1] == "--tag" || args[len(args)-1] == "-t") {
+			return getQueryTags(cfg)
+		}
 		if len(args) >= 2 {
 			if args[1] == "queries" {
 				return getCurrentConnectionQueries(cfg)
@@ -86,12 +198,14 @@ func getCompletions(args []string, cfg *config.Config) []string {
 				return []string{}
 			}
 			// Partial match for subcommands or query completion
-			result := []string{"queries", "connections"}
+			result := []string{"queries", "connections", "--tag", "--tree"}
+			result = append(result, getQueryFolders(cfg)...)
 			result = append(result, getCurrentConnectionQueries(cfg)...)
 			return result
 		}
 		// No subcommand yet - return subcommands and query names
-		result := []string{"queries", "connections"}
+		result := []string{"queries", "connections", "--tag", "--tree"}
+		result = append(result, getQueryFolders(cfg)...)
 		result = append(result, getCurrentConnectionQueries(cfg)...)
 		return result
 	case "info":
@@ -99,7 +213,16 @@ func getCompletions(args []string, cfg *config.Config) []string {
 			return []string{"table", "view"}
 		}
 		return []string{"table", "view"}
-	case "edit", "delete", "rm", "remove":
+	case "edit":
+		if len(args) >= 2 && args[len(args)-1] == "--folder" {
+			return getQueryFolders(cfg)
+		}
+		if len(args) >= 2 && (args[len(args)-1] == "--tag" || args[len(args)-1] == "-t") {
+			return getQueryTags(cfg)
+		}
+		result := getCurrentConnectionQueries(cfg)
+		return append(result, "--description", "--tag", "--folder", "--assert")
+	case "delete", "rm", "remove":
 		return getCurrentConnectionQueries(cfg)
 	case "--connection", "-c":
 		return getAllConnections(cfg)
@@ -128,17 +251,39 @@ func getSubcommands() []string {
 		"status",
 		"test",
 		"history",
+		"export",
+		"import",
 		"tables",
 		"t",
+		"table-view",
+		"tv",
+		"table",
 		"disconnect",
 		"clear",
 		"unset",
 		"config",
 		"explain",
+		"plan",
+		"erd",
+		"profile",
+		"seed",
+		"migrate",
+		"check",
+		"open",
+		"group",
+		"federate",
 		"help",
 	}
 }
 
+func getAllGroups(cfg *config.Config) []string {
+	var names []string
+	for name := range cfg.Groups() {
+		names = append(names, name)
+	}
+	return names
+}
+
 func getAllConnections(cfg *config.Config) []string {
 	var names []string
 	for name := range cfg.Connections {
@@ -153,15 +298,100 @@ func getCurrentConnectionQueries(cfg *config.Config) []string {
 		return []string{}
 	}
 
-	conn, exists := cfg.Connections[cfg.CurrentConnection]
-	if !exists {
+	if _, exists := cfg.Connections[cfg.CurrentConnection]; !exists {
 		return []string{}
 	}
 
+	// Foldered queries also complete by path, e.g. billing/invoices
 	var names []string
-	for name := range conn.Queries {
+	for name, q := range cfg.Queries(cfg.CurrentConnection) {
 		names = append(names, name)
+		if q.Folder != "" {
+			names = append(names, q.Path())
+		}
 	}
 	sort.Strings(names)
 	return names
 }
+
+// getQueryParamFlags returns a --name flag per parameter of a saved query.
+func getQueryParamFlags(cfg *config.Config, selector string) []string {
+	q, ok := db.FindQueryWithSelector(cfg.Queries(cfg.CurrentConnection), selector)
+	if !ok {
+		return nil
+	}
+	var flags []string
+	for name := range params.ExtractParameters(q.SQL) {
+		flags = append(flags, "--"+name)
+	}
+	sort.Strings(flags)
+	return flags
+}
+
+// getParamLookupValues completes the value of a --name flag from the
+// query's "-- @param name lookup: ..." query, cached between completions.
+func getParamLookupValues(cfg *config.Config, selector, flag string) ([]string, bool) {
+	name, isFlag := strings.CutPrefix(flag, "--")
+	if !isFlag {
+		return nil, false
+	}
+	q, ok := db.FindQueryWithSelector(cfg.Queries(cfg.CurrentConnection), selector)
+	if !ok {
+		return nil, false
+	}
+	lookupSQL, ok := params.ExtractLookups(q.SQL)[name]
+	if !ok {
+		return nil, false
+	}
+	connYAML, ok := cfg.Connections[cfg.CurrentConnection]
+	if !ok {
+		return nil, false
+	}
+
+	suggestions, err := params.RunLookup(config.FromConnectionYaml(connYAML), lookupSQL)
+	if err != nil {
+		return []string{}, true
+	}
+	values := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		values[i] = s.Value
+	}
+	return values, true
+}
+
+// getQueryFolders returns every folder of the current connection's queries,
+// parents included, with a trailing slash.
+func getQueryFolders(cfg *config.Config) []string {
+	seen := map[string]bool{}
+	for _, q := range cfg.Queries(cfg.CurrentConnection) {
+		for folder := q.Folder; folder != ""; {
+			seen[folder+"/"] = true
+			i := strings.LastIndex(folder, "/")
+			if i < 0 {
+				break
+			}
+			folder = folder[:i]
+		}
+	}
+	folders := make([]string, 0, len(seen))
+	for folder := range seen {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+func getQueryTags(cfg *config.Config) []string {
+	seen := map[string]bool{}
+	for _, q := range cfg.Queries(cfg.CurrentConnection) {
+		for _, tag := range q.Tags {
+			seen[tag] = true
+		}
+	}
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
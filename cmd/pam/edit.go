@@ -8,20 +8,29 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/caiolandgraf/pam/internal/config"
 	"github.com/caiolandgraf/pam/internal/db"
 	"github.com/caiolandgraf/pam/internal/editor"
+	"github.com/caiolandgraf/pam/internal/params"
 	"github.com/caiolandgraf/pam/internal/styles"
 )
 
 func (a *App) handleEdit() {
-	if len(os.Args) >= 3 {
-		querySelector := os.Args[2]
+	meta, args := parseQueryMetaFlags(os.Args[2:])
+	if len(args) >= 1 {
+		querySelector := args[0]
 		if querySelector == "config" {
 			// Correção: Usar "%s" para formatar a string
 			printError("%s", "Config editing moved to 'pam config' command")
 			return
 		}
+		if meta.isSet() {
+			a.updateQueryMeta(querySelector, meta)
+			return
+		}
 		a.editSingleQuery(querySelector)
+	} else if meta.isSet() {
+		printError("%s", "Usage: pam edit <query> [--description <text>] [--tag <tag>]... [--folder <path>] [--assert <expr>]")
 	} else {
 		a.editQueries()
 	}
@@ -40,7 +49,7 @@ func (a *App) editSingleQuery(selector string) {
 	}
 
 	// Find the query
-	query, exists := db.FindQueryWithSelector(conn.Queries, selector)
+	query, exists := db.FindQueryWithSelector(a.config.Queries(a.config.CurrentConnection), selector)
 	if !exists {
 		log.Fatalf(
 			"Query '%s' not found in connection '%s'",
@@ -49,9 +58,24 @@ func (a *App) editSingleQuery(selector string) {
 		)
 	}
 
+	// Project queries are edited in place, front matter included
+	if query.Source != "" {
+		pq, _ := config.Project.Lookup(a.config.CurrentConnection, query.Name)
+		cmd := exec.Command(editor.GetEditorCommand(), pq.Path)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			log.Fatalf("Failed to open editor: %v", err)
+		}
+		fmt.Printf("✓ Updated query '%s' (%s)\n", query.Name, query.Source)
+		return
+	}
+
 	// Create temp file with the query SQL
 	var content strings.Builder
 	content.WriteString(fmt.Sprintf("-- %s\n", query.Name))
+	content.WriteString(metadataHeader(query))
 	content.WriteString(query.SQL)
 
 	tmpFile, err := editor.CreateTempFile("pam-edit-query-", content.String())
@@ -98,6 +122,7 @@ func (a *App) editSingleQuery(selector string) {
 	// Update query
 	query.Name = newName
 	query.SQL = newSQL
+	parseMetadataHeader(editedData).apply(&query)
 	conn.Queries[query.Name] = query
 	a.config.Connections[a.config.CurrentConnection] = conn
 
@@ -179,6 +204,15 @@ func (a *App) editQueriesWithEditor(editorCmd string) {
 		log.Fatalf("Failed to parse edited queries: %v", err)
 	}
 
+	// Everything but the SQL is left out of the file; keep it by name
+	for name, q := range editedQueries {
+		if old, ok := conn.Queries[name]; ok {
+			q.Description, q.Tags, q.Folder = old.Description, old.Tags, old.Folder
+			q.Assert, q.Metadata = old.Assert, old.Metadata
+			editedQueries[name] = q
+		}
+	}
+
 	conn.Queries = editedQueries
 	a.config.Connections[a.config.CurrentConnection] = conn
 
@@ -192,6 +226,35 @@ func (a *App) editQueriesWithEditor(editorCmd string) {
 	)
 }
 
+// updateQueryMeta sets a query's description, tags or folder without
+// opening the editor.
+func (a *App) updateQueryMeta(selector string, meta queryMeta) {
+	if a.config.CurrentConnection == "" {
+		printError("No active connection. Use 'pam switch <connection>' or 'pam init' first")
+	}
+
+	query, exists := db.FindQueryWithSelector(a.config.Queries(a.config.CurrentConnection), selector)
+	if !exists {
+		printError("Query '%s' not found in connection '%s'", selector, a.config.CurrentConnection)
+	}
+
+	if query.Source != "" {
+		pq, _ := config.Project.Lookup(a.config.CurrentConnection, query.Name)
+		meta.apply(&pq.Query)
+		if _, err := config.Project.Save(pq); err != nil {
+			printError("Could not save %s: %v", query.Source, err)
+		}
+	} else {
+		meta.apply(&query)
+		a.config.Connections[a.config.CurrentConnection].Queries[query.Name] = query
+		if err := a.config.Save(); err != nil {
+			printError("Could not save configuration file: %v", err)
+		}
+	}
+
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Updated query '%s'", query.Name)))
+}
+
 // parseSingleQueryFile parses a file containing a single query
 // Expected format:
 //
@@ -230,9 +293,9 @@ func parseSingleQueryFile(content string) (string, string, error) {
 			}
 		}
 
-		// Rest is SQL
+		// Rest is SQL, lookup declarations included
 		if foundName {
-			if !strings.HasPrefix(trimmed, "--") {
+			if !strings.HasPrefix(trimmed, "--") || params.IsLookupLine(trimmed) {
 				sqlLines = append(sqlLines, line)
 			}
 		}
@@ -295,8 +358,9 @@ func parseSQLQueriesFile(content string) (map[string]db.Query, error) {
 	for line := range strings.SplitSeq(content, "\n") {
 		trimmed := strings.TrimSpace(line)
 
-		// Check for query name comment
-		if comment, ok := strings.CutPrefix(trimmed, "--"); ok {
+		// Check for query name comment; a lookup declaration belongs to
+		// the query above it
+		if comment, ok := strings.CutPrefix(trimmed, "--"); ok && !params.IsLookupLine(trimmed) {
 			comment = strings.TrimSpace(comment)
 
 			// Skip help comments
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/params"
+)
+
+const lookupSQL = "-- @param customer_id lookup: SELECT id, name FROM customers\nSELECT * FROM orders WHERE customer_id = :customer_id"
+
+// useTempConfig points the config file at a temporary directory
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	path, file := config.CfgPath, config.CfgFile
+	config.CfgPath = t.TempDir()
+	config.CfgFile = filepath.Join(config.CfgPath, "config.yaml")
+	t.Cleanup(func() { config.CfgPath, config.CfgFile = path, file })
+}
+
+func TestRemoveCommentLines_KeepsLookups(t *testing.T) {
+	content := "-- Creating new run:  orders\n-- Write your SQL run below and save\n\n" + lookupSQL + "\n"
+	got := removeCommentLines(content)
+	if lookups := params.ExtractLookups(got); lookups["customer_id"] != "SELECT id, name FROM customers" {
+		t.Errorf("lookup lost from %q", got)
+	}
+	if params.StripLookups(got) != "SELECT * FROM orders WHERE customer_id = :customer_id" {
+		t.Errorf("comments kept in %q", got)
+	}
+}
+
+func TestParseSingleQueryFile_KeepsLookups(t *testing.T) {
+	q := db.Query{Name: "orders", SQL: lookupSQL, Description: "By customer"}
+	name, sql, err := parseSingleQueryFile("-- orders\n" + metadataHeader(q) + q.SQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "orders" || sql != lookupSQL {
+		t.Errorf("parsed %q, %q", name, sql)
+	}
+}
+
+func TestEditQueries_KeepsLookups(t *testing.T) {
+	useTempConfig(t)
+	app := NewApp(&config.Config{
+		CurrentConnection: "local",
+		Connections: map[string]*config.ConnectionYAML{
+			"local": {
+				Name:   "local",
+				DBType: "sqlite",
+				Queries: map[string]db.Query{
+					"orders": {Name: "orders", SQL: lookupSQL, Id: 1},
+					"users":  {Name: "users", SQL: "SELECT * FROM users", Id: 2},
+				},
+			},
+		},
+	})
+
+	// An editor that saves the file unchanged
+	app.editQueriesWithEditor("true")
+
+	queries := app.config.Connections["local"].Queries
+	if len(queries) != 2 {
+		t.Fatalf("queries = %+v", queries)
+	}
+	if got := queries["orders"].SQL; got != lookupSQL {
+		t.Errorf("orders SQL = %q", got)
+	}
+}
+
+func TestEditQueries_KeepsMetadata(t *testing.T) {
+	useTempConfig(t)
+	view := map[string]string{"view": `{"filter":"total > 0"}`}
+	app := NewApp(&config.Config{
+		CurrentConnection: "local",
+		Connections: map[string]*config.ConnectionYAML{
+			"local": {
+				Name:   "local",
+				DBType: "sqlite",
+				Queries: map[string]db.Query{
+					"negative_totals": {
+						Name:     "negative_totals",
+						SQL:      "SELECT * FROM orders WHERE total < 0",
+						Id:       1,
+						Tags:     []string{"check"},
+						Assert:   "rows between 0 and 2",
+						Metadata: view,
+					},
+				},
+			},
+		},
+	})
+
+	app.editQueriesWithEditor("true")
+
+	q := app.config.Connections["local"].Queries["negative_totals"]
+	if q.Assert != "rows between 0 and 2" || q.Metadata["view"] != view["view"] || len(q.Tags) != 1 {
+		t.Errorf("after a bulk edit = %+v", q)
+	}
+}
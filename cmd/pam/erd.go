@@ -0,0 +1,471 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+// graphColumn is a column of a table in an exported ER diagram.
+type graphColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+	ForeignKey string `json:"foreign_key,omitempty"` // "table.column"
+	Unique     bool   `json:"unique,omitempty"`
+}
+
+type graphTable struct {
+	Name    string        `json:"name"`
+	Columns []graphColumn `json:"columns"`
+}
+
+// graphEdge is a relationship normalized so that From holds the foreign key
+// and To is the referenced table. For N:N edges From/To are the two sides
+// and Via is the junction table.
+type graphEdge struct {
+	From        string `json:"from"`
+	FromColumn  string `json:"from_column,omitempty"`
+	To          string `json:"to"`
+	ToColumn    string `json:"to_column,omitempty"`
+	Type        string `json:"type"`
+	Cardinality string `json:"cardinality"`
+	Via         string `json:"via,omitempty"`
+}
+
+type relationshipGraph struct {
+	Tables        []graphTable `json:"tables"`
+	Relationships []graphEdge  `json:"relationships"`
+}
+
+var graphFormats = []string{"mermaid", "dot", "json"}
+
+type erdFlags struct {
+	format string
+	output string
+	tables []string
+}
+
+func parseErdFlags() erdFlags {
+	flags := erdFlags{format: "mermaid"}
+	args := os.Args[2:]
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-f":
+			if i+1 < len(args) {
+				flags.format = args[i+1]
+				i++
+			} else {
+				printError("--format requires a value (mermaid, dot, json)")
+			}
+		case strings.HasPrefix(arg, "--format="):
+			flags.format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--output" || arg == "-o":
+			if i+1 < len(args) {
+				flags.output = args[i+1]
+				i++
+			} else {
+				printError("--output requires a file path")
+			}
+		case strings.HasPrefix(arg, "--output="):
+			flags.output = strings.TrimPrefix(arg, "--output=")
+		case !strings.HasPrefix(arg, "-"):
+			flags.tables = append(flags.tables, arg)
+		}
+	}
+
+	return flags
+}
+
+func (a *App) handleErd() {
+	if a.config.CurrentConnection == "" {
+		printError("No active connection. Use 'pam switch <connection>' or 'pam init' first")
+	}
+
+	flags := parseErdFlags()
+
+	conn := config.FromConnectionYaml(a.config.Connections[a.config.CurrentConnection])
+	if err := conn.Open(); err != nil {
+		printError("Could not open connection to %s: %v", a.config.CurrentConnection, err)
+	}
+	defer conn.Close()
+
+	tables := flags.tables
+	if len(tables) == 0 {
+		var err error
+		tables, err = conn.GetTables()
+		if err != nil {
+			printError("Could not list tables: %v", err)
+		}
+	}
+	if len(tables) == 0 {
+		printError("No tables found")
+	}
+
+	graph := a.collectSchemaGraph(conn, tables)
+	out, err := renderGraph(graph, flags.format)
+	if err != nil {
+		printError("%v", err)
+	}
+
+	if flags.output == "" {
+		fmt.Print(out)
+		return
+	}
+
+	if err := os.WriteFile(flags.output, []byte(out), 0o644); err != nil {
+		printError("Could not write %s: %v", flags.output, err)
+	}
+	fmt.Fprintln(os.Stderr, styles.Success.Render(fmt.Sprintf(
+		"✓ Wrote %d table(s) and %d relationship(s) to %s",
+		len(graph.Tables), len(graph.Relationships), flags.output,
+	)))
+}
+
+// collectRelationshipGraph walks relationships from root up to maxDepth,
+// following the same rules as the explain tree: self-references and N:N
+// relationships are not expanded and tables are visited once.
+func (a *App) collectRelationshipGraph(
+	conn db.DatabaseConnection,
+	root string,
+	maxDepth int,
+) relationshipGraph {
+	builder := newGraphBuilder()
+	builder.addTable(conn, root)
+
+	type queued struct {
+		table string
+		depth int
+	}
+	visited := map[string]bool{root: true}
+	queue := []queued{{root, 0}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.depth >= maxDepth {
+			continue
+		}
+
+		for _, rel := range a.getChildRelationships(conn, current.table) {
+			builder.addTable(conn, rel.referencedTable)
+			builder.addRelationship(current.table, rel)
+
+			if rel.relType == hasManyToMany || visited[rel.referencedTable] {
+				continue
+			}
+			visited[rel.referencedTable] = true
+			queue = append(queue, queued{rel.referencedTable, current.depth + 1})
+		}
+	}
+
+	return builder.graph(nil)
+}
+
+// collectSchemaGraph builds a diagram of exactly the given tables and the
+// relationships between them.
+func (a *App) collectSchemaGraph(
+	conn db.DatabaseConnection,
+	tables []string,
+) relationshipGraph {
+	builder := newGraphBuilder()
+	included := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		included[t] = true
+		builder.addTable(conn, t)
+	}
+
+	for _, t := range tables {
+		for _, rel := range a.getChildRelationships(conn, t) {
+			builder.addRelationship(t, rel)
+		}
+	}
+
+	return builder.graph(included)
+}
+
+type graphBuilder struct {
+	tables    map[string]graphTable
+	order     []string
+	edges     []graphEdge
+	seenEdges map[string]bool
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{
+		tables:    map[string]graphTable{},
+		seenEdges: map[string]bool{},
+	}
+}
+
+func (b *graphBuilder) addTable(conn db.DatabaseConnection, name string) {
+	if _, ok := b.tables[name]; ok {
+		return
+	}
+
+	fks := map[string]string{}
+	if foreignKeys, err := conn.GetForeignKeys(name); err == nil {
+		for _, fk := range foreignKeys {
+			fks[fk.Column] = fk.ReferencedTable + "." + fk.ReferencedColumn
+		}
+	}
+	uniques := map[string]bool{}
+	if constraints, err := conn.GetUniqueConstraints(name); err == nil {
+		for _, uc := range constraints {
+			uniques[uc] = true
+		}
+	}
+
+	table := graphTable{Name: name}
+	if details, err := conn.GetColumnDetails(name); err == nil {
+		for _, col := range details {
+			table.Columns = append(table.Columns, graphColumn{
+				Name:       col.Name,
+				Type:       col.DataType,
+				Nullable:   strings.EqualFold(col.Nullable, "YES"),
+				PrimaryKey: col.IsPrimaryKey,
+				ForeignKey: fks[col.Name],
+				Unique:     uniques[col.Name],
+			})
+		}
+	}
+
+	b.tables[name] = table
+	b.order = append(b.order, name)
+}
+
+// addRelationship records rel (as seen from table) in canonical direction so
+// the same FK found from both ends is only emitted once.
+func (b *graphBuilder) addRelationship(table string, rel relationship) {
+	var edge graphEdge
+
+	switch rel.relType {
+	case belongsTo, hasOne:
+		if rel.relType == hasOne && !b.hasForeignKey(table, rel) {
+			// has one seen from the referenced side: the FK lives on the other table
+			edge = graphEdge{From: rel.referencedTable, FromColumn: rel.column, To: table, ToColumn: rel.referencedColumn}
+		} else {
+			edge = graphEdge{From: table, FromColumn: rel.column, To: rel.referencedTable, ToColumn: rel.referencedColumn}
+		}
+	case hasMany:
+		edge = graphEdge{From: rel.referencedTable, FromColumn: rel.column, To: table, ToColumn: rel.referencedColumn}
+	case hasManyToMany:
+		from, to := table, rel.referencedTable
+		if to < from {
+			from, to = to, from
+		}
+		edge = graphEdge{From: from, To: to, Via: rel.junctionTable}
+	}
+
+	if rel.relType == hasManyToMany {
+		edge.Type = "many_to_many"
+		edge.Cardinality = hasManyToMany.cardinality()
+	} else if rel.relType == hasOne {
+		edge.Type = "one_to_one"
+		edge.Cardinality = hasOne.cardinality()
+	} else {
+		edge.Type = "many_to_one"
+		edge.Cardinality = belongsTo.cardinality()
+	}
+	edge.Cardinality = strings.Trim(edge.Cardinality, "[]")
+
+	key := fmt.Sprintf("%s.%s>%s.%s|%s", edge.From, edge.FromColumn, edge.To, edge.ToColumn, edge.Via)
+	if b.seenEdges[key] {
+		return
+	}
+	b.seenEdges[key] = true
+	b.edges = append(b.edges, edge)
+}
+
+// hasForeignKey reports whether table itself holds the FK described by rel.
+func (b *graphBuilder) hasForeignKey(table string, rel relationship) bool {
+	target := rel.referencedTable + "." + rel.referencedColumn
+	for _, c := range b.tables[table].Columns {
+		if c.Name == rel.column && c.ForeignKey == target {
+			return true
+		}
+	}
+	return false
+}
+
+// graph returns the collected tables and edges. When included is set, only
+// edges between included tables are kept, and N:N edges are dropped when
+// their junction table is part of the diagram (its own FKs already show it).
+func (b *graphBuilder) graph(included map[string]bool) relationshipGraph {
+	g := relationshipGraph{Tables: []graphTable{}, Relationships: []graphEdge{}}
+	for _, name := range b.order {
+		if included == nil || included[name] {
+			g.Tables = append(g.Tables, b.tables[name])
+		}
+	}
+
+	present := included
+	if present == nil {
+		present = map[string]bool{}
+		for _, name := range b.order {
+			present[name] = true
+		}
+	}
+
+	for _, e := range b.edges {
+		if !present[e.From] || !present[e.To] {
+			continue
+		}
+		if e.Via != "" && present[e.Via] && included != nil {
+			continue
+		}
+		g.Relationships = append(g.Relationships, e)
+	}
+
+	sort.SliceStable(g.Relationships, func(i, j int) bool {
+		if g.Relationships[i].From != g.Relationships[j].From {
+			return g.Relationships[i].From < g.Relationships[j].From
+		}
+		return g.Relationships[i].To < g.Relationships[j].To
+	})
+
+	return g
+}
+
+func renderGraph(g relationshipGraph, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "mermaid", "mmd":
+		return renderMermaid(g), nil
+	case "dot", "graphviz":
+		return renderDot(g), nil
+	case "json":
+		out, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("could not encode graph: %w", err)
+		}
+		return string(out) + "\n", nil
+	}
+	return "", fmt.Errorf("unknown format '%s'. Use one of: %s", format, strings.Join(graphFormats, ", "))
+}
+
+// keyMarkers returns the PK/FK/UK markers for a column.
+func keyMarkers(c graphColumn) []string {
+	var markers []string
+	if c.PrimaryKey {
+		markers = append(markers, "PK")
+	}
+	if c.ForeignKey != "" {
+		markers = append(markers, "FK")
+	}
+	if c.Unique && !c.PrimaryKey {
+		markers = append(markers, "UK")
+	}
+	return markers
+}
+
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_\-]`)
+
+func mermaidName(s string) string {
+	return mermaidUnsafe.ReplaceAllString(s, "_")
+}
+
+func renderMermaid(g relationshipGraph) string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	for _, t := range g.Tables {
+		fmt.Fprintf(&b, "    %s {\n", mermaidName(t.Name))
+		for _, c := range t.Columns {
+			colType := mermaidName(c.Type)
+			if colType == "" {
+				colType = "unknown"
+			}
+			fmt.Fprintf(&b, "        %s %s", colType, mermaidName(c.Name))
+			if markers := keyMarkers(c); len(markers) > 0 {
+				b.WriteString(" " + strings.Join(markers, ", "))
+			}
+			if c.ForeignKey != "" {
+				fmt.Fprintf(&b, " \"→ %s\"", c.ForeignKey)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+
+	for _, e := range g.Relationships {
+		var connector, label string
+		switch e.Type {
+		case "many_to_many":
+			connector = "}o--o{"
+			label = fmt.Sprintf("via %s [%s]", e.Via, e.Cardinality)
+		case "one_to_one":
+			connector = "|o--||"
+			label = fmt.Sprintf("%s → %s [%s]", e.FromColumn, e.ToColumn, e.Cardinality)
+		default:
+			connector = "}o--||"
+			label = fmt.Sprintf("%s → %s [%s]", e.FromColumn, e.ToColumn, e.Cardinality)
+		}
+		fmt.Fprintf(&b, "    %s %s %s : \"%s\"\n", mermaidName(e.From), connector, mermaidName(e.To), label)
+	}
+
+	return b.String()
+}
+
+func dotID(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func renderDot(g relationshipGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph erd {\n")
+	b.WriteString("    graph [rankdir=LR];\n")
+	b.WriteString("    node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	for _, t := range g.Tables {
+		fmt.Fprintf(&b, "    %s [label=<\n", dotID(t.Name))
+		b.WriteString("      <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+		fmt.Fprintf(&b, "        <tr><td colspan=\"2\" bgcolor=\"lightgrey\"><b>%s</b></td></tr>\n", html.EscapeString(t.Name))
+		for _, c := range t.Columns {
+			name := html.EscapeString(c.Name)
+			if c.PrimaryKey {
+				name = "<u>" + name + "</u>"
+			}
+			markers := ""
+			if m := keyMarkers(c); len(m) > 0 {
+				markers = " " + strings.Join(m, ",")
+			}
+			fmt.Fprintf(&b, "        <tr><td align=\"left\" port=%s>%s%s</td><td align=\"left\">%s</td></tr>\n",
+				dotID(c.Name), name, markers, html.EscapeString(c.Type))
+		}
+		b.WriteString("      </table>\n    >];\n")
+	}
+
+	if len(g.Relationships) > 0 {
+		b.WriteString("\n")
+	}
+	for _, e := range g.Relationships {
+		switch e.Type {
+		case "many_to_many":
+			fmt.Fprintf(&b, "    %s -> %s [label=%s, dir=both, arrowhead=crow, arrowtail=crow, style=dashed];\n",
+				dotID(e.From), dotID(e.To), dotID(fmt.Sprintf("via %s [%s]", e.Via, e.Cardinality)))
+		default:
+			arrowtail := "crow"
+			if e.Type == "one_to_one" {
+				arrowtail = "tee"
+			}
+			fmt.Fprintf(&b, "    %s:%s -> %s:%s [label=%s, dir=both, arrowhead=tee, arrowtail=%s];\n",
+				dotID(e.From), dotID(e.FromColumn), dotID(e.To), dotID(e.ToColumn),
+				dotID(fmt.Sprintf("%s → %s [%s]", e.FromColumn, e.ToColumn, e.Cardinality)), arrowtail)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -15,6 +15,7 @@ import (
 type explainFlags struct {
 	depth   int
 	verbose bool
+	format  string
 }
 
 func parseExplainFlags() (explainFlags, []string) {
@@ -35,6 +36,13 @@ func parseExplainFlags() (explainFlags, []string) {
 			}
 		} else if arg == "--verbose" || arg == "-v" {
 			flags.verbose = true
+		} else if arg == "--format" || arg == "-f" {
+			if i+1 < len(args) {
+				flags.format = args[i+1]
+				i++
+			}
+		} else if strings.HasPrefix(arg, "--format=") {
+			flags.format = strings.TrimPrefix(arg, "--format=")
 		} else if !strings.HasPrefix(arg, "-") {
 			remainingArgs = append(remainingArgs, arg)
 		}
@@ -53,7 +61,7 @@ func (a *App) handleExplain() {
 	flags, args := parseExplainFlags()
 
 	if len(args) == 0 {
-		fmt.Println("Usage: pam explain [--depth|-d N] [--verbose|-v] <table-name>")
+		fmt.Println("Usage: pam explain [--depth|-d N] [--verbose|-v] [--format|-f mermaid|dot|json] <table-name>")
 		os.Exit(1)
 	}
 
@@ -72,6 +80,16 @@ func (a *App) handleExplain() {
 
 	tableName := args[0]
 
+	if flags.format != "" {
+		graph := a.collectRelationshipGraph(conn, tableName, flags.depth)
+		out, err := renderGraph(graph, flags.format)
+		if err != nil {
+			printError("%v", err)
+		}
+		fmt.Print(out)
+		return
+	}
+
 	// Cache for FK lookups to improve performance
 	fkCache := make(map[string][]db.ForeignKey)
 	visited := make(map[string]bool)
@@ -98,6 +116,21 @@ const (
 	hasManyToMany
 )
 
+// cardinality returns the label shown next to a relationship, e.g. "[N:1]".
+func (r relationshipType) cardinality() string {
+	switch r {
+	case belongsTo:
+		return "[N:1]"
+	case hasMany:
+		return "[1:N]"
+	case hasOne:
+		return "[1:1]"
+	case hasManyToMany:
+		return "[N:N]"
+	}
+	return ""
+}
+
 type relationship struct {
 	relType          relationshipType
 	column           string
@@ -118,117 +151,7 @@ func (a *App) buildRelationshipTree(
 		return ""
 	}
 
-	var relationships []relationship
-	seenTables := make(map[string]bool)
-
-	// Get unique constraints for this table (for 1:1 detection)
-	uniqueConstraints, _ := conn.GetUniqueConstraints(tableName)
-	uniqueMap := make(map[string]bool)
-	for _, uc := range uniqueConstraints {
-		uniqueMap[uc] = true
-	}
-
-	// Get "belongs to" relationships (FKs from this table to other tables)
-	belongsToFKs, err := conn.GetForeignKeys(tableName)
-	if err == nil {
-		for _, fk := range belongsToFKs {
-			// Create a unique key for deduplication
-			key := fmt.Sprintf(
-				"%s:%s:%s",
-				fk.ReferencedTable,
-				fk.Column,
-				fk.ReferencedColumn,
-			)
-			if seenTables[key] {
-				continue
-			}
-			seenTables[key] = true
-
-			// Check if FK column has UNIQUE constraint (1:1 relationship)
-			relType := belongsTo
-			if uniqueMap[fk.Column] {
-				relType = hasOne
-			}
-
-			relationships = append(relationships, relationship{
-				relType:          relType,
-				column:           fk.Column,
-				referencedTable:  fk.ReferencedTable,
-				referencedColumn: fk.ReferencedColumn,
-			})
-		}
-	}
-
-	// Get "has many" relationships (FKs from other tables to this table)
-	hasManyFKs, err := conn.GetForeignKeysReferencingTable(tableName)
-	if err == nil {
-		for _, fk := range hasManyFKs {
-			// Check if the referencing table is a junction table (N:N detection)
-			if a.isJunctionTable(conn, fk.ReferencedTable) {
-				// Get the other side of the N:N relationship
-				otherTable := a.getJunctionTableOtherSide(
-					conn,
-					fk.ReferencedTable,
-					tableName,
-				)
-				if otherTable != "" {
-					// Create a unique key for deduplication
-					key := fmt.Sprintf(
-						"nn:%s:%s:%s",
-						otherTable,
-						fk.ReferencedTable,
-						tableName,
-					)
-					if seenTables[key] {
-						continue
-					}
-					seenTables[key] = true
-
-					relationships = append(relationships, relationship{
-						relType:         hasManyToMany,
-						referencedTable: otherTable,
-						junctionTable:   fk.ReferencedTable,
-					})
-				}
-			} else {
-				// Check if FK column in referencing table has UNIQUE constraint (1:1 relationship)
-				otherTableUnique, _ := conn.GetUniqueConstraints(
-					fk.ReferencedTable,
-				)
-				isOneToOne := false
-				for _, uc := range otherTableUnique {
-					if uc == fk.Column {
-						isOneToOne = true
-						break
-					}
-				}
-
-				// Create a unique key for deduplication
-				key := fmt.Sprintf(
-					"%s:%s:%s",
-					fk.ReferencedTable,
-					fk.Column,
-					fk.ReferencedColumn,
-				)
-				if seenTables[key] {
-					continue
-				}
-				seenTables[key] = true
-
-				relType := hasMany
-				if isOneToOne {
-					relType = hasOne
-				}
-
-				relationships = append(relationships, relationship{
-					relType:          relType,
-					column:           fk.Column,
-					referencedTable:  fk.ReferencedTable,
-					referencedColumn: fk.ReferencedColumn,
-				})
-			}
-		}
-	}
+	relationships := a.getChildRelationships(conn, tableName)
 
 	return a.renderNode(
 		conn,
@@ -347,7 +270,7 @@ func (a *App) renderNode(
 
 		if rel.relType == belongsTo {
 			relText = "belongs to"
-			cardinality = "[N:1]"
+			cardinality = rel.relType.cardinality()
 			relStyle = styles.BelongsToStyle
 			if verbose {
 				fkDetails = fmt.Sprintf(
@@ -359,7 +282,7 @@ func (a *App) renderNode(
 			}
 		} else if rel.relType == hasOne {
 			relText = "has one"
-			cardinality = "[1:1]"
+			cardinality = rel.relType.cardinality()
 			relStyle = styles.HasOneStyle
 			if verbose {
 				fkDetails = fmt.Sprintf(
@@ -371,7 +294,7 @@ func (a *App) renderNode(
 			}
 		} else if rel.relType == hasMany {
 			relText = "has many"
-			cardinality = "[1:N]"
+			cardinality = rel.relType.cardinality()
 			relStyle = styles.HasManyStyle
 			if verbose {
 				fkDetails = fmt.Sprintf(
@@ -383,7 +306,7 @@ func (a *App) renderNode(
 			}
 		} else if rel.relType == hasManyToMany {
 			relText = "↔"
-			cardinality = "[N:N]"
+			cardinality = rel.relType.cardinality()
 			relStyle = styles.HasManyToManyStyle
 			if verbose {
 				fkDetails = fmt.Sprintf("(via %s)", rel.junctionTable)
@@ -2,22 +2,37 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/caiolandgraf/pam/internal/config"
 	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/mask"
 	"github.com/caiolandgraf/pam/internal/spinner"
 	"github.com/caiolandgraf/pam/internal/styles"
 )
 
+// sqlDialects are the values --target-dialect and --source-dialect take
+var sqlDialects = []string{
+	"postgres", "mysql", "sqlite", "sqlserver", "oracle", "duckdb",
+	"clickhouse", "firebird", "snowflake",
+}
+
 type exportFlags struct {
 	tableName    string
 	outputFile   string
 	noCreate     bool
 	dropIfExists bool
 	noData       bool
+	dialect      string
+	dir          string
+	jobs         int
+	compression  string
+	resume       bool
+	noMask       bool
 }
 
 func parseExportFlags() exportFlags {
@@ -59,7 +74,61 @@ func parseExportFlags() exportFlags {
 			flags.outputFile = strings.TrimPrefix(arg, "-o=")
 			i++
 
+		// --target-dialect
+		case arg == "--target-dialect":
+			if i+1 < len(args) {
+				flags.dialect = args[i+1]
+				i += 2
+			} else {
+				printError("--target-dialect requires a value")
+			}
+		case strings.HasPrefix(arg, "--target-dialect="):
+			flags.dialect = strings.TrimPrefix(arg, "--target-dialect=")
+			i++
+
+		// --dir
+		case arg == "--dir":
+			if i+1 < len(args) {
+				flags.dir = args[i+1]
+				i += 2
+			} else {
+				printError("--dir requires a value")
+			}
+		case strings.HasPrefix(arg, "--dir="):
+			flags.dir = strings.TrimPrefix(arg, "--dir=")
+			i++
+
+		// --jobs / -j
+		case arg == "--jobs" || arg == "-j":
+			if i+1 < len(args) {
+				flags.jobs = parseJobs(args[i+1])
+				i += 2
+			} else {
+				printError("--jobs requires a value")
+			}
+		case strings.HasPrefix(arg, "--jobs="):
+			flags.jobs = parseJobs(strings.TrimPrefix(arg, "--jobs="))
+			i++
+		case strings.HasPrefix(arg, "-j="):
+			flags.jobs = parseJobs(strings.TrimPrefix(arg, "-j="))
+			i++
+
+		// compression
+		case arg == "--gzip" || arg == "--zstd":
+			compression := strings.TrimPrefix(arg, "--")
+			if flags.compression != "" && flags.compression != compression {
+				printError("--gzip and --zstd cannot be used together")
+			}
+			flags.compression = compression
+			i++
+
 		// boolean flags
+		case arg == "--resume":
+			flags.resume = true
+			i++
+		case arg == "--no-mask":
+			flags.noMask = true
+			i++
 		case arg == "--no-create" || arg == "--no-create-table":
 			flags.noCreate = true
 			i++
@@ -85,6 +154,22 @@ func parseExportFlags() exportFlags {
 	return flags
 }
 
+func compressOutput(w io.Writer, compression string) io.WriteCloser {
+	out, err := db.NewCompressWriter(w, compression)
+	if err != nil {
+		printError("%v", err)
+	}
+	return out
+}
+
+func parseJobs(value string) int {
+	jobs, err := strconv.Atoi(value)
+	if err != nil || jobs < 1 {
+		printError("--jobs must be a positive number, got %q", value)
+	}
+	return jobs
+}
+
 func (a *App) handleExport() {
 	if a.config.CurrentConnection == "" {
 		printError(
@@ -101,12 +186,29 @@ func (a *App) handleExport() {
 	if flags.noCreate && flags.noData {
 		printError("--no-create and --no-data would produce an empty dump")
 	}
+	if flags.dialect != "" {
+		if _, err := db.NormalizeDialect(flags.dialect); err != nil {
+			printError("%v", err)
+		}
+	}
+	if flags.dir == "" && flags.jobs > 1 {
+		printError("--jobs exports into a directory: add --dir <path>")
+	}
+	if flags.dir == "" && flags.resume {
+		printError("--resume finishes a directory export: add --dir <path>")
+	}
+	if flags.dir != "" && flags.outputFile != "" {
+		printError("--dir and --output cannot be used together")
+	}
 
 	// Determine output destination.
 	// SQL always goes to out; progress/status messages always go to stderr
 	// so they never pollute a redirect (pam export > dump.sql).
-	var out *os.File
-	if flags.outputFile != "" {
+	// A directory export writes its own files.
+	var out io.WriteCloser
+	switch {
+	case flags.dir != "":
+	case flags.outputFile != "":
 		f, err := os.Create(flags.outputFile)
 		if err != nil {
 			printError(
@@ -116,9 +218,9 @@ func (a *App) handleExport() {
 			)
 		}
 		defer f.Close()
-		out = f
-	} else {
-		out = os.Stdout
+		out = compressOutput(f, flags.compression)
+	default:
+		out = compressOutput(os.Stdout, flags.compression)
 	}
 
 	// Progress always on stderr
@@ -130,7 +232,7 @@ func (a *App) handleExport() {
 
 	// Show spinner only when output goes to a file (stdout might be piped).
 	var done chan struct{}
-	if flags.outputFile != "" {
+	if flags.outputFile != "" || flags.dir != "" {
 		done = make(chan struct{})
 		go spinner.CircleWaitWithTimer(done)
 	}
@@ -161,6 +263,14 @@ func (a *App) handleExport() {
 		NoData:        flags.noData,
 		Output:        out,
 		Progress:      progress,
+		TargetDialect: flags.dialect,
+		Dir:           flags.dir,
+		Jobs:          flags.jobs,
+		Compression:   flags.compression,
+		Resume:        flags.resume,
+	}
+	if !flags.noMask {
+		opts.Mask = mask.Active
 	}
 
 	start := time.Now()
@@ -171,6 +281,14 @@ func (a *App) handleExport() {
 		}
 		printError("Export failed: %v", err)
 	}
+	if out != nil {
+		if err := out.Close(); err != nil {
+			if done != nil {
+				done <- struct{}{}
+			}
+			printError("Export failed: %v", err)
+		}
+	}
 
 	elapsed := time.Since(start)
 
@@ -179,12 +297,12 @@ func (a *App) handleExport() {
 	}
 
 	// Print a summary to stderr (so it doesn't pollute a redirected file).
-	if flags.outputFile != "" {
+	if dest := flags.outputFile + flags.dir; dest != "" {
 		fmt.Fprintf(
 			os.Stderr,
 			"%s Exported to %s in %s\n",
 			styles.Success.Render("✓"),
-			styles.Title.Render(flags.outputFile),
+			styles.Title.Render(dest),
 			elapsed.Round(time.Millisecond),
 		)
 	}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/federate"
+	"github.com/caiolandgraf/pam/internal/run"
+	"github.com/caiolandgraf/pam/internal/spinner"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+type federateFlags struct {
+	format      string
+	materialize bool
+}
+
+func parseFederateFlags() (federateFlags, []string) {
+	flags := federateFlags{}
+	remainingArgs := []string{}
+	args := os.Args[2:]
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-f":
+			if i+1 < len(args) {
+				flags.format = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--format="):
+			flags.format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--materialize" || arg == "-m":
+			flags.materialize = true
+		case !strings.HasPrefix(arg, "-"):
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+
+	return flags, remainingArgs
+}
+
+func (a *App) handleFederate() {
+	flags, args := parseFederateFlags()
+	if len(args) == 0 {
+		fmt.Println(`Usage: pam federate "SELECT ... FROM <conn>.<table> JOIN <conn>.<table> ..." [--format fmt] [--materialize]`)
+		os.Exit(1)
+	}
+	if len(a.config.Connections) == 0 {
+		printError("No connections configured. Use 'pam init' first")
+	}
+
+	conns := make(map[string]db.DatabaseConnection, len(a.config.Connections))
+	for name, yc := range a.config.Connections {
+		conns[name] = config.FromConnectionYaml(yc)
+	}
+
+	session, err := federate.NewSession(flags.materialize)
+	if err != nil {
+		printError("Could not start DuckDB session: %v", err)
+	}
+	defer session.Close()
+
+	// attach reports on stderr so machine formats stay clean
+	attach := func(sql string) error {
+		done := make(chan struct{})
+		if flags.format == "" {
+			go spinner.CircleWaitWithTimer(done)
+		}
+		attached, err := session.Attach(sql, conns)
+		if flags.format == "" {
+			done <- struct{}{}
+			fmt.Print("\r\033[2K")
+		}
+		if err != nil {
+			return err
+		}
+		for _, att := range attached {
+			fmt.Fprintln(os.Stderr, styles.Faint.Render(describeAttachment(att)))
+		}
+		return nil
+	}
+
+	sql := strings.Join(args, " ")
+	if err := attach(sql); err != nil {
+		printError("%v", err)
+	}
+
+	execParams := run.ExecutionParams{
+		Query:      db.Query{SQL: sql},
+		Connection: session.Connection(),
+		Config:     a.config,
+	}
+	execParams.OnRerun = func(editedSQL string) error {
+		if err := attach(editedSQL); err != nil {
+			return err
+		}
+		rerun := execParams
+		rerun.Query = db.Query{SQL: editedSQL}
+		return run.ExecuteWithOpenConn(rerun)
+	}
+
+	if flags.format != "" {
+		err = run.ExecuteExportWithOpenConn(execParams, flags.format)
+	} else {
+		err = run.ExecuteWithOpenConn(execParams)
+	}
+	if err != nil {
+		printError("%v", err)
+	}
+}
+
+func describeAttachment(att *federate.Attachment) string {
+	if att.Mode == federate.ModeScanner {
+		return fmt.Sprintf("• %s (%s) attached live", att.Alias, att.DbType)
+	}
+
+	tables := make([]string, len(att.Tables))
+	for i, t := range att.Tables {
+		tables[i] = t.String()
+	}
+	msg := fmt.Sprintf("• %s (%s) copied %s: %d rows", att.Alias, att.DbType, strings.Join(tables, ", "), att.Rows)
+	if att.Fallback != "" {
+		msg += fmt.Sprintf(" [scanner unavailable: %s]", firstLine(att.Fallback))
+	}
+	return msg
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
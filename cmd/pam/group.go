@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/params"
+	"github.com/caiolandgraf/pam/internal/run"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+// handleGroup lists groups or tags connections into them:
+//
+//	pam group
+//	pam group add <group> <connection>...
+//	pam group remove <group> <connection>...
+func (a *App) handleGroup() {
+	args := os.Args[2:]
+	if len(args) == 0 || args[0] == "list" {
+		a.printGroups()
+		return
+	}
+
+	if len(args) < 3 || (args[0] != "add" && args[0] != "remove" && args[0] != "rm") {
+		fmt.Println("Usage: pam group [list] | pam group add|remove <group> <connection>...")
+		os.Exit(1)
+	}
+
+	group := args[1]
+	for _, name := range args[2:] {
+		conn, ok := a.config.Connections[name]
+		if !ok {
+			printError("Connection '%s' does not exist", name)
+		}
+		if args[0] == "add" {
+			if !containsString(conn.Groups, group) {
+				conn.Groups = append(conn.Groups, group)
+			}
+			continue
+		}
+		kept := conn.Groups[:0]
+		for _, g := range conn.Groups {
+			if g != group {
+				kept = append(kept, g)
+			}
+		}
+		conn.Groups = kept
+	}
+
+	if err := a.config.Save(); err != nil {
+		printError("Could not save config: %v", err)
+	}
+	members := a.config.GroupMembers(group)
+	fmt.Println(styles.Success.Render(fmt.Sprintf("✓ %s: %s", group, strings.Join(members, ", "))))
+}
+
+func (a *App) printGroups() {
+	groups := a.config.Groups()
+	if len(groups) == 0 {
+		fmt.Println(styles.Faint.Render("No groups. Tag connections with 'pam group add <group> <connection>...'"))
+		return
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s %s\n", styles.Title.Render(name), styles.Faint.Render(strings.Join(groups[name], ", ")))
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// runGroup runs a query against every connection of flags.Group. A saved
+// query is looked up on the current connection first, then on the members.
+func (a *App) runGroup(args []string, flags run.Flags) error {
+	members := a.config.GroupMembers(flags.Group)
+	if len(members) == 0 {
+		return fmt.Errorf("no connections in group '%s'. Use 'pam group add %s <connection>...'", flags.Group, flags.Group)
+	}
+
+	conns := make([]db.DatabaseConnection, len(members))
+	for i, name := range members {
+		conns[i] = config.FromConnectionYaml(a.config.Connections[name])
+	}
+
+	candidates := conns
+	if current, ok := a.config.Connections[a.config.CurrentConnection]; ok {
+		candidates = append([]db.DatabaseConnection{config.FromConnectionYaml(current)}, conns...)
+	}
+	var resolved run.ResolvedQuery
+	var err error
+	for _, conn := range candidates {
+		resolved, err = run.ResolveQuery(flags, a.config, conn.GetName(), conn)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if run.ShouldCreateNewQuery(resolved) {
+		return fmt.Errorf("no query specified. Use a query name, inline SQL, or --last")
+	}
+	if flags.EditMode && !flags.LastQuery {
+		resolved.Query = a.editQueryOrExit(resolved.Query)
+	}
+	if len(run.SplitScript(resolved.Query.SQL)) > 1 {
+		return fmt.Errorf("group runs take a single statement")
+	}
+
+	paramFlags := parseParameterFlagsFrom(args)
+	positionalArgs := params.MapPositionalArgs(
+		resolved.Query.SQL,
+		parsePositionalArgsFrom(args, flags.Selector),
+	)
+	noInteractive := flags.ExportFormat != ""
+
+	// Values are resolved once; placeholders are substituted per dialect
+	buildTargets := func(sql string) ([]run.FanOutTarget, error) {
+		if err := confirmGroupWrite(flags.Group, members, sql, flags.Yes); err != nil {
+			return nil, err
+		}
+		values, err := a.resolveParamValues(sql, conns[0], paramFlags, positionalArgs, noInteractive)
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]run.FanOutTarget, len(conns))
+		for i, conn := range conns {
+			targets[i] = run.FanOutTarget{Connection: conn, SQL: sql}
+			if values == nil {
+				continue
+			}
+			finalSQL, finalArgs, _, err := substituteParams(sql, values, conn)
+			if err != nil {
+				return nil, err
+			}
+			targets[i].SQL, targets[i].Args = finalSQL, finalArgs
+		}
+		return targets, nil
+	}
+
+	targets, err := buildTargets(resolved.Query.SQL)
+	if err != nil {
+		return err
+	}
+
+	return run.ExecuteFanOut(run.FanOutParams{
+		Group:       flags.Group,
+		Query:       resolved.Query,
+		Targets:     targets,
+		Parallelism: flags.Parallel,
+		Config:      a.config,
+		Format:      flags.ExportFormat,
+		AllowWrites: true, // buildTargets has confirmed
+		Rerun:       buildTargets,
+	})
+}
+
+// confirmGroupWrite asks before a statement that writes runs on every
+// member of a group. --yes skips the question; without a terminal to ask
+// on, the write is refused.
+func confirmGroupWrite(group string, members []string, sql string, yes bool) error {
+	if yes || run.IsReadOnlyQuery(sql) {
+		return nil
+	}
+	if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf(
+			"this statement writes to every connection of group '%s'; pass --yes to run it",
+			group,
+		)
+	}
+
+	fmt.Printf(
+		"%s\n%s",
+		styles.Error.Render(fmt.Sprintf(
+			"This statement writes and will run on %d connection(s) of group '%s': %s",
+			len(members), group, strings.Join(members, ", "),
+		)),
+		styles.Error.Render("Continue? [y/N]: "),
+	)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if err != nil || (response != "y" && response != "yes") {
+		return fmt.Errorf("cancelled")
+	}
+	return nil
+}
@@ -142,6 +142,10 @@ func (a *App) PrintGeneralHelp() {
 	)
 	fmt.Println(cmdEntry("edit", "queries", "Edit saved queries in $EDITOR"))
 	fmt.Println(cmdEntry("history", "", "Show query execution history"))
+	fmt.Println(
+		cmdEntry("export", "[table]", "Dump tables, indexes and views as SQL"),
+	)
+	fmt.Println(cmdEntry("import", "<file>", "Run a SQL dump against the connection"))
 	fmt.Println()
 
 	// ── DATABASE ──────────────────────────────────────────────────
@@ -154,6 +158,20 @@ func (a *App) PrintGeneralHelp() {
 			"List tables or query one directly (alias: t, explore)",
 		),
 	)
+	fmt.Println(
+		cmdEntry(
+			"table-view",
+			"<table>",
+			"Manage columns, indexes, constraints and triggers (alias: tv)",
+		),
+	)
+	fmt.Println(
+		cmdEntry(
+			"table",
+			"<action> <table>",
+			"Create, rename, truncate, drop or clone a table",
+		),
+	)
 	fmt.Println(
 		"  remove      " + styles.Faint.Render(
 			"Remove a saved query by name/id, or remove a connection entirely (alias: delete)",
@@ -208,6 +226,51 @@ func (a *App) PrintGeneralHelp() {
 			"Show relationships between tables",
 		),
 	)
+	fmt.Println(
+		"  plan        " + styles.Faint.Render(
+			"Visualize the execution plan of a query",
+		),
+	)
+	fmt.Println(
+		"  erd         " + styles.Faint.Render(
+			"Export an ER diagram (mermaid, dot, json)",
+		),
+	)
+	fmt.Println(
+		"  profile     " + styles.Faint.Render(
+			"Column statistics for a table (nulls, distinct, top values)",
+		),
+	)
+	fmt.Println(
+		"  seed        " + styles.Faint.Render(
+			"Fill a table with realistic fake rows",
+		),
+	)
+	fmt.Println(
+		"  migrate     " + styles.Faint.Render(
+			"Create, apply and revert schema migrations",
+		),
+	)
+	fmt.Println(
+		"  check       " + styles.Faint.Render(
+			"Run queries tagged 'check' as data-quality assertions",
+		),
+	)
+	fmt.Println(
+		"  group       " + styles.Faint.Render(
+			"Tag connections into groups for 'pam run --group'",
+		),
+	)
+	fmt.Println(
+		"  open        " + styles.Faint.Render(
+			"Open a CSV, Parquet or JSON file in the table viewer",
+		),
+	)
+	fmt.Println(
+		"  federate    " + styles.Faint.Render(
+			"Join tables across connections in a local DuckDB session",
+		),
+	)
 	fmt.Println(
 		"  help        " + styles.Faint.Render(
 			"Show help for pam or a specific command",
@@ -286,6 +349,9 @@ func (a *App) PrintCommandHelp() {
 			"  --conn,   -c          Connection string (alias: --conn-string)",
 		)
 		fmt.Println("  --schema, -s          Default schema (optional)")
+		fmt.Println(
+			"  --protected           Require typing the table name for destructive table operations",
+		)
 		fmt.Println()
 		section("Interactive TUI fields")
 		fmt.Println(
@@ -368,7 +434,14 @@ func (a *App) PrintCommandHelp() {
 		)
 		fmt.Println()
 		section("Usage")
-		fmt.Println("  pam add <run-name> [query]")
+		fmt.Println("  pam add [folder/]<run-name> [query] [--project]")
+		fmt.Println()
+		section("Flags")
+		fmt.Println("  --description, -d <text>    Describe the query")
+		fmt.Println("  --tag, -t <tag>             Tag the query (repeat or comma-separate)")
+		fmt.Println("  --folder <path>             File it under a folder, e.g. billing/monthly")
+		fmt.Println("  --assert <expr>             What 'pam check' expects, e.g. \"rows = 0\"")
+		fmt.Println("  --project                   Save to the project's .pam/ directory")
 		fmt.Println()
 		section("Description")
 		fmt.Println(
@@ -377,9 +450,38 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println("    can write the query interactively.")
 		fmt.Println("  - Each query gets a numeric ID as well as a name.")
 		fmt.Println("  - Requires an active connection (use 'pam switch').")
+		fmt.Println(
+			"  - --project writes the query to .pam/<name>.sql in the nearest",
+		)
+		fmt.Println(
+			"    project (or the current directory), to be shared through git.",
+		)
+		fmt.Println()
+		section("Project queries")
+		fmt.Println(
+			"  A .pam/ directory found by walking up from the working directory",
+		)
+		fmt.Println(
+			"  holds one .sql file per query, merged with the global queries.",
+		)
+		fmt.Println("  A leading comment header describes each one:")
+		fmt.Println("    -- name: active_users")
+		fmt.Println("    -- connection: prod          (optional, default: all)")
+		fmt.Println("    -- table: users")
+		fmt.Println("    -- params: status=active, limit=10")
+		fmt.Println("    -- description: Users seen this month")
+		fmt.Println("    -- tags: users, reporting")
+		fmt.Println("    -- folder: reports          (optional, default: subdirectory)")
+		fmt.Println("    -- assert: rows = 0          (optional, see 'pam help check')")
 		fmt.Println()
 		section("Examples")
 		fmt.Println("  pam add list_users \"SELECT * FROM users\"")
+		fmt.Println(
+			"  pam add active_users \"SELECT * FROM users WHERE status = :status\" --project",
+		)
+		fmt.Println(
+			"  pam add billing/invoices \"SELECT * FROM invoices\" -t finance -d \"All invoices\"",
+		)
 		fmt.Println("  pam add update_status    # opens editor to write SQL")
 
 	case "remove", "delete":
@@ -423,6 +525,18 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"  pam run <query-name-or-id> [--edit | -e] [--last | -l] [--format | -f <fmt>]",
 		)
+		fmt.Println(
+			"  pam run <query> --chart line|bar|sparkline [--x <col>] [--y <col,col>] [--width N] [--height N]",
+		)
+		fmt.Println(
+			"  pam run <query> --format svg [--chart <type>] [--output <file.svg>]",
+		)
+		fmt.Println(
+			"  pam run \"SELECT * FROM 'events.parquet'\" --file-mode",
+		)
+		fmt.Println(
+			"  pam run <query> --group <group> [--parallel N] [--yes]",
+		)
 		fmt.Println(
 			"  pam run                      " + styles.Faint.Render(
 				"# Opens the editor to build sql query",
@@ -454,6 +568,84 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"    the table UI. Formats: csv, json, tsv, html, sql, markdown",
 		)
+		fmt.Println(
+			"  - Scripts with several ';'-separated statements run in order on one",
+		)
+		fmt.Println(
+			"    connection. Each result set opens in its own tab; with '--format'",
+		)
+		fmt.Println(
+			"    they are printed one after another, with timing on stderr.",
+		)
+		fmt.Println(
+			"  - With '--chart', plots the result in the terminal. '--x' defaults to the",
+		)
+		fmt.Println(
+			"    first date/time or text column and '--y' to every numeric column;",
+		)
+		fmt.Println(
+			"    date/time x values are placed on a time axis.",
+		)
+		fmt.Println(
+			"  - '--format svg' writes the same chart to '--output' (default",
+		)
+		fmt.Println(
+			"    <query-name>.svg). '--x', '--y', '--output', '--width' and '--height'",
+		)
+		fmt.Println(
+			"    are only chart flags when a chart is requested.",
+		)
+		fmt.Println(
+			"  - With '--file-mode', runs on an in-memory DuckDB instead of the current",
+		)
+		fmt.Println(
+			"    connection, so quoted .csv, .tsv, .parquet and .json paths can be",
+		)
+		fmt.Println(
+			"    queried as tables. Queries are saved to the 'scratch' connection.",
+		)
+		fmt.Println(
+			"  - With '--group', runs the query on every connection of the group, at",
+		)
+		fmt.Println(
+			"    most '--parallel' (default 4) at a time, and merges the rows behind a",
+		)
+		fmt.Println(
+			"    '_connection' column. Failing connections are reported on stderr.",
+		)
+		fmt.Println(
+			"    A statement that writes asks for confirmation first ('--yes' skips it)",
+		)
+		fmt.Println(
+			"    and lists the connections it ran on.",
+		)
+		fmt.Println()
+		section("Parameters")
+		fmt.Println(
+			"  :name, :name|default, or typed as :name:type|default. Values come from",
+		)
+		fmt.Println(
+			"  '--name value', positional args or a prompt, and typed values are",
+		)
+		fmt.Println("  checked before the query runs:")
+		fmt.Println(
+			"    :n:int  :x:float  :on:bool  :since:date  :at:timestamp  :s:text",
+		)
+		fmt.Println(
+			"    :status:enum(open,closed)     " + styles.Faint.Render("one of the listed values"),
+		)
+		fmt.Println(
+			"    :ids:int[]                    " + styles.Faint.Render("comma-separated; expands to IN (...)"),
+		)
+		fmt.Println(
+			"  A comment '-- @param <name> lookup: <select>' gives the prompt a",
+		)
+		fmt.Println(
+			"  searchable picker of the lookup's first column (the rest label it),",
+		)
+		fmt.Println(
+			"  and completes '--<name> <TAB>'. Results are cached for 5 minutes.",
+		)
 		fmt.Println()
 		section("Interactive table view")
 		fmt.Println(
@@ -542,6 +734,66 @@ func (a *App) PrintCommandHelp() {
 				"Navigate to next/previous column match",
 			),
 		)
+		fmt.Println(
+			"  Tab / Shift+Tab       " + styles.Faint.Render(
+				"Switch result set (multi-statement scripts)",
+			),
+		)
+		fmt.Println(
+			"  o                     " + styles.Faint.Render(
+				"Open the row referenced by the foreign key under the cursor",
+			),
+		)
+		fmt.Println(
+			"  O                     " + styles.Faint.Render(
+				"List rows in other tables that reference the current row",
+			),
+		)
+		fmt.Println(
+			"  b / Backspace         " + styles.Faint.Render(
+				"Go back to the previous view after o / O",
+			),
+		)
+		fmt.Println(
+			"  i                     " + styles.Faint.Render(
+				"Column statistics: nulls, distinct, min/max, top values, histogram",
+			),
+		)
+		fmt.Println(
+			"  C                     " + styles.Faint.Render(
+				"Chart the results (line, bar, sparkline; t to switch)",
+			),
+		)
+		fmt.Println(
+			"  A                     " + styles.Faint.Render(
+				"Summarize loaded rows: group by, count/sum/avg/min/max, pivot",
+			),
+		)
+		fmt.Println(
+			"  F                     " + styles.Faint.Render(
+				"Filter loaded rows, e.g. status = 'failed' and amount > 100",
+			),
+		)
+		fmt.Println(
+			"  S                     " + styles.Faint.Render(
+				"Sort loaded rows by column (asc/desc/off, repeat for multi-column)",
+			),
+		)
+		fmt.Println(
+			"  H / c                 " + styles.Faint.Render(
+				"Hide column / open the column manager (show, reorder, pin)",
+			),
+		)
+		fmt.Println(
+			"  < / > / P             " + styles.Faint.Render(
+				"Move column left/right, pin column to the left",
+			),
+		)
+		fmt.Println(
+			"  W / R                 " + styles.Faint.Render(
+				"Save the view with the query / reset the view",
+			),
+		)
 		fmt.Println(
 			"  Esc /Ctrl+c           " + styles.Faint.Render(
 				"Quit the table view",
@@ -557,6 +809,11 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"  pam run \"SELECT * FROM users\" --format csv > users.csv",
 		)
+		fmt.Println("  pam run \"select * from users; select * from orders\"")
+		fmt.Println("  pam run daily_sales --chart line --x day --y total")
+		fmt.Println("  pam run daily_sales --format svg --output sales.svg")
+		fmt.Println("  pam run \"select status, count(*) from 'orders.csv' group by 1\" --file-mode")
+		fmt.Println("  pam run health_check --group tenants --parallel 8 -f csv")
 		fmt.Println("  pam query list_users")
 
 	case "shell", "repl":
@@ -601,7 +858,8 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(styles.Faint.Render("List connections or saved queries."))
 		fmt.Println()
 		section("Usage")
-		fmt.Println("  pam list [connections | queries] [search-term]")
+		fmt.Println("  pam list [connections | queries] [search-term | folder/]")
+		fmt.Println("  pam list [--tag <tag>] [--tree | --oneline]")
 		fmt.Println()
 		section("Description")
 		fmt.Println(
@@ -613,6 +871,14 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"                 Optionally filter by search term (searches name and SQL)",
 		)
+		fmt.Println(
+			"                 Each query shows its source: 'global' or its .pam/ file",
+		)
+		fmt.Println(
+			"  folder/        Only queries in that folder or its subfolders",
+		)
+		fmt.Println("  --tag, -t      Only queries with the tag")
+		fmt.Println("  --tree         Show queries as a folder tree")
 		fmt.Println()
 		section("Examples")
 		fmt.Println(
@@ -628,6 +894,8 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"  pam list queries --oneline    # list each query in one separate line",
 		)
+		fmt.Println("  pam list billing/             # queries in the billing folder")
+		fmt.Println("  pam list --tag finance --tree")
 		fmt.Println("  pam list connections")
 
 	case "tables":
@@ -651,6 +919,76 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println("  pam tables users        # query the users table")
 		fmt.Println("  pam tables --oneline    # list tables in oneline format")
 
+	case "table-view", "tv":
+		section("Command: table-view")
+		fmt.Println(
+			styles.Faint.Render(
+				"Inspect and change a table's structure. Each tab lists one kind of object;",
+			),
+		)
+		fmt.Println(
+			styles.Faint.Render(
+				"every change opens the generated DDL in an editor before it runs.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam table-view <table-name>")
+		fmt.Println()
+		section("Tabs")
+		fmt.Println("  1 Columns        a add, e alter, r rename, D drop")
+		fmt.Println("  2 Indexes        columns, uniqueness and method; a create, r rename, D drop")
+		fmt.Println("  3 Constraints    check and foreign keys; a add, r rename, D drop")
+		fmt.Println("  4 Triggers       timing and events; a create, r rename, D drop")
+		fmt.Println()
+		section("Keys")
+		fmt.Println("  tab / shift+tab, 1-4    switch tabs")
+		fmt.Println("  ctrl+s                  run the previewed DDL")
+		fmt.Println("  esc                     cancel without running anything")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam table-view orders")
+		fmt.Println("  pam tv customers")
+
+	case "table":
+		section("Command: table")
+		fmt.Println(
+			styles.Faint.Render(
+				"Create and manage whole tables. Every statement is shown before it runs.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam table create <table>")
+		fmt.Println("  pam table rename <table> <new-name> [--yes]")
+		fmt.Println("  pam table truncate <table> [--yes]")
+		fmt.Println("  pam table drop <table> [--yes]")
+		fmt.Println("  pam table clone <table> <new-name> [--schema-only] [--yes]")
+		fmt.Println()
+		section("Description")
+		fmt.Println(
+			"  'create' opens a form for columns, types, nullability, defaults, the",
+		)
+		fmt.Println(
+			"  primary key, unique columns and foreign keys to existing tables, then",
+		)
+		fmt.Println("  previews the CREATE TABLE in the connection's dialect.")
+		fmt.Println()
+		fmt.Println(
+			"  On connections marked 'protected: true', rename, truncate and drop",
+		)
+		fmt.Println("  require typing the table name and refuse --yes.")
+		fmt.Println()
+		section("Flags")
+		fmt.Println("  --yes, -y          Skip the confirmation prompt")
+		fmt.Println("  --schema-only      Clone the structure without copying rows")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam table create invoices")
+		fmt.Println("  pam table clone orders orders_backup")
+		fmt.Println("  pam table truncate staging_events --yes")
+		fmt.Println("  pam table rename users accounts")
+
 	case "disconnect":
 		section("Command: disconnect")
 		fmt.Println(styles.Faint.Render("Clear the current active connection."))
@@ -679,6 +1017,9 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println()
 		section("Usage")
 		fmt.Println("  pam edit [<query-name-or-id>]")
+		fmt.Println(
+			"  pam edit <query> [--description <text>] [--tag <tag>]... [--folder <path>] [--assert <expr>]",
+		)
 		fmt.Println()
 		section("Description")
 		fmt.Println(
@@ -689,12 +1030,21 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"    - Query name can be changed by editing the '-- queryname' header",
 		)
+		fmt.Println(
+			"    - Description, tags and folder are edited on the lines below it,",
+		)
+		fmt.Println("      and the assertion too on queries tagged 'check'")
+		fmt.Println(
+			"  - With --description, --tag, --folder or --assert, sets them without the editor;",
+		)
+		fmt.Println("    --tag replaces the query's tags.")
 		fmt.Println("  - Requires an active connection (use 'pam switch').")
 		fmt.Println()
 		section("Examples")
 		fmt.Println("  pam edit                    # edit all queries")
 		fmt.Println("  pam edit list_users         # edit single query")
 		fmt.Println("  pam edit 3                  # edit query by ID")
+		fmt.Println("  pam edit 3 --tag billing --folder reports/monthly")
 	case "config":
 		section("Command: config")
 		fmt.Println(
@@ -760,12 +1110,15 @@ func (a *App) PrintCommandHelp() {
 		)
 		fmt.Println()
 		section("Usage")
-		fmt.Println("  pam explain <table> [--depth | -d N]")
+		fmt.Println("  pam explain <table> [--depth | -d N] [--format | -f mermaid|dot|json]")
 		fmt.Println()
 		section("Flags")
 		fmt.Println(
 			"  --depth, -d N    Depth of relationships to traverse (default: 1)",
 		)
+		fmt.Println(
+			"  --format, -f     Print an ER diagram (mermaid, dot, json) instead of the tree",
+		)
 		fmt.Println()
 		section("Relationship types")
 		fmt.Println(
@@ -779,6 +1132,270 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println("  pam explain employees")
 		fmt.Println("  pam explain employees --depth 2")
 		fmt.Println("  pam explain departments -d 3")
+		fmt.Println("  pam explain employees -d 2 --format mermaid")
+
+	case "erd":
+		section("Command: erd")
+		fmt.Println(
+			styles.Faint.Render(
+				"Export an entity-relationship diagram of the schema or a set of tables.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam erd [table...] [--format | -f mermaid|dot|json] [--output | -o <file>]")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Without tables, every table of the current connection is included.")
+		fmt.Println("  - With tables, only those tables and the relationships between them.")
+		fmt.Println("  - Columns are listed with types and PK / FK / UK markers; relationships")
+		fmt.Println("    carry their cardinality ([N:1], [1:1], [N:N]).")
+		fmt.Println("  - Default format is mermaid. Render dot with: dot -Tsvg erd.dot -o erd.svg")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam erd > schema.mmd")
+		fmt.Println("  pam erd employees departments -f dot -o erd.dot")
+		fmt.Println("  pam erd --format json")
+
+	case "profile":
+		section("Command: profile")
+		fmt.Println(
+			styles.Faint.Render(
+				"Profile every column of a table with aggregate queries on the server.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam profile <table> [--format | -f json|markdown] [--top | -n N] [--buckets N] [--columns | -c a,b]")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - For each column: null count and percentage, distinct count, min/max,")
+		fmt.Println("    average for numeric columns, the most frequent values and a histogram.")
+		fmt.Println("  - Statistics cover the whole table, not a sample of loaded rows.")
+		fmt.Println("  - Columns whose type cannot be compared or counted distinctly (JSON, LOBs)")
+		fmt.Println("    fall back to the counts the database can compute.")
+		fmt.Println("  - In the results table, press i for the same panel over the loaded rows.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam profile orders")
+		fmt.Println("  pam profile orders -c status,amount --top 10")
+		fmt.Println("  pam profile orders -f markdown > orders-profile.md")
+
+	case "seed":
+		section("Command: seed")
+		fmt.Println(
+			styles.Faint.Render(
+				"Fill a table with fake rows that fit its columns, keys and constraints.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam seed <table> [--rows | -n N] [--seed N] [--output | -o file] [--format | -f sql|csv] [--batch-size N]")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Values match each column's type, length and nullability; names such as")
+		fmt.Println("    email, name, phone or city get values that look the part.")
+		fmt.Println("  - Foreign keys draw from existing parent rows. Empty parent tables are")
+		fmt.Println("    seeded first with a tenth as many rows.")
+		fmt.Println("  - Primary keys and unique constraints are respected; columns the database")
+		fmt.Println("    numbers itself are left to it.")
+		fmt.Println("  - The same --seed against the same data gives the same rows. Without it a")
+		fmt.Println("    seed is picked and printed.")
+		fmt.Println("  - Rows are inserted in transactions of --batch-size rows (default 500),")
+		fmt.Println("    or written to --output as SQL or CSV (the format follows the extension).")
+		fmt.Println("    CSV holds the target table only.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam seed customers --rows 10000")
+		fmt.Println("  pam seed orders -n 500 --seed 42")
+		fmt.Println("  pam seed orders -n 500 --seed 42 -o fixtures/orders.sql")
+		fmt.Println("  pam seed customers -n 100 -o customers.csv")
+
+	case "migrate":
+		section("Command: migrate")
+		fmt.Println(
+			styles.Faint.Render(
+				"Apply schema migrations kept as .sql files with up and down sections.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam migrate new <name>")
+		fmt.Println("  pam migrate up [--steps | -n N] [--dry-run]")
+		fmt.Println("  pam migrate down [--steps | -n N] [--dry-run]")
+		fmt.Println("  pam migrate status")
+		fmt.Println("  All take --dir | -d <path>")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Migrations live in migrations/ next to the project's .pam directory, or")
+		fmt.Println("    in the working directory, as <timestamp>_<name>.sql files.")
+		fmt.Println("  - A file has a '-- migrate:up' section and an optional '-- migrate:down'.")
+		fmt.Println("    Add 'transaction:false' to a marker for statements that can't run in a")
+		fmt.Println("    transaction, such as CREATE INDEX CONCURRENTLY.")
+		fmt.Println("  - Applied versions are recorded in the pam_migrations table with a")
+		fmt.Println("    checksum; an edited migration stops up and down until it is restored.")
+		fmt.Println("  - Each migration runs in a transaction on PostgreSQL, SQLite, SQL Server,")
+		fmt.Println("    DuckDB and Firebird. MySQL, Oracle, Snowflake and ClickHouse commit DDL")
+		fmt.Println("    as it runs, so a failed migration there may be half applied.")
+		fmt.Println("  - up applies every pending migration, down reverts the latest one;")
+		fmt.Println("    --steps changes how many. --dry-run prints the SQL instead.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam migrate new create_users")
+		fmt.Println("  pam migrate up --dry-run")
+		fmt.Println("  pam migrate up")
+		fmt.Println("  pam migrate down --steps 2")
+
+	case "check":
+		section("Command: check")
+		fmt.Println(
+			styles.Faint.Render(
+				"Run saved queries tagged 'check' and fail when their assertion is violated.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam check [query...] [--junit <file>] [--json <file>] [--update-snapshots]")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Runs every query of the current connection tagged 'check', or the")
+		fmt.Println("    named ones, and exits with status 1 when any fails or errors.")
+		fmt.Println("  - A query's assertion is set with --assert on 'pam add' or 'pam edit'")
+		fmt.Println("    (or '-- assert:' in a project file). Without one it must return no rows.")
+		fmt.Println("  - Assertions, joined with ';' when there are several:")
+		fmt.Println("      rows = 0, rows <= 10        row count with = != < <= > >=")
+		fmt.Println("      rows between 1 and 500      row count range")
+		fmt.Println("      amount >= 0, status = 'ok'  must hold on every row; NULL fails")
+		fmt.Println("      snapshot                    result matches the saved snapshot")
+		fmt.Println("  - Snapshots are JSON in .pam/snapshots/ for project queries, otherwise")
+		fmt.Println("    in the config directory. --update-snapshots saves the current results.")
+		fmt.Println("  - --junit and --json write reports with each check's duration and up to")
+		fmt.Println("    50 offending rows per failure; '-' writes to stdout.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam edit orphan_orders --tag check --assert \"rows = 0\"")
+		fmt.Println("  pam edit daily_totals --tag check --assert \"total >= 0; rows between 1 and 31\"")
+		fmt.Println("  pam check")
+		fmt.Println("  pam check --junit report.xml --json report.json")
+		fmt.Println("  pam check country_codes --update-snapshots")
+
+	case "group":
+		section("Command: group")
+		fmt.Println(
+			styles.Faint.Render(
+				"Tag connections into named groups to run one query against all of them.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam group [list]")
+		fmt.Println("  pam group add <group> <connection>...")
+		fmt.Println("  pam group remove <group> <connection>...")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Groups are stored in each connection's 'groups' list in the config.")
+		fmt.Println("  - 'pam run <query> --group <group>' runs the query on every member.")
+		fmt.Println("  - Writes (UPDATE, DELETE, DDL) ask for confirmation; '--yes' skips it.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam group add tenants tenant_a tenant_b tenant_c")
+		fmt.Println("  pam run \"select count(*) from users\" --group tenants")
+
+	case "open":
+		section("Command: open")
+		fmt.Println(
+			styles.Faint.Render(
+				"Open a local file in the table viewer without creating a connection.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam open <file> [--format | -f fmt]")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Reads .csv, .tsv, .parquet, .json, .jsonl and .ndjson files (also")
+		fmt.Println("    gzipped) in an in-memory DuckDB, which infers the column types.")
+		fmt.Println("  - Without CGO, pam uses an in-memory SQLite instead: CSV and TSV files")
+		fmt.Println("    are loaded into a table with INTEGER, REAL or TEXT columns.")
+		fmt.Println("  - Queries saved from the table go to the 'scratch' connection; run them")
+		fmt.Println("    again with 'pam run <name> --file-mode'.")
+		fmt.Println("  - With '--format', prints the file like 'pam run --format'.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam open events.parquet")
+		fmt.Println("  pam open export.csv -f json > export.json")
+
+	case "federate":
+		section("Command: federate")
+		fmt.Println(
+			styles.Faint.Render(
+				"Run one query across several connections in an in-process DuckDB session.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println(`  pam federate "<sql>" [--format | -f fmt] [--materialize | -m]`)
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Qualify tables with a connection name: <conn>.<table> or <conn>.<schema>.<table>.")
+		fmt.Println("  - Postgres, MySQL and SQLite are attached live through DuckDB's scanner")
+		fmt.Println("    extensions when they can be loaded; DuckDB files are attached directly.")
+		fmt.Println("  - Other engines, or any engine with --materialize, are copied into")
+		fmt.Println("    in-memory tables first using the connection's own driver.")
+		fmt.Println("  - The query runs locally in DuckDB's dialect; results open in the table")
+		fmt.Println("    viewer, or print with --format like pam run.")
+		fmt.Println()
+		section("Examples")
+		fmt.Println(`  pam federate "SELECT c.name, sum(o.total) FROM pg.orders o JOIN crm.customers c ON c.id = o.customer_id GROUP BY 1"`)
+		fmt.Println(`  pam federate "SELECT * FROM legacy.dbo.invoices i JOIN pg.orders o USING (id)" -f csv`)
+
+	case "plan":
+		section("Command: plan")
+		fmt.Println(
+			styles.Faint.Render(
+				"Run the dialect's EXPLAIN for a query and show the plan as a tree.",
+			),
+		)
+		fmt.Println()
+		section("Usage")
+		fmt.Println("  pam plan <query-name|id|sql> [--analyze | --no-analyze] [--format text|json]")
+		fmt.Println()
+		section("Description")
+		fmt.Println("  - Postgres: EXPLAIN (FORMAT JSON, ANALYZE)   MySQL: EXPLAIN FORMAT=JSON")
+		fmt.Println("  - SQLite: EXPLAIN QUERY PLAN   DuckDB: EXPLAIN (ANALYZE, FORMAT JSON)")
+		fmt.Println("  - SQL Server: estimated showplan XML (SET SHOWPLAN_XML ON)")
+		fmt.Println("  - Each node shows cost, rows and time where the database reports them.")
+		fmt.Println("  - ▲ marks the most expensive nodes; ⚠ marks sequential scans on large tables.")
+		fmt.Println()
+		section("Flags")
+		fmt.Println(
+			"  --analyze, -a    " + styles.Faint.Render(
+				"Execute the statement to collect actual rows and timings (default for read-only SELECT)",
+			),
+		)
+		fmt.Println(
+			"  --no-analyze     " + styles.Faint.Render(
+				"Only show the estimated plan",
+			),
+		)
+		fmt.Println(
+			"  --format, -f     " + styles.Faint.Render(
+				"Print the plan as text or json instead of opening the viewer",
+			),
+		)
+		fmt.Println()
+		section("Plan viewer keys")
+		fmt.Println("  j / k            " + styles.Faint.Render("Move between nodes"))
+		fmt.Println("  Enter / Space    " + styles.Faint.Render("Collapse or expand the current node"))
+		fmt.Println("  h / l            " + styles.Faint.Render("Collapse / expand"))
+		fmt.Println("  n                " + styles.Faint.Render("Jump to the next expensive node or large seq scan"))
+		fmt.Println("  d                " + styles.Faint.Render("Toggle the node detail pane"))
+		fmt.Println("  q / Esc          " + styles.Faint.Render("Quit"))
+		fmt.Println()
+		section("Examples")
+		fmt.Println("  pam plan list_users")
+		fmt.Println("  pam plan \"select * from orders where status = 'open'\"")
+		fmt.Println("  pam plan monthly_report --no-analyze -f json")
 
 	case "info":
 		section("Command: info")
@@ -812,8 +1429,9 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println()
 		section("Usage")
 		fmt.Println("  pam history")
-		fmt.Println()
-		section("Description")
+
+	case "export":
+		section("Command: export")
 		fmt.Println(
 			styles.Faint.Render(
 				"Export one or all tables from the active connection as a SQL dump.",
@@ -826,6 +1444,38 @@ func (a *App) PrintCommandHelp() {
 			"  pam export <table>           # shorthand for --table=<table>",
 		)
 		fmt.Println()
+		section("Description")
+		fmt.Println(
+			"  Tables are written in the database's own DDL (SHOW CREATE TABLE, sqlite_master,",
+		)
+		fmt.Println(
+			"  the Postgres catalog, DBMS_METADATA), parents before the tables referencing",
+		)
+		fmt.Println(
+			"  them. Indexes and triggers follow the data, and a full export ends with the views.",
+		)
+		fmt.Println(
+			"  With --target-dialect the tables are rebuilt from their columns for the target:",
+		)
+		fmt.Println(
+			"  types, identifier quoting, booleans, auto-increment and string escapes are",
+		)
+		fmt.Println(
+			"  translated. Views are left out, since their queries are in the source's SQL.",
+		)
+		fmt.Println(
+			"  With --dir the dump is a directory: a file per table and a manifest.json listing",
+		)
+		fmt.Println(
+			"  them in load order. --jobs exports several tables at once, reading from one",
+		)
+		fmt.Println(
+			"  snapshot on PostgreSQL and MySQL, and --resume finishes an interrupted export.",
+		)
+		fmt.Println(
+			"  Columns matched by the config's masking rules are exported masked.",
+		)
+		fmt.Println()
 		section("Flags")
 		fmt.Println("  --table,  -t <table>    Export only the specified table")
 		fmt.Println(
@@ -839,6 +1489,25 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println(
 			"  --data-only             Data only — skip CREATE TABLE (alias: --no-create)",
 		)
+		fmt.Println(
+			"  --target-dialect <db>   Write the dump for another database: postgres, mysql,",
+		)
+		fmt.Println(
+			"                          sqlite, sqlserver, oracle, duckdb, clickhouse, firebird, snowflake",
+		)
+		fmt.Println(
+			"  --dir <path>            Write a directory: one file per table plus a manifest",
+		)
+		fmt.Println(
+			"  --jobs,   -j <n>        Export n tables at once (requires --dir)",
+		)
+		fmt.Println("  --gzip, --zstd          Compress the dump or each file in --dir")
+		fmt.Println(
+			"  --resume                Skip the tables --dir's manifest lists as complete",
+		)
+		fmt.Println(
+			"  --no-mask               Export real values, ignoring the config's masking rules",
+		)
 		fmt.Println()
 		section("Examples")
 		fmt.Println("  pam export")
@@ -847,6 +1516,10 @@ func (a *App) PrintCommandHelp() {
 		fmt.Println("  pam export --drop --output=full.sql")
 		fmt.Println("  pam export --no-data --output=schema.sql")
 		fmt.Println("  pam export --data-only > inserts.sql")
+		fmt.Println("  pam export --target-dialect sqlite --output=local.sql")
+		fmt.Println("  pam export --zstd --output=dump.sql.zst")
+		fmt.Println("  pam export --dir=dump --jobs=8 --gzip")
+		fmt.Println("  pam export --dir=dump --jobs=8 --gzip --resume")
 
 	case "import":
 		section("Command: import")
@@ -862,20 +1535,55 @@ func (a *App) PrintCommandHelp() {
 			"  pam import <file>                  # shorthand for --file=<file>",
 		)
 		fmt.Println("  cat dump.sql | pam import          # read from stdin")
+		fmt.Println(
+			"  pam import <dir>                   # a directory from pam export --dir",
+		)
+		fmt.Println()
+		section("Description")
+		fmt.Println(
+			"  Gzip and zstd dumps are decompressed. A directory is loaded in its manifest's",
+		)
+		fmt.Println(
+			"  order: drops, each table's rows parents first, then indexes, triggers and views.",
+		)
+		fmt.Println(
+			"  Statements are read as they run, so dumps larger than memory import too; a",
+		)
+		fmt.Println(
+			"  progress bar shows throughput and the time left when stderr is a terminal.",
+		)
 		fmt.Println()
 		section("Flags")
-		fmt.Println("  --file,  -f <file>       SQL file to import")
+		fmt.Println("  --file,  -f <file>       SQL file or dump directory to import")
 		fmt.Println(
 			"  --continue-on-error      Keep going after failed statements (alias: --continue)",
 		)
 		fmt.Println(
 			"  --dry-run                Parse and list statements without executing",
 		)
+		fmt.Println(
+			"  --source-dialect <db>    Translate a dump written for another database, e.g. mysql",
+		)
+		fmt.Println(
+			"  --single-transaction     Run the import in one transaction, rolled back on failure",
+		)
+		fmt.Println(
+			"  --batch-size <n>         Commit every n statements; a failure rolls back its batch",
+		)
+		fmt.Println(
+			"  --error-report <file>    Write the failed statements with their lines as JSON",
+		)
 		fmt.Println()
 		section("Examples")
 		fmt.Println("  pam import dump.sql")
 		fmt.Println("  pam import dump.sql --continue-on-error")
 		fmt.Println("  pam import dump.sql --dry-run")
+		fmt.Println("  pam import mysqldump.sql --source-dialect mysql")
+		fmt.Println("  pam import dump.sql.gz")
+		fmt.Println("  pam import dump/")
+		fmt.Println("  pam import dump.sql --single-transaction")
+		fmt.Println("  pam import big.sql.zst --batch-size 1000")
+		fmt.Println("  pam import dump.sql --continue --error-report errors.json")
 		fmt.Println("  cat dump.sql | pam import")
 
 	case "completion":
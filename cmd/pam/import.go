@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +17,10 @@ type importFlags struct {
 	inputFile       string
 	continueOnError bool
 	dryRun          bool
+	dialect         string
+	singleTx        bool
+	batchSize       int
+	errorReport     string
 }
 
 func parseImportFlags() importFlags {
@@ -42,6 +47,47 @@ func parseImportFlags() importFlags {
 			flags.inputFile = strings.TrimPrefix(arg, "-f=")
 			i++
 
+		// --source-dialect
+		case arg == "--source-dialect":
+			if i+1 < len(args) {
+				flags.dialect = args[i+1]
+				i += 2
+			} else {
+				printError("--source-dialect requires a value")
+			}
+		case strings.HasPrefix(arg, "--source-dialect="):
+			flags.dialect = strings.TrimPrefix(arg, "--source-dialect=")
+			i++
+
+		// --batch-size
+		case arg == "--batch-size":
+			if i+1 < len(args) {
+				flags.batchSize = parseBatchSize(args[i+1])
+				i += 2
+			} else {
+				printError("--batch-size requires a value")
+			}
+		case strings.HasPrefix(arg, "--batch-size="):
+			flags.batchSize = parseBatchSize(strings.TrimPrefix(arg, "--batch-size="))
+			i++
+
+		// --error-report
+		case arg == "--error-report":
+			if i+1 < len(args) {
+				flags.errorReport = args[i+1]
+				i += 2
+			} else {
+				printError("--error-report requires a value")
+			}
+		case strings.HasPrefix(arg, "--error-report="):
+			flags.errorReport = strings.TrimPrefix(arg, "--error-report=")
+			i++
+
+		// --single-transaction
+		case arg == "--single-transaction":
+			flags.singleTx = true
+			i++
+
 		// --continue-on-error
 		case arg == "--continue-on-error" || arg == "--continue":
 			flags.continueOnError = true
@@ -64,6 +110,14 @@ func parseImportFlags() importFlags {
 	return flags
 }
 
+func parseBatchSize(value string) int {
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 {
+		printError("--batch-size must be a positive number, got %q", value)
+	}
+	return size
+}
+
 func (a *App) handleImport() {
 	if a.config.CurrentConnection == "" {
 		printError(
@@ -72,12 +126,30 @@ func (a *App) handleImport() {
 	}
 
 	flags := parseImportFlags()
+	if flags.singleTx && flags.batchSize > 0 {
+		printError("--single-transaction and --batch-size cannot be used together")
+	}
+	if flags.continueOnError && (flags.singleTx || flags.batchSize > 0) {
+		printError(
+			"--continue-on-error cannot be combined with --single-transaction or --batch-size",
+		)
+	}
+	if flags.dialect != "" {
+		if _, err := db.NormalizeDialect(flags.dialect); err != nil {
+			printError("%v", err)
+		}
+	}
 
 	// Determine input source: file or stdin.
 	var input *os.File
 	var inputName string
+	var inputDir bool
 
-	if flags.inputFile != "" {
+	if info, err := os.Stat(flags.inputFile); err == nil && info.IsDir() {
+		// A directory written by pam export --dir
+		inputDir = true
+		inputName = flags.inputFile
+	} else if flags.inputFile != "" {
 		f, err := os.Open(flags.inputFile)
 		if err != nil {
 			printError("Could not open input file %q: %v", flags.inputFile, err)
@@ -136,17 +208,44 @@ func (a *App) handleImport() {
 	fmt.Fprintln(os.Stderr)
 
 	opts := db.ImportOptions{
-		ContinueOnError: flags.continueOnError,
-		DryRun:          flags.dryRun,
-		Progress:        os.Stderr,
+		ContinueOnError:   flags.continueOnError,
+		DryRun:            flags.dryRun,
+		Progress:          os.Stderr,
+		SourceDialect:     flags.dialect,
+		SingleTransaction: flags.singleTx,
+		BatchSize:         flags.batchSize,
+	}
+	// A progress bar on a terminal; messages clear it before they print
+	if stat, err := os.Stderr.Stat(); err == nil &&
+		stat.Mode()&os.ModeCharDevice != 0 && !flags.dryRun {
+		bar := &progressBar{out: os.Stderr}
+		opts.Progress = bar
+		opts.OnProgress = bar.update
+		defer bar.clear()
 	}
 
 	start := time.Now()
-	result, err := db.ImportSQL(conn, input, opts)
+	var result *db.ImportResult
+	var err error
+	if inputDir {
+		result, err = db.ImportDir(conn, flags.inputFile, opts)
+	} else {
+		result, err = db.ImportSQL(conn, input, opts)
+	}
+	if result == nil {
+		printError("Import failed: %v", err)
+	}
 	elapsed := time.Since(start)
+	if bar, ok := opts.Progress.(*progressBar); ok {
+		bar.clear()
+	}
 
 	fmt.Fprintln(os.Stderr)
 
+	if flags.errorReport != "" {
+		writeImportReport(flags.errorReport, result)
+	}
+
 	// ── Summary ────────────────────────────────────────────────────────────
 
 	if flags.dryRun {
@@ -165,12 +264,20 @@ func (a *App) handleImport() {
 	if err != nil {
 		fmt.Fprintf(
 			os.Stderr,
-			"%s Import aborted after %d/%d statement(s) in %s\n",
+			"%s Import aborted after %d/%d statement(s) in %s",
 			styles.Error.Render("✗"),
 			result.Executed,
 			result.Total,
 			elapsed.Round(time.Millisecond),
 		)
+		if result.RolledBack > 0 {
+			fmt.Fprintf(os.Stderr, " — %d rolled back", result.RolledBack)
+		}
+		fmt.Fprintln(os.Stderr)
+		if len(result.Errors) == 0 {
+			// Not a failed statement, which was shown as it happened
+			fmt.Fprintf(os.Stderr, "  %s\n", styles.Error.Render(err.Error()))
+		}
 		os.Exit(1)
 	}
 
@@ -216,3 +323,98 @@ func (a *App) handleImport() {
 		}
 	}
 }
+
+// writeImportReport writes the failed statements as JSON for CI and scripts
+func writeImportReport(path string, result *db.ImportResult) {
+	f, err := os.Create(path)
+	if err != nil {
+		printError("Could not create error report %q: %v", path, err)
+	}
+	defer f.Close()
+	if err := result.WriteErrorReport(f); err != nil {
+		printError("Could not write error report %q: %v", path, err)
+	}
+}
+
+// progressBar draws an import's progress on the last line of a terminal.
+// Messages written through it clear the bar first; the next update
+// redraws it.
+type progressBar struct {
+	out   *os.File
+	drawn bool
+}
+
+func (b *progressBar) Write(p []byte) (int, error) {
+	b.clear()
+	return b.out.Write(p)
+}
+
+func (b *progressBar) clear() {
+	if b.drawn {
+		fmt.Fprint(b.out, "\r\033[2K")
+		b.drawn = false
+	}
+}
+
+func (b *progressBar) update(p db.ImportProgress) {
+	seconds := p.Elapsed.Seconds()
+	if seconds <= 0 {
+		return
+	}
+	rate := fmt.Sprintf(
+		"%s stmt/s  %s/s",
+		formatCount(float64(p.Statements)/seconds),
+		formatBytes(float64(p.Bytes)/seconds),
+	)
+
+	var line string
+	if p.Size > 0 {
+		const width = 30
+		fraction := min(float64(p.Bytes)/float64(p.Size), 1)
+		filled := int(fraction * width)
+		eta := "--"
+		if fraction > 0 {
+			remaining := time.Duration(seconds*(1-fraction)/fraction) * time.Second
+			eta = remaining.Round(time.Second).String()
+		}
+		line = fmt.Sprintf(
+			"%s%s %3.0f%%  %s/%s  %s  ETA %s",
+			styles.Success.Render(strings.Repeat("█", filled)),
+			styles.Faint.Render(strings.Repeat("░", width-filled)),
+			fraction*100,
+			formatBytes(float64(p.Bytes)),
+			formatBytes(float64(p.Size)),
+			rate,
+			eta,
+		)
+	} else {
+		line = fmt.Sprintf(
+			"%d statement(s)  %s read  %s",
+			p.Statements,
+			formatBytes(float64(p.Bytes)),
+			rate,
+		)
+	}
+	fmt.Fprintf(b.out, "\r\033[2K%s", line)
+	b.drawn = true
+}
+
+func formatBytes(n float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+func formatCount(n float64) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%.1fk", n/1000)
+	}
+	return fmt.Sprintf("%.0f", n)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/caiolandgraf/pam/internal/config"
@@ -91,6 +92,7 @@ func (a *App) handleInit() {
 	a.config.CurrentConnection = conn.GetName()
 	yaml := config.ToConnectionYAML(conn)
 	yaml.ConnString = rawConnString
+	yaml.Protected = slices.Contains(args, "--protected")
 	a.config.Connections[a.config.CurrentConnection] = yaml
 	err = a.config.Save()
 	if err != nil {
@@ -13,16 +13,29 @@ import (
 
 type listFlags struct {
 	oneline    bool
+	tree       bool
 	searchTerm string
+	tag        string
+	folder     string
 }
 
 func parseListFlags(args []string) (listFlags, []string) {
 	flags := listFlags{}
 	remainingArgs := []string{}
 
-	for _, arg := range args {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		if arg == "--oneline" || arg == "-o" {
 			flags.oneline = true
+		} else if arg == "--tree" {
+			flags.tree = true
+		} else if arg == "--tag" || arg == "-t" {
+			if i+1 < len(args) {
+				i++
+				flags.tag = args[i]
+			}
+		} else if tag, ok := strings.CutPrefix(arg, "--tag="); ok {
+			flags.tag = tag
 		} else if !strings.HasPrefix(arg, "-") {
 			remainingArgs = append(remainingArgs, arg)
 		}
@@ -51,6 +64,12 @@ func (a *App) handleListWithArgs(args []string) {
 		flags.searchTerm = remaining[0]
 	}
 
+	// A trailing slash selects a folder rather than searching
+	if strings.HasSuffix(flags.searchTerm, "/") {
+		flags.folder = flags.searchTerm
+		flags.searchTerm = ""
+	}
+
 	a.renderList(objectType, flags)
 }
 
@@ -80,14 +99,20 @@ func (a *App) renderList(objectType string, flags listFlags) {
 		if a.config.CurrentConnection == "" {
 			printError("No active connection.  Use 'pam switch <connection>' or 'pam init' first")
 		}
-		conn := a.config.Connections[a.config.CurrentConnection]
-		if len(conn.Queries) == 0 {
+		queries := a.config.Queries(a.config.CurrentConnection)
+		if len(queries) == 0 {
 			fmt.Println(styles.Faint.Render("No queries saved"))
 			return
 		}
 
-		queryList := make([]db.Query, 0, len(conn.Queries))
-		for _, query := range conn.Queries {
+		queryList := make([]db.Query, 0, len(queries))
+		for _, query := range queries {
+			if flags.tag != "" && !query.HasTag(flags.tag) {
+				continue
+			}
+			if !query.InFolder(flags.folder) {
+				continue
+			}
 			if flags.searchTerm == "" {
 				queryList = append(queryList, query)
 				continue
@@ -102,8 +127,12 @@ func (a *App) renderList(objectType string, flags listFlags) {
 				strings.ToLower(query.SQL),
 				searchLower,
 			)
+			descriptionMatch := strings.Contains(
+				strings.ToLower(query.Description),
+				searchLower,
+			)
 
-			if nameMatch || sqlMatch {
+			if nameMatch || sqlMatch || descriptionMatch {
 				queryList = append(queryList, query)
 			}
 		}
@@ -112,23 +141,35 @@ func (a *App) renderList(objectType string, flags listFlags) {
 			return queryList[i].Id < queryList[j].Id
 		})
 
-		if flags.searchTerm != "" && len(queryList) == 0 {
+		if len(queryList) == 0 {
+			filter := flags.searchTerm
+			if flags.folder != "" {
+				filter = flags.folder
+			}
+			if flags.tag != "" {
+				filter = strings.TrimSpace(filter + " #" + flags.tag)
+			}
 			fmt.Printf(
 				styles.Faint.Render("No queries found matching '%s'\n"),
-				flags.searchTerm,
+				filter,
 			)
 			return
 		}
 
+		if flags.tree {
+			displayQueriesTree(queryList)
+			return
+		}
+
 		if flags.oneline {
 			displayQueriesOneline(queryList)
 			return
 		}
 
 		for _, query := range queryList {
-			displayName := query.Name
+			displayName := query.Path()
 			if flags.searchTerm != "" {
-				displayName = highlightMatches(query.Name, flags.searchTerm)
+				displayName = highlightMatches(query.Path(), flags.searchTerm)
 			}
 
 			tableName := db.ExtractTableNameFromSQL(query.SQL)
@@ -145,7 +186,10 @@ func (a *App) renderList(objectType string, flags listFlags) {
 				displayName,
 				tableName,
 			)
-			fmt.Println(styles.Title.Render(formatedItem))
+			fmt.Println(styles.Title.Render(formatedItem) + formatTags(query.Tags) + " " + styles.Faint.Render(querySource(query)))
+			if query.Description != "" {
+				fmt.Println(styles.Faint.Render(query.Description))
+			}
 
 			displaySQL := query.SQL
 			if flags.searchTerm != "" {
@@ -178,14 +222,93 @@ func displayQueriesOneline(queries []db.Query) {
 			tableDisplay = "<unknown>"
 		}
 
-		fmt.Printf("%s %s %s\n",
+		fmt.Printf("%s %s %s%s %s\n",
 			styles.Faint.Render(fmt.Sprintf("%d", query.Id)),
-			styles.Title.Render(query.Name),
+			styles.Title.Render(query.Path()),
 			tableDisplay,
+			formatTags(query.Tags),
+			styles.Faint.Render(querySource(query)),
 		)
 	}
 }
 
+// formatTags renders tags as " #a #b", or "" when there are none.
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return " " + styles.Success.Render("#"+strings.Join(tags, " #"))
+}
+
+// queryFolder is a node of the tree view.
+type queryFolder struct {
+	folders map[string]*queryFolder
+	queries []db.Query
+}
+
+// displayQueriesTree prints queries grouped by folder, folders first.
+func displayQueriesTree(queries []db.Query) {
+	root := &queryFolder{folders: map[string]*queryFolder{}}
+	for _, query := range queries {
+		node := root
+		if query.Folder != "" {
+			for _, part := range strings.Split(query.Folder, "/") {
+				child, ok := node.folders[part]
+				if !ok {
+					child = &queryFolder{folders: map[string]*queryFolder{}}
+					node.folders[part] = child
+				}
+				node = child
+			}
+		}
+		node.queries = append(node.queries, query)
+	}
+	printQueryFolder(root, "")
+}
+
+func printQueryFolder(node *queryFolder, indent string) {
+	names := make([]string, 0, len(node.folders))
+	for name := range node.folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.Slice(node.queries, func(i, j int) bool {
+		return node.queries[i].Name < node.queries[j].Name
+	})
+
+	total := len(names) + len(node.queries)
+	branch := func(i int) (string, string) {
+		if i == total-1 {
+			return "└── ", "    "
+		}
+		return "├── ", "│   "
+	}
+
+	for i, name := range names {
+		prefix, childIndent := branch(i)
+		fmt.Println(styles.TreeConnector.Render(indent+prefix) + styles.Title.Render(name+"/"))
+		printQueryFolder(node.folders[name], indent+childIndent)
+	}
+	for i, query := range node.queries {
+		prefix, _ := branch(len(names) + i)
+		fmt.Printf("%s%s %s%s\n",
+			styles.TreeConnector.Render(indent+prefix),
+			styles.Faint.Render(fmt.Sprintf("%d", query.Id)),
+			query.Name,
+			formatTags(query.Tags),
+		)
+	}
+}
+
+// querySource is where a query is stored: its project file or the global
+// config.
+func querySource(query db.Query) string {
+	if query.Source != "" {
+		return query.Source
+	}
+	return "global"
+}
+
 func highlightMatches(text, searchTerm string) string {
 	if searchTerm == "" {
 		return text
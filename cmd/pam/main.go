@@ -2,8 +2,10 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/mask"
 	"github.com/caiolandgraf/pam/internal/styles"
 )
 
@@ -13,9 +15,17 @@ func main() {
 		log.Fatal("Could not load config file", err)
 	}
 
+	if cwd, err := os.Getwd(); err == nil {
+		config.LoadProject(cwd)
+	}
+
 	// Initialize color scheme
 	styles.InitScheme(cfg.ColorScheme, cfg.CustomColorScheme)
 
+	if err := mask.Init(cfg.Masking); err != nil {
+		log.Fatal("Invalid masking config: ", err)
+	}
+
 	app := NewApp(cfg)
 	app.Run()
 }
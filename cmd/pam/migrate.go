@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/migrate"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+type migrateFlags struct {
+	dir    string
+	steps  int
+	dryRun bool
+}
+
+func parseMigrateFlags(args []string) (migrateFlags, []string) {
+	flags := migrateFlags{}
+	remainingArgs := []string{}
+
+	steps := func(value string) int {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			printError("--steps must be a positive number, got %q", value)
+		}
+		return n
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dir" || arg == "-d":
+			if i+1 >= len(args) {
+				printError("--dir requires a value")
+			}
+			flags.dir = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--dir="):
+			flags.dir = strings.TrimPrefix(arg, "--dir=")
+		case arg == "--steps" || arg == "-n":
+			if i+1 >= len(args) {
+				printError("--steps requires a value")
+			}
+			flags.steps = steps(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--steps="):
+			flags.steps = steps(strings.TrimPrefix(arg, "--steps="))
+		case arg == "--dry-run":
+			flags.dryRun = true
+		case !strings.HasPrefix(arg, "-"):
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+
+	if flags.dir == "" {
+		// Next to the project's .pam directory, or in the working directory
+		flags.dir = migrate.DefaultDir
+		if config.Project != nil {
+			flags.dir = filepath.Join(filepath.Dir(config.Project.Dir), migrate.DefaultDir)
+		}
+	}
+	return flags, remainingArgs
+}
+
+// handleMigrate creates and applies schema migrations:
+//
+//	pam migrate new <name>
+//	pam migrate up [--steps N] [--dry-run]
+//	pam migrate down [--steps N] [--dry-run]
+//	pam migrate status
+func (a *App) handleMigrate() {
+	usage := "Usage: pam migrate new <name> | up [--steps N] [--dry-run] | down [--steps N] [--dry-run] | status [--dir path]"
+	if len(os.Args) < 3 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	command := os.Args[2]
+	flags, args := parseMigrateFlags(os.Args[3:])
+
+	if command == "new" {
+		if len(args) == 0 {
+			fmt.Println("Usage: pam migrate new <name> [--dir path]")
+			os.Exit(1)
+		}
+		path, err := migrate.Create(flags.dir, strings.Join(args, "_"), time.Now())
+		if err != nil {
+			printError("Could not create migration: %v", err)
+		}
+		fmt.Printf("%s Created %s\n", styles.Success.Render("✓"), styles.Title.Render(path))
+		return
+	}
+	if command != "up" && command != "down" && command != "status" {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	if a.config.CurrentConnection == "" {
+		printError(
+			"No active connection. Use 'pam switch <connection>' or 'pam init' first",
+		)
+	}
+	migrations, err := migrate.Load(flags.dir)
+	if err != nil {
+		printError("Could not read migrations: %v", err)
+	}
+
+	conn := config.FromConnectionYaml(
+		a.config.Connections[a.config.CurrentConnection],
+	)
+	if err := conn.Open(); err != nil {
+		printError(
+			"Could not open connection to %s: %v",
+			a.config.CurrentConnection,
+			err,
+		)
+	}
+	defer conn.Close()
+
+	runner := migrate.NewRunner(conn)
+	runner.DryRun = flags.dryRun
+	runner.Out = os.Stdout
+	runner.Done = func(m migrate.Migration, up bool, elapsed time.Duration) {
+		verb := "Applied"
+		if !up {
+			verb = "Reverted"
+		}
+		fmt.Printf(
+			"%s %s %s %s\n",
+			styles.Success.Render("✓"),
+			verb,
+			styles.Title.Render(m.ID()),
+			styles.Faint.Render(elapsed.Round(time.Millisecond).String()),
+		)
+	}
+
+	switch command {
+	case "status":
+		statuses, err := runner.Status(migrations)
+		if err != nil {
+			printError("%v", err)
+		}
+		printMigrationStatus(statuses, flags.dir)
+	case "up":
+		n, err := runner.Up(migrations, flags.steps)
+		if err != nil {
+			printError("%v", err)
+		}
+		if n == 0 {
+			fmt.Println(styles.Faint.Render("No pending migrations in " + flags.dir))
+		}
+	case "down":
+		steps := flags.steps
+		if steps == 0 {
+			steps = 1
+		}
+		n, err := runner.Down(migrations, steps)
+		if err != nil {
+			printError("%v", err)
+		}
+		if n == 0 {
+			fmt.Println(styles.Faint.Render("No applied migrations to revert"))
+		}
+	}
+}
+
+func printMigrationStatus(statuses []migrate.Status, dir string) {
+	if len(statuses) == 0 {
+		fmt.Println(styles.Faint.Render(
+			"No migrations in " + dir + ". Create one with 'pam migrate new <name>'",
+		))
+		return
+	}
+
+	width := 0
+	for _, s := range statuses {
+		width = max(width, len(s.Version)+1+len(s.Name))
+	}
+	pending := 0
+	for _, s := range statuses {
+		id := fmt.Sprintf("%-*s", width, s.Version+"_"+s.Name)
+		switch s.State {
+		case migrate.StateApplied:
+			fmt.Printf("  %s %s  %s\n", styles.Success.Render("✓"), id, styles.Faint.Render("applied "+s.AppliedAt))
+		case migrate.StatePending:
+			pending++
+			fmt.Printf("  %s %s  %s\n", styles.Faint.Render("•"), id, styles.Faint.Render("pending"))
+		case migrate.StateModified:
+			fmt.Printf("  %s %s  %s\n", styles.Error.Render("!"), id, styles.Error.Render("modified since it was applied"))
+		case migrate.StateMissing:
+			fmt.Printf("  %s %s  %s\n", styles.Error.Render("?"), id, styles.Error.Render("applied, file missing"))
+		}
+	}
+	fmt.Println()
+	fmt.Println(styles.Faint.Render(fmt.Sprintf(
+		"%d migration(s), %d pending", len(statuses), pending,
+	)))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/filemode"
+	"github.com/caiolandgraf/pam/internal/run"
+)
+
+func hasFileModeFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--file-mode" {
+			return true
+		}
+	}
+	return false
+}
+
+// openScratch starts an in-memory database for querying files, backed by
+// the scratch pseudo-connection so queries can be saved and rerun.
+func (a *App) openScratch() (*filemode.Session, db.DatabaseConnection) {
+	session, err := filemode.Open()
+	if err != nil {
+		printError("Could not start an in-memory database: %v", err)
+	}
+
+	yc, ok := a.config.Connections[filemode.Scratch]
+	if !ok {
+		// Only written to the config file once a query is saved
+		yc = &config.ConnectionYAML{
+			Name:       filemode.Scratch,
+			DBType:     session.DbType(),
+			ConnString: filemode.MemoryConnString,
+			Queries:    map[string]db.Query{},
+		}
+		a.config.Connections[filemode.Scratch] = yc
+	} else if yc.ConnString != filemode.MemoryConnString {
+		printError("A connection named '%s' already exists and is not the in-memory scratch connection", filemode.Scratch)
+	}
+
+	conn := session.Connection()
+	conn.SetQueries(yc.Queries)
+	conn.SetLastQuery(yc.LastQuery)
+	return session, conn
+}
+
+func (a *App) handleOpen() {
+	var path, format string
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-f":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case !strings.HasPrefix(arg, "-") && path == "":
+			path = arg
+		}
+	}
+	if path == "" {
+		fmt.Println("Usage: pam open <file.csv|.tsv|.parquet|.json> [--format fmt]")
+		os.Exit(1)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		printError("Could not open %s: %v", path, err)
+	}
+	// Saved queries must work from any directory
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	session, conn := a.openScratch()
+	defer session.Close()
+
+	sql, err := session.FileSQL(path)
+	if err != nil {
+		printError("%v", err)
+	}
+
+	execParams := run.ExecutionParams{
+		Connection:   conn,
+		Config:       a.config,
+		SaveCallback: a.saveQueryCallback(filemode.Scratch),
+	}
+	execParams.OnRerun = func(editedSQL string) error {
+		rerun := execParams
+		rerun.Query = db.Query{SQL: editedSQL}
+		return run.Execute(rerun)
+	}
+
+	if format != "" {
+		execParams.Query = db.Query{SQL: sql}
+		err = run.ExecuteExport(execParams, format)
+	} else {
+		// Unnamed queries are not limited by the executor, and a file has
+		// no table metadata to infer
+		if a.config.DefaultRowLimit > 0 {
+			sql = conn.ApplyRowLimit(sql, a.config.DefaultRowLimit)
+		}
+		execParams.Query = db.Query{SQL: sql}
+		err = run.Execute(execParams)
+	}
+	if err != nil {
+		printError("%v", err)
+	}
+}
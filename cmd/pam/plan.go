@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/params"
+	"github.com/caiolandgraf/pam/internal/plan"
+	"github.com/caiolandgraf/pam/internal/run"
+	"github.com/caiolandgraf/pam/internal/spinner"
+)
+
+type planFlags struct {
+	analyze   bool
+	noAnalyze bool
+	format    string
+	lastQuery bool
+	selector  string
+}
+
+func parsePlanFlags(args []string) planFlags {
+	flags := planFlags{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--analyze" || arg == "-a":
+			flags.analyze = true
+		case arg == "--no-analyze":
+			flags.noAnalyze = true
+		case arg == "--last" || arg == "-l":
+			flags.lastQuery = true
+		case arg == "--format" || arg == "-f":
+			if i+1 < len(args) {
+				flags.format = args[i+1]
+				i++
+			} else {
+				printError("--format requires a value (text, json)")
+			}
+		case strings.HasPrefix(arg, "--format="):
+			flags.format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "-"):
+			// Parameter flags (--name value) are handled by parseParameterFlagsFrom
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+			}
+		default:
+			if flags.selector == "" {
+				flags.selector = arg
+			}
+		}
+	}
+
+	return flags
+}
+
+// stripPlanFlags removes plan-only flags so the remaining args can be parsed
+// as query parameters like in 'pam run'.
+func stripPlanFlags(args []string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--analyze" || arg == "-a" || arg == "--no-analyze":
+			continue
+		case arg == "--last" || arg == "-l":
+			continue
+		case arg == "--format" || arg == "-f":
+			i++
+			continue
+		case strings.HasPrefix(arg, "--format="):
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+func (a *App) handlePlan() {
+	if a.config.CurrentConnection == "" {
+		printError("No active connection. Use 'pam switch <connection>' or 'pam init' first")
+	}
+
+	args := os.Args[2:]
+	flags := parsePlanFlags(args)
+
+	if flags.selector == "" && !flags.lastQuery {
+		fmt.Println("Usage: pam plan <query-name|id|sql> [--analyze | --no-analyze] [--format text|json]")
+		os.Exit(1)
+	}
+	if flags.format != "" && flags.format != "text" && flags.format != "json" {
+		printError("Unknown plan format '%s'. Use text or json", flags.format)
+	}
+
+	conn := config.FromConnectionYaml(a.config.Connections[a.config.CurrentConnection])
+	if !plan.Supported(conn.GetDbType()) {
+		printError("Query plans are not supported for %s connections", conn.GetDbType())
+	}
+
+	resolved, err := run.ResolveQuery(
+		run.Flags{Selector: flags.selector, LastQuery: flags.lastQuery},
+		a.config,
+		a.config.CurrentConnection,
+		conn,
+	)
+	if err != nil {
+		printError("%v", err)
+	}
+
+	if len(run.SplitScript(resolved.Query.SQL)) > 1 {
+		printError("pam plan explains a single statement; the query contains several")
+	}
+
+	// ANALYZE executes the statement, so only do it implicitly for plain
+	// reads; anything that could write needs an explicit --analyze
+	analyze := run.IsReadOnlyQuery(resolved.Query.SQL) && !flags.noAnalyze
+	if flags.analyze {
+		analyze = true
+	}
+
+	if err := conn.Open(); err != nil {
+		printError("Could not open connection to %s: %v", a.config.CurrentConnection, err)
+	}
+	defer conn.Close()
+
+	paramArgs := stripPlanFlags(args)
+	paramFlags := parseParameterFlagsFrom(paramArgs)
+	positionalArgs := params.MapPositionalArgs(
+		resolved.Query.SQL,
+		parsePositionalArgsFrom(paramArgs, flags.selector),
+	)
+	sql, queryArgs, _, err := a.processParameters(resolved.Query.SQL, conn, paramFlags, positionalArgs, flags.format != "")
+	if err != nil {
+		printError("%v", err)
+	}
+
+	done := make(chan struct{})
+	go spinner.CircleWaitWithTimer(done)
+	p, err := plan.Run(conn, sql, queryArgs, analyze)
+	done <- struct{}{}
+	fmt.Print("\r\033[2K")
+	if err != nil {
+		printError("Could not get query plan: %v", err)
+	}
+	p.SQL = resolved.Query.SQL
+
+	switch flags.format {
+	case "json":
+		out, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			printError("Could not encode plan: %v", err)
+		}
+		fmt.Println(string(out))
+	case "text":
+		fmt.Print(plan.RenderText(p))
+	default:
+		if err := plan.Render(p); err != nil {
+			printError("Error rendering plan: %v", err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/profile"
+	"github.com/caiolandgraf/pam/internal/spinner"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+type profileFlags struct {
+	format  string
+	top     int
+	buckets int
+	columns []string
+}
+
+func parseProfileFlags() (profileFlags, []string) {
+	flags := profileFlags{
+		top:     profile.DefaultTopN,
+		buckets: profile.DefaultBuckets,
+	}
+	remainingArgs := []string{}
+	args := os.Args[2:]
+
+	intValue := func(name, value string) int {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			printError("%s expects a non-negative number, got '%s'", name, value)
+		}
+		return n
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format" || arg == "-f":
+			if i+1 < len(args) {
+				flags.format = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--format="):
+			flags.format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--top" || arg == "-n":
+			if i+1 < len(args) {
+				flags.top = intValue("--top", args[i+1])
+				i++
+			}
+		case arg == "--buckets":
+			if i+1 < len(args) {
+				flags.buckets = intValue("--buckets", args[i+1])
+				i++
+			}
+		case arg == "--columns" || arg == "-c":
+			if i+1 < len(args) {
+				flags.columns = strings.Split(args[i+1], ",")
+				i++
+			}
+		case !strings.HasPrefix(arg, "-"):
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+
+	return flags, remainingArgs
+}
+
+func (a *App) handleProfile() {
+	if a.config.CurrentConnection == "" {
+		printError(
+			"No active connection. Use 'pam switch <connection>' or 'pam init' first",
+		)
+	}
+
+	flags, args := parseProfileFlags()
+	if len(args) == 0 {
+		fmt.Println("Usage: pam profile <table-name> [--format json|markdown] [--top N] [--buckets N] [--columns a,b]")
+		os.Exit(1)
+	}
+	if flags.format != "" && flags.format != "json" &&
+		flags.format != "markdown" && flags.format != "md" {
+		printError("Unknown profile format '%s'. Use json or markdown", flags.format)
+	}
+
+	conn := config.FromConnectionYaml(
+		a.config.Connections[a.config.CurrentConnection],
+	)
+	if err := conn.Open(); err != nil {
+		printError(
+			"Could not open connection to %s: %v",
+			a.config.CurrentConnection,
+			err,
+		)
+	}
+	defer conn.Close()
+
+	tableName := args[0]
+	details, err := conn.GetColumnDetails(tableName)
+	if err != nil {
+		printError("Could not read columns of %s: %v", tableName, err)
+	}
+	if len(details) == 0 {
+		printError("Table '%s' not found or has no columns", tableName)
+	}
+
+	// The spinner would end up in json/markdown output
+	done := make(chan struct{})
+	if flags.format == "" {
+		go spinner.CircleWaitWithTimer(done)
+	}
+
+	var results []profile.ColumnStats
+	var failures []string
+	for _, col := range details {
+		if len(flags.columns) > 0 && !containsFold(flags.columns, col.Name) {
+			continue
+		}
+		stats, err := profile.Server(
+			conn,
+			tableName,
+			col.Name,
+			col.DataType,
+			flags.top,
+			flags.buckets,
+		)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", col.Name, err))
+			continue
+		}
+		results = append(results, stats)
+	}
+
+	if flags.format == "" {
+		done <- struct{}{}
+		fmt.Print("\r\033[2K")
+	}
+
+	for _, f := range failures {
+		fmt.Fprintln(os.Stderr, styles.Error.Render("✗ "+f))
+	}
+	if len(results) == 0 {
+		printError("No columns could be profiled")
+	}
+
+	switch flags.format {
+	case "json":
+		out, err := json.MarshalIndent(map[string]any{
+			"table":   tableName,
+			"columns": results,
+		}, "", "  ")
+		if err != nil {
+			printError("Could not encode profile: %v", err)
+		}
+		fmt.Println(string(out))
+	case "markdown", "md":
+		fmt.Print(profile.Markdown(tableName, results))
+	default:
+		fmt.Println(styles.Title.Render(fmt.Sprintf("Profile: %s", tableName)) +
+			styles.Faint.Render(fmt.Sprintf("  %d rows, %d columns", results[0].Rows, len(results))))
+		fmt.Println()
+		for _, stats := range results {
+			fmt.Println(profile.RenderColumn(stats, ""))
+		}
+	}
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/check"
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// queryMeta holds the metadata flags of 'pam add' and 'pam edit'. Nil
+// fields were not given and leave the query unchanged.
+type queryMeta struct {
+	description *string
+	tags        []string
+	tagsSet     bool
+	folder      *string
+	assert      *string
+}
+
+func (m queryMeta) isSet() bool {
+	return m.description != nil || m.tagsSet || m.folder != nil || m.assert != nil
+}
+
+// parseQueryMetaFlags takes --description/-d, --tag/-t (repeatable or
+// comma-separated), --folder and --assert out of args.
+func parseQueryMetaFlags(args []string) (queryMeta, []string) {
+	var meta queryMeta
+	var rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--description", "-d", "--tag", "-t", "--folder", "--assert":
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				printError("%s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--description", "-d":
+			meta.description = &value
+		case "--tag", "-t":
+			meta.tags = append(meta.tags, db.ParseTags(value)...)
+			meta.tagsSet = true
+		case "--folder":
+			folder := strings.Trim(value, "/")
+			meta.folder = &folder
+		case "--assert":
+			meta.assert = &value
+		}
+	}
+	return meta, rest
+}
+
+func (m queryMeta) apply(q *db.Query) {
+	if m.description != nil {
+		q.Description = *m.description
+	}
+	if m.tagsSet {
+		q.Tags = m.tags
+	}
+	if m.folder != nil {
+		q.Folder = *m.folder
+	}
+	if m.assert != nil {
+		q.Assert = *m.assert
+	}
+}
+
+// metadataHeader renders the editable metadata lines shown under the query
+// name in the editor. The assert line only shows on checks.
+func metadataHeader(q db.Query) string {
+	header := fmt.Sprintf(
+		"-- description: %s\n-- tags: %s\n-- folder: %s\n",
+		q.Description,
+		strings.Join(q.Tags, ", "),
+		q.Folder,
+	)
+	if q.Assert != "" || check.IsCheck(q) {
+		header += fmt.Sprintf("-- assert: %s\n", q.Assert)
+	}
+	return header
+}
+
+var metadataLine = regexp.MustCompile(`^--\s*(description|tags|folder|assert)\s*:\s*(.*?)\s*$`)
+
+// parseMetadataHeader reads the metadata lines back from edited content.
+// Keys that are absent are left unset.
+func parseMetadataHeader(content string) queryMeta {
+	var meta queryMeta
+	for line := range strings.SplitSeq(content, "\n") {
+		m := metadataLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		value := m[2]
+		switch m[1] {
+		case "description":
+			meta.description = &value
+		case "tags":
+			meta.tags = db.ParseTags(value)
+			meta.tagsSet = true
+		case "folder":
+			folder := strings.Trim(value, "/")
+			meta.folder = &folder
+		case "assert":
+			meta.assert = &value
+		}
+	}
+	return meta
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/caiolandgraf/pam/internal/config"
 	"github.com/caiolandgraf/pam/internal/db"
 	"github.com/caiolandgraf/pam/internal/styles"
 )
@@ -39,13 +40,26 @@ func (a *App) handleRemove() {
 
 	// Otherwise, remove query (original behavior)
 	conn := a.config.Connections[a.config.CurrentConnection]
-	queries := conn.Queries
+	queries := a.config.Queries(a.config.CurrentConnection)
 	query, exists := db.FindQueryWithSelector(queries, os.Args[2])
 	if !exists {
 		printError("Query '%s' could not be found", os.Args[2])
 		return
 	}
 
+	if query.Source != "" {
+		pq, _ := config.Project.Lookup(a.config.CurrentConnection, query.Name)
+		if err := config.Project.Remove(pq); err != nil {
+			printError("Could not remove %s: %v", query.Source, err)
+			return
+		}
+		fmt.Printf(
+			"%s",
+			styles.Success.Render(fmt.Sprintf("✓ Removed run '%s' (%s)", query.Name, query.Source)),
+		)
+		return
+	}
+
 	delete(conn.Queries, query.Name)
 	err := a.config.Save()
 	if err != nil {
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/caiolandgraf/pam/internal/config"
 	"github.com/caiolandgraf/pam/internal/db"
@@ -13,12 +15,30 @@ import (
 )
 
 func (a *App) handleRun() {
+	args := os.Args[2:]
+
+	if flags := parseRunFlagsFrom(args); flags.Group != "" {
+		if err := a.runGroup(args, flags); err != nil {
+			printError("%v", err)
+		}
+		return
+	}
+
+	// --file-mode runs against an in-memory database instead of a connection
+	if hasFileModeFlag(args) {
+		session, conn := a.openScratch()
+		defer session.Close()
+		if err := a.runFromArgs(args, conn); err != nil {
+			printError("%v", err)
+		}
+		return
+	}
+
 	if a.config.CurrentConnection == "" {
 		printError("No active connection.   Use 'pam switch <connection>' or 'pam init' first")
 	}
 
 	conn := config.FromConnectionYaml(a.config.Connections[a.config.CurrentConnection])
-	args := os.Args[2:]
 
 	// Check for empty args → open editor for new query (CLI-only feature)
 	if len(args) == 0 {
@@ -40,7 +60,7 @@ func (a *App) runFromArgs(args []string, conn db.DatabaseConnection) error {
 	resolved, err := run.ResolveQuery(
 		flags,
 		a.config,
-		a.config.CurrentConnection,
+		conn.GetName(),
 		conn,
 	)
 	if err != nil {
@@ -56,7 +76,7 @@ func (a *App) runFromArgs(args []string, conn db.DatabaseConnection) error {
 		resolved.Query = a.editQueryOrExit(resolved.Query)
 	}
 
-	a.saveIfNeeded(resolved)
+	a.saveIfNeeded(conn.GetName(), resolved)
 
 	// Parse parameter flags and positional args
 	paramFlags := parseParameterFlagsFrom(args)
@@ -67,6 +87,12 @@ func (a *App) runFromArgs(args []string, conn db.DatabaseConnection) error {
 		positionalArgsSlice,
 	)
 
+	if flags.Chart.Enabled() {
+		return a.executeQueryWithParamsInternal(resolved.Query, conn, paramFlags, positionalArgs, func(p run.ExecutionParams) error {
+			return run.ExecuteChart(p, flags.Chart)
+		}, true)
+	}
+
 	// If --format is set, use export executor
 	if flags.ExportFormat != "" {
 		return a.executeQueryWithParamsInternal(resolved.Query, conn, paramFlags, positionalArgs, func(p run.ExecutionParams) error {
@@ -77,12 +103,52 @@ func (a *App) runFromArgs(args []string, conn db.DatabaseConnection) error {
 	return a.executeQueryWithParams(resolved.Query, conn, paramFlags, positionalArgs)
 }
 
+// chartValueFlags take a value and are only flags when a chart is requested
+var chartValueFlags = map[string]bool{
+	"--x": true, "--y": true, "--output": true, "--width": true, "--height": true,
+}
+
+// chartRequested reports whether args ask for a chart (--chart or --format svg).
+func chartRequested(args []string) bool {
+	for i, arg := range args {
+		if arg == "--chart" {
+			return true
+		}
+		if (arg == "--format" || arg == "-f") && i+1 < len(args) && args[i+1] == "svg" {
+			return true
+		}
+	}
+	return false
+}
+
 func parseRunFlagsFrom(args []string) run.Flags {
 	flags := run.Flags{}
+	charting := chartRequested(args)
 
 	for i, arg := range args {
+		if arg == "--chart" || (charting && chartValueFlags[arg]) {
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				setChartFlag(&flags.Chart, arg, args[i+1])
+			} else if arg == "--chart" {
+				// A bare --chart draws the default line chart
+				flags.Chart.Kind = "line"
+			}
+			continue
+		}
+		if arg == "--group" || arg == "--parallel" {
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				setGroupFlag(&flags, arg, args[i+1])
+			}
+			continue
+		}
+
+		if arg == "--yes" || arg == "-y" {
+			flags.Yes = true
+			continue
+		}
+
 		// Skip parameter flags and their values
-		if strings.HasPrefix(arg, "--") && arg != "--edit" && arg != "-e" && arg != "--last" && arg != "-l" && arg != "--format" {
+		if strings.HasPrefix(arg, "--") && arg != "--edit" && arg != "-e" && arg != "--last" && arg != "-l" && arg != "--format" && arg != "--file-mode" {
 			// This is a parameter flag, skip it and its value
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
 				continue
@@ -90,6 +156,8 @@ func parseRunFlagsFrom(args []string) run.Flags {
 		}
 
 		switch arg {
+		case "--file-mode":
+			flags.FileMode = true
 		case "--edit", "-e":
 			flags.EditMode = true
 		case "--last", "-l":
@@ -98,24 +166,76 @@ func parseRunFlagsFrom(args []string) run.Flags {
 			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 				flags.ExportFormat = args[i+1]
 			}
+			if flags.ExportFormat == "svg" {
+				flags.Chart.SVG = true
+			}
 		default:
 			if !strings.HasPrefix(arg, "--") && !strings.HasPrefix(arg, "-") && flags.Selector == "" {
 				flags.Selector = arg
 			}
 		}
 	}
+	if flags.Chart.Enabled() && flags.Chart.Kind == "" {
+		flags.Chart.Kind = "line"
+	}
 	return flags
 }
 
+func setChartFlag(opts *run.ChartOptions, flag, value string) {
+	switch flag {
+	case "--chart":
+		opts.Kind = value
+	case "--x":
+		opts.X = value
+	case "--y":
+		opts.Y = strings.Split(value, ",")
+	case "--output":
+		opts.Output = value
+	case "--width", "--height":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			printError("%s expects a positive number, got '%s'", flag, value)
+		}
+		if flag == "--width" {
+			opts.Width = n
+		} else {
+			opts.Height = n
+		}
+	}
+}
+
+func setGroupFlag(flags *run.Flags, flag, value string) {
+	switch flag {
+	case "--group":
+		flags.Group = value
+	case "--parallel":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			printError("--parallel expects a positive number, got '%s'", value)
+		}
+		flags.Parallel = n
+	}
+}
+
 func parseParameterFlagsFrom(args []string) map[string]string {
 	paramValues := make(map[string]string)
+	charting := chartRequested(args)
 
 	i := 0
 	for i < len(args) {
 		arg := args[i]
 
+		// Chart and group flags are not parameters
+		if arg == "--chart" || (charting && chartValueFlags[arg]) || arg == "--group" || arg == "--parallel" {
+			i++
+			if i < len(args) && !strings.HasPrefix(args[i], "-") {
+				i++
+			}
+			continue
+		}
+
 		// Skip known flags (and their values for --format/-f)
-		if arg == "--edit" || arg == "-e" || arg == "--last" || arg == "-l" {
+		if arg == "--edit" || arg == "-e" || arg == "--last" || arg == "-l" || arg == "--file-mode" || arg == "--yes" || arg == "-y" {
 			i++
 			continue
 		}
@@ -160,6 +280,11 @@ func parsePositionalArgsFrom(args []string, selector string) []string {
 	for i < len(args) {
 		arg := args[i]
 
+		if arg == "--file-mode" || arg == "--yes" {
+			i++
+			continue
+		}
+
 		// Skip flags and their values
 		if strings.HasPrefix(arg, "--") {
 			// Skip the flag itself
@@ -170,7 +295,7 @@ func parsePositionalArgsFrom(args []string, selector string) []string {
 			}
 			continue
 		}
-		if arg == "-e" || arg == "-l" {
+		if arg == "-e" || arg == "-l" || arg == "-y" {
 			i++
 			continue
 		}
@@ -222,14 +347,14 @@ func (a *App) editQueryOrExit(query db.Query) db.Query {
 	return query
 }
 
-func (a *App) saveIfNeeded(resolved run.ResolvedQuery) {
+func (a *App) saveIfNeeded(connName string, resolved run.ResolvedQuery) {
 	if !resolved.Saveable {
 		return
 	}
 
 	// Save the query and update last query
 	if err := a.config.SaveQueryAndLast(
-		a.config.CurrentConnection,
+		connName,
 		resolved.Query,
 		true,
 	); err != nil {
@@ -240,60 +365,62 @@ func (a *App) saveIfNeeded(resolved run.ResolvedQuery) {
 type executorFunc func(run.ExecutionParams) error
 
 func (a *App) executeQueryWithParamsInternal(query db.Query, conn db.DatabaseConnection, paramFlags, positionalArgs map[string]string, executor executorFunc, noInteractive bool) error {
-	sql, args, displaySQL, err := a.processParameters(query.SQL, conn, paramFlags, positionalArgs, noInteractive)
-	if err != nil {
-		return err
-	}
+	var onRerun func(string) error
 
-	processedQuery := db.Query{
-		Name: query.Name,
-		SQL:  sql,
-		Id:   query.Id,
-	}
+	// buildParams prepares execution params for sql, splitting it into
+	// statements when it is a multi-statement script.
+	buildParams := func(sql string, rerun bool) (run.ExecutionParams, error) {
+		execParams := run.ExecutionParams{
+			Connection:   conn,
+			Config:       a.config,
+			SaveCallback: a.saveQueryCallback(conn.GetName()),
+			OnRerun:      onRerun,
+		}
 
-	var onRerun func(string) error
-	onRerun = func(editedSQL string) error {
-		finalSQL := editedSQL
+		if statements := run.SplitScript(sql); len(statements) > 1 {
+			processed, err := a.processScript(sql, statements, conn, paramFlags, positionalArgs, noInteractive)
+			if err != nil {
+				return execParams, err
+			}
+			execParams.Query = db.Query{Name: query.Name, SQL: sql, Id: query.Id}
+			execParams.Statements = processed
+			return execParams, nil
+		}
+
+		finalSQL := sql
 		finalArgs := []any{}
 		finalDisplaySQL := ""
 
-		if strings.Contains(editedSQL, ":") {
+		if !rerun || strings.Contains(sql, ":") {
 			var procErr error
-			finalSQL, finalArgs, finalDisplaySQL, procErr = a.processParameters(editedSQL, conn, paramFlags, positionalArgs, noInteractive)
+			finalSQL, finalArgs, finalDisplaySQL, procErr = a.processParameters(sql, conn, paramFlags, positionalArgs, noInteractive)
 			if procErr != nil {
-				return procErr
+				return execParams, procErr
 			}
 		}
-		if finalDisplaySQL == "" {
+		if rerun && finalDisplaySQL == "" {
 			finalDisplaySQL = finalSQL
 		}
 
-		processedQuery := db.Query{
-			Name: query.Name,
-			SQL:  finalSQL,
-			Id:   query.Id,
+		execParams.Query = db.Query{Name: query.Name, SQL: finalSQL, Id: query.Id}
+		execParams.Args = finalArgs
+		execParams.DisplaySQL = finalDisplaySQL
+		return execParams, nil
+	}
+
+	onRerun = func(editedSQL string) error {
+		execParams, err := buildParams(editedSQL, true)
+		if err != nil {
+			return err
 		}
+		return executor(execParams)
+	}
 
-		return executor(run.ExecutionParams{
-			Query:        processedQuery,
-			Connection:   conn,
-			Config:       a.config,
-			SaveCallback: a.saveQueryFromTable,
-			Args:         finalArgs,
-			DisplaySQL:   finalDisplaySQL,
-			OnRerun:      onRerun,
-		})
-	}
-
-	return executor(run.ExecutionParams{
-		Query:        processedQuery,
-		Connection:   conn,
-		Config:       a.config,
-		SaveCallback: a.saveQueryFromTable,
-		Args:         args,
-		DisplaySQL:   displaySQL,
-		OnRerun:      onRerun,
-	})
+	execParams, err := buildParams(query.SQL, false)
+	if err != nil {
+		return err
+	}
+	return executor(execParams)
 }
 
 func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection, paramFlags, positionalArgs map[string]string) error {
@@ -302,11 +429,51 @@ func (a *App) executeQueryWithParams(query db.Query, conn db.DatabaseConnection,
 
 // processParameters handles parameter extraction, validation, and substitution
 func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValues, positionals map[string]string, noInteractive bool) (string, []any, string, error) {
+	paramValues, err := a.resolveParamValues(sql, conn, cliValues, positionals, noInteractive)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if paramValues == nil {
+		return sql, []any{}, "", nil
+	}
+
+	return substituteParams(sql, paramValues, conn)
+}
+
+// processScript resolves parameters once for a whole multi-statement script
+// (prompting at most once) and substitutes them into each statement.
+func (a *App) processScript(sql string, statements []string, conn db.DatabaseConnection, cliValues, positionals map[string]string, noInteractive bool) ([]run.Statement, error) {
+	paramValues, err := a.resolveParamValues(sql, conn, cliValues, positionals, noInteractive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]run.Statement, 0, len(statements))
+	for _, stmt := range statements {
+		if paramValues == nil || len(params.ExtractParameters(stmt)) == 0 {
+			result = append(result, run.Statement{SQL: stmt, DisplaySQL: stmt})
+			continue
+		}
+
+		finalSQL, args, displaySQL, err := substituteParams(stmt, paramValues, conn)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, run.Statement{SQL: finalSQL, Args: args, DisplaySQL: displaySQL})
+	}
+
+	return result, nil
+}
+
+// resolveParamValues extracts, validates and resolves parameter values for sql,
+// prompting for missing ones unless noInteractive is set. Lookup queries for
+// the prompt run on conn. It returns nil when the SQL has no parameters.
+func (a *App) resolveParamValues(sql string, conn db.DatabaseConnection, cliValues, positionals map[string]string, noInteractive bool) (map[string]string, error) {
 	// Extract parameter definitions from SQL
 	paramDefs := params.ExtractParameters(sql)
 
 	if len(paramDefs) == 0 {
-		return sql, []any{}, "", nil
+		return nil, nil
 	}
 
 	// Map positional args to parameter names
@@ -316,31 +483,43 @@ func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValue
 
 	// Validate CLI values
 	if err := params.ValidateCLIValues(cliValues, paramDefs); err != nil {
-		return "", nil, "", fmt.Errorf("parameter validation error: %w", err)
+		return nil, fmt.Errorf("parameter validation error: %w", err)
 	}
 
 	// Validate param names don't conflict with reserved flags
 	if err := params.ValidateParamNames(paramDefs); err != nil {
-		return "", nil, "", fmt.Errorf("parameter name conflict: %w", err)
+		return nil, fmt.Errorf("parameter name conflict: %w", err)
+	}
+
+	types, err := params.ExtractTypes(sql)
+	if err != nil {
+		return nil, fmt.Errorf("parameter type error: %w", err)
 	}
 
 	// Resolve parameters (CLI > defaults)
 	paramValues := params.ResolveParameters(paramDefs, cliValues)
+	if err := params.ValidateValues(types, paramValues); err != nil {
+		return nil, fmt.Errorf("parameter validation error: %w", err)
+	}
 
 	// Check for missing required parameters
 	missing := params.GetMissingRequired(paramDefs, paramValues)
 	if len(missing) > 0 {
 		if noInteractive {
-			return "", nil, "", fmt.Errorf("missing required parameters: %s (provide values via --param or positional args)", strings.Join(missing, ", "))
+			return nil, fmt.Errorf("missing required parameters: %s (provide values via --param or positional args)", strings.Join(missing, ", "))
 		}
 		// Launch interactive TUI
 		collectedValues, err := params.CollectParameters(
 			sql,
 			missing,
 			paramDefs,
+			types,
+			func(lookupSQL string) ([]params.Suggestion, error) {
+				return params.RunLookup(conn, lookupSQL)
+			},
 		)
 		if err != nil {
-			return "", nil, "", fmt.Errorf("error collecting parameters: %w", err)
+			return nil, fmt.Errorf("error collecting parameters: %w", err)
 		}
 		// Merge collected values
 		for k, v := range collectedValues {
@@ -348,6 +527,12 @@ func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValue
 		}
 	}
 
+	return paramValues, nil
+}
+
+// substituteParams replaces named parameters in sql with DB-specific
+// placeholders and returns the final SQL, its args and the display SQL.
+func substituteParams(sql string, paramValues map[string]string, conn db.DatabaseConnection) (string, []any, string, error) {
 	// Substitute parameters with DB-specific placeholders
 	finalSQL, args, err := params.SubstituteParameters(sql, paramValues, conn)
 	if err != nil {
@@ -370,7 +555,9 @@ func (a *App) processParameters(sql string, conn db.DatabaseConnection, cliValue
 // substituteOracleLiterals replaces :1, :2 placeholders with actual values for Oracle
 func substituteOracleLiterals(sql string, args []any) string {
 	result := sql
-	for i, arg := range args {
+	// Highest index first, so :1 does not match the start of :10
+	for i := len(args) - 1; i >= 0; i-- {
+		arg := args[i]
 		placeholder := fmt.Sprintf(":%d", i+1)
 		var value string
 		switch v := arg.(type) {
@@ -382,6 +569,13 @@ func substituteOracleLiterals(sql string, args []any) string {
 			value = fmt.Sprintf("%d", v)
 		case float32, float64:
 			value = fmt.Sprintf("%f", v)
+		case bool:
+			value = "0"
+			if v {
+				value = "1"
+			}
+		case time.Time:
+			value = fmt.Sprintf("TIMESTAMP '%s'", v.Format("2006-01-02 15:04:05"))
 		default:
 			// For other types, try to convert to string
 			value = fmt.Sprintf("'%v'", v)
@@ -392,11 +586,34 @@ func substituteOracleLiterals(sql string, args []any) string {
 }
 
 func (a *App) saveQueryFromTable(query db.Query) (db.Query, error) {
-	connName := a.config.CurrentConnection
+	return a.saveQueryToConnection(a.config.CurrentConnection, query)
+}
+
+// saveQueryCallback saves queries from the table into connName, which is not
+// the current connection in file mode.
+func (a *App) saveQueryCallback(connName string) func(db.Query) (db.Query, error) {
+	return func(query db.Query) (db.Query, error) {
+		return a.saveQueryToConnection(connName, query)
+	}
+}
+
+func (a *App) saveQueryToConnection(connName string, query db.Query) (db.Query, error) {
 	if connName == "" {
 		return db.Query{}, fmt.Errorf("no active connection")
 	}
 
+	// A query without SQL only updates metadata (e.g. a saved table view).
+	// It is looked up among the project queries too, so the view of a
+	// project query is written to its file rather than the config.
+	if query.SQL == "" {
+		existing, ok := a.config.Queries(connName)[query.Name]
+		if !ok {
+			return db.Query{}, fmt.Errorf("query '%s' not found", query.Name)
+		}
+		existing.Metadata = query.Metadata
+		query = existing
+	}
+
 	// Save query with auto-ID generation
 	savedQuery, err := a.config.SaveQueryToConnection(connName, query)
 	if err != nil {
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestParseRunFlags_Chart(t *testing.T) {
+	cases := []struct {
+		args []string
+		kind string
+	}{
+		{[]string{"sales", "--chart", "bar"}, "bar"},
+		{[]string{"sales", "--chart"}, "line"},
+		{[]string{"sales", "--chart", "--x", "day"}, "line"},
+		{[]string{"sales", "--format", "svg"}, "line"},
+		{[]string{"sales"}, ""},
+	}
+	for _, c := range cases {
+		flags := parseRunFlagsFrom(c.args)
+		if flags.Chart.Kind != c.kind {
+			t.Errorf("%v: kind = %q, want %q", c.args, flags.Chart.Kind, c.kind)
+		}
+		if flags.Chart.Enabled() != (c.kind != "") {
+			t.Errorf("%v: Enabled() = %v", c.args, flags.Chart.Enabled())
+		}
+		if flags.Selector != "sales" {
+			t.Errorf("%v: selector = %q", c.args, flags.Selector)
+		}
+	}
+}
+
+func TestParseParameterFlags_BareChart(t *testing.T) {
+	got := parseParameterFlagsFrom([]string{"sales", "--chart", "--region", "north"})
+	if len(got) != 1 || got["region"] != "north" {
+		t.Errorf("params = %v, want region=north", got)
+	}
+}
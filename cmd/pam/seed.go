@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/seed"
+	"github.com/caiolandgraf/pam/internal/spinner"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+type seedFlags struct {
+	rows       int
+	seed       uint64
+	seedSet    bool
+	outputFile string
+	format     string
+	batchSize  int
+}
+
+func parseSeedFlags() (seedFlags, []string) {
+	flags := seedFlags{rows: 100, batchSize: 500}
+	remainingArgs := []string{}
+	args := os.Args[2:]
+
+	rows := func(value string) int {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			printError("--rows must be a positive number, got %q", value)
+		}
+		return n
+	}
+	seedValue := func(value string) uint64 {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			printError("--seed must be a non-negative number, got %q", value)
+		}
+		return n
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--rows" || arg == "-n":
+			if i+1 >= len(args) {
+				printError("--rows requires a value")
+			}
+			flags.rows = rows(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--rows="):
+			flags.rows = rows(strings.TrimPrefix(arg, "--rows="))
+		case arg == "--seed":
+			if i+1 >= len(args) {
+				printError("--seed requires a value")
+			}
+			flags.seed, flags.seedSet = seedValue(args[i+1]), true
+			i++
+		case strings.HasPrefix(arg, "--seed="):
+			flags.seed, flags.seedSet = seedValue(strings.TrimPrefix(arg, "--seed=")), true
+		case arg == "--output" || arg == "-o":
+			if i+1 >= len(args) {
+				printError("--output requires a value")
+			}
+			flags.outputFile = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--output="):
+			flags.outputFile = strings.TrimPrefix(arg, "--output=")
+		case arg == "--format" || arg == "-f":
+			if i+1 >= len(args) {
+				printError("--format requires a value")
+			}
+			flags.format = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			flags.format = strings.TrimPrefix(arg, "--format=")
+		case arg == "--batch-size":
+			if i+1 >= len(args) {
+				printError("--batch-size requires a value")
+			}
+			flags.batchSize = parseBatchSize(args[i+1])
+			i++
+		case strings.HasPrefix(arg, "--batch-size="):
+			flags.batchSize = parseBatchSize(strings.TrimPrefix(arg, "--batch-size="))
+		case !strings.HasPrefix(arg, "-"):
+			remainingArgs = append(remainingArgs, arg)
+		}
+	}
+
+	return flags, remainingArgs
+}
+
+func (a *App) handleSeed() {
+	if a.config.CurrentConnection == "" {
+		printError(
+			"No active connection. Use 'pam switch <connection>' or 'pam init' first",
+		)
+	}
+
+	flags, args := parseSeedFlags()
+	if len(args) == 0 {
+		fmt.Println("Usage: pam seed <table-name> [--rows N] [--seed N] [--output file] [--format sql|csv] [--batch-size N]")
+		os.Exit(1)
+	}
+	if flags.format == "" && flags.outputFile != "" {
+		flags.format = "sql"
+		if strings.EqualFold(filepath.Ext(flags.outputFile), ".csv") {
+			flags.format = "csv"
+		}
+	}
+	if flags.format != "" && flags.format != "sql" && flags.format != "csv" {
+		printError("Unknown seed format '%s'. Use sql or csv", flags.format)
+	}
+	if flags.format != "" && flags.outputFile == "" {
+		printError("--format needs --output; without it rows are inserted")
+	}
+	if !flags.seedSet {
+		flags.seed = uint64(time.Now().UnixNano() % 1000000)
+	}
+
+	conn := config.FromConnectionYaml(
+		a.config.Connections[a.config.CurrentConnection],
+	)
+	if err := conn.Open(); err != nil {
+		printError(
+			"Could not open connection to %s: %v",
+			a.config.CurrentConnection,
+			err,
+		)
+	}
+	defer conn.Close()
+
+	tableName := args[0]
+	plan, err := seed.New(conn, tableName, flags.rows, flags.seed)
+	if err != nil {
+		printError("Seed failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go spinner.CircleWaitWithTimer(done)
+	stop := func() {
+		done <- struct{}{}
+		fmt.Print("\r\033[2K")
+	}
+	start := time.Now()
+
+	if flags.outputFile == "" {
+		err = plan.Insert(flags.batchSize, func(t *seed.Table) {
+			fmt.Printf(
+				"\r\033[2K%s Seeded %d row(s) into %s\n",
+				styles.Success.Render("✓"),
+				t.Rows,
+				styles.Title.Render(t.Name),
+			)
+		})
+	} else {
+		err = writeSeedFile(plan, flags)
+	}
+	stop()
+	if err != nil {
+		printError("Seed failed: %v", err)
+	}
+
+	if flags.outputFile != "" {
+		for _, t := range plan.Tables {
+			fmt.Printf(
+				"%s Wrote %d row(s) for %s\n",
+				styles.Success.Render("✓"),
+				t.Rows,
+				styles.Title.Render(t.Name),
+			)
+		}
+		fmt.Printf("  Saved to %s\n", styles.Title.Render(flags.outputFile))
+	}
+	fmt.Printf(
+		"  Done in %s with seed %d (pass --seed %d for the same rows)\n",
+		time.Since(start).Round(time.Millisecond),
+		flags.seed,
+		flags.seed,
+	)
+}
+
+// writeSeedFile writes a plan's rows to the --output file
+func writeSeedFile(plan *seed.Plan, flags seedFlags) error {
+	f, err := os.Create(flags.outputFile)
+	if err != nil {
+		return err
+	}
+	if flags.format == "csv" {
+		err = plan.WriteCSV(f)
+	} else {
+		err = plan.WriteSQL(f, flags.batchSize)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(flags.outputFile)
+	}
+	return err
+}
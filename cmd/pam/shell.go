@@ -203,7 +203,7 @@ func (a *App) runFromArgsOpenConn(args []string, conn db.DatabaseConnection) err
 		resolved.Query = a.editQueryOrExit(resolved.Query)
 	}
 
-	a.saveIfNeeded(resolved)
+	a.saveIfNeeded(a.config.CurrentConnection, resolved)
 
 	paramFlags := parseParameterFlagsFrom(args)
 	positionalArgsSlice := parsePositionalArgsFrom(args, flags.Selector)
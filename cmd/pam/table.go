@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+	"github.com/caiolandgraf/pam/internal/tablewizard"
+)
+
+const tableUsage = "Usage: pam table <create|rename|truncate|drop|clone> <table> [args] [--yes]"
+
+func (a *App) handleTable() {
+	if a.config.CurrentConnection == "" {
+		printError(
+			"No active connection. Use 'pam switch <connection>' or 'pam init' first",
+		)
+	}
+
+	var positional []string
+	var yes, schemaOnly bool
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--yes", "-y":
+			yes = true
+		case "--schema-only":
+			schemaOnly = true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) < 2 {
+		printError("%s", tableUsage)
+	}
+	action, tableName := positional[0], positional[1]
+
+	connYAML := a.config.Connections[a.config.CurrentConnection]
+	conn := config.FromConnectionYaml(connYAML)
+	if err := conn.Open(); err != nil {
+		printError(
+			"Could not open connection to %s/%s: %s",
+			conn.GetDbType(),
+			conn.GetName(),
+			err,
+		)
+	}
+	defer conn.Close()
+
+	tables, err := conn.GetTables()
+	if err != nil {
+		printError("Could not list tables: %v", err)
+	}
+	exists := func(name string) bool {
+		return slices.ContainsFunc(tables, func(t string) bool {
+			return strings.EqualFold(t, name)
+		})
+	}
+
+	var sql, done string
+	destructive := false
+	switch action {
+	case "create":
+		if exists(tableName) {
+			printError("Table '%s' already exists", tableName)
+		}
+		_, sql, err = tablewizard.CollectTableDefinition(tableName, conn, tables)
+		if err != nil {
+			if err == tablewizard.ErrAborted {
+				fmt.Println(styles.Faint.Render("Aborted"))
+				return
+			}
+			printError("Failed to collect table definition: %v", err)
+		}
+		// The wizard already showed the statement for review
+		yes = true
+		done = fmt.Sprintf("Created table '%s'", tableName)
+
+	case "rename":
+		if len(positional) < 3 {
+			printError("Usage: pam table rename <table> <new-name>")
+		}
+		newName := positional[2]
+		requireTable(exists, tableName)
+		if exists(newName) {
+			printError("Table '%s' already exists", newName)
+		}
+		sql = conn.BuildRenameTableSQL(tableName, newName)
+		done = fmt.Sprintf("Renamed table '%s' to '%s'", tableName, newName)
+		destructive = true
+
+	case "truncate":
+		requireTable(exists, tableName)
+		sql = conn.BuildTruncateTableSQL(tableName)
+		done = fmt.Sprintf("Truncated table '%s'", tableName)
+		destructive = true
+
+	case "drop":
+		requireTable(exists, tableName)
+		sql = conn.BuildDropTableSQL(tableName)
+		done = fmt.Sprintf("Dropped table '%s'", tableName)
+		destructive = true
+
+	case "clone":
+		if len(positional) < 3 {
+			printError("Usage: pam table clone <table> <new-name> [--schema-only]")
+		}
+		target := positional[2]
+		requireTable(exists, tableName)
+		if exists(target) {
+			printError("Table '%s' already exists", target)
+		}
+		sql = conn.BuildCloneTableSQL(tableName, target, !schemaOnly)
+		done = fmt.Sprintf("Cloned table '%s' to '%s'", tableName, target)
+
+	default:
+		printError("Unknown table action '%s'. %s", action, tableUsage)
+	}
+
+	statements := db.SplitSQLStatements(sql)
+	if len(statements) == 0 {
+		// Builders explain unsupported operations in a comment
+		printError(
+			"%s",
+			strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(sql), "--")),
+		)
+	}
+
+	if action != "create" {
+		fmt.Println(styles.Faint.Render(
+			fmt.Sprintf("%s/%s", conn.GetDbType(), conn.GetName()),
+		))
+		fmt.Println(sql)
+		fmt.Println()
+	}
+
+	switch {
+	case destructive && connYAML.Protected:
+		if yes {
+			printError(
+				"Connection '%s' is protected; --yes is not allowed for %s",
+				conn.GetName(),
+				action,
+			)
+		}
+		if !confirmTableName(tableName) {
+			fmt.Println(styles.Faint.Render("Aborted"))
+			return
+		}
+	case !yes:
+		if !confirmTableAction(action, tableName) {
+			fmt.Println(styles.Faint.Render("Aborted"))
+			return
+		}
+	}
+
+	for _, stmt := range statements {
+		if err := conn.Exec(stmt); err != nil {
+			printError("Could not %s table '%s': %v", action, tableName, err)
+		}
+	}
+
+	fmt.Println(styles.Success.Render("✓ " + done))
+}
+
+func requireTable(exists func(string) bool, tableName string) {
+	if !exists(tableName) {
+		printError("Table '%s' does not exist", tableName)
+	}
+}
+
+func confirmTableAction(action, tableName string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf(
+		"%s",
+		styles.Error.Render(
+			fmt.Sprintf("This will %s table '%s'. Continue? [y/N]: ", action, tableName),
+		),
+	)
+
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// confirmTableName asks for the table name to be typed back, the safeguard
+// for destructive operations on protected connections
+func confirmTableName(tableName string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf(
+		"%s",
+		styles.Error.Render(
+			fmt.Sprintf(
+				"This connection is protected. Type the table name (%s) to continue: ",
+				tableName,
+			),
+		),
+	)
+
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(response) == tableName
+}
@@ -0,0 +1,328 @@
+// Package chart turns result sets into line, bar and sparkline charts,
+// rendered for the terminal or as SVG.
+package chart
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/profile"
+)
+
+type Kind string
+
+const (
+	Line      Kind = "line"
+	Bar       Kind = "bar"
+	Sparkline Kind = "sparkline"
+)
+
+// Kinds lists the chart kinds in the order the TUI cycles through them
+var Kinds = []Kind{Line, Bar, Sparkline}
+
+func ParseKind(s string) (Kind, error) {
+	switch strings.ToLower(s) {
+	case "line", "":
+		return Line, nil
+	case "bar":
+		return Bar, nil
+	case "sparkline", "spark":
+		return Sparkline, nil
+	default:
+		return "", fmt.Errorf("unknown chart type '%s' (supported: line, bar, sparkline)", s)
+	}
+}
+
+// Series is one y column. Values are NaN where the cell is NULL.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Data is a result set reduced to an x axis and numeric series. X holds
+// the position of each point: Unix seconds for time axes, the value for
+// numeric axes and the row index otherwise.
+type Data struct {
+	XName    string
+	Labels   []string
+	X        []float64
+	Temporal bool
+	NumericX bool
+	Series   []Series
+}
+
+// timeLayouts are tried in order to recognize date/time x values. The last
+// one is how time.Time values are formatted in result sets.
+var timeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01",
+}
+
+func parseTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func isTimeType(typeName string) bool {
+	t := strings.ToLower(typeName)
+	return strings.Contains(t, "date") || strings.Contains(t, "time")
+}
+
+func parseValue(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return f
+}
+
+// isNumericColumn trusts the driver's column type when there is one and
+// falls back to the values for untyped columns (SQLite expressions).
+func isNumericColumn(typeName string, data [][]string, col int) bool {
+	if typeName != "" {
+		return profile.IsNumericType(typeName)
+	}
+	seen := false
+	for _, row := range data {
+		if row[col] == "NULL" {
+			continue
+		}
+		if math.IsNaN(parseValue(row[col])) {
+			return false
+		}
+		seen = true
+	}
+	return seen
+}
+
+func isTimeColumn(typeName string, data [][]string, col int) bool {
+	if isTimeType(typeName) {
+		return true
+	}
+	if typeName != "" && profile.IsNumericType(typeName) {
+		return false
+	}
+	seen := false
+	for _, row := range data {
+		if row[col] == "NULL" {
+			continue
+		}
+		if _, ok := parseTime(row[col]); !ok {
+			return false
+		}
+		seen = true
+	}
+	return seen
+}
+
+// Build picks the x column and numeric y columns out of a result set. An
+// empty x uses the first date/time or text column; no ys plots every other
+// numeric column.
+func Build(columns, columnTypes []string, data [][]string, x string, ys []string) (Data, error) {
+	typeOf := func(col int) string {
+		if col < len(columnTypes) {
+			return columnTypes[col]
+		}
+		return ""
+	}
+	find := func(name string) (int, error) {
+		for i, c := range columns {
+			if strings.EqualFold(c, name) {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("column '%s' not found (columns: %s)", name, strings.Join(columns, ", "))
+	}
+
+	xCol := -1
+	if x != "" {
+		i, err := find(x)
+		if err != nil {
+			return Data{}, err
+		}
+		xCol = i
+	} else {
+		for i := range columns {
+			if isTimeColumn(typeOf(i), data, i) || !isNumericColumn(typeOf(i), data, i) {
+				xCol = i
+				break
+			}
+		}
+	}
+
+	var yCols []int
+	if len(ys) > 0 {
+		for _, y := range ys {
+			i, err := find(strings.TrimSpace(y))
+			if err != nil {
+				return Data{}, err
+			}
+			if !isNumericColumn(typeOf(i), data, i) {
+				return Data{}, fmt.Errorf("column '%s' is not numeric (%s)", columns[i], typeOf(i))
+			}
+			yCols = append(yCols, i)
+		}
+	} else {
+		for i := range columns {
+			if i != xCol && !isTimeColumn(typeOf(i), data, i) &&
+				isNumericColumn(typeOf(i), data, i) {
+				yCols = append(yCols, i)
+			}
+		}
+	}
+	if len(yCols) == 0 {
+		return Data{}, fmt.Errorf("no numeric column to plot")
+	}
+
+	d := Data{}
+	rows := make([]int, len(data))
+	for i := range rows {
+		rows[i] = i
+	}
+
+	if xCol >= 0 {
+		d.XName = columns[xCol]
+		switch {
+		case isTimeColumn(typeOf(xCol), data, xCol):
+			d.Temporal = true
+			times := make([]float64, len(data))
+			for i, row := range data {
+				t, ok := parseTime(row[xCol])
+				if !ok {
+					times[i] = math.NaN()
+					continue
+				}
+				times[i] = float64(t.UnixNano()) / 1e9
+			}
+			rows = withoutNaN(rows, times)
+			sort.SliceStable(rows, func(a, b int) bool {
+				return times[rows[a]] < times[rows[b]]
+			})
+			for _, r := range rows {
+				d.X = append(d.X, times[r])
+			}
+		case isNumericColumn(typeOf(xCol), data, xCol):
+			d.NumericX = true
+			values := make([]float64, len(data))
+			for i, row := range data {
+				values[i] = parseValue(row[xCol])
+			}
+			rows = withoutNaN(rows, values)
+			sort.SliceStable(rows, func(a, b int) bool {
+				return values[rows[a]] < values[rows[b]]
+			})
+			for _, r := range rows {
+				d.X = append(d.X, values[r])
+			}
+		}
+		for _, r := range rows {
+			d.Labels = append(d.Labels, data[r][xCol])
+		}
+	} else {
+		for i := range rows {
+			d.Labels = append(d.Labels, strconv.Itoa(i+1))
+		}
+	}
+	if d.X == nil {
+		for i := range rows {
+			d.X = append(d.X, float64(i))
+		}
+	}
+
+	for _, c := range yCols {
+		s := Series{Name: columns[c], Values: make([]float64, len(rows))}
+		for i, r := range rows {
+			s.Values[i] = parseValue(data[r][c])
+		}
+		d.Series = append(d.Series, s)
+	}
+
+	if len(d.X) == 0 {
+		return Data{}, fmt.Errorf("no rows to plot")
+	}
+	return d, nil
+}
+
+func withoutNaN(rows []int, values []float64) []int {
+	var kept []int
+	for _, r := range rows {
+		if !math.IsNaN(values[r]) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// yRange returns the min and max over every series, widened when flat so
+// scaling never divides by zero.
+func (d Data) yRange() (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range d.Series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v) {
+				lo = math.Min(lo, v)
+				hi = math.Max(hi, v)
+			}
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	if hi == lo {
+		return lo - 1, hi + 1
+	}
+	return lo, hi
+}
+
+func (d Data) xRange() (float64, float64) {
+	lo, hi := d.X[0], d.X[len(d.X)-1]
+	if hi == lo {
+		return lo - 1, hi + 1
+	}
+	return lo, hi
+}
+
+// labelAt renders an x position as an axis label.
+func (d Data) labelAt(x float64) string {
+	switch {
+	case d.Temporal:
+		t := time.Unix(0, int64(x*1e9)).UTC()
+		midnight := t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+		if lo, hi := d.xRange(); hi-lo < 2*86400 && !midnight {
+			return t.Format("01-02 15:04")
+		}
+		return t.Format("2006-01-02")
+	case d.NumericX:
+		return profile.FormatFloat(x)
+	}
+	i := min(max(int(math.Round(x)), 0), len(d.Labels)-1)
+	return d.Labels[i]
+}
+
+// formatY renders a y axis label compactly (12.5k, 3.2M).
+func formatY(v float64) string {
+	abs := math.Abs(v)
+	switch {
+	case abs >= 1e9:
+		return strconv.FormatFloat(v/1e9, 'f', 1, 64) + "G"
+	case abs >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', 1, 64) + "M"
+	case abs >= 1e4:
+		return strconv.FormatFloat(v/1e3, 'f', 1, 64) + "k"
+	}
+	return profile.FormatFloat(v)
+}
@@ -0,0 +1,110 @@
+package chart
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBuild_TimeAxis(t *testing.T) {
+	d, err := Build(
+		[]string{"day", "total", "region"},
+		[]string{"DATE", "NUMERIC", "TEXT"},
+		[][]string{
+			{"2026-01-03", "30", "n"},
+			{"2026-01-01", "10", "s"},
+			{"2026-01-02", "NULL", "n"},
+		},
+		"",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !d.Temporal || d.XName != "day" {
+		t.Fatalf("x axis = %q temporal=%v, want day as time axis", d.XName, d.Temporal)
+	}
+	if len(d.Series) != 1 || d.Series[0].Name != "total" {
+		t.Fatalf("series = %+v, want only total (region is text)", d.Series)
+	}
+	v := d.Series[0].Values
+	if v[0] != 10 || !math.IsNaN(v[1]) || v[2] != 30 {
+		t.Errorf("values should be sorted by day with NULL as NaN, got %v", v)
+	}
+	if got := d.labelAt(d.X[2]); got != "2026-01-03" {
+		t.Errorf("label = %q", got)
+	}
+}
+
+func TestBuild_TypedAndUntypedColumns(t *testing.T) {
+	// "01310" is a zip code: typed as text it must not be plotted
+	_, err := Build(
+		[]string{"city", "zip"},
+		[]string{"TEXT", "VARCHAR"},
+		[][]string{{"a", "01310"}},
+		"city",
+		[]string{"zip"},
+	)
+	if err == nil {
+		t.Error("a VARCHAR column should not be accepted as y")
+	}
+
+	// Untyped columns (SQLite expressions) fall back to their values
+	d, err := Build(
+		[]string{"status", "n"},
+		[]string{"TEXT", ""},
+		[][]string{{"paid", "3"}, {"failed", "1"}},
+		"",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Temporal || d.NumericX || d.labelAt(d.X[1]) != "failed" {
+		t.Errorf("categorical axis should keep row order and labels, got %+v", d)
+	}
+
+	if _, err := Build([]string{"a"}, nil, [][]string{{"1"}}, "missing", nil); err == nil {
+		t.Error("unknown x column should fail")
+	}
+}
+
+func TestRender(t *testing.T) {
+	d, err := Build(
+		[]string{"day", "total"},
+		[]string{"DATE", "INTEGER"},
+		[][]string{{"2026-01-01", "10"}, {"2026-01-02", "40"}, {"2026-01-03", "20"}},
+		"",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line := Render(Line, d, 60, 12)
+	if lines := strings.Count(line, "\n") + 1; lines != 12 {
+		t.Errorf("line chart has %d lines, want 12", lines)
+	}
+	for _, want := range []string{"40 ┤", "10 ┤", "2026-01-01", "2026-01-03", "total"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("line chart missing %q:\n%s", want, line)
+		}
+	}
+
+	bars := Render(Bar, d, 60, 12)
+	if strings.Count(bars, "│") != 3 || !strings.Contains(bars, "2026-01-02") {
+		t.Errorf("bar chart:\n%s", bars)
+	}
+
+	if got := sparkline([]float64{1, 2, math.NaN(), 8}, 10); got != "▁▂ █" {
+		t.Errorf("sparkline = %q", got)
+	}
+
+	svg := SVG(Line, d, 400, 200, "a < b")
+	for _, want := range []string{"<svg", "<polyline", "a &lt; b", "</svg>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg missing %q", want)
+		}
+	}
+}
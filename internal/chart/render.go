@@ -0,0 +1,371 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// seriesStyle colors the i-th series from the active color scheme.
+func seriesStyle(i int) lipgloss.Style {
+	colors := []string{
+		styles.ActiveScheme.Primary,
+		styles.ActiveScheme.Accent,
+		styles.ActiveScheme.Success,
+		styles.ActiveScheme.Error,
+		"13",
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(colors[i%len(colors)]))
+}
+
+// Render draws the chart in a width × height block of terminal cells.
+func Render(kind Kind, d Data, width, height int) string {
+	width = max(width, 20)
+	height = max(height, 5)
+
+	switch kind {
+	case Bar:
+		return renderBar(d, width, height)
+	case Sparkline:
+		return renderSparkline(d, width)
+	default:
+		return renderLine(d, width, height)
+	}
+}
+
+func legend(d Data) string {
+	var parts []string
+	for i, s := range d.Series {
+		parts = append(parts, seriesStyle(i).Render("■")+" "+s.Name)
+	}
+	return strings.Join(parts, "   ")
+}
+
+// brailleBits maps a dot inside a 2×4 braille cell to its bit
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+type canvas struct {
+	cols, rows int
+	cells      [][]rune
+	owner      [][]int
+}
+
+func newCanvas(cols, rows int) *canvas {
+	c := &canvas{cols: cols, rows: rows}
+	c.cells = make([][]rune, rows)
+	c.owner = make([][]int, rows)
+	for r := range c.cells {
+		c.cells[r] = make([]rune, cols)
+		c.owner[r] = make([]int, cols)
+	}
+	return c
+}
+
+func (c *canvas) set(x, y, series int) {
+	if x < 0 || y < 0 || x >= c.cols*2 || y >= c.rows*4 {
+		return
+	}
+	c.cells[y/4][x/2] |= brailleBits[y%4][x%2]
+	c.owner[y/4][x/2] = series
+}
+
+func (c *canvas) line(x0, y0, x1, y1, series int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		c.set(x0, y0, series)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func renderLine(d Data, width, height int) string {
+	lo, hi := d.yRange()
+	xlo, xhi := d.xRange()
+
+	labelWidth := 0
+	for _, v := range []float64{lo, hi, (lo + hi) / 2} {
+		labelWidth = max(labelWidth, len(formatY(v)))
+	}
+	plotW := width - labelWidth - 2
+	plotH := height - 3
+
+	c := newCanvas(plotW, plotH)
+	dotsX, dotsY := float64(plotW*2-1), float64(plotH*4-1)
+	for si, s := range d.Series {
+		prevX, prevY, havePrev := 0, 0, false
+		for i, v := range s.Values {
+			if math.IsNaN(v) {
+				havePrev = false
+				continue
+			}
+			x := int(math.Round((d.X[i] - xlo) / (xhi - xlo) * dotsX))
+			y := int(math.Round((hi - v) / (hi - lo) * dotsY))
+			if havePrev {
+				c.line(prevX, prevY, x, y, si)
+			} else {
+				c.set(x, y, si)
+			}
+			prevX, prevY, havePrev = x, y, true
+		}
+	}
+
+	var b strings.Builder
+	mid := plotH / 2
+	for r := 0; r < plotH; r++ {
+		label, tick := "", "│"
+		if r == 0 || r == mid || r == plotH-1 {
+			value := hi - float64(r)/float64(max(plotH-1, 1))*(hi-lo)
+			label, tick = formatY(value), "┤"
+		}
+		b.WriteString(styles.Faint.Render(fmt.Sprintf("%*s %s", labelWidth, label, tick)))
+		for col, cell := range c.cells[r] {
+			if cell == 0 {
+				b.WriteRune(' ')
+				continue
+			}
+			b.WriteString(seriesStyle(c.owner[r][col]).Render(string(0x2800 + cell)))
+		}
+		b.WriteString("\n")
+	}
+
+	pad := strings.Repeat(" ", labelWidth+1)
+	b.WriteString(styles.Faint.Render(pad + "└" + strings.Repeat("─", plotW)))
+	b.WriteString("\n")
+	b.WriteString(styles.Faint.Render(pad + " " + xAxisLabels(d, plotW)))
+	b.WriteString("\n")
+	b.WriteString(legend(d))
+	return b.String()
+}
+
+// xAxisLabels spreads the first, middle and last x labels over width.
+func xAxisLabels(d Data, width int) string {
+	xlo, xhi := d.xRange()
+	first, last := d.labelAt(d.X[0]), d.labelAt(d.X[len(d.X)-1])
+	if len(d.X) == 1 {
+		return first
+	}
+
+	line := []rune(strings.Repeat(" ", width))
+	place := func(s string, at int) {
+		r := []rune(s)
+		at = min(max(at, 0), width-len(r))
+		if at < 0 {
+			return
+		}
+		copy(line[at:], r)
+	}
+	place(first, 0)
+	place(last, width)
+
+	midX := xlo + (xhi-xlo)/2
+	if !d.Temporal && !d.NumericX {
+		midX = math.Round(midX)
+	}
+	midLabel := d.labelAt(midX)
+	midAt := width/2 - len([]rune(midLabel))/2
+	if midAt > len([]rune(first))+1 &&
+		midAt+len([]rune(midLabel)) < width-len([]rune(last))-1 {
+		place(midLabel, midAt)
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// eighths are partial blocks for sub-cell bar lengths
+var eighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+func bar(value, peak float64, width int) string {
+	if peak <= 0 || math.IsNaN(value) {
+		return ""
+	}
+	units := int(math.Round(math.Abs(value) / peak * float64(width*8)))
+	return strings.Repeat("█", units/8) + eighths[units%8]
+}
+
+func renderBar(d Data, width, height int) string {
+	rows := len(d.X)
+	perRow := len(d.Series)
+	shown := min(rows, max((height-2)/perRow, 1))
+
+	labelWidth := 0
+	for i := range shown {
+		labelWidth = max(labelWidth, len([]rune(d.labelAt(d.X[i]))))
+	}
+	labelWidth = min(labelWidth, 20)
+
+	peak := 0.0
+	valueWidth := 0
+	for _, s := range d.Series {
+		for _, v := range s.Values[:shown] {
+			if !math.IsNaN(v) {
+				peak = math.Max(peak, math.Abs(v))
+				valueWidth = max(valueWidth, len(formatY(v)))
+			}
+		}
+	}
+	barWidth := max(width-labelWidth-valueWidth-4, 1)
+
+	var b strings.Builder
+	for i := 0; i < shown; i++ {
+		for si, s := range d.Series {
+			label := ""
+			if si == 0 {
+				label = truncate(d.labelAt(d.X[i]), labelWidth)
+			}
+			value := "NULL"
+			if !math.IsNaN(s.Values[i]) {
+				value = formatY(s.Values[i])
+			}
+			style := seriesStyle(si)
+			if s.Values[i] < 0 {
+				style = styles.Error
+			}
+			fmt.Fprintf(
+				&b,
+				"%s %s %s %s\n",
+				styles.Faint.Render(fmt.Sprintf("%*s", labelWidth, label)),
+				styles.Faint.Render("│"),
+				style.Render(bar(s.Values[i], peak, barWidth)),
+				value,
+			)
+		}
+	}
+	if shown < rows {
+		b.WriteString(styles.Faint.Render(fmt.Sprintf("… %d more rows", rows-shown)))
+		b.WriteString("\n")
+	}
+	b.WriteString(legend(d))
+	return b.String()
+}
+
+// sparkLevels are the eight heights of a sparkline cell
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// resample averages values into at most n buckets, keeping NaN for buckets
+// without any value.
+func resample(values []float64, n int) []float64 {
+	if len(values) <= n {
+		return values
+	}
+	out := make([]float64, n)
+	for i := range out {
+		from, to := i*len(values)/n, (i+1)*len(values)/n
+		sum, count := 0.0, 0
+		for _, v := range values[from:to] {
+			if !math.IsNaN(v) {
+				sum += v
+				count++
+			}
+		}
+		out[i] = math.NaN()
+		if count > 0 {
+			out[i] = sum / float64(count)
+		}
+	}
+	return out
+}
+
+func sparkline(values []float64, width int) string {
+	points := resample(values, width)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range points {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range points {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkLevels[len(sparkLevels)/2])
+		default:
+			b.WriteRune(sparkLevels[int((v-lo)/(hi-lo)*float64(len(sparkLevels)-1))])
+		}
+	}
+	return b.String()
+}
+
+func renderSparkline(d Data, width int) string {
+	nameWidth, statsWidth := 0, 0
+	stats := make([]string, len(d.Series))
+	for i, s := range d.Series {
+		nameWidth = max(nameWidth, len([]rune(s.Name)))
+
+		lo, hi, last := math.Inf(1), math.Inf(-1), math.NaN()
+		for _, v := range s.Values {
+			if !math.IsNaN(v) {
+				lo, hi, last = math.Min(lo, v), math.Max(hi, v), v
+			}
+		}
+		stats[i] = "no values"
+		if !math.IsNaN(last) {
+			stats[i] = fmt.Sprintf("min %s  max %s  last %s", formatY(lo), formatY(hi), formatY(last))
+		}
+		statsWidth = max(statsWidth, len(stats[i]))
+	}
+	nameWidth = min(nameWidth, 20)
+	// Every line gets the same width so the series line up in time
+	lineWidth := max(width-nameWidth-statsWidth-4, 5)
+
+	var b strings.Builder
+	for si, s := range d.Series {
+		line := sparkline(s.Values, lineWidth)
+		fmt.Fprintf(
+			&b,
+			"%-*s  %s%s  %s\n",
+			nameWidth,
+			truncate(s.Name, nameWidth),
+			seriesStyle(si).Render(line),
+			strings.Repeat(" ", lineWidth-len([]rune(line))),
+			styles.Faint.Render(stats[si]),
+		)
+	}
+	b.WriteString(styles.Faint.Render(
+		fmt.Sprintf("%d points, %s → %s", len(d.X), d.labelAt(d.X[0]), d.labelAt(d.X[len(d.X)-1])),
+	))
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,238 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// svgPalette is used instead of the terminal scheme, whose colors may be
+// ANSI codes a browser cannot render
+var svgPalette = []string{"#2563eb", "#f59e0b", "#16a34a", "#dc2626", "#9333ea"}
+
+const (
+	svgFont   = `font-family="sans-serif" font-size="12"`
+	svgMargin = 50
+)
+
+// SVG draws the chart as a standalone SVG document of width × height pixels.
+func SVG(kind Kind, d Data, width, height int, title string) string {
+	width = max(width, 200)
+	height = max(height, 120)
+
+	var b strings.Builder
+	fmt.Fprintf(
+		&b,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height,
+	)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	if title != "" {
+		fmt.Fprintf(
+			&b,
+			`<text x="%d" y="20" %s font-weight="bold">%s</text>`+"\n",
+			svgMargin, svgFont, html.EscapeString(title),
+		)
+	}
+
+	switch kind {
+	case Bar:
+		svgBar(&b, d, width, height)
+	case Sparkline:
+		svgSparkline(&b, d, width, height)
+	default:
+		svgLine(&b, d, width, height)
+	}
+
+	svgLegend(&b, d, height)
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func svgText(b *strings.Builder, x, y float64, anchor, text string) {
+	fmt.Fprintf(
+		b,
+		`<text x="%.1f" y="%.1f" %s fill="#555555" text-anchor="%s">%s</text>`+"\n",
+		x, y, svgFont, anchor, html.EscapeString(text),
+	)
+}
+
+func svgLegend(b *strings.Builder, d Data, height int) {
+	x := float64(svgMargin)
+	y := float64(height - 10)
+	for i, s := range d.Series {
+		fmt.Fprintf(
+			b,
+			`<rect x="%.1f" y="%.1f" width="10" height="10" fill="%s"/>`+"\n",
+			x, y-9, svgPalette[i%len(svgPalette)],
+		)
+		svgText(b, x+14, y, "start", s.Name)
+		x += float64(24 + 7*len(s.Name))
+	}
+}
+
+func svgLine(b *strings.Builder, d Data, width, height int) {
+	lo, hi := d.yRange()
+	xlo, xhi := d.xRange()
+
+	left, top := float64(svgMargin+20), 35.0
+	plotW := float64(width) - left - 20
+	plotH := float64(height) - top - 55
+	px := func(x float64) float64 { return left + (x-xlo)/(xhi-xlo)*plotW }
+	py := func(y float64) float64 { return top + (hi-y)/(hi-lo)*plotH }
+
+	for i := 0; i <= 4; i++ {
+		v := lo + float64(i)/4*(hi-lo)
+		fmt.Fprintf(
+			b,
+			`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e5e7eb"/>`+"\n",
+			left, py(v), left+plotW, py(v),
+		)
+		svgText(b, left-6, py(v)+4, "end", formatY(v))
+	}
+	fmt.Fprintf(
+		b,
+		`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#9ca3af"/>`+"\n",
+		left, top+plotH, left+plotW, top+plotH,
+	)
+
+	ticks := min(len(d.X), 5)
+	for i := 0; i < ticks; i++ {
+		x := xlo + float64(i)/float64(max(ticks-1, 1))*(xhi-xlo)
+		if !d.Temporal && !d.NumericX {
+			x = math.Round(x)
+		}
+		anchor := "middle"
+		switch {
+		case i == 0:
+			anchor = "start"
+		case i == ticks-1:
+			anchor = "end"
+		}
+		svgText(b, px(x), top+plotH+18, anchor, d.labelAt(x))
+	}
+
+	for si, s := range d.Series {
+		color := svgPalette[si%len(svgPalette)]
+		// NULLs split a series into separate runs
+		var run [][2]float64
+		flush := func() {
+			switch len(run) {
+			case 0:
+			case 1:
+				fmt.Fprintf(
+					b,
+					`<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s"/>`+"\n",
+					run[0][0], run[0][1], color,
+				)
+			default:
+				fmt.Fprintf(
+					b,
+					`<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`+"\n",
+					color, svgPoints(run),
+				)
+			}
+			run = nil
+		}
+		for i, v := range s.Values {
+			if math.IsNaN(v) {
+				flush()
+				continue
+			}
+			run = append(run, [2]float64{px(d.X[i]), py(v)})
+		}
+		flush()
+	}
+}
+
+func svgPoints(points [][2]float64) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+	}
+	return strings.Join(parts, " ")
+}
+
+func svgBar(b *strings.Builder, d Data, width, height int) {
+	rows := len(d.X)
+	perRow := len(d.Series)
+
+	labelWidth := 0
+	for _, x := range d.X {
+		labelWidth = max(labelWidth, len([]rune(truncate(d.labelAt(x), 20))))
+	}
+	left := float64(20 + labelWidth*7)
+	top := 35.0
+	plotW := float64(width) - left - 70
+	plotH := float64(height) - top - 30
+	rowH := plotH / float64(rows*perRow)
+
+	peak := 0.0
+	for _, s := range d.Series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v) {
+				peak = math.Max(peak, math.Abs(v))
+			}
+		}
+	}
+	if peak == 0 {
+		peak = 1
+	}
+
+	for i := 0; i < rows; i++ {
+		groupTop := top + float64(i*perRow)*rowH
+		svgText(b, left-6, groupTop+float64(perRow)*rowH/2+4, "end", truncate(d.labelAt(d.X[i]), 20))
+		for si, s := range d.Series {
+			v := s.Values[i]
+			if math.IsNaN(v) {
+				continue
+			}
+			w := math.Abs(v) / peak * plotW
+			y := groupTop + float64(si)*rowH
+			fmt.Fprintf(
+				b,
+				`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n",
+				left, y+rowH*0.1, w, rowH*0.8, svgPalette[si%len(svgPalette)],
+			)
+			if rowH >= 10 {
+				svgText(b, left+w+4, y+rowH/2+4, "start", formatY(v))
+			}
+		}
+	}
+	fmt.Fprintf(
+		b,
+		`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#9ca3af"/>`+"\n",
+		left, top, left, top+plotH,
+	)
+}
+
+func svgSparkline(b *strings.Builder, d Data, width, height int) {
+	top := 35.0
+	rowH := (float64(height) - top - 30) / float64(len(d.Series))
+	left := float64(svgMargin + 80)
+	plotW := float64(width) - left - 20
+
+	xlo, xhi := d.xRange()
+	for si, s := range d.Series {
+		lo, hi := Data{Series: []Series{s}}.yRange()
+		y0 := top + float64(si)*rowH
+		svgText(b, left-10, y0+rowH/2+4, "end", truncate(s.Name, 20))
+
+		var points [][2]float64
+		for i, v := range s.Values {
+			if math.IsNaN(v) {
+				continue
+			}
+			points = append(points, [2]float64{
+				left + (d.X[i]-xlo)/(xhi-xlo)*plotW,
+				y0 + rowH*0.15 + (hi-v)/(hi-lo)*rowH*0.7,
+			})
+		}
+		fmt.Fprintf(
+			b,
+			`<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`+"\n",
+			svgPalette[si%len(svgPalette)], svgPoints(points),
+		)
+	}
+}
@@ -0,0 +1,417 @@
+// Package check runs saved queries as data-quality checks. A query tagged
+// "check" carries an assert expression describing what its result should
+// look like; an empty one means the query must return no rows:
+//
+//	rows = 0
+//	rows between 1 and 500
+//	amount >= 0; status != 'void'
+//	snapshot
+//
+// A column assertion must hold on every row, and a NULL value fails it.
+// snapshot compares the result with an expected result saved earlier.
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// Tag marks a saved query as a check
+const Tag = "check"
+
+// MaxRows caps the offending rows kept per failure; Failure.Total still
+// counts all of them
+const MaxRows = 50
+
+// IsCheck reports whether q is tagged as a check
+func IsCheck(q db.Query) bool {
+	return slices.ContainsFunc(q.Tags, func(tag string) bool {
+		return strings.EqualFold(tag, Tag)
+	})
+}
+
+type kind int
+
+const (
+	rowCount kind = iota
+	rowRange
+	columnValue
+	snapshot
+)
+
+// Assertion is one clause of an assert expression
+type Assertion struct {
+	// Text is the clause as written
+	Text string
+
+	kind      kind
+	column    string
+	op        string
+	low, high float64
+	value     string
+	number    bool
+}
+
+var (
+	rangePattern      = regexp.MustCompile(`(?i)^rows\s+between\s+(\d+)\s+and\s+(\d+)$`)
+	comparisonPattern = regexp.MustCompile(`^("[^"]+"|[A-Za-z_][\w.]*)\s*(<=|>=|!=|<>|=|<|>)\s*(.+)$`)
+)
+
+// Parse reads an assert expression: clauses separated by semicolons, all of
+// which must hold. An empty expression asserts that no rows come back.
+func Parse(expr string) ([]Assertion, error) {
+	var assertions []Assertion
+	for _, clause := range strings.Split(expr, ";") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		a, err := parseClause(clause)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+	if len(assertions) == 0 {
+		return []Assertion{{Text: "rows = 0", kind: rowCount, op: "=", value: "0", number: true}}, nil
+	}
+	return assertions, nil
+}
+
+func parseClause(clause string) (Assertion, error) {
+	a := Assertion{Text: clause}
+	if strings.EqualFold(clause, "snapshot") {
+		a.kind = snapshot
+		return a, nil
+	}
+	if m := rangePattern.FindStringSubmatch(clause); m != nil {
+		a.kind = rowRange
+		a.low, _ = strconv.ParseFloat(m[1], 64)
+		a.high, _ = strconv.ParseFloat(m[2], 64)
+		if a.low > a.high {
+			return a, fmt.Errorf("%q: the lower bound is above the upper one", clause)
+		}
+		return a, nil
+	}
+
+	m := comparisonPattern.FindStringSubmatch(clause)
+	if m == nil {
+		return a, fmt.Errorf(
+			"%q: expected rows <op> N, rows between A and B, <column> <op> <value> or snapshot",
+			clause,
+		)
+	}
+	a.op = m[2]
+	if a.op == "<>" {
+		a.op = "!="
+	}
+	value := strings.TrimSpace(m[3])
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		a.value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	} else if n, err := strconv.ParseFloat(value, 64); err == nil {
+		a.value, a.low, a.number = value, n, true
+	} else {
+		return a, fmt.Errorf("%q: %s is neither a number nor a 'quoted' string", clause, value)
+	}
+
+	if strings.EqualFold(m[1], "rows") {
+		if !a.number {
+			return a, fmt.Errorf("%q: a row count is compared with a number", clause)
+		}
+		a.kind = rowCount
+		return a, nil
+	}
+	a.kind = columnValue
+	a.column = strings.Trim(m[1], `"`)
+	return a, nil
+}
+
+// NeedsSnapshot reports whether any assertion compares with a snapshot
+func NeedsSnapshot(assertions []Assertion) bool {
+	return slices.ContainsFunc(assertions, func(a Assertion) bool {
+		return a.kind == snapshot
+	})
+}
+
+// Failure is a violated assertion and the rows that violate it
+type Failure struct {
+	Assertion string
+	Message   string
+	Columns   []string
+	Rows      [][]string
+	// Total counts the offending rows, including those past MaxRows
+	Total int
+}
+
+func (f *Failure) add(row []string) {
+	f.Total++
+	if len(f.Rows) < MaxRows {
+		f.Rows = append(f.Rows, row)
+	}
+}
+
+// Evaluate checks a result against the assertions. expected is the saved
+// snapshot, nil when there is none.
+func Evaluate(assertions []Assertion, columns []string, rows [][]string, expected *Snapshot) ([]Failure, error) {
+	var failures []Failure
+	for _, a := range assertions {
+		f, err := a.evaluate(columns, rows, expected)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			failures = append(failures, *f)
+		}
+	}
+	return failures, nil
+}
+
+func (a Assertion) evaluate(columns []string, rows [][]string, expected *Snapshot) (*Failure, error) {
+	count := float64(len(rows))
+	switch a.kind {
+	case rowCount:
+		if compareNumbers(count, a.op, a.low) {
+			return nil, nil
+		}
+		f := &Failure{
+			Assertion: a.Text,
+			Message:   fmt.Sprintf("expected rows %s %s, got %d", a.op, a.value, len(rows)),
+		}
+		// Too many rows: the rows themselves are what's wrong
+		if count > a.low || (a.op == "<" && count == a.low) {
+			f.Columns = columns
+			for _, row := range rows {
+				f.add(row)
+			}
+		}
+		return f, nil
+
+	case rowRange:
+		if count >= a.low && count <= a.high {
+			return nil, nil
+		}
+		f := &Failure{
+			Assertion: a.Text,
+			Message: fmt.Sprintf(
+				"expected between %g and %g rows, got %d", a.low, a.high, len(rows),
+			),
+		}
+		if count > a.high {
+			f.Columns = columns
+			for _, row := range rows {
+				f.add(row)
+			}
+		}
+		return f, nil
+
+	case columnValue:
+		index := slices.IndexFunc(columns, func(c string) bool {
+			return strings.EqualFold(c, a.column)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("%q: the result has no column %s", a.Text, a.column)
+		}
+		f := &Failure{Assertion: a.Text, Columns: columns}
+		for _, row := range rows {
+			if !a.holds(row[index]) {
+				f.add(row)
+			}
+		}
+		if f.Total == 0 {
+			return nil, nil
+		}
+		f.Message = fmt.Sprintf("%d row(s) where %s is not %s %s", f.Total, a.column, a.op, a.value)
+		return f, nil
+
+	case snapshot:
+		return diffSnapshot(a.Text, expected, columns, rows), nil
+	}
+	return nil, nil
+}
+
+// holds tests one cell; NULL and, for a number, anything non-numeric fail
+func (a Assertion) holds(cell string) bool {
+	if cell == "NULL" {
+		return false
+	}
+	if a.number {
+		n, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+		return err == nil && compareNumbers(n, a.op, a.low)
+	}
+	c := strings.Compare(cell, a.value)
+	switch a.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func compareNumbers(n float64, op string, want float64) bool {
+	switch op {
+	case "=":
+		return n == want
+	case "!=":
+		return n != want
+	case "<":
+		return n < want
+	case "<=":
+		return n <= want
+	case ">":
+		return n > want
+	case ">=":
+		return n >= want
+	}
+	return false
+}
+
+// diffSnapshot compares rows with the snapshot as multisets, so row order
+// doesn't matter. Offending rows carry a leading diff column: "-" for an
+// expected row that is gone, "+" for one that is new.
+func diffSnapshot(text string, expected *Snapshot, columns []string, rows [][]string) *Failure {
+	if expected == nil {
+		return &Failure{
+			Assertion: text,
+			Message:   "no snapshot saved yet; run pam check --update-snapshots",
+		}
+	}
+	if !slices.Equal(expected.Columns, columns) {
+		return &Failure{
+			Assertion: text,
+			Message: fmt.Sprintf(
+				"columns changed from %s to %s",
+				strings.Join(expected.Columns, ", "),
+				strings.Join(columns, ", "),
+			),
+		}
+	}
+
+	key := func(row []string) string { return strings.Join(row, "\x00") }
+	remaining := map[string]int{}
+	for _, row := range rows {
+		remaining[key(row)]++
+	}
+	f := &Failure{Assertion: text, Columns: append([]string{"diff"}, columns...)}
+	for _, row := range expected.Rows {
+		if remaining[key(row)] > 0 {
+			remaining[key(row)]--
+			continue
+		}
+		f.add(append([]string{"-"}, row...))
+	}
+	for _, row := range rows {
+		if remaining[key(row)] > 0 {
+			remaining[key(row)]--
+			f.add(append([]string{"+"}, row...))
+		}
+	}
+	if f.Total == 0 {
+		return nil
+	}
+	f.Message = fmt.Sprintf("%d row(s) differ from the snapshot", f.Total)
+	return f
+}
+
+// Check is a saved query to run as a check
+type Check struct {
+	Name string
+	SQL  string
+	Args []any
+	// Assert is the query's assert expression
+	Assert string
+	// SnapshotPath is where its expected result is kept
+	SnapshotPath string
+}
+
+// Result is the outcome of one check. Err is set when the check could not
+// be evaluated at all, such as on a bad expression or a failing query.
+type Result struct {
+	Name     string
+	SQL      string
+	Assert   string
+	Rows     int
+	Duration time.Duration
+	Failures []Failure
+	Err      error
+}
+
+// Result states, as reported
+const (
+	Passed  = "passed"
+	Failed  = "failed"
+	Errored = "error"
+)
+
+// Status is Passed, Failed or Errored
+func (r Result) Status() string {
+	switch {
+	case r.Err != nil:
+		return Errored
+	case len(r.Failures) > 0:
+		return Failed
+	}
+	return Passed
+}
+
+// Run executes a check's query and evaluates its assertions. With update,
+// the result is saved as the check's snapshot first, so it passes.
+func Run(conn db.DatabaseConnection, c Check, update bool) Result {
+	result := Result{Name: c.Name, SQL: c.SQL, Assert: c.Assert}
+	if result.Assert == "" {
+		result.Assert = "rows = 0"
+	}
+	assertions, err := Parse(c.Assert)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	start := time.Now()
+	columns, rows, err := query(conn, c.SQL, c.Args)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Rows = len(rows)
+
+	var expected *Snapshot
+	if NeedsSnapshot(assertions) {
+		if update {
+			if err := SaveSnapshot(c.SnapshotPath, Snapshot{Columns: columns, Rows: rows}); err != nil {
+				result.Err = err
+				return result
+			}
+		}
+		if expected, err = LoadSnapshot(c.SnapshotPath); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	result.Failures, result.Err = Evaluate(assertions, columns, rows, expected)
+	return result
+}
+
+func query(conn db.DatabaseConnection, sql string, args []any) ([]string, [][]string, error) {
+	rows, err := conn.ExecQuery(sql, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	return db.FormatTableData(rows)
+}
@@ -0,0 +1,195 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestParse(t *testing.T) {
+	assertions, err := Parse("")
+	if err != nil || len(assertions) != 1 || assertions[0].Text != "rows = 0" {
+		t.Fatalf("empty = %+v, %v", assertions, err)
+	}
+
+	assertions, err = Parse(`rows between 1 and 10; amount >= 0; "Status" <> 'void'; snapshot`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assertions) != 4 || !NeedsSnapshot(assertions) {
+		t.Fatalf("assertions = %+v", assertions)
+	}
+	if a := assertions[2]; a.column != "Status" || a.op != "!=" || a.value != "void" || a.number {
+		t.Errorf("text comparison = %+v", a)
+	}
+
+	for expr, want := range map[string]string{
+		"rows between 5 and 1": "lower bound",
+		"rows > 'a'":           "row count",
+		"amount >= zero":       "neither a number",
+		"whatever":             "expected rows",
+	} {
+		if _, err := Parse(expr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) err = %v, want %q", expr, err, want)
+		}
+	}
+}
+
+func evaluate(t *testing.T, expr string, columns []string, rows [][]string, expected *Snapshot) []Failure {
+	t.Helper()
+	assertions, err := Parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures, err := Evaluate(assertions, columns, rows, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return failures
+}
+
+func TestEvaluate(t *testing.T) {
+	columns := []string{"id", "amount", "status"}
+	rows := [][]string{
+		{"1", "10", "paid"},
+		{"2", "-5", "paid"},
+		{"3", "NULL", "void"},
+	}
+
+	if f := evaluate(t, "", columns, rows, nil); len(f) != 1 || f[0].Total != 3 {
+		t.Errorf("rows = 0 = %+v", f)
+	}
+	if f := evaluate(t, "rows between 1 and 3; rows >= 3", columns, rows, nil); len(f) != 0 {
+		t.Errorf("row count = %+v", f)
+	}
+	if f := evaluate(t, "rows > 5", columns, rows, nil); len(f) != 1 || len(f[0].Rows) != 0 {
+		t.Errorf("too few rows = %+v", f)
+	}
+
+	f := evaluate(t, "amount >= 0; status != 'void'", columns, rows, nil)
+	if len(f) != 2 {
+		t.Fatalf("failures = %+v", f)
+	}
+	if f[0].Total != 2 || f[0].Rows[0][0] != "2" || f[0].Rows[1][0] != "3" {
+		t.Errorf("amount >= 0 should flag the negative and NULL rows: %+v", f[0])
+	}
+	if f[1].Total != 1 || f[1].Rows[0][0] != "3" {
+		t.Errorf("status != 'void' = %+v", f[1])
+	}
+
+	assertions, _ := Parse("missing = 1")
+	if _, err := Evaluate(assertions, columns, rows, nil); err == nil {
+		t.Error("a column the result lacks should be an error")
+	}
+}
+
+func TestEvaluate_CapsRows(t *testing.T) {
+	var rows [][]string
+	for range MaxRows + 10 {
+		rows = append(rows, []string{"x"})
+	}
+	f := evaluate(t, "", []string{"a"}, rows, nil)
+	if len(f) != 1 || len(f[0].Rows) != MaxRows || f[0].Total != MaxRows+10 {
+		t.Errorf("kept %d of %d rows", len(f[0].Rows), f[0].Total)
+	}
+}
+
+func TestEvaluate_Snapshot(t *testing.T) {
+	columns := []string{"id", "name"}
+	expected := &Snapshot{Columns: columns, Rows: [][]string{{"1", "a"}, {"2", "b"}}}
+
+	if f := evaluate(t, "snapshot", columns, [][]string{{"2", "b"}, {"1", "a"}}, expected); len(f) != 0 {
+		t.Errorf("reordered rows should match: %+v", f)
+	}
+	if f := evaluate(t, "snapshot", columns, nil, nil); len(f) != 1 || !strings.Contains(f[0].Message, "no snapshot") {
+		t.Errorf("missing snapshot = %+v", f)
+	}
+	if f := evaluate(t, "snapshot", []string{"id"}, nil, expected); len(f) != 1 || !strings.Contains(f[0].Message, "columns changed") {
+		t.Errorf("changed columns = %+v", f)
+	}
+
+	f := evaluate(t, "snapshot", columns, [][]string{{"1", "a"}, {"3", "c"}}, expected)
+	if len(f) != 1 || f[0].Total != 2 {
+		t.Fatalf("diff = %+v", f)
+	}
+	got := strings.Join(f[0].Rows[0], ",") + " " + strings.Join(f[0].Rows[1], ",")
+	if got != "-,2,b +,3,c" || f[0].Columns[0] != "diff" {
+		t.Errorf("diff rows = %s", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	conn, err := db.NewSQLiteConnection("check", filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Exec("CREATE TABLE orders (id INTEGER, total INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec("INSERT INTO orders VALUES (1, 10), (2, -3)"); err != nil {
+		t.Fatal(err)
+	}
+
+	negative := Run(conn, Check{Name: "no_negative", SQL: "SELECT * FROM orders WHERE total < ?", Args: []any{0}}, false)
+	if negative.Status() != Failed || negative.Rows != 1 || negative.Assert != "rows = 0" {
+		t.Errorf("no_negative = %+v", negative)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshots", "orders.json")
+	orders := Check{Name: "orders", SQL: "SELECT * FROM orders ORDER BY id", Assert: "snapshot", SnapshotPath: path}
+	if r := Run(conn, orders, false); r.Status() != Failed {
+		t.Errorf("without a snapshot = %+v", r)
+	}
+	if r := Run(conn, orders, true); r.Status() != Passed {
+		t.Errorf("updating = %+v", r)
+	}
+	if err := conn.Exec("UPDATE orders SET total = 3 WHERE id = 2"); err != nil {
+		t.Fatal(err)
+	}
+	changed := Run(conn, orders, false)
+	if changed.Status() != Failed || changed.Failures[0].Total != 2 {
+		t.Errorf("after a change = %+v", changed)
+	}
+
+	broken := Run(conn, Check{Name: "broken", SQL: "SELECT * FROM nowhere"}, false)
+	if broken.Status() != Errored {
+		t.Errorf("broken = %+v", broken)
+	}
+
+	results := []Result{negative, changed, broken}
+	var out bytes.Buffer
+	if err := WriteJUnit(&out, "local", results); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(out.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Tests != 3 || suites.Failures != 2 || suites.Errors != 1 || len(suites.Suites[0].Cases) != 3 {
+		t.Errorf("junit = %+v", suites)
+	}
+	if failure := suites.Suites[0].Cases[0].Failure; failure == nil || !strings.Contains(failure.Body, "2\t-3") {
+		t.Errorf("junit failure should list the offending row: %+v", failure)
+	}
+
+	out.Reset()
+	if err := WriteJSON(&out, "local", results); err != nil {
+		t.Fatal(err)
+	}
+	var report jsonReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.Failed != 2 || report.Checks[2].Status != Errored || report.Checks[0].Failures[0].Rows[0][1] != "-3" {
+		t.Errorf("json = %+v", report)
+	}
+}
@@ -0,0 +1,257 @@
+package check
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Snapshot is the expected result of a check, kept as JSON
+type Snapshot struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// LoadSnapshot reads the snapshot at path, nil when there is none yet
+func LoadSnapshot(path string) (*Snapshot, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// SaveSnapshot writes s to path, creating its directory
+func SaveSnapshot(path string, s Snapshot) error {
+	if s.Rows == nil {
+		s.Rows = [][]string{}
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// Summary counts results by status
+type Summary struct {
+	Total    int           `json:"total"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Errors   int           `json:"errors"`
+	Duration time.Duration `json:"-"`
+}
+
+// Summarize counts results by status and adds up their durations
+func Summarize(results []Result) Summary {
+	s := Summary{Total: len(results)}
+	for _, r := range results {
+		s.Duration += r.Duration
+		switch r.Status() {
+		case Passed:
+			s.Passed++
+		case Failed:
+			s.Failed++
+		case Errored:
+			s.Errors++
+		}
+	}
+	return s
+}
+
+type jsonFailure struct {
+	Assertion string     `json:"assertion"`
+	Message   string     `json:"message"`
+	Columns   []string   `json:"columns,omitempty"`
+	Rows      [][]string `json:"rows,omitempty"`
+	Total     int        `json:"total_rows,omitempty"`
+}
+
+type jsonResult struct {
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`
+	Assert     string        `json:"assert"`
+	DurationMS float64       `json:"duration_ms"`
+	Rows       int           `json:"rows"`
+	Error      string        `json:"error,omitempty"`
+	Failures   []jsonFailure `json:"failures,omitempty"`
+	SQL        string        `json:"sql"`
+}
+
+type jsonReport struct {
+	Connection string       `json:"connection"`
+	Summary    Summary      `json:"summary"`
+	DurationMS float64      `json:"duration_ms"`
+	Checks     []jsonResult `json:"checks"`
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// WriteJSON writes the results as a JSON report
+func WriteJSON(w io.Writer, connection string, results []Result) error {
+	summary := Summarize(results)
+	report := jsonReport{
+		Connection: connection,
+		Summary:    summary,
+		DurationMS: milliseconds(summary.Duration),
+		Checks:     make([]jsonResult, 0, len(results)),
+	}
+	for _, r := range results {
+		jr := jsonResult{
+			Name:       r.Name,
+			Status:     r.Status(),
+			Assert:     r.Assert,
+			DurationMS: milliseconds(r.Duration),
+			Rows:       r.Rows,
+			SQL:        r.SQL,
+		}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+		for _, f := range r.Failures {
+			jr.Failures = append(jr.Failures, jsonFailure(f))
+		}
+		report.Checks = append(report.Checks, jr)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitText struct {
+	Body string `xml:",cdata"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",cdata"`
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteJUnit writes the results as JUnit XML, one testsuite per connection
+// and one testcase per check. A failure's body lists the offending rows.
+func WriteJUnit(w io.Writer, connection string, results []Result) error {
+	summary := Summarize(results)
+	suite := junitSuite{
+		Name:     connection,
+		Tests:    summary.Total,
+		Failures: summary.Failed,
+		Errors:   summary.Errors,
+		Time:     seconds(summary.Duration),
+	}
+	for _, r := range results {
+		tc := junitCase{
+			Name:      r.Name,
+			Classname: "pam.check." + connection,
+			Time:      seconds(r.Duration),
+			SystemOut: &junitText{Body: r.SQL},
+		}
+		switch r.Status() {
+		case Errored:
+			tc.Error = &junitMessage{Message: r.Err.Error(), Type: "error", Body: r.Err.Error()}
+		case Failed:
+			var messages []string
+			for _, f := range r.Failures {
+				messages = append(messages, f.Assertion+": "+f.Message)
+			}
+			tc.Failure = &junitMessage{
+				Message: strings.Join(messages, "; "),
+				Type:    "assertion",
+				Body:    FormatFailures(r.Failures),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	report := junitSuites{
+		Name:     "pam check",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// FormatFailures renders failures and their offending rows as plain text,
+// with the rows tab-separated under a header line
+func FormatFailures(failures []Failure) string {
+	var b strings.Builder
+	for i, f := range failures {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s: %s\n", f.Assertion, f.Message)
+		if len(f.Rows) == 0 {
+			continue
+		}
+		b.WriteString(strings.Join(f.Columns, "\t") + "\n")
+		for _, row := range f.Rows {
+			b.WriteString(strings.Join(row, "\t") + "\n")
+		}
+		if f.Total > len(f.Rows) {
+			fmt.Fprintf(&b, "... %d more row(s)\n", f.Total-len(f.Rows))
+		}
+	}
+	return b.String()
+}
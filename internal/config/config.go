@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/caiolandgraf/pam/internal/mask"
 	"github.com/caiolandgraf/pam/internal/styles"
 	"gopkg.in/yaml.v2"
 )
@@ -21,6 +22,7 @@ type Config struct {
 	DefaultRowLimit       int                         `yaml:"default_row_limit"`
 	DefaultColumnWidth    int                         `yaml:"default_column_width"`
 	UIVisibility          UIVisibility                `yaml:"ui_visibility"`
+	Masking               *mask.Config                `yaml:"masking,omitempty"`
 }
 
 type History struct {
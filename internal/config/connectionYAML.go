@@ -12,6 +12,8 @@ type ConnectionYAML struct {
 	DBType     string              `yaml:"db_type"`
 	ConnString string              `yaml:"conn_string"`
 	Schema     string              `yaml:"schema,omitempty"`
+	Groups     []string            `yaml:"groups,omitempty"`
+	Protected  bool                `yaml:"protected,omitempty"` // guards destructive table operations
 	Queries    map[string]db.Query `yaml:"queries"`
 	LastQuery  db.Query            `yaml:"last_query"`
 }
@@ -37,7 +39,7 @@ func FromConnectionYaml(yc *ConnectionYAML) db.DatabaseConnection {
 		)
 	}
 	conn.SetSchema(yc.Schema)
-	conn.SetQueries(yc.Queries)
+	conn.SetQueries(MergeQueries(yc.Name, yc.Queries))
 	conn.SetLastQuery(yc.LastQuery)
 	return conn
 }
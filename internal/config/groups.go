@@ -0,0 +1,31 @@
+package config
+
+import "sort"
+
+// GroupMembers returns the names of the connections tagged with group, sorted.
+func (c *Config) GroupMembers(group string) []string {
+	var members []string
+	for name, conn := range c.Connections {
+		for _, g := range conn.Groups {
+			if g == group {
+				members = append(members, name)
+				break
+			}
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// Groups maps every group name to its members.
+func (c *Config) Groups() map[string][]string {
+	groups := map[string][]string{}
+	for _, conn := range c.Connections {
+		for _, g := range conn.Groups {
+			if _, ok := groups[g]; !ok {
+				groups[g] = c.GroupMembers(g)
+			}
+		}
+	}
+	return groups
+}
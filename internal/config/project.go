@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/params"
+	"github.com/caiolandgraf/pam/internal/workspace"
+)
+
+// Project is the .pam workspace found from the working directory, or nil
+var Project *workspace.Workspace
+
+// LoadProject discovers the .pam workspace above dir. Query files that do
+// not parse are reported on stderr and skipped.
+func LoadProject(dir string) {
+	Project = nil
+	path := workspace.Find(dir)
+	if path == "" {
+		return
+	}
+	ws, errs := workspace.Load(path)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: skipping project query %v\n", err)
+	}
+	Project = ws
+}
+
+// MergeQueries returns the global queries of connection connName with the
+// project's added. A project query replaces a global one of the same name
+// and keeps its ID; new ones are numbered after the global queries.
+func MergeQueries(connName string, global map[string]db.Query) map[string]db.Query {
+	merged := make(map[string]db.Query, len(global))
+	for name, q := range global {
+		merged[name] = q
+	}
+	if Project == nil {
+		return merged
+	}
+
+	// Queries for every connection first, so connection-specific ones win
+	project := Project.For(connName)
+	sort.SliceStable(project, func(i, j int) bool {
+		return project[i].Connection == "" && project[j].Connection != ""
+	})
+
+	nextID := GetNextQueryId(global)
+	for _, pq := range project {
+		q := pq.Query
+		q.Source = Project.Rel(pq.Path)
+		if len(pq.Params) > 0 {
+			defaults := make(map[string]string, len(pq.Params))
+			for _, p := range pq.Params {
+				defaults[p.Name] = p.Default
+			}
+			q.SQL = params.ApplyDefaults(q.SQL, defaults)
+		}
+
+		if existing, ok := merged[q.Name]; ok {
+			q.Id = existing.Id
+		} else {
+			q.Id = nextID
+			nextID++
+		}
+		merged[q.Name] = q
+	}
+	return merged
+}
+
+// Queries returns the merged global and project queries of a connection.
+func (c *Config) Queries(connName string) map[string]db.Query {
+	var global map[string]db.Query
+	if conn, ok := c.Connections[connName]; ok {
+		global = conn.Queries
+	}
+	return MergeQueries(connName, global)
+}
+
+// saveProjectQuery writes query back to its project file when connName has
+// a project query of that name. It reports false for global queries.
+func saveProjectQuery(connName string, query db.Query) (db.Query, bool, error) {
+	if Project == nil {
+		return query, false, nil
+	}
+	pq, ok := Project.Lookup(connName, query.Name)
+	if !ok {
+		return query, false, nil
+	}
+	if query.Id == -1 {
+		return query, true, fmt.Errorf("query '%s' already exists in %s", query.Name, Project.Rel(pq.Path))
+	}
+
+	// Leave the file alone when only the declared defaults differ
+	if query.SQL != MergeQueries(connName, nil)[query.Name].SQL {
+		pq.SQL = query.SQL
+	}
+	pq.Metadata = query.Metadata
+	if _, err := Project.Save(pq); err != nil {
+		return query, true, err
+	}
+	query.Source = Project.Rel(pq.Path)
+	return query, true, nil
+}
@@ -23,6 +23,11 @@ func (c *Config) SaveQueryToConnection(
 	connName string,
 	query db.Query,
 ) (db.Query, error) {
+	// Project queries are saved back to their .sql file
+	if saved, ok, err := saveProjectQuery(connName, query); ok {
+		return saved, err
+	}
+
 	connData := c.Connections[connName]
 
 	// Check if query with this name already exists (when creating new)
@@ -61,8 +66,9 @@ func (c *Config) SaveQueryAndLast(
 ) error {
 	connData := c.Connections[connName]
 
-	// Save the query (if it has a name and isn't inline)
-	if query.Name != "<inline>" && query.Name != "" && query.SQL != "" {
+	// Save the query (if it has a name, isn't inline and isn't a project
+	// query, which lives in its own file)
+	if query.Name != "<inline>" && query.Name != "" && query.SQL != "" && query.Source == "" {
 		connData.Queries[query.Name] = query
 	}
 
@@ -35,6 +35,9 @@ func (b *BaseConnection) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
 func (b *BaseConnection) Exec(sql string, args ...any) error {
 	return errors.New("Exec() not implemented for base connection")
 }
+func (b *BaseConnection) GetDB() *sql.DB {
+	return nil
+}
 
 func (b *BaseConnection) GetTableMetadata(
 	tableName string,
@@ -88,6 +91,30 @@ func (b *BaseConnection) GetUniqueConstraints(
 	)
 }
 
+func (b *BaseConnection) GetIndexes(tableName string) ([]IndexInfo, error) {
+	return nil, errors.New("GetIndexes() not implemented for base connection")
+}
+
+func (b *BaseConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	return nil, errors.New(
+		"GetConstraints() not implemented for base connection",
+	)
+}
+
+func (b *BaseConnection) GetTriggers(tableName string) ([]TriggerInfo, error) {
+	return nil, errors.New("GetTriggers() not implemented for base connection")
+}
+
+func (b *BaseConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	return TableDDL{}, errors.New("GetTableDDL() not implemented for base connection")
+}
+
+func (b *BaseConnection) GetViewDDL(viewName string) (string, error) {
+	return "", errors.New("GetViewDDL() not implemented for base connection")
+}
+
 func (b *BaseConnection) BuildAddColumnSQL(
 	tableName, columnName, dataType string,
 	nullable bool,
@@ -159,6 +186,143 @@ func (b *BaseConnection) BuildDropColumnSQL(
 	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", tableName, columnName)
 }
 
+func (b *BaseConnection) BuildCreateTableSQL(def TableDefinition) string {
+	return createTableSQL(def, noQuote)
+}
+
+func (b *BaseConnection) BuildRenameTableSQL(oldName, newName string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", oldName, newName)
+}
+
+func (b *BaseConnection) BuildTruncateTableSQL(tableName string) string {
+	return fmt.Sprintf("TRUNCATE TABLE %s;", tableName)
+}
+
+func (b *BaseConnection) BuildDropTableSQL(tableName string) string {
+	return fmt.Sprintf("DROP TABLE %s;", tableName)
+}
+
+// BuildCloneTableSQL copies the columns, and optionally the rows, of
+// source. Constraints and indexes are not copied.
+func (b *BaseConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	if !withData {
+		return fmt.Sprintf(
+			"CREATE TABLE %s AS SELECT * FROM %s WHERE 1 = 0;",
+			target,
+			source,
+		)
+	}
+	return fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s;", target, source)
+}
+
+func (b *BaseConnection) BuildCreateIndexSQL(
+	tableName, indexName string,
+	columns []string,
+	unique bool,
+) string {
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf(
+		"CREATE %s %s ON %s (%s);",
+		kind,
+		indexName,
+		tableName,
+		strings.Join(columns, ", "),
+	)
+}
+
+func (b *BaseConnection) BuildDropIndexSQL(
+	tableName string,
+	index IndexInfo,
+) string {
+	if index.Primary {
+		return fmt.Sprintf(
+			"ALTER TABLE %s DROP CONSTRAINT %s;",
+			tableName,
+			index.Name,
+		)
+	}
+	return fmt.Sprintf("DROP INDEX %s;", index.Name)
+}
+
+func (b *BaseConnection) BuildRenameIndexSQL(
+	tableName string,
+	index IndexInfo,
+	newName string,
+) string {
+	return fmt.Sprintf("ALTER INDEX %s RENAME TO %s;", index.Name, newName)
+}
+
+func (b *BaseConnection) BuildAddConstraintSQL(
+	tableName, constraintName, definition string,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE %s ADD CONSTRAINT %s %s;",
+		tableName,
+		constraintName,
+		definition,
+	)
+}
+
+func (b *BaseConnection) BuildDropConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE %s DROP CONSTRAINT %s;",
+		tableName,
+		constraint.Name,
+	)
+}
+
+func (b *BaseConnection) BuildRenameConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE %s RENAME CONSTRAINT %s TO %s;",
+		tableName,
+		constraint.Name,
+		newName,
+	)
+}
+
+func (b *BaseConnection) BuildCreateTriggerSQL(
+	tableName, triggerName string,
+) string {
+	return fmt.Sprintf(
+		"CREATE TRIGGER %s\nBEFORE UPDATE ON %s\nFOR EACH ROW\nEXECUTE FUNCTION trigger_function();",
+		triggerName,
+		tableName,
+	)
+}
+
+func (b *BaseConnection) BuildDropTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+) string {
+	return fmt.Sprintf("DROP TRIGGER %s ON %s;", trigger.Name, tableName)
+}
+
+func (b *BaseConnection) BuildRenameTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"ALTER TRIGGER %s ON %s RENAME TO %s;",
+		trigger.Name,
+		tableName,
+		newName,
+	)
+}
+
 func (b *BaseConnection) BuildUpdateStatement(
 	tableName, columnName, currentValue, pkColumn, pkValue string,
 ) string {
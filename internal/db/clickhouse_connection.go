@@ -97,6 +97,10 @@ func (c *ClickHouseConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (c *ClickHouseConnection) GetDB() *sql.DB {
+	return c.db
+}
+
 func (c *ClickHouseConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -411,3 +415,86 @@ func (c *ClickHouseConnection) ApplyRowLimit(sql string, limit int) string {
 
 	return fmt.Sprintf("%s\nLIMIT %d", strings.TrimRight(sql, ";"), limit)
 }
+
+// BuildCreateTableSQL creates a MergeTree table ordered by the primary key.
+// ClickHouse has no UNIQUE or FOREIGN KEY constraints, so those are left out.
+func (c *ClickHouseConnection) BuildCreateTableSQL(def TableDefinition) string {
+	return clickhouseCreateTableSQL(def, noQuote)
+}
+
+// clickhouseCreateTableSQL renders a MergeTree table ordered by the primary
+// key. ClickHouse has no foreign keys or unique constraints.
+func clickhouseCreateTableSQL(def TableDefinition, quote func(string) string) string {
+	var lines []string
+	for _, col := range def.Columns {
+		dataType := col.DataType
+		if col.Nullable && !col.PrimaryKey {
+			dataType = "Nullable(" + dataType + ")"
+		}
+		line := quote(col.Name) + " " + dataType
+		if col.Default != "" {
+			line += " DEFAULT " + col.Default
+		}
+		lines = append(lines, line)
+	}
+
+	orderBy := "tuple()"
+	if pk := def.PrimaryKey(); len(pk) > 0 {
+		orderBy = "(" + quoteList(pk, quote) + ")"
+	}
+	return fmt.Sprintf(
+		"CREATE TABLE %s (\n    %s\n)\nENGINE = MergeTree\nORDER BY %s;",
+		quote(def.Name),
+		strings.Join(lines, ",\n    "),
+		orderBy,
+	)
+}
+
+func (c *ClickHouseConnection) BuildRenameTableSQL(oldName, newName string) string {
+	return fmt.Sprintf("RENAME TABLE %s TO %s;", oldName, newName)
+}
+
+func (c *ClickHouseConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	stmt := fmt.Sprintf("CREATE TABLE %s AS %s;", target, source)
+	if withData {
+		stmt += fmt.Sprintf("\nINSERT INTO %s SELECT * FROM %s;", target, source)
+	}
+	return stmt
+}
+
+// createQuery returns the CREATE statement ClickHouse keeps for a table or
+// view, without the database prefix so the dump loads into any database
+func (c *ClickHouseConnection) createQuery(name string) (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("database not open")
+	}
+
+	var create, database string
+	err := c.db.QueryRow(`
+		SELECT create_table_query, currentDatabase()
+		FROM system.tables
+		WHERE database = currentDatabase()
+		  AND name = ?
+	`, name).Scan(&create, &database)
+	if err != nil {
+		return "", fmt.Errorf("%q not found: %w", name, err)
+	}
+	create = strings.Replace(create, " "+database+".", " ", 1)
+	create = strings.Replace(create, " `"+database+"`.", " ", 1)
+	return create + ";", nil
+}
+
+func (c *ClickHouseConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	create, err := c.createQuery(tableName)
+	if err != nil {
+		return TableDDL{}, err
+	}
+	return TableDDL{Create: create}, nil
+}
+
+func (c *ClickHouseConnection) GetViewDDL(viewName string) (string, error) {
+	return c.createQuery(viewName)
+}
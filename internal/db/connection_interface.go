@@ -9,6 +9,7 @@ type DatabaseConnection interface {
 	Query(queryName string, args ...any) (any, error)
 	ExecQuery(sql string, args ...any) (*sql.Rows, error)
 	Exec(sql string, args ...any) error
+	GetDB() *sql.DB
 	GetInfoSQL(infoType string) string
 	GetTables() ([]string, error)
 	GetViews() ([]string, error)
@@ -17,6 +18,11 @@ type DatabaseConnection interface {
 	GetForeignKeys(tableName string) ([]ForeignKey, error)
 	GetForeignKeysReferencingTable(tableName string) ([]ForeignKey, error)
 	GetUniqueConstraints(tableName string) ([]string, error)
+	GetIndexes(tableName string) ([]IndexInfo, error)
+	GetConstraints(tableName string) ([]ConstraintInfo, error)
+	GetTriggers(tableName string) ([]TriggerInfo, error)
+	GetTableDDL(tableName string) (TableDDL, error)
+	GetViewDDL(viewName string) (string, error)
 	BuildUpdateStatement(
 		tableName, columnName, currentValue, pkColumn, pkValue string,
 	) string
@@ -33,6 +39,32 @@ type DatabaseConnection interface {
 	) string
 	BuildRenameColumnSQL(tableName, oldName, newName string) string
 	BuildDropColumnSQL(tableName, columnName string) string
+	BuildCreateIndexSQL(
+		tableName, indexName string,
+		columns []string,
+		unique bool,
+	) string
+	BuildDropIndexSQL(tableName string, index IndexInfo) string
+	BuildRenameIndexSQL(tableName string, index IndexInfo, newName string) string
+	BuildAddConstraintSQL(tableName, constraintName, definition string) string
+	BuildDropConstraintSQL(tableName string, constraint ConstraintInfo) string
+	BuildRenameConstraintSQL(
+		tableName string,
+		constraint ConstraintInfo,
+		newName string,
+	) string
+	BuildCreateTableSQL(def TableDefinition) string
+	BuildRenameTableSQL(oldName, newName string) string
+	BuildTruncateTableSQL(tableName string) string
+	BuildDropTableSQL(tableName string) string
+	BuildCloneTableSQL(source, target string, withData bool) string
+	BuildCreateTriggerSQL(tableName, triggerName string) string
+	BuildDropTriggerSQL(tableName string, trigger TriggerInfo) string
+	BuildRenameTriggerSQL(
+		tableName string,
+		trigger TriggerInfo,
+		newName string,
+	) string
 	ApplyRowLimit(sql string, limit int) string
 	GetPlaceholder(paramIndex int) string
 
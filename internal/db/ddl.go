@@ -0,0 +1,160 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableDefinition describes a table for BuildCreateTableSQL
+type TableDefinition struct {
+	Name    string
+	Columns []ColumnDefinition
+}
+
+// ColumnDefinition is one column of a TableDefinition. The columns marked
+// PrimaryKey together form the table's primary key.
+type ColumnDefinition struct {
+	Name             string
+	DataType         string
+	Nullable         bool
+	Default          string
+	PrimaryKey       bool
+	Unique           bool
+	ReferencedTable  string // foreign key target, if any
+	ReferencedColumn string
+}
+
+// PrimaryKey returns the names of the primary key columns
+func (t TableDefinition) PrimaryKey() []string {
+	var pk []string
+	for _, col := range t.Columns {
+		if col.PrimaryKey {
+			pk = append(pk, col.Name)
+		}
+	}
+	return pk
+}
+
+// TableDDL is a table's definition in its database's own dialect, split
+// around the data so a dump can load rows before building indexes and
+// triggers
+type TableDDL struct {
+	Create string // the CREATE TABLE, preceded by anything it needs such as sequences
+	After  string // indexes, triggers, foreign keys and sequence positions
+}
+
+// dataTypes lists the common column types of each database, most used
+// first, for the create-table type picker
+var dataTypes = map[string][]string{
+	"postgres": {
+		"integer", "bigint", "serial", "bigserial", "smallint",
+		"numeric(10,2)", "real", "double precision", "boolean",
+		"text", "varchar(255)", "char(1)", "date", "timestamp",
+		"timestamptz", "time", "uuid", "jsonb", "bytea",
+	},
+	"mysql": {
+		"INT", "BIGINT", "SMALLINT", "TINYINT(1)", "DECIMAL(10,2)",
+		"FLOAT", "DOUBLE", "BOOLEAN", "VARCHAR(255)", "CHAR(1)", "TEXT",
+		"DATE", "DATETIME", "TIMESTAMP", "TIME", "JSON", "BLOB",
+	},
+	"sqlite": {"INTEGER", "TEXT", "REAL", "NUMERIC", "BLOB"},
+	"sqlserver": {
+		"INT", "BIGINT", "SMALLINT", "BIT", "DECIMAL(10,2)", "FLOAT",
+		"NVARCHAR(255)", "NVARCHAR(MAX)", "CHAR(1)", "DATE", "DATETIME2",
+		"DATETIMEOFFSET", "TIME", "UNIQUEIDENTIFIER", "VARBINARY(MAX)",
+	},
+	"oracle": {
+		"NUMBER(10)", "NUMBER", "NUMBER(10,2)", "BINARY_DOUBLE",
+		"VARCHAR2(255)", "NVARCHAR2(255)", "CHAR(1)", "CLOB", "DATE",
+		"TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "BLOB",
+	},
+	"duckdb": {
+		"INTEGER", "BIGINT", "SMALLINT", "DECIMAL(10,2)", "DOUBLE",
+		"BOOLEAN", "VARCHAR", "DATE", "TIMESTAMP", "TIMESTAMPTZ", "TIME",
+		"UUID", "JSON", "BLOB",
+	},
+	"clickhouse": {
+		"Int64", "Int32", "UInt64", "UInt32", "Float64", "Decimal(10,2)",
+		"Bool", "String", "FixedString(16)", "Date", "DateTime",
+		"DateTime64(3)", "UUID",
+	},
+	"firebird": {
+		"INTEGER", "BIGINT", "SMALLINT", "NUMERIC(10,2)",
+		"DOUBLE PRECISION", "BOOLEAN", "VARCHAR(255)", "CHAR(1)",
+		"BLOB SUB_TYPE TEXT", "DATE", "TIMESTAMP", "TIME",
+	},
+	"snowflake": {
+		"NUMBER(38,0)", "NUMBER(10,2)", "FLOAT", "BOOLEAN", "VARCHAR",
+		"VARCHAR(255)", "DATE", "TIMESTAMP_NTZ", "TIMESTAMP_TZ",
+		"VARIANT", "BINARY",
+	},
+}
+
+// DataTypes returns common column types for dbType. The first one suits an
+// integer primary key.
+func DataTypes(dbType string) []string {
+	if types, ok := dataTypes[dbType]; ok {
+		return types
+	}
+	return []string{"INTEGER", "VARCHAR(255)", "TEXT", "DATE", "TIMESTAMP"}
+}
+
+// createTableSQL renders a CREATE TABLE statement with table-level primary
+// key, unique and foreign key constraints, quoting identifiers with quote.
+func createTableSQL(def TableDefinition, quote func(string) string) string {
+	var lines []string
+	for _, col := range def.Columns {
+		line := quote(col.Name) + " " + col.DataType
+		if col.Default != "" {
+			line += " DEFAULT " + col.Default
+		}
+		if !col.Nullable || col.PrimaryKey {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+
+	if pk := def.PrimaryKey(); len(pk) > 0 {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)", quoteList(pk, quote)))
+	}
+	for _, col := range def.Columns {
+		if col.Unique && !col.PrimaryKey {
+			lines = append(lines, fmt.Sprintf("UNIQUE (%s)", quote(col.Name)))
+		}
+	}
+	for _, col := range def.Columns {
+		if col.ReferencedTable == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(
+			"FOREIGN KEY (%s) REFERENCES %s (%s)",
+			quote(col.Name),
+			quote(col.ReferencedTable),
+			quote(col.ReferencedColumn),
+		))
+	}
+
+	return fmt.Sprintf(
+		"CREATE TABLE %s (\n    %s\n);",
+		quote(def.Name),
+		strings.Join(lines, ",\n    "),
+	)
+}
+
+func quoteList(names []string, quote func(string) string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// noQuote leaves identifiers as typed, which keeps the default case folding
+// of each database
+func noQuote(name string) string {
+	return name
+}
+
+func backtickQuote(name string) string {
+	return "`" + name + "`"
+}
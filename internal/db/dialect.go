@@ -0,0 +1,570 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialects are named after the connection types: postgres, mysql, sqlite,
+// sqlserver, oracle, duckdb, clickhouse, firebird and snowflake.
+var dialectAliases = map[string]string{
+	"postgres":   "postgres",
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+	"mariadb":    "mysql",
+	"sqlite":     "sqlite",
+	"sqlite3":    "sqlite",
+	"sqlserver":  "sqlserver",
+	"mssql":      "sqlserver",
+	"oracle":     "oracle",
+	"godror":     "oracle",
+	"duckdb":     "duckdb",
+	"clickhouse": "clickhouse",
+	"firebird":   "firebird",
+	"interbase":  "firebird",
+	"snowflake":  "snowflake",
+}
+
+// NormalizeDialect resolves a database type or one of its aliases, such as
+// "mariadb" or "postgresql", to its dialect name
+func NormalizeDialect(name string) (string, error) {
+	if dialect, ok := dialectAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return dialect, nil
+	}
+	return "", fmt.Errorf(
+		"unknown dialect %q (use postgres, mysql, sqlite, sqlserver, oracle, duckdb, clickhouse, firebird or snowflake)",
+		name,
+	)
+}
+
+// dialectOf returns the dialect of a connection, or its raw type when it
+// isn't one of the known dialects
+func dialectOf(conn DatabaseConnection) string {
+	if dialect, err := NormalizeDialect(conn.GetDbType()); err == nil {
+		return dialect
+	}
+	return conn.GetDbType()
+}
+
+// QuoteIdentifierFor quotes an identifier the way dialect does: backticks
+// for MySQL and ClickHouse, brackets for SQL Server and double quotes for the
+// rest.
+func QuoteIdentifierFor(dialect, name string) string {
+	switch dialect {
+	case "mysql", "clickhouse":
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case "sqlserver":
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	}
+	return quoteIdentifier(name)
+}
+
+// QuoteName quotes a table or column name for conn's dialect, each part of
+// a schema-qualified name separately
+func QuoteName(conn DatabaseConnection, name string) string {
+	dialect := dialectOf(conn)
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = QuoteIdentifierFor(dialect, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// QuoteStringFor renders a string literal. MySQL and ClickHouse read
+// backslashes as escapes, so theirs are doubled too.
+func QuoteStringFor(dialect, value string) string {
+	if backslashEscapes(dialect) {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func backslashEscapes(dialect string) bool {
+	return dialect == "mysql" || dialect == "clickhouse"
+}
+
+// booleanLiteralFor renders a boolean. SQL Server and Oracle have no
+// boolean literals and store flags as 1 and 0.
+func booleanLiteralFor(dialect string, value bool) string {
+	switch dialect {
+	case "sqlserver", "oracle":
+		if value {
+			return "1"
+		}
+		return "0"
+	}
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// Column type kinds shared by every dialect
+const (
+	kindSmallInt    = "smallint"
+	kindInteger     = "integer"
+	kindBigInt      = "bigint"
+	kindBoolean     = "boolean"
+	kindDecimal     = "decimal"
+	kindFloat       = "float"
+	kindDouble      = "double"
+	kindString      = "string" // bounded, with a length
+	kindText        = "text"
+	kindDate        = "date"
+	kindTime        = "time"
+	kindTimestamp   = "timestamp"
+	kindTimestampTZ = "timestamptz"
+	kindBinary      = "binary"
+	kindJSON        = "json"
+	kindUUID        = "uuid"
+)
+
+// columnType is a column type reduced to its kind, with the length of
+// strings or the precision and scale of decimals (0 when not given)
+type columnType struct {
+	kind      string
+	length    int
+	precision int
+	scale     int
+}
+
+var typeArgsPattern = regexp.MustCompile(`\(([^)]*)\)`)
+
+// parseColumnType classifies a type as spelled by the source dialect
+func parseColumnType(dataType, dialect string) columnType {
+	lower := strings.ToLower(strings.TrimSpace(dataType))
+
+	// ClickHouse wraps types: Nullable(String), LowCardinality(String)
+	for _, wrapper := range []string{"nullable(", "lowcardinality("} {
+		for strings.HasPrefix(lower, wrapper) && strings.HasSuffix(lower, ")") {
+			lower = lower[len(wrapper) : len(lower)-1]
+		}
+	}
+
+	var args []int
+	if match := typeArgsPattern.FindStringSubmatch(lower); match != nil {
+		for _, arg := range strings.Split(match[1], ",") {
+			n, _ := strconv.Atoi(strings.TrimSpace(arg))
+			args = append(args, n)
+		}
+		if strings.Contains(match[1], "max") {
+			args = []int{-1}
+		}
+	}
+	arg := func(i int) int {
+		if i < len(args) {
+			return args[i]
+		}
+		return 0
+	}
+	base := strings.TrimSpace(typeArgsPattern.ReplaceAllString(lower, ""))
+	base = strings.TrimSpace(strings.NewReplacer(
+		" unsigned", "", " zerofill", "", " signed", "",
+	).Replace(base))
+	if strings.HasSuffix(base, "[]") {
+		return columnType{kind: kindText}
+	}
+
+	switch base {
+	case "bool", "boolean":
+		return columnType{kind: kindBoolean}
+	case "tinyint":
+		if arg(0) == 1 {
+			return columnType{kind: kindBoolean}
+		}
+		return columnType{kind: kindSmallInt}
+	case "bit":
+		if arg(0) <= 1 {
+			return columnType{kind: kindBoolean}
+		}
+		return columnType{kind: kindBigInt}
+	case "smallint", "int2", "int16", "uint8", "smallserial", "byte", "year":
+		return columnType{kind: kindSmallInt}
+	case "int8":
+		if dialect == "clickhouse" {
+			return columnType{kind: kindSmallInt}
+		}
+		return columnType{kind: kindBigInt}
+	case "int", "integer", "int4", "mediumint", "int32", "uint16", "serial":
+		return columnType{kind: kindInteger}
+	case "bigint", "int64", "uint32", "uint64", "bigserial", "long":
+		return columnType{kind: kindBigInt}
+	case "number":
+		if len(args) == 0 {
+			return columnType{kind: kindDecimal}
+		}
+		if arg(1) == 0 {
+			switch {
+			case arg(0) == 1:
+				return columnType{kind: kindBoolean}
+			case arg(0) <= 4:
+				return columnType{kind: kindSmallInt}
+			case arg(0) <= 9:
+				return columnType{kind: kindInteger}
+			case arg(0) <= 18:
+				return columnType{kind: kindBigInt}
+			}
+		}
+		return columnType{kind: kindDecimal, precision: arg(0), scale: arg(1)}
+	case "decimal", "numeric", "dec", "money", "smallmoney", "decimal32",
+		"decimal64", "decimal128", "fixed":
+		if strings.HasPrefix(base, "decimal") && base != "decimal" {
+			// ClickHouse DecimalN(S) only gives the scale
+			return columnType{kind: kindDecimal, precision: 38, scale: arg(0)}
+		}
+		return columnType{kind: kindDecimal, precision: arg(0), scale: arg(1)}
+	case "real", "float4", "float32", "binary_float":
+		if dialect == "sqlite" {
+			return columnType{kind: kindDouble}
+		}
+		return columnType{kind: kindFloat}
+	case "float", "double", "double precision", "float8", "float64",
+		"binary_double":
+		return columnType{kind: kindDouble}
+	case "char", "varchar", "character", "character varying", "nchar",
+		"nvarchar", "varchar2", "nvarchar2", "bpchar", "fixedstring",
+		"national character varying", "varying":
+		if arg(0) <= 0 {
+			return columnType{kind: kindText}
+		}
+		return columnType{kind: kindString, length: arg(0)}
+	case "text", "tinytext", "mediumtext", "longtext", "clob", "nclob",
+		"ntext", "citext", "string", "blob sub_type text", "blob sub_type 1",
+		"enum", "set", "interval", "xml", "inet", "cidr", "macaddr":
+		return columnType{kind: kindText}
+	case "date", "date32":
+		return columnType{kind: kindDate}
+	case "time", "time without time zone", "timetz", "time with time zone":
+		return columnType{kind: kindTime}
+	case "datetime", "datetime2", "smalldatetime", "timestamp",
+		"timestamp without time zone", "timestamp_ntz", "datetime64":
+		if dialect == "oracle" && base == "timestamp" && strings.Contains(lower, "time zone") {
+			return columnType{kind: kindTimestampTZ}
+		}
+		return columnType{kind: kindTimestamp}
+	case "timestamptz", "timestamp with time zone", "datetimeoffset",
+		"timestamp_tz", "timestamp_ltz", "timestamp with local time zone":
+		return columnType{kind: kindTimestampTZ}
+	case "blob", "bytea", "binary", "varbinary", "tinyblob", "mediumblob",
+		"longblob", "image", "raw", "long raw", "blob sub_type binary",
+		"blob sub_type 0":
+		return columnType{kind: kindBinary}
+	case "json", "jsonb", "variant", "object", "array":
+		return columnType{kind: kindJSON}
+	case "uuid", "uniqueidentifier":
+		return columnType{kind: kindUUID}
+	}
+
+	// Oracle spells its zone variants with the arguments in the middle
+	if strings.HasPrefix(base, "timestamp") {
+		if strings.Contains(base, "time zone") {
+			return columnType{kind: kindTimestampTZ}
+		}
+		return columnType{kind: kindTimestamp}
+	}
+	return columnType{kind: kindText}
+}
+
+// dialectTypes spells each kind in one dialect
+type dialectTypes struct {
+	smallInt, integer, bigInt, boolean         string
+	float, double, text, date, time            string
+	timestamp, timestampTZ, binary, json, uuid string
+	varchar                                    func(length int) string
+	decimal                                    func(precision, scale int) string
+}
+
+func sized(format string, max int, fallback string) func(int) string {
+	return func(length int) string {
+		if !strings.Contains(format, "%") {
+			return format
+		}
+		if length < 0 || (max > 0 && length > max) {
+			return fallback
+		}
+		return fmt.Sprintf(format, length)
+	}
+}
+
+func decimalOf(format string, defaultPrecision, defaultScale int) func(int, int) string {
+	return func(precision, scale int) string {
+		if precision <= 0 {
+			precision, scale = defaultPrecision, defaultScale
+		}
+		if precision <= 0 {
+			return strings.SplitN(format, "(", 2)[0]
+		}
+		return fmt.Sprintf(format, precision, scale)
+	}
+}
+
+var typesByDialect = map[string]dialectTypes{
+	"postgres": {
+		smallInt: "smallint", integer: "integer", bigInt: "bigint",
+		boolean: "boolean", float: "real", double: "double precision",
+		text: "text", date: "date", time: "time", timestamp: "timestamp",
+		timestampTZ: "timestamptz", binary: "bytea", json: "jsonb", uuid: "uuid",
+		varchar: sized("varchar(%d)", 0, "text"),
+		decimal: decimalOf("numeric(%d,%d)", 0, 0),
+	},
+	"mysql": {
+		smallInt: "SMALLINT", integer: "INT", bigInt: "BIGINT",
+		boolean: "TINYINT(1)", float: "FLOAT", double: "DOUBLE",
+		text: "LONGTEXT", date: "DATE", time: "TIME", timestamp: "DATETIME",
+		timestampTZ: "DATETIME", binary: "LONGBLOB", json: "JSON", uuid: "CHAR(36)",
+		varchar: sized("VARCHAR(%d)", 16383, "LONGTEXT"),
+		decimal: decimalOf("DECIMAL(%d,%d)", 65, 30),
+	},
+	"sqlite": {
+		smallInt: "INTEGER", integer: "INTEGER", bigInt: "INTEGER",
+		boolean: "BOOLEAN", float: "REAL", double: "REAL",
+		text: "TEXT", date: "DATE", time: "TIME", timestamp: "DATETIME",
+		timestampTZ: "DATETIME", binary: "BLOB", json: "TEXT", uuid: "TEXT",
+		varchar: sized("VARCHAR(%d)", 0, "TEXT"),
+		decimal: decimalOf("NUMERIC(%d,%d)", 0, 0),
+	},
+	"duckdb": {
+		smallInt: "SMALLINT", integer: "INTEGER", bigInt: "BIGINT",
+		boolean: "BOOLEAN", float: "REAL", double: "DOUBLE",
+		text: "VARCHAR", date: "DATE", time: "TIME", timestamp: "TIMESTAMP",
+		timestampTZ: "TIMESTAMPTZ", binary: "BLOB", json: "JSON", uuid: "UUID",
+		varchar: sized("VARCHAR(%d)", 0, "VARCHAR"),
+		decimal: decimalOf("DECIMAL(%d,%d)", 38, 10),
+	},
+	"sqlserver": {
+		smallInt: "SMALLINT", integer: "INT", bigInt: "BIGINT",
+		boolean: "BIT", float: "REAL", double: "FLOAT",
+		text: "NVARCHAR(MAX)", date: "DATE", time: "TIME", timestamp: "DATETIME2",
+		timestampTZ: "DATETIMEOFFSET", binary: "VARBINARY(MAX)",
+		json: "NVARCHAR(MAX)", uuid: "UNIQUEIDENTIFIER",
+		varchar: sized("NVARCHAR(%d)", 4000, "NVARCHAR(MAX)"),
+		decimal: decimalOf("DECIMAL(%d,%d)", 38, 10),
+	},
+	"oracle": {
+		smallInt: "NUMBER(5)", integer: "NUMBER(10)", bigInt: "NUMBER(19)",
+		boolean: "NUMBER(1)", float: "BINARY_FLOAT", double: "BINARY_DOUBLE",
+		text: "CLOB", date: "DATE", time: "VARCHAR2(16)", timestamp: "TIMESTAMP",
+		timestampTZ: "TIMESTAMP WITH TIME ZONE", binary: "BLOB", json: "CLOB",
+		uuid:    "VARCHAR2(36)",
+		varchar: sized("VARCHAR2(%d)", 4000, "CLOB"),
+		decimal: decimalOf("NUMBER(%d,%d)", 0, 0),
+	},
+	"clickhouse": {
+		smallInt: "Int16", integer: "Int32", bigInt: "Int64",
+		boolean: "Bool", float: "Float32", double: "Float64",
+		text: "String", date: "Date", time: "String", timestamp: "DateTime",
+		timestampTZ: "DateTime", binary: "String", json: "String", uuid: "UUID",
+		varchar: sized("String", 0, "String"),
+		decimal: decimalOf("Decimal(%d,%d)", 38, 10),
+	},
+	"firebird": {
+		smallInt: "SMALLINT", integer: "INTEGER", bigInt: "BIGINT",
+		boolean: "BOOLEAN", float: "FLOAT", double: "DOUBLE PRECISION",
+		text: "BLOB SUB_TYPE TEXT", date: "DATE", time: "TIME",
+		timestamp: "TIMESTAMP", timestampTZ: "TIMESTAMP WITH TIME ZONE",
+		binary: "BLOB", json: "BLOB SUB_TYPE TEXT", uuid: "CHAR(36)",
+		varchar: sized("VARCHAR(%d)", 32765, "BLOB SUB_TYPE TEXT"),
+		decimal: decimalOf("NUMERIC(%d,%d)", 18, 4),
+	},
+	"snowflake": {
+		smallInt: "NUMBER(5,0)", integer: "NUMBER(10,0)", bigInt: "NUMBER(19,0)",
+		boolean: "BOOLEAN", float: "FLOAT", double: "FLOAT",
+		text: "VARCHAR", date: "DATE", time: "TIME", timestamp: "TIMESTAMP_NTZ",
+		timestampTZ: "TIMESTAMP_TZ", binary: "BINARY", json: "VARIANT",
+		uuid:    "VARCHAR(36)",
+		varchar: sized("VARCHAR(%d)", 0, "VARCHAR"),
+		decimal: decimalOf("NUMBER(%d,%d)", 38, 10),
+	},
+}
+
+// TranslateType converts a column type from the source dialect to the
+// closest type of the target dialect
+func TranslateType(dataType, source, target string) string {
+	return renderColumnType(parseColumnType(dataType, source), target)
+}
+
+func renderColumnType(t columnType, dialect string) string {
+	types, ok := typesByDialect[dialect]
+	if !ok {
+		types = typesByDialect["postgres"]
+	}
+	switch t.kind {
+	case kindSmallInt:
+		return types.smallInt
+	case kindInteger:
+		return types.integer
+	case kindBigInt:
+		return types.bigInt
+	case kindBoolean:
+		return types.boolean
+	case kindDecimal:
+		return types.decimal(t.precision, t.scale)
+	case kindFloat:
+		return types.float
+	case kindDouble:
+		return types.double
+	case kindString:
+		return types.varchar(t.length)
+	case kindDate:
+		return types.date
+	case kindTime:
+		return types.time
+	case kindTimestamp:
+		return types.timestamp
+	case kindTimestampTZ:
+		return types.timestampTZ
+	case kindBinary:
+		return types.binary
+	case kindJSON:
+		return types.json
+	case kindUUID:
+		return types.uuid
+	}
+	return types.text
+}
+
+// autoIncrement is how a dialect numbers an integer key column. next is the
+// first value to hand out, or 0 when unknown. It returns the column's type
+// and default with the dialect's syntax, and statements for before the
+// table is created and after its rows are loaded.
+func autoIncrement(
+	dialect, table, column, dataType string,
+	next int64,
+) (colType, colDefault string, before, after []string) {
+	qt := QuoteIdentifierFor(dialect, table)
+	qc := QuoteIdentifierFor(dialect, column)
+	if next < 1 {
+		next = 1
+	}
+
+	switch dialect {
+	case "sqlite":
+		// An INTEGER primary key is the rowid, which numbers itself
+		return "INTEGER", "", nil, nil
+	case "mysql":
+		return dataType + " AUTO_INCREMENT", "", nil, nil
+	case "postgres":
+		return dataType + " GENERATED BY DEFAULT AS IDENTITY", "", nil, []string{fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence(%s, %s), COALESCE(MAX(%s), 0) + 1, false) FROM %s;",
+			QuoteStringFor(dialect, qt), QuoteStringFor(dialect, column), qc, qt,
+		)}
+	case "duckdb":
+		seq := table + "_" + column + "_seq"
+		return dataType, fmt.Sprintf("nextval(%s)", QuoteStringFor(dialect, seq)),
+			[]string{fmt.Sprintf(
+				"CREATE SEQUENCE IF NOT EXISTS %s START WITH %d;",
+				QuoteIdentifierFor(dialect, seq), next,
+			)}, nil
+	case "sqlserver":
+		return fmt.Sprintf("%s IDENTITY(%d,1)", dataType, next), "", nil, nil
+	case "oracle":
+		return dataType + " GENERATED BY DEFAULT AS IDENTITY", "", nil, []string{fmt.Sprintf(
+			"ALTER TABLE %s MODIFY (%s GENERATED BY DEFAULT AS IDENTITY (START WITH LIMIT VALUE));",
+			qt, qc,
+		)}
+	case "firebird":
+		return fmt.Sprintf("%s GENERATED BY DEFAULT AS IDENTITY (START WITH %d)", dataType, next),
+			"", nil, nil
+	case "snowflake":
+		return fmt.Sprintf("%s AUTOINCREMENT START %d", dataType, next), "", nil, nil
+	}
+	return dataType, "", nil, nil
+}
+
+// IsAutoIncrement reports whether a column numbers itself, judging by the
+// markers each connection's GetColumnDetails leaves
+func IsAutoIncrement(col ColumnInfo) bool {
+	extra := strings.ToLower(col.Extra)
+	return strings.Contains(extra, "auto_increment") ||
+		strings.Contains(extra, "autoincrement") ||
+		strings.Contains(extra, "identity") ||
+		strings.Contains(extra, "serial") ||
+		strings.HasPrefix(strings.ToLower(col.DefaultValue), "nextval(")
+}
+
+var (
+	pgCastPattern      = regexp.MustCompile(`::[\w ]+(\(\d+(,\d+)?\))?(\[\])?`)
+	currentTimePattern = regexp.MustCompile(
+		`(?i)^(now\(\)|getdate\(\)|sysdate|systimestamp|current_timestamp(\(\d*\))?|localtimestamp|sysdatetime\(\)|datetime\('now'\))$`,
+	)
+)
+
+// translateDefault converts a column default to the target dialect.
+// Expressions other than literals and the current time are dropped, since
+// their functions rarely exist elsewhere; ok is false when it was dropped.
+func translateDefault(def string, col columnType, source, target string) (string, bool) {
+	def = strings.TrimSpace(def)
+	if def == "" || strings.EqualFold(def, "NULL") {
+		return "", true
+	}
+	// SQL Server wraps defaults in parentheses: ((0)), ('x')
+	for strings.HasPrefix(def, "(") && strings.HasSuffix(def, ")") &&
+		balancedParens(def[1:len(def)-1]) {
+		def = strings.TrimSpace(def[1 : len(def)-1])
+	}
+	def = strings.TrimSpace(pgCastPattern.ReplaceAllString(def, ""))
+
+	if currentTimePattern.MatchString(def) {
+		return "CURRENT_TIMESTAMP", true
+	}
+
+	if col.kind == kindBoolean {
+		switch strings.ToLower(strings.Trim(def, "'")) {
+		case "1", "true", "t", "b'1'", "y":
+			return booleanLiteralFor(target, true), true
+		case "0", "false", "f", "b'0'", "n":
+			return booleanLiteralFor(target, false), true
+		}
+	}
+	if looksNumeric(strings.TrimPrefix(def, "-")) {
+		return def, true
+	}
+	if strings.HasPrefix(def, "'") || strings.HasPrefix(strings.ToUpper(def), "N'") {
+		tokens := tokenizeSQL(strings.TrimPrefix(strings.TrimPrefix(def, "N"), "n"), source)
+		if len(tokens) == 1 && tokens[0].kind == tokString {
+			return QuoteStringFor(target, tokens[0].text), true
+		}
+		return "", false
+	}
+	// MySQL reports plain string defaults without their quotes
+	if source == "mysql" && !strings.Contains(def, "(") {
+		return QuoteStringFor(target, def), true
+	}
+	return "", false
+}
+
+func balancedParens(s string) bool {
+	depth := 0
+	for _, ch := range s {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// renderCreateTable writes a CREATE TABLE for the target dialect with every
+// identifier quoted. constraints are extra table constraints already in the
+// target's SQL, such as foreign keys with their actions.
+func renderCreateTable(def TableDefinition, constraints []string, dialect string) string {
+	quote := func(name string) string { return QuoteIdentifierFor(dialect, name) }
+	if dialect == "clickhouse" {
+		// No foreign keys or checks, and NULL has to be declared
+		return clickhouseCreateTableSQL(def, quote)
+	}
+
+	stmt := createTableSQL(def, quote)
+	if len(constraints) == 0 {
+		return stmt
+	}
+	return strings.TrimSuffix(stmt, "\n);") + ",\n    " +
+		strings.Join(constraints, ",\n    ") + "\n);"
+}
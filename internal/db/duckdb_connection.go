@@ -5,6 +5,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 
 	_ "github.com/duckdb/duckdb-go/v2"
@@ -64,6 +65,10 @@ func (d *DuckDBConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (d *DuckDBConnection) GetDB() *sql.DB {
+	return d.db
+}
+
 func (d *DuckDBConnection) GetTableMetadata(tableName string) (*TableMetadata, error) {
 	if d.db == nil {
 		return nil, fmt.Errorf("database is not open")
@@ -178,10 +183,10 @@ func (d *DuckDBConnection) GetViews() ([]string, error) {
 	}
 
 	query := `
-		SELECT table_name
-		FROM information_schema.views
-		WHERE table_schema NOT IN ('information_schema', 'pg_catalog')
-		ORDER BY table_name
+		SELECT view_name
+		FROM duckdb_views()
+		WHERE NOT internal
+		ORDER BY view_name
 	`
 
 	rows, err := d.db.Query(query)
@@ -330,3 +335,227 @@ func parseDuckDBArray(s string) []string {
 	}
 	return result
 }
+
+func (d *DuckDBConnection) GetIndexes(tableName string) ([]IndexInfo, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	// Primary keys and unique constraints have indexes of their own, but
+	// duckdb_indexes() only lists the ones made with CREATE INDEX
+	query := `
+		SELECT constraint_name,
+		       constraint_column_names::VARCHAR,
+		       true,
+		       constraint_type = 'PRIMARY KEY',
+		       ''
+		FROM duckdb_constraints()
+		WHERE table_name = ?
+		  AND constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+		UNION ALL
+		SELECT index_name,
+		       expressions::VARCHAR,
+		       is_unique,
+		       is_primary,
+		       COALESCE(sql, '')
+		FROM duckdb_indexes()
+		WHERE table_name = ?
+	`
+
+	rows, err := d.db.Query(query, tableName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var idx IndexInfo
+		var columns, definition string
+		if err := rows.Scan(
+			&idx.Name,
+			&columns,
+			&idx.Unique,
+			&idx.Primary,
+			&definition,
+		); err == nil {
+			idx.Columns = parseDuckDBArray(columns)
+			idx.Method = "art"
+			idx.Definition = strings.TrimSuffix(strings.TrimSpace(definition), ";")
+			indexes = append(indexes, idx)
+		}
+	}
+
+	return indexes, nil
+}
+
+func (d *DuckDBConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	if d.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	query := `
+		SELECT constraint_name,
+		       constraint_type,
+		       constraint_column_names::VARCHAR,
+		       constraint_text
+		FROM duckdb_constraints()
+		WHERE table_name = ?
+		  AND constraint_type IN ('CHECK', 'FOREIGN KEY')
+		ORDER BY constraint_type, constraint_name
+	`
+
+	rows, err := d.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var constraints []ConstraintInfo
+	for rows.Next() {
+		var c ConstraintInfo
+		var columns string
+		if err := rows.Scan(&c.Name, &c.Type, &columns, &c.Definition); err == nil {
+			c.Columns = parseDuckDBArray(columns)
+			constraints = append(constraints, c)
+		}
+	}
+
+	return constraints, nil
+}
+
+func (d *DuckDBConnection) GetTriggers(tableName string) ([]TriggerInfo, error) {
+	return nil, fmt.Errorf("DuckDB does not support triggers")
+}
+
+func (d *DuckDBConnection) BuildRenameIndexSQL(
+	tableName string,
+	index IndexInfo,
+	newName string,
+) string {
+	if index.Definition == "" {
+		return fmt.Sprintf(
+			"-- Index '%s' belongs to a constraint and can't be renamed.",
+			index.Name,
+		)
+	}
+	return fmt.Sprintf(
+		"-- DuckDB can't rename indexes, so the index is recreated.\nDROP INDEX %s;\n%s;",
+		index.Name,
+		RenameInDefinition(index.Definition, index.Name, newName),
+	)
+}
+
+func (d *DuckDBConnection) BuildAddConstraintSQL(
+	tableName, constraintName, definition string,
+) string {
+	return fmt.Sprintf(
+		"-- DuckDB can't add constraints to an existing table.\n-- Recreate '%s' with the constraint instead:\n-- CONSTRAINT %s %s",
+		tableName,
+		constraintName,
+		definition,
+	)
+}
+
+func (d *DuckDBConnection) BuildDropConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+) string {
+	return fmt.Sprintf(
+		"-- DuckDB can't drop constraints from an existing table.\n-- Recreate '%s' without:\n-- %s",
+		tableName,
+		constraint.Definition,
+	)
+}
+
+func (d *DuckDBConnection) BuildRenameConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+	newName string,
+) string {
+	return "-- DuckDB names constraints itself; they can't be renamed."
+}
+
+func (d *DuckDBConnection) BuildCreateTriggerSQL(
+	tableName, triggerName string,
+) string {
+	return "-- DuckDB does not support triggers."
+}
+
+var duckdbNextvalPattern = regexp.MustCompile(`nextval\('([^']+)'\)`)
+
+// GetTableDDL returns the statements DuckDB keeps in its catalog. Sequences
+// used by column defaults are created first, starting where they left off.
+func (d *DuckDBConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	if d.db == nil {
+		return TableDDL{}, fmt.Errorf("database not open")
+	}
+
+	var create string
+	err := d.db.QueryRow(
+		"SELECT sql FROM duckdb_tables() WHERE table_name = ?",
+		tableName,
+	).Scan(&create)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("table %q not found: %w", tableName, err)
+	}
+
+	var before []string
+	for _, match := range duckdbNextvalPattern.FindAllStringSubmatch(create, -1) {
+		var start, increment int64
+		err := d.db.QueryRow(`
+			SELECT COALESCE(last_value + increment_by, start_value), increment_by
+			FROM duckdb_sequences()
+			WHERE sequence_name = ?
+		`, match[1]).Scan(&start, &increment)
+		if err != nil {
+			return TableDDL{}, fmt.Errorf("failed to read sequence %s: %w", match[1], err)
+		}
+		before = append(before, fmt.Sprintf(
+			"CREATE SEQUENCE IF NOT EXISTS %s START WITH %d INCREMENT BY %d;",
+			match[1], start, increment,
+		))
+	}
+	before = append(before, strings.TrimRight(create, ";")+";")
+
+	rows, err := d.db.Query(
+		"SELECT sql FROM duckdb_indexes() WHERE table_name = ? AND sql IS NOT NULL",
+		tableName,
+	)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var after []string
+	for rows.Next() {
+		var sql string
+		if err := rows.Scan(&sql); err != nil {
+			return TableDDL{}, err
+		}
+		after = append(after, strings.TrimRight(sql, ";")+";")
+	}
+
+	return TableDDL{
+		Create: strings.Join(before, "\n"),
+		After:  strings.Join(after, "\n"),
+	}, nil
+}
+
+func (d *DuckDBConnection) GetViewDDL(viewName string) (string, error) {
+	if d.db == nil {
+		return "", fmt.Errorf("database not open")
+	}
+
+	var sql string
+	err := d.db.QueryRow(
+		"SELECT sql FROM duckdb_views() WHERE view_name = ? AND NOT internal",
+		viewName,
+	).Scan(&sql)
+	if err != nil {
+		return "", fmt.Errorf("view %q not found: %w", viewName, err)
+	}
+	return strings.TrimRight(sql, ";") + ";", nil
+}
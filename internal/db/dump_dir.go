@@ -0,0 +1,411 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestFile is the name of a directory dump's manifest
+const ManifestFile = "manifest.json"
+
+// DumpManifest describes a directory dump: the options it was made with
+// and each table's file, in the order they load. Tables are marked complete
+// as their files are finished, which is what an interrupted export resumes
+// from.
+type DumpManifest struct {
+	Connection    string      `json:"connection"`
+	Database      string      `json:"database"`
+	Dialect       string      `json:"dialect"`
+	Compression   string      `json:"compression,omitempty"`
+	IncludeCreate bool        `json:"include_create"`
+	DropIfExists  bool        `json:"drop_if_exists"`
+	NoData        bool        `json:"no_data"`
+	Masked        bool        `json:"masked,omitempty"`
+	Snapshot      string      `json:"snapshot,omitempty"`
+	Snapshots     []string    `json:"snapshots,omitempty"` // each run's, once a resumed export spans several
+	Started       time.Time   `json:"started"`
+	Complete      bool        `json:"complete"`
+	Drop          string      `json:"drop,omitempty"`  // DROP statements, loaded first
+	Views         string      `json:"views,omitempty"` // CREATE VIEW statements, loaded last
+	Tables        []DumpTable `json:"tables"`
+}
+
+// DumpTable is one table of a directory dump
+type DumpTable struct {
+	Name     string `json:"name"`
+	File     string `json:"file"`            // CREATE TABLE and rows
+	After    string `json:"after,omitempty"` // indexes and triggers
+	Rows     int    `json:"rows"`
+	Complete bool   `json:"complete"`
+}
+
+// ReadDumpManifest reads the manifest of the directory dump in dir
+func ReadDumpManifest(dir string) (*DumpManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest DumpManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	return &manifest, nil
+}
+
+func writeDumpManifest(dir string, manifest *DumpManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Replaced in one step, so an interrupted export never leaves half a manifest
+	path := filepath.Join(dir, ManifestFile)
+	if err := os.WriteFile(path+".partial", append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".partial", path)
+}
+
+// writeDumpFile writes one file of a directory dump under a temporary name,
+// renaming it once complete
+func writeDumpFile(
+	dir, name, compression string,
+	write func(io.Writer) error,
+) error {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path + ".partial")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := NewCompressWriter(f, compression)
+	if err != nil {
+		return err
+	}
+	if err := write(w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(path+".partial", path)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// exportDir writes a dump as a directory: a file per table with its
+// CREATE TABLE and rows, a file per table for its indexes and triggers,
+// files for the drops and views, and the manifest listing them in load
+// order. opts.Jobs tables are exported at once, each worker in its own
+// session of a shared snapshot where the database has one.
+func exportDir(
+	conn DatabaseConnection,
+	tables []string,
+	opts ExportOptions,
+) error {
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return fmt.Errorf("could not create %s: %w", opts.Dir, err)
+	}
+
+	manifest, err := ReadDumpManifest(opts.Dir)
+	switch {
+	case err == nil && !opts.Resume:
+		return fmt.Errorf(
+			"%s already holds an export; use --resume to finish it or choose another directory",
+			opts.Dir,
+		)
+	case err == nil:
+		if mismatch := manifest.mismatch(conn, opts); mismatch != "" {
+			return fmt.Errorf(
+				"cannot resume the export in %s: it was made with %s",
+				opts.Dir,
+				mismatch,
+			)
+		}
+		if manifest.Complete {
+			fmt.Fprintf(opts.Progress, "The export in %s is already complete.\n", opts.Dir)
+			return nil
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		manifest, err = startDumpDir(conn, tables, opts)
+		if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+
+	var pending []int
+	for i, table := range manifest.Tables {
+		_, statErr := os.Stat(filepath.Join(opts.Dir, table.File))
+		if !table.Complete || statErr != nil {
+			pending = append(pending, i)
+		}
+	}
+	total := len(manifest.Tables)
+	if skipped := total - len(pending); skipped > 0 {
+		fmt.Fprintf(
+			opts.Progress,
+			"Resuming: %d of %d table(s) already exported.\n",
+			skipped,
+			total,
+		)
+	}
+
+	jobs := max(opts.Jobs, 1)
+	sessions, snapshot, release := openExportSessions(conn, jobs)
+	defer release()
+	if snapshot != "" {
+		fmt.Fprintf(opts.Progress, "Reading from a %s.\n", snapshot)
+	} else if jobs > 1 {
+		fmt.Fprintf(
+			opts.Progress,
+			"No shared snapshot on %s: each table is read as it is exported.\n",
+			conn.GetDbType(),
+		)
+	}
+	manifest.recordSnapshot(snapshot, total-len(pending) > 0)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		started  = total - len(pending)
+	)
+	work := make(chan int)
+	for _, session := range sessions {
+		wg.Add(1)
+		go func(session *exportSession) {
+			defer wg.Done()
+			for i := range work {
+				mu.Lock()
+				table := manifest.Tables[i]
+				started++
+				fmt.Fprintf(
+					opts.Progress,
+					"[%d/%d] Exporting table: %s\n",
+					started,
+					total,
+					table.Name,
+				)
+				mu.Unlock()
+
+				err := exportTableFile(conn, session, &table, opts)
+
+				mu.Lock()
+				if err == nil {
+					manifest.Tables[i] = table
+					err = writeDumpManifest(opts.Dir, manifest)
+				} else {
+					err = fmt.Errorf("error exporting table %q: %w", table.Name, err)
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(session)
+	}
+
+	for _, i := range pending {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	manifest.Complete = true
+	if err := writeDumpManifest(opts.Dir, manifest); err != nil {
+		return err
+	}
+	fmt.Fprintf(
+		opts.Progress,
+		"Done. %d table(s) exported to %s.\n",
+		total,
+		opts.Dir,
+	)
+	return nil
+}
+
+// startDumpDir plans a new directory dump and writes its manifest, drops
+// and views
+func startDumpDir(
+	conn DatabaseConnection,
+	tables []string,
+	opts ExportOptions,
+) (*DumpManifest, error) {
+	plan, err := planExport(conn, tables, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := CompressionExt(opts.Compression)
+	manifest := &DumpManifest{
+		Connection:    conn.GetName(),
+		Database:      conn.GetDbType(),
+		Dialect:       opts.TargetDialect,
+		Compression:   opts.Compression,
+		IncludeCreate: opts.IncludeCreate,
+		DropIfExists:  opts.DropIfExists,
+		NoData:        opts.NoData,
+		Masked:        opts.Mask.Enabled(),
+		Started:       time.Now(),
+	}
+	for i, name := range plan.tables {
+		base := fmt.Sprintf("%04d_%s", i+1, unsafeFileChars.ReplaceAllString(name, "_"))
+		manifest.Tables = append(manifest.Tables, DumpTable{
+			Name: name,
+			File: base + ".sql" + ext,
+		})
+	}
+
+	if opts.DropIfExists {
+		manifest.Drop = "drop.sql" + ext
+		err := writeDumpFile(opts.Dir, manifest.Drop, opts.Compression, func(w io.Writer) error {
+			writeDropStatements(w, plan, dialectOf(conn), opts)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(plan.views) > 0 {
+		manifest.Views = "views.sql" + ext
+		err := writeDumpFile(opts.Dir, manifest.Views, opts.Compression, func(w io.Writer) error {
+			writeViews(w, plan)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(plan.untranslated) > 0 {
+		fmt.Fprintf(
+			opts.Progress,
+			"Skipping views (not translated): %s\n",
+			strings.Join(plan.untranslated, ", "),
+		)
+	}
+
+	return manifest, writeDumpManifest(opts.Dir, manifest)
+}
+
+// recordSnapshot notes the snapshot a run reads from. A run that resumes
+// after tables were already exported reads from a new one, so the dump no
+// longer comes from a single snapshot.
+func (m *DumpManifest) recordSnapshot(snapshot string, resumed bool) {
+	if !resumed {
+		m.Snapshot, m.Snapshots = snapshot, nil
+		return
+	}
+	if m.Snapshot == "" && snapshot == "" && len(m.Snapshots) == 0 {
+		// Never read from a snapshot
+		return
+	}
+	label := func(s string) string {
+		if s == "" {
+			return "no snapshot"
+		}
+		return s
+	}
+	if len(m.Snapshots) == 0 {
+		m.Snapshots = []string{label(m.Snapshot)}
+	}
+	m.Snapshots = append(m.Snapshots, label(snapshot))
+	m.Snapshot = ""
+}
+
+// mismatch names the first option, or the connection, a resumed export
+// differs in
+func (m *DumpManifest) mismatch(conn DatabaseConnection, opts ExportOptions) string {
+	switch {
+	case m.Connection != conn.GetName():
+		return "connection " + m.Connection
+	case m.Database != conn.GetDbType():
+		return "a " + m.Database + " database"
+	case m.Dialect != opts.TargetDialect:
+		return "dialect " + m.Dialect
+	case m.Compression != opts.Compression:
+		if m.Compression == "" {
+			return "no compression"
+		}
+		return "--" + m.Compression
+	case m.IncludeCreate != opts.IncludeCreate:
+		return "a different --no-create"
+	case m.DropIfExists != opts.DropIfExists:
+		return "a different --drop"
+	case m.NoData != opts.NoData:
+		return "a different --no-data"
+	case m.Masked != opts.Mask.Enabled():
+		return "different masking"
+	}
+	return ""
+}
+
+// exportTableFile writes a table's file and, when it has any, the file with
+// its indexes and triggers, and marks the table complete
+func exportTableFile(
+	conn DatabaseConnection,
+	session *exportSession,
+	table *DumpTable,
+	opts ExportOptions,
+) error {
+	var after string
+	var rows int
+	err := writeDumpFile(opts.Dir, table.File, opts.Compression, func(w io.Writer) error {
+		tableOpts := opts
+		tableOpts.Output = w
+		if opts.TargetDialect == "mysql" {
+			fmt.Fprintf(w, "SET FOREIGN_KEY_CHECKS = 0;\n")
+		}
+		var err error
+		after, rows, err = exportTable(conn, session, table.Name, tableOpts)
+		if err != nil {
+			return err
+		}
+		if opts.TargetDialect == "mysql" {
+			fmt.Fprintf(w, "\nSET FOREIGN_KEY_CHECKS = 1;\n")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	table.After = ""
+	if after != "" {
+		ext := CompressionExt(opts.Compression)
+		table.After = strings.TrimSuffix(table.File, ".sql"+ext) + ".after.sql" + ext
+		err = writeDumpFile(opts.Dir, table.After, opts.Compression, func(w io.Writer) error {
+			writeSectionHeader(w, fmt.Sprintf("Indexes and triggers: %s", table.Name))
+			_, err := fmt.Fprintf(w, "%s\n", after)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	table.Rows = rows
+	table.Complete = true
+	return nil
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDumpManifest_RecordSnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		manifest      DumpManifest
+		snapshot      string
+		resumed       bool
+		wantSnapshot  string
+		wantSnapshots []string
+	}{
+		{
+			name:         "a fresh run keeps its snapshot",
+			manifest:     DumpManifest{Snapshots: []string{"old", "older"}},
+			snapshot:     "snapshot 1",
+			wantSnapshot: "snapshot 1",
+		},
+		{
+			name:          "a resumed run records both",
+			manifest:      DumpManifest{Snapshot: "snapshot 1"},
+			snapshot:      "snapshot 2",
+			resumed:       true,
+			wantSnapshots: []string{"snapshot 1", "snapshot 2"},
+		},
+		{
+			name:          "a resumed run without a snapshot",
+			manifest:      DumpManifest{Snapshot: "snapshot 1"},
+			resumed:       true,
+			wantSnapshots: []string{"snapshot 1", "no snapshot"},
+		},
+		{
+			name:          "a second resume adds to the list",
+			manifest:      DumpManifest{Snapshots: []string{"snapshot 1", "snapshot 2"}},
+			snapshot:      "snapshot 3",
+			resumed:       true,
+			wantSnapshots: []string{"snapshot 1", "snapshot 2", "snapshot 3"},
+		},
+		{
+			name:    "never from a snapshot",
+			resumed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.manifest
+			m.recordSnapshot(tt.snapshot, tt.resumed)
+			if m.Snapshot != tt.wantSnapshot || !slices.Equal(m.Snapshots, tt.wantSnapshots) {
+				t.Errorf("snapshot = %q, snapshots = %v", m.Snapshot, m.Snapshots)
+			}
+		})
+	}
+}
+
+func openShop(t *testing.T, name, path string) DatabaseConnection {
+	t.Helper()
+	conn, err := NewSQLiteConnection(name, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestDumpManifest_Mismatch(t *testing.T) {
+	conn := openShop(t, "shop", filepath.Join(t.TempDir(), "shop.db"))
+	manifest := DumpManifest{
+		Connection:    "shop",
+		Database:      "sqlite",
+		Dialect:       "sqlite",
+		IncludeCreate: true,
+	}
+	same := ExportOptions{TargetDialect: "sqlite", IncludeCreate: true}
+
+	tests := []struct {
+		name     string
+		manifest func(*DumpManifest)
+		opts     func(*ExportOptions)
+		want     string
+	}{
+		{name: "same options", want: ""},
+		{name: "connection", manifest: func(m *DumpManifest) { m.Connection = "prod" }, want: "connection prod"},
+		{name: "database", manifest: func(m *DumpManifest) { m.Database = "postgres" }, want: "a postgres database"},
+		{name: "dialect", opts: func(o *ExportOptions) { o.TargetDialect = "postgres" }, want: "dialect sqlite"},
+		{name: "compression", opts: func(o *ExportOptions) { o.Compression = CompressionGzip }, want: "no compression"},
+		{name: "create", opts: func(o *ExportOptions) { o.IncludeCreate = false }, want: "a different --no-create"},
+		{name: "data", opts: func(o *ExportOptions) { o.NoData = true }, want: "a different --no-data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, opts := manifest, same
+			if tt.manifest != nil {
+				tt.manifest(&m)
+			}
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+			if got := m.mismatch(conn, opts); got != tt.want {
+				t.Errorf("mismatch = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportSQL_DirResume(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "shop.db")
+	conn := openShop(t, "shop", dbPath)
+	for _, stmt := range []string{
+		`CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES customers(id))`,
+		`INSERT INTO customers VALUES (1, 'Ann'), (2, 'Bo')`,
+		`INSERT INTO orders VALUES (1, 1)`,
+	} {
+		if err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "dump")
+	var progress bytes.Buffer
+	opts := ExportOptions{IncludeCreate: true, Dir: dir, Jobs: 2, Progress: &progress}
+	if err := ExportSQL(conn, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := ReadDumpManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifest.Complete || len(manifest.Tables) != 2 || manifest.Tables[0].Name != "customers" ||
+		manifest.Tables[0].Rows != 2 || manifest.Connection != "shop" {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+
+	if err := ExportSQL(conn, nil, opts); err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Errorf("exporting into a used directory: %v", err)
+	}
+
+	// Interrupted before orders was finished
+	manifest.Complete = false
+	manifest.Tables[1].Complete = false
+	if err := os.Remove(filepath.Join(dir, manifest.Tables[1].File)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeDumpManifest(dir, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	other := openShop(t, "copy", dbPath)
+	opts.Resume = true
+	if err := ExportSQL(other, nil, opts); err == nil || !strings.Contains(err.Error(), "connection shop") {
+		t.Errorf("resuming from another connection: %v", err)
+	}
+
+	progress.Reset()
+	if err := ExportSQL(conn, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(progress.String(), "Resuming: 1 of 2 table(s) already exported.") ||
+		strings.Contains(progress.String(), "Exporting table: customers") {
+		t.Errorf("progress = %s", progress.String())
+	}
+	manifest, err = ReadDumpManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifest.Complete || !manifest.Tables[1].Complete || manifest.Tables[1].Rows != 1 {
+		t.Errorf("resumed manifest = %+v", manifest)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, manifest.Tables[1].File))
+	if err != nil || !strings.Contains(string(content), `INSERT INTO "orders"`) {
+		t.Errorf("orders file = %s, %v", content, err)
+	}
+}
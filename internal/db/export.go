@@ -1,10 +1,15 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/caiolandgraf/pam/internal/mask"
 )
 
 // ExportOptions configures how the SQL dump is generated.
@@ -19,29 +24,92 @@ type ExportOptions struct {
 	Output io.Writer
 	// Progress is the destination writer for status messages (e.g. os.Stderr).
 	Progress io.Writer
+	// TargetDialect is the dialect the dump is written for, such as
+	// "sqlite"; empty means the connection's own. A different dialect gets
+	// DDL rebuilt from the column metadata with translated types.
+	TargetDialect string
+	// Dir writes the dump as a directory, one file per table plus a
+	// manifest, instead of to Output.
+	Dir string
+	// Jobs is how many tables are exported at once into Dir.
+	Jobs int
+	// Compression compresses the files written to Dir: CompressionGzip,
+	// CompressionZstd or empty for plain SQL.
+	Compression string
+	// Resume skips the tables Dir's manifest already lists as complete.
+	Resume bool
+	// Mask masks the values of the columns its rules match; nil exports
+	// them as they are.
+	Mask *mask.Masker
+
+	// listed holds the table names the connection lists, which are safe to
+	// quote
+	listed map[string]bool
+}
+
+// tableIdentifier is how the dump writes tableName for dialect. Names the
+// connection lists are quoted; a name the user typed is left as typed, so
+// the database folds its case as it would in a query, unless the dump is
+// translated, where the CREATE TABLE quotes it too.
+func (o ExportOptions) tableIdentifier(dialect, tableName string, translated bool) string {
+	if o.listed[tableName] || translated {
+		return QuoteIdentifierFor(dialect, tableName)
+	}
+	return tableName
+}
+
+// listedTables is the set of names conn.GetTables returns, empty when it
+// can't list them
+func listedTables(conn DatabaseConnection) map[string]bool {
+	listed := map[string]bool{}
+	names, _ := conn.GetTables()
+	for _, name := range names {
+		listed[name] = true
+	}
+	return listed
 }
 
 // ExportSQL exports one or all tables from the given connection as a SQL dump.
-// If tables is empty, all tables are exported.
+// If tables is empty, all tables are exported, followed by the views.
+//
+// Tables are written in foreign key order, parents first, using the
+// database's own DDL where the connection provides it. Indexes, triggers and
+// other deferred statements come after all the data. With opts.Dir set,
+// the dump is written as a directory instead (see exportDir).
 func ExportSQL(
 	conn DatabaseConnection,
 	tables []string,
 	opts ExportOptions,
 ) error {
-	if opts.Output == nil {
+	if opts.Dir == "" && opts.Output == nil {
 		return fmt.Errorf("export: Output writer is required")
 	}
 	if opts.Progress == nil {
 		opts.Progress = io.Discard
 	}
+	source := dialectOf(conn)
+	if opts.TargetDialect == "" {
+		opts.TargetDialect = source
+	}
+	target, err := NormalizeDialect(opts.TargetDialect)
+	if err != nil {
+		return err
+	}
+	opts.TargetDialect = target
+	opts.listed = listedTables(conn)
 
-	if len(tables) == 0 {
-		var err error
-		tables, err = conn.GetTables()
-		if err != nil {
-			return fmt.Errorf("could not list tables: %w", err)
-		}
+	if opts.Dir != "" {
+		return exportDir(conn, tables, opts)
+	}
+
+	plan, err := planExport(conn, tables, opts)
+	if err != nil {
+		return err
 	}
+	tables, views := plan.tables, plan.views
+
+	sessions, snapshot, release := openExportSessions(conn, 1)
+	defer release()
 
 	// Write dump header
 	fmt.Fprintf(opts.Output, "-- SQL Dump generated by pam\n")
@@ -53,8 +121,37 @@ func ExportSQL(
 		time.Now().Format(time.RFC3339),
 	)
 	fmt.Fprintf(opts.Output, "-- Tables     : %d\n", len(tables))
+	if len(views) > 0 {
+		fmt.Fprintf(opts.Output, "-- Views      : %d\n", len(views))
+	}
+	if target != source {
+		fmt.Fprintf(opts.Output, "-- Dialect    : %s\n", target)
+	}
+	if snapshot != "" {
+		fmt.Fprintf(opts.Output, "-- Snapshot   : %s\n", snapshot)
+	}
+	if opts.Mask.Enabled() {
+		fmt.Fprintf(opts.Output, "-- Masked     : yes\n")
+	}
+	if len(plan.untranslated) > 0 {
+		fmt.Fprintf(
+			opts.Output,
+			"-- Skipped    : views %s (not translated)\n",
+			strings.Join(plan.untranslated, ", "),
+		)
+	}
 	fmt.Fprintf(opts.Output, "\n")
 
+	if target == "mysql" {
+		// Self-referencing rows and cycles can't be ordered
+		fmt.Fprintf(opts.Output, "SET FOREIGN_KEY_CHECKS = 0;\n\n")
+	}
+
+	if opts.DropIfExists {
+		writeDropStatements(opts.Output, plan, source, opts)
+	}
+
+	deferred := make([]string, len(tables))
 	for i, tableName := range tables {
 		fmt.Fprintf(
 			opts.Progress,
@@ -64,37 +161,153 @@ func ExportSQL(
 			tableName,
 		)
 
-		if err := exportTable(conn, tableName, opts); err != nil {
+		after, _, err := exportTable(conn, sessions[0], tableName, opts)
+		if err != nil {
 			return fmt.Errorf("error exporting table %q: %w", tableName, err)
 		}
+		deferred[i] = after
+	}
+
+	for i, after := range deferred {
+		if after == "" {
+			continue
+		}
+		writeSectionHeader(
+			opts.Output,
+			fmt.Sprintf("Indexes and triggers: %s", tables[i]),
+		)
+		fmt.Fprintf(opts.Output, "%s\n", after)
+	}
+
+	writeViews(opts.Output, plan)
+
+	if target == "mysql" {
+		fmt.Fprintf(opts.Output, "\nSET FOREIGN_KEY_CHECKS = 1;\n")
 	}
 
 	fmt.Fprintf(opts.Progress, "Done. %d table(s) exported.\n", len(tables))
 	return nil
 }
 
+// exportPlan is what an export writes: the tables in foreign key order and,
+// for a full dump, the views in dependency order
+type exportPlan struct {
+	tables       []string
+	views        []string
+	viewDDL      map[string]string
+	untranslated []string // views left out of a translated dump
+}
+
+func planExport(
+	conn DatabaseConnection,
+	tables []string,
+	opts ExportOptions,
+) (exportPlan, error) {
+	allTables := len(tables) == 0
+	if allTables {
+		var err error
+		tables, err = conn.GetTables()
+		if err != nil {
+			return exportPlan{}, fmt.Errorf("could not list tables: %w", err)
+		}
+	}
+	plan := exportPlan{
+		tables:  orderTablesByForeignKeys(conn, tables),
+		viewDDL: map[string]string{},
+	}
+
+	// Views only make sense in a full dump, where what they select from exists
+	if allTables && opts.IncludeCreate && opts.TargetDialect != dialectOf(conn) {
+		// A view's query is written in the source's SQL
+		plan.untranslated, _ = conn.GetViews()
+	} else if allTables && opts.IncludeCreate {
+		// Not every connection can list views; the dump goes on without them
+		names, _ := conn.GetViews()
+		for _, view := range names {
+			ddl, err := conn.GetViewDDL(view)
+			if err != nil {
+				ddl = fmt.Sprintf(
+					"-- Warning: could not generate CREATE VIEW for %q: %v",
+					view,
+					err,
+				)
+			}
+			plan.viewDDL[view] = ddl
+		}
+		plan.views = orderViewsByReferences(names, plan.viewDDL)
+	}
+	return plan, nil
+}
+
+// writeDropStatements drops the plan's views and tables, dependents first
+func writeDropStatements(out io.Writer, plan exportPlan, source string, opts ExportOptions) {
+	target := opts.TargetDialect
+	for i := len(plan.views) - 1; i >= 0; i-- {
+		// Views are only dumped by name from GetViews
+		fmt.Fprintf(
+			out,
+			"DROP VIEW IF EXISTS %s;\n",
+			QuoteIdentifierFor(target, plan.views[i]),
+		)
+	}
+	for i := len(plan.tables) - 1; i >= 0; i-- {
+		fmt.Fprintf(
+			out,
+			"DROP TABLE IF EXISTS %s;\n",
+			opts.tableIdentifier(target, plan.tables[i], source != target),
+		)
+	}
+}
+
+func writeViews(out io.Writer, plan exportPlan) {
+	for _, view := range plan.views {
+		writeSectionHeader(out, fmt.Sprintf("View: %s", view))
+		fmt.Fprintf(out, "%s\n", plan.viewDDL[view])
+	}
+}
+
+func writeSectionHeader(out io.Writer, title string) {
+	fmt.Fprintf(out, "\n-- -----------------------------------------------\n")
+	fmt.Fprintf(out, "-- %s\n", title)
+	fmt.Fprintf(out, "-- -----------------------------------------------\n\n")
+}
+
 // exportTable writes the DDL and/or data for a single table to opts.Output.
+// It returns the statements that must wait until all data is loaded, and
+// the number of rows written.
 func exportTable(
 	conn DatabaseConnection,
+	session *exportSession,
 	tableName string,
 	opts ExportOptions,
-) error {
-	fmt.Fprintf(
-		opts.Output,
-		"\n-- -----------------------------------------------\n",
-	)
-	fmt.Fprintf(opts.Output, "-- Table: %s\n", tableName)
-	fmt.Fprintf(
-		opts.Output,
-		"-- -----------------------------------------------\n\n",
-	)
+) (string, int, error) {
+	writeSectionHeader(opts.Output, fmt.Sprintf("Table: %s", tableName))
 
-	if opts.DropIfExists {
-		fmt.Fprintf(opts.Output, "DROP TABLE IF EXISTS %s;\n\n", tableName)
+	source, target := dialectOf(conn), opts.TargetDialect
+
+	// Translated dumps and SQL Server's identity inserts need the columns
+	var cols []ColumnInfo
+	if source != target || target == "sqlserver" {
+		var err error
+		cols, err = conn.GetColumnDetails(tableName)
+		if err != nil {
+			return "", 0, fmt.Errorf("GetColumnDetails: %w", err)
+		}
 	}
 
+	var after string
 	if opts.IncludeCreate {
-		ddl, err := buildCreateTableSQL(conn, tableName)
+		var ddl TableDDL
+		var err error
+		if source != target {
+			ddl, err = translatedTableDDL(conn, session, tableName, cols, opts)
+		} else {
+			ddl, err = conn.GetTableDDL(tableName)
+		}
+		if err != nil && source == target {
+			// No native DDL for this database: rebuild it from the columns
+			ddl.Create, err = buildCreateTableSQL(conn, tableName)
+		}
 		if err != nil {
 			fmt.Fprintf(
 				opts.Output,
@@ -103,21 +316,144 @@ func exportTable(
 				err,
 			)
 		} else {
-			fmt.Fprintf(opts.Output, "%s\n\n", ddl)
+			fmt.Fprintf(opts.Output, "%s\n\n", ddl.Create)
+			after = ddl.After
 		}
 	}
 
+	rows := 0
 	if !opts.NoData {
-		if err := exportTableData(conn, tableName, opts.Output); err != nil {
-			return fmt.Errorf("could not export data: %w", err)
+		var err error
+		rows, err = exportTableData(conn, session, tableName, cols, opts)
+		if err != nil {
+			return "", 0, fmt.Errorf("could not export data: %w", err)
 		}
 	}
 
-	return nil
+	return after, rows, nil
+}
+
+// orderTablesByForeignKeys sorts tables so every table follows the tables it
+// references. Tables in a reference cycle keep their original order.
+func orderTablesByForeignKeys(
+	conn DatabaseConnection,
+	tables []string,
+) []string {
+	return orderByDependencies(tables, func(table string) []string {
+		fks, err := conn.GetForeignKeys(table)
+		if err != nil {
+			return nil
+		}
+		deps := make([]string, len(fks))
+		for i, fk := range fks {
+			deps[i] = fk.ReferencedTable
+		}
+		return deps
+	})
+}
+
+// orderViewsByReferences sorts views so a view follows the views its
+// definition mentions
+func orderViewsByReferences(views []string, ddl map[string]string) []string {
+	return orderByDependencies(views, func(view string) []string {
+		var deps []string
+		for _, other := range views {
+			pattern := `(?i)\b` + regexp.QuoteMeta(other) + `\b`
+			if other != view && regexp.MustCompile(pattern).MatchString(ddl[view]) {
+				deps = append(deps, other)
+			}
+		}
+		return deps
+	})
+}
+
+// orderByDependencies is a stable topological sort: each name is placed
+// after its dependencies among names, otherwise keeping the input order.
+// Names are compared case-insensitively; unresolvable cycles are emitted in
+// input order.
+func orderByDependencies(names []string, deps func(string) []string) []string {
+	pending := map[string][]string{}
+	for _, name := range names {
+		for _, dep := range deps(name) {
+			dep = strings.ToLower(dep)
+			if dep != strings.ToLower(name) {
+				pending[name] = append(pending[name], dep)
+			}
+		}
+	}
+	known := map[string]bool{}
+	for _, name := range names {
+		known[strings.ToLower(name)] = true
+	}
+
+	placed := map[string]bool{}
+	ready := func(name string) bool {
+		for _, dep := range pending[name] {
+			if known[dep] && !placed[dep] {
+				return false
+			}
+		}
+		return true
+	}
+
+	byLower := map[string]string{}
+	for _, name := range names {
+		byLower[strings.ToLower(name)] = name
+	}
+	// inCycle reports whether name depends on itself through names not
+	// placed yet
+	inCycle := func(name string) bool {
+		start := strings.ToLower(name)
+		seen := map[string]bool{}
+		stack := []string{name}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, dep := range pending[current] {
+				if dep == start {
+					return true
+				}
+				if known[dep] && !placed[dep] && !seen[dep] {
+					seen[dep] = true
+					stack = append(stack, byLower[dep])
+				}
+			}
+		}
+		return false
+	}
+
+	ordered := make([]string, 0, len(names))
+	for len(ordered) < len(names) {
+		next := ""
+		for _, name := range names {
+			if !placed[strings.ToLower(name)] && ready(name) {
+				next = name
+				break
+			}
+		}
+		if next == "" {
+			// A cycle: break it at its first name, so names that merely
+			// depend on the cycle still come after it
+			var remaining []string
+			for _, name := range names {
+				if !placed[strings.ToLower(name)] {
+					remaining = append(remaining, name)
+				}
+			}
+			next = remaining[0]
+			if i := slices.IndexFunc(remaining, inCycle); i >= 0 {
+				next = remaining[i]
+			}
+		}
+		placed[strings.ToLower(next)] = true
+		ordered = append(ordered, next)
+	}
+	return ordered
 }
 
 // buildCreateTableSQL generates a portable CREATE TABLE statement for the given table.
-// It uses GetColumnDetails to inspect column metadata.
+// It uses GetColumnDetails to inspect column metadata, for connections
+// without GetTableDDL.
 func buildCreateTableSQL(
 	conn DatabaseConnection,
 	tableName string,
@@ -173,37 +509,172 @@ func buildCreateTableSQL(
 	return ddl, nil
 }
 
-// exportTableData streams all rows of a table as INSERT statements.
+// translatedTableDDL rebuilds a table's DDL for another dialect from its
+// column metadata. Types, defaults and auto-increment are translated,
+// foreign keys and checks become table constraints, and secondary indexes
+// go in After.
+func translatedTableDDL(
+	conn DatabaseConnection,
+	session *exportSession,
+	tableName string,
+	cols []ColumnInfo,
+	opts ExportOptions,
+) (TableDDL, error) {
+	if len(cols) == 0 {
+		return TableDDL{}, fmt.Errorf("no columns found for table %q", tableName)
+	}
+	source, target := dialectOf(conn), opts.TargetDialect
+	quote := func(name string) string { return QuoteIdentifierFor(target, name) }
+
+	primaryKeys := 0
+	for _, col := range cols {
+		if col.IsPrimaryKey {
+			primaryKeys++
+		}
+	}
+
+	def := TableDefinition{Name: tableName}
+	var before, after []string
+	for _, col := range cols {
+		kind := parseColumnType(col.DataType, source)
+		column := ColumnDefinition{
+			Name:       col.Name,
+			DataType:   renderColumnType(kind, target),
+			Nullable:   col.Nullable != "NO" && !col.IsPrimaryKey,
+			PrimaryKey: col.IsPrimaryKey,
+		}
+		// SQLite flags every INTEGER key column, but only a lone one is
+		// the rowid
+		auto := IsAutoIncrement(col) && (source != "sqlite" || primaryKeys == 1)
+		if auto {
+			var b, a []string
+			column.DataType, column.Default, b, a = autoIncrement(
+				target, tableName, col.Name, column.DataType,
+				nextAutoValue(session, opts.tableIdentifier(source, tableName, false), QuoteIdentifierFor(source, col.Name)),
+			)
+			before = append(before, b...)
+			after = append(after, a...)
+		} else if value, ok := translateDefault(col.DefaultValue, kind, source, target); ok {
+			column.Default = value
+		}
+		def.Columns = append(def.Columns, column)
+	}
+
+	var constraints []string
+	if target != "clickhouse" {
+		// Not every connection lists constraints; the table goes without
+		existing, _ := conn.GetConstraints(tableName)
+		for _, c := range existing {
+			stmt := translateFragment(c.Definition, source, target)
+			if c.Name != "" && !strings.HasPrefix(strings.ToLower(c.Name), "sqlite_") {
+				stmt = "CONSTRAINT " + quote(c.Name) + " " + stmt
+			}
+			constraints = append(constraints, stmt)
+		}
+
+		indexes, _ := conn.GetIndexes(tableName)
+		for _, index := range indexes {
+			if index.Primary || len(index.Columns) == 0 ||
+				slices.ContainsFunc(index.Columns, func(c string) bool {
+					return strings.ContainsAny(c, "() ")
+				}) {
+				// Keys come with the table; expression indexes don't travel
+				continue
+			}
+			unique := ""
+			if index.Unique {
+				unique = "UNIQUE "
+			}
+			indexName := index.Name
+			if strings.HasPrefix(strings.ToLower(indexName), "sqlite_") {
+				// SQLite's names for UNIQUE columns are reserved
+				indexName = tableName + "_" + strings.Join(index.Columns, "_") + "_key"
+			}
+			after = append(after, fmt.Sprintf(
+				"CREATE %sINDEX %s ON %s (%s);",
+				unique, quote(indexName), quote(tableName),
+				quoteList(index.Columns, quote),
+			))
+		}
+	}
+
+	statements := append(before, renderCreateTable(def, constraints, target))
+	return TableDDL{
+		Create: strings.Join(statements, "\n\n"),
+		After:  strings.Join(after, "\n"),
+	}, nil
+}
+
+// nextAutoValue returns the value after a column's largest, where an
+// auto-increment column of the translated table should continue. table and
+// column are written as the source reads them.
+func nextAutoValue(session *exportSession, table, column string) int64 {
+	rows, err := session.query(
+		fmt.Sprintf("SELECT MAX(%s) FROM %s", column, table),
+	)
+	if err != nil {
+		return 1
+	}
+	defer rows.Close()
+
+	var max sql.NullInt64
+	if !rows.Next() || rows.Scan(&max) != nil || !max.Valid {
+		return 1
+	}
+	return max.Int64 + 1
+}
+
+// exportTableData streams all rows of a table as INSERT statements written
+// for the target dialect, returning how many. cols, when given, supply the
+// declared column types, which tell booleans stored as integers apart.
+// Values of the columns masker matches are masked.
 func exportTableData(
 	conn DatabaseConnection,
+	session *exportSession,
 	tableName string,
-	out io.Writer,
-) error {
-	query := fmt.Sprintf("SELECT * FROM %s", tableName)
+	cols []ColumnInfo,
+	opts ExportOptions,
+) (int, error) {
+	source, target := dialectOf(conn), opts.TargetDialect
+	out := opts.Output
+	query := fmt.Sprintf("SELECT * FROM %s", opts.tableIdentifier(source, tableName, false))
 
-	rows, err := conn.ExecQuery(query)
+	rows, err := session.query(query)
 	if err != nil {
-		return fmt.Errorf("SELECT failed: %w", err)
+		return 0, fmt.Errorf("SELECT failed: %w", err)
 	}
 	defer rows.Close()
 
 	columns, columnTypes, data, err := FormatTableDataWithTypes(rows)
 	if err != nil {
-		return fmt.Errorf("could not read rows: %w", err)
+		return 0, fmt.Errorf("could not read rows: %w", err)
 	}
 
 	if len(data) == 0 {
 		fmt.Fprintf(out, "-- (no rows in %s)\n", tableName)
-		return nil
+		return 0, nil
 	}
+	data = opts.Mask.Rows(tableName, columns, data)
 
-	// Quote column names generically with double-quotes (standard SQL).
+	declared := map[string]string{}
+	identity := false
+	for _, col := range cols {
+		declared[strings.ToLower(col.Name)] = col.DataType
+		identity = identity || IsAutoIncrement(col)
+	}
+
+	quote := func(name string) string { return QuoteIdentifierFor(target, name) }
+	table := opts.tableIdentifier(target, tableName, source != target)
 	quotedCols := make([]string, len(columns))
 	for i, c := range columns {
-		quotedCols[i] = quoteIdentifier(c)
+		quotedCols[i] = quote(c)
 	}
 	colList := strings.Join(quotedCols, ", ")
 
+	if identity && target == "sqlserver" {
+		// Explicit values for an identity column need IDENTITY_INSERT
+		fmt.Fprintf(out, "SET IDENTITY_INSERT %s ON;\n", table)
+	}
 	for _, row := range data {
 		values := make([]string, len(row))
 		for i, val := range row {
@@ -211,28 +682,96 @@ func exportTableData(
 			if i < len(columnTypes) {
 				dbType = columnTypes[i]
 			}
-			values[i] = formatSQLValue(val, dbType)
+			if t, ok := declared[strings.ToLower(columns[i])]; ok {
+				dbType = t
+			}
+			values[i] = formatSQLValue(val, dbType, source, target)
 		}
 		fmt.Fprintf(out, "INSERT INTO %s (%s) VALUES (%s);\n",
-			tableName,
+			table,
 			colList,
 			strings.Join(values, ", "),
 		)
 	}
+	if identity && target == "sqlserver" {
+		fmt.Fprintf(out, "SET IDENTITY_INSERT %s OFF;\n", table)
+	}
 
-	return nil
+	return len(data), nil
 }
 
+// InsertStatements renders rows as INSERT statements for conn's dialect.
+// types are the columns' declared types, which decide how each value is
+// written, and "NULL" is a null. Up to perStatement rows share a statement
+// where the dialect accepts several.
+func InsertStatements(
+	conn DatabaseConnection,
+	tableName string,
+	columns, types []string,
+	rows [][]string,
+	perStatement int,
+) []string {
+	dialect := dialectOf(conn)
+	if dialect == "oracle" || dialect == "firebird" || perStatement < 1 {
+		// No multi-row VALUES
+		perStatement = 1
+	}
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = QuoteIdentifierFor(dialect, c)
+	}
+	prefix := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES ",
+		QuoteIdentifierFor(dialect, tableName),
+		strings.Join(quoted, ", "),
+	)
+
+	var statements []string
+	for start := 0; start < len(rows); start += perStatement {
+		end := min(start+perStatement, len(rows))
+		tuples := make([]string, 0, end-start)
+		for _, row := range rows[start:end] {
+			values := make([]string, len(row))
+			for i, val := range row {
+				dbType := ""
+				if i < len(types) {
+					dbType = types[i]
+				}
+				values[i] = formatSQLValue(val, dbType, dialect, dialect)
+			}
+			tuples = append(tuples, "("+strings.Join(values, ", ")+")")
+		}
+		statements = append(statements, prefix+strings.Join(tuples, ", "))
+	}
+	return statements
+}
+
+// goTimeLayout is how fmt prints a time.Time
+const goTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
 // formatSQLValue converts a string-formatted cell value into a proper SQL literal.
-// It uses the database column type name to decide whether to quote the value.
-func formatSQLValue(val, dbTypeName string) string {
+// It uses the database column type name, as the source dialect spells it,
+// to decide whether to quote the value, and writes booleans and string
+// escapes the way the target dialect reads them.
+func formatSQLValue(val, dbTypeName, source, target string) string {
 	if val == "NULL" {
 		return "NULL"
 	}
 
 	upperType := strings.ToUpper(dbTypeName)
+	parsed := parseColumnType(dbTypeName, source)
+
+	if isBooleanType(upperType) || parsed.kind == kindBoolean {
+		switch strings.ToUpper(val) {
+		case "TRUE", "1", "T", "YES":
+			return booleanLiteralFor(target, true)
+		case "FALSE", "0", "F", "NO":
+			return booleanLiteralFor(target, false)
+		}
+	}
 
-	if isNumericType(upperType) {
+	if isNumericType(upperType) || isNumericKind(parsed.kind) {
 		// Sanity-check: make sure the value actually looks numeric before
 		// emitting it unquoted. If not, fall through to the string path.
 		if looksNumeric(val) {
@@ -240,18 +779,18 @@ func formatSQLValue(val, dbTypeName string) string {
 		}
 	}
 
-	if isBooleanType(upperType) {
-		switch strings.ToUpper(val) {
-		case "TRUE", "1", "T", "YES":
-			return "TRUE"
-		case "FALSE", "0", "F", "NO":
-			return "FALSE"
+	// Drivers hand back timestamps as time.Time, which prints with the zone
+	// name appended; databases want the ISO form
+	if t, err := time.Parse(goTimeLayout, val); err == nil {
+		if t.Location() == time.UTC {
+			val = t.Format("2006-01-02 15:04:05.999999999")
+		} else {
+			val = t.Format("2006-01-02 15:04:05.999999999-07:00")
 		}
 	}
 
-	// Default: treat as a string literal — escape single quotes.
-	escaped := strings.ReplaceAll(val, "'", "''")
-	return fmt.Sprintf("'%s'", escaped)
+	// Default: treat as a string literal
+	return QuoteStringFor(target, val)
 }
 
 // isNumericType returns true when the database type name represents a numeric
@@ -274,6 +813,15 @@ func isNumericType(upperType string) bool {
 	return false
 }
 
+// isNumericKind reports the kinds written as bare numbers
+func isNumericKind(kind string) bool {
+	switch kind {
+	case kindSmallInt, kindInteger, kindBigInt, kindDecimal, kindFloat, kindDouble:
+		return true
+	}
+	return false
+}
+
 // isBooleanType returns true when the database type name represents a boolean.
 func isBooleanType(upperType string) bool {
 	return upperType == "BOOL" || upperType == "BOOLEAN"
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOrderByDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		deps  map[string][]string
+		want  []string
+	}{
+		{
+			name:  "no dependencies keeps the order",
+			names: []string{"c", "a", "b"},
+			want:  []string{"c", "a", "b"},
+		},
+		{
+			name:  "chain",
+			names: []string{"order_items", "orders", "customers"},
+			deps: map[string][]string{
+				"order_items": {"orders"},
+				"orders":      {"customers"},
+			},
+			want: []string{"customers", "orders", "order_items"},
+		},
+		{
+			name:  "case-insensitive and self references",
+			names: []string{"Child", "Parent"},
+			deps:  map[string][]string{"Child": {"PARENT", "child"}},
+			want:  []string{"Parent", "Child"},
+		},
+		{
+			name:  "dependencies outside the list are ignored",
+			names: []string{"a", "b"},
+			deps:  map[string][]string{"a": {"elsewhere"}},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:  "a cycle keeps the input order",
+			names: []string{"x", "a", "b"},
+			deps: map[string][]string{
+				"x": {"b"},
+				"a": {"b"},
+				"b": {"a"},
+			},
+			want: []string{"a", "b", "x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderByDependencies(tt.names, func(name string) []string { return tt.deps[name] })
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportSQL_ForeignKeyOrder(t *testing.T) {
+	conn, err := NewSQLiteConnection("shop", filepath.Join(t.TempDir(), "shop.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, stmt := range []string{
+		// Created children first, so listing order alone would be wrong
+		`CREATE TABLE items (id INTEGER PRIMARY KEY, order_id INTEGER REFERENCES orders(id))`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES customers(id))`,
+		`CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT)`,
+		`CREATE VIEW big_orders AS SELECT * FROM order_totals WHERE n > 1`,
+		`CREATE VIEW order_totals AS SELECT order_id, count(*) AS n FROM items GROUP BY order_id`,
+		`INSERT INTO customers VALUES (1, 'Ann')`,
+		`INSERT INTO orders VALUES (1, 1)`,
+		`INSERT INTO items VALUES (1, 1)`,
+	} {
+		if err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := ExportSQL(conn, nil, ExportOptions{IncludeCreate: true, DropIfExists: true, Output: &out}); err != nil {
+		t.Fatal(err)
+	}
+	dump := out.String()
+
+	before := func(first, second string) {
+		t.Helper()
+		i, j := strings.Index(dump, first), strings.Index(dump, second)
+		if i < 0 || j < 0 || i > j {
+			t.Errorf("%q should come before %q in:\n%s", first, second, dump)
+		}
+	}
+	before("CREATE TABLE customers", "CREATE TABLE orders")
+	before("CREATE TABLE orders", "CREATE TABLE items")
+	before(`INSERT INTO "customers"`, `INSERT INTO "orders"`)
+	before(`INSERT INTO "orders"`, `INSERT INTO "items"`)
+	before("CREATE VIEW order_totals", "CREATE VIEW big_orders")
+	// Dependents are dropped first
+	before(`DROP VIEW IF EXISTS "big_orders"`, `DROP VIEW IF EXISTS "order_totals"`)
+	before(`DROP TABLE IF EXISTS "items"`, `DROP TABLE IF EXISTS "customers"`)
+}
@@ -72,6 +72,10 @@ func (f *FirebirdConnection) Exec(sqlStr string, args ...any) error {
 	return err
 }
 
+func (f *FirebirdConnection) GetDB() *sql.DB {
+	return f.db
+}
+
 func (f *FirebirdConnection) SetSchema(schema string) {
 	// Firebird doesn't use schemas like PostgreSQL
 	// No-op implementation
@@ -398,3 +402,26 @@ func (f *FirebirdConnection) ApplyRowLimit(sqlStr string, limit int) string {
 
 	return fmt.Sprintf("%s FIRST %d %s", before, limit, after)
 }
+
+func (f *FirebirdConnection) BuildRenameTableSQL(oldName, newName string) string {
+	return fmt.Sprintf(
+		"-- Firebird can't rename tables; clone '%s' to '%s' and drop it instead.",
+		oldName,
+		newName,
+	)
+}
+
+func (f *FirebirdConnection) BuildTruncateTableSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s;", tableName)
+}
+
+func (f *FirebirdConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	return fmt.Sprintf(
+		"-- Firebird has no CREATE TABLE ... AS; create '%s' and copy '%s' into it with INSERT ... SELECT.",
+		target,
+		source,
+	)
+}
@@ -1,9 +1,19 @@
 package db
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // ImportOptions configures how the SQL import is performed.
@@ -14,10 +24,33 @@ type ImportOptions struct {
 	DryRun bool
 	// Progress is the destination writer for status messages.
 	Progress io.Writer
+	// SourceDialect is the dialect the dump was written for, such as
+	// "mysql"; empty means the connection's own. Statements are translated
+	// when it differs.
+	SourceDialect string
+	// SingleTransaction runs the whole import in one transaction, rolled
+	// back if a statement fails.
+	SingleTransaction bool
+	// BatchSize commits every BatchSize statements; a failure rolls back
+	// only the current batch. Zero runs each statement on its own.
+	BatchSize int
+	// OnProgress, when set, is called a few times a second while
+	// statements run.
+	OnProgress func(ImportProgress)
+}
+
+// ImportProgress is a snapshot of a running import.
+type ImportProgress struct {
+	Statements int           // statements run so far
+	Bytes      int64         // input bytes read so far
+	Size       int64         // total input size, zero when unknown (stdin)
+	Elapsed    time.Duration // time since the import started
 }
 
 // ImportError holds a single failed statement and its error.
 type ImportError struct {
+	File      string // file of a directory dump, empty for a single input
+	Line      int    // line the statement starts on, zero when unknown
 	Index     int
 	Statement string
 	Err       error
@@ -25,117 +58,648 @@ type ImportError struct {
 
 // ImportResult holds the summary of an import operation.
 type ImportResult struct {
-	Total    int
-	Executed int
-	Skipped  int // empty / whitespace-only statements
-	Errors   []ImportError
+	Total      int
+	Executed   int
+	Skipped    int // empty / whitespace-only statements
+	RolledBack int // executed statements undone by a rolled back transaction
+	Errors     []ImportError
+}
+
+// WriteErrorReport writes the result as JSON: the counts, then each failed
+// statement with its file, line and error.
+func (r *ImportResult) WriteErrorReport(w io.Writer) error {
+	type reportError struct {
+		File      string `json:"file,omitempty"`
+		Line      int    `json:"line,omitempty"`
+		Index     int    `json:"index"`
+		Statement string `json:"statement"`
+		Error     string `json:"error"`
+	}
+	report := struct {
+		Total      int           `json:"total"`
+		Executed   int           `json:"executed"`
+		RolledBack int           `json:"rolled_back"`
+		Errors     []reportError `json:"errors"`
+	}{
+		Total:      r.Total,
+		Executed:   r.Executed,
+		RolledBack: r.RolledBack,
+		Errors:     []reportError{},
+	}
+	for _, ie := range r.Errors {
+		report.Errors = append(report.Errors, reportError{
+			File:      ie.File,
+			Line:      ie.Line,
+			Index:     ie.Index,
+			Statement: ie.Statement,
+			Error:     ie.Err.Error(),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
 }
 
 // ImportSQL reads SQL from r, splits it into statements, and executes each one.
 // It returns a summary result and a non-nil error only when the import is
 // aborted early (ContinueOnError == false and a statement fails).
+// Gzip and zstd input is decompressed, and statements are read as they
+// are run, so the input need not fit in memory unless it is translated.
 func ImportSQL(
 	conn DatabaseConnection,
 	r io.Reader,
 	opts ImportOptions,
 ) (*ImportResult, error) {
+	im, err := newImporter(conn, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer im.close()
+	if f, ok := r.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			im.size = info.Size()
+		}
+	}
+
+	if err := im.load(r, ""); err != nil {
+		return im.result, err
+	}
+	return im.result, im.finish()
+}
+
+// ImportDir loads a directory dump written by pam export --dir: the drops,
+// each table's file in the manifest's dependency order, then the indexes
+// and triggers, then the views. The statements are translated from the
+// manifest's dialect unless opts.SourceDialect says otherwise.
+func ImportDir(
+	conn DatabaseConnection,
+	dir string,
+	opts ImportOptions,
+) (*ImportResult, error) {
+	manifest, err := ReadDumpManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the dump's manifest: %w", err)
+	}
+	if !manifest.Complete {
+		return nil, fmt.Errorf(
+			"the export in %s is incomplete; finish it with pam export --dir %s --resume",
+			dir,
+			dir,
+		)
+	}
+	if opts.SourceDialect == "" {
+		opts.SourceDialect = manifest.Dialect
+	}
+
+	var files []string
+	if manifest.Drop != "" {
+		files = append(files, manifest.Drop)
+	}
+	for _, table := range manifest.Tables {
+		files = append(files, table.File)
+	}
+	for _, table := range manifest.Tables {
+		if table.After != "" {
+			files = append(files, table.After)
+		}
+	}
+	if manifest.Views != "" {
+		files = append(files, manifest.Views)
+	}
+
+	im, err := newImporter(conn, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer im.close()
+	for _, name := range files {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			im.size += info.Size()
+		}
+	}
+
+	for i, name := range files {
+		fmt.Fprintf(im.opts.Progress, "[%d/%d] %s\n", i+1, len(files), name)
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return im.result, err
+		}
+		err = im.load(f, name)
+		f.Close()
+		if err != nil {
+			return im.result, err
+		}
+	}
+	return im.result, im.finish()
+}
+
+// ExecBatch runs statements in one transaction, rolled back if one fails.
+// On ClickHouse, which has no transactions, they simply run in order.
+func ExecBatch(conn DatabaseConnection, statements []string) error {
+	im, err := newImporter(conn, ImportOptions{
+		SingleTransaction: dialectOf(conn) != "clickhouse",
+	})
+	if err != nil {
+		return err
+	}
+	defer im.close()
+	for _, stmt := range statements {
+		if err := im.exec(stmt); err != nil {
+			return err
+		}
+		im.pending++
+	}
+	return im.commit()
+}
+
+// transactionControlPattern matches a dump's own BEGIN and COMMIT, which
+// would end the transaction the import runs in
+var transactionControlPattern = regexp.MustCompile(
+	`(?i)^(BEGIN|START|COMMIT|ROLLBACK|END)(\s+(TRANSACTION|WORK))?$`,
+)
+
+// importer runs the statements of one or more inputs, in transactions when
+// opts ask for them
+type importer struct {
+	conn   DatabaseConnection
+	opts   ImportOptions
+	result *ImportResult
+
+	session *sql.Conn // the connection transactions run on
+	tx      *sql.Tx
+	pending int // statements run in tx
+
+	size         int64
+	read         int64 // bytes of the inputs already loaded
+	reading      *countingReader
+	started      time.Time
+	lastProgress time.Time
+}
+
+func newImporter(conn DatabaseConnection, opts ImportOptions) (*importer, error) {
 	if opts.Progress == nil {
 		opts.Progress = io.Discard
 	}
+	transactional := opts.SingleTransaction || opts.BatchSize > 0
+	if transactional && opts.ContinueOnError {
+		return nil, fmt.Errorf(
+			"continuing after errors cannot be combined with transactions: a failed statement aborts the transaction on some databases",
+		)
+	}
+
+	im := &importer{
+		conn:    conn,
+		opts:    opts,
+		result:  &ImportResult{},
+		started: time.Now(),
+	}
+	if !transactional || opts.DryRun {
+		return im, nil
+	}
 
-	raw, err := io.ReadAll(r)
+	if dialectOf(conn) == "clickhouse" {
+		return nil, fmt.Errorf("clickhouse does not support transactions")
+	}
+	pool := conn.GetDB()
+	if pool == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+	session, err := pool.Conn(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("could not read input: %w", err)
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	for _, stmt := range sessionSetupSQL(conn) {
+		// Open applies these to one pooled connection only
+		_, _ = session.ExecContext(context.Background(), stmt)
 	}
+	im.session = session
+	return im, nil
+}
 
-	statements := SplitSQLStatements(string(raw))
+// load runs the statements read from r. file names the input in errors
+// when it is part of a directory dump.
+func (im *importer) load(r io.Reader, file string) error {
+	im.reading = &countingReader{r: r}
+	defer func() {
+		im.read += im.reading.n
+		im.reading = nil
+	}()
 
-	fmt.Fprintf(
-		opts.Progress,
-		"Parsed %d statement(s).\n",
-		len(statements),
-	)
+	decompressed, err := NewDecompressReader(im.reading)
+	if err != nil {
+		return fmt.Errorf("could not decompress input: %w", err)
+	}
+	defer decompressed.Close()
 
-	result := &ImportResult{}
+	target := dialectOf(im.conn)
+	source := target
+	if im.opts.SourceDialect != "" {
+		if source, err = NormalizeDialect(im.opts.SourceDialect); err != nil {
+			return err
+		}
+	}
+	scanner := newStatementScanner(decompressed, backslashEscapes(source))
 
-	for idx, stmt := range statements {
-		trimmed := strings.TrimSpace(stmt)
-		if trimmed == "" {
-			result.Skipped++
-			continue
+	if source == target {
+		index := 0
+		for scanner.Scan() {
+			index++
+			stmt, line := scanner.Statement()
+			if err := im.run(stmt, file, index, line); err != nil {
+				return err
+			}
 		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("could not read input: %w", err)
+		}
+		fmt.Fprintf(im.opts.Progress, "Parsed %d statement(s).\n", index)
+		return nil
+	}
 
-		result.Total++
+	// Translation reads the whole dump first: it needs every key inserted
+	// before it can create the target's counters
+	var statements []string
+	var lines []int
+	for scanner.Scan() {
+		stmt, line := scanner.Statement()
+		statements = append(statements, stmt)
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("could not read input: %w", err)
+	}
+	translator, err := NewTranslator(source, target)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(im.opts.Progress, "Translating from %s to %s.\n", source, target)
+	fmt.Fprintf(im.opts.Progress, "Parsed %d statement(s).\n", len(statements))
 
-		if opts.DryRun {
-			fmt.Fprintf(
-				opts.Progress,
-				"  [%d] (dry-run) %s\n",
-				result.Total,
-				truncateStmt(trimmed, 80),
-			)
-			result.Executed++
-			continue
+	translator.learnKeys(statements)
+	index := 0
+	for i, stmt := range statements {
+		for _, translated := range translator.Translate(stmt) {
+			index++
+			if err := im.run(translated, file, index, lines[i]); err != nil {
+				return err
+			}
+		}
+	}
+	for _, stmt := range translator.deferred {
+		index++
+		if err := im.run(stmt, file, index, 0); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if err := conn.Exec(trimmed); err != nil {
-			ie := ImportError{
-				Index:     idx + 1,
-				Statement: trimmed,
-				Err:       err,
-			}
-			result.Errors = append(result.Errors, ie)
+// run executes one statement, committing when a batch is full
+func (im *importer) run(stmt, file string, index, line int) error {
+	result := im.result
+	trimmed := strings.TrimSpace(stmt)
+	if trimmed == "" ||
+		im.session != nil && transactionControlPattern.MatchString(trimmed) {
+		result.Skipped++
+		return nil
+	}
+
+	result.Total++
+	defer im.reportProgress(false)
 
-			fmt.Fprintf(
-				opts.Progress,
-				"  ✗ [%d] %v\n      SQL: %s\n",
+	if im.opts.DryRun {
+		fmt.Fprintf(
+			im.opts.Progress,
+			"  [%d] (dry-run) %s\n",
+			result.Total,
+			truncateStmt(trimmed, 80),
+		)
+		result.Executed++
+		return nil
+	}
+
+	if err := im.exec(trimmed); err != nil {
+		ie := ImportError{
+			File:      file,
+			Line:      line,
+			Index:     index,
+			Statement: trimmed,
+			Err:       err,
+		}
+		result.Errors = append(result.Errors, ie)
+
+		location := fmt.Sprintf("line %d", line)
+		if line == 0 {
+			location = fmt.Sprintf("statement %d", index)
+		}
+		if file != "" {
+			location = file + ", " + location
+		}
+		fmt.Fprintf(
+			im.opts.Progress,
+			"  ✗ [%d] %s: %v\n      SQL: %s\n",
+			result.Total,
+			location,
+			err,
+			truncateStmt(strings.ReplaceAll(trimmed, "\n", " "), 100),
+		)
+
+		if !im.opts.ContinueOnError {
+			im.rollback()
+			return fmt.Errorf(
+				"import stopped at statement %d: %w",
 				result.Total,
 				err,
-				truncateStmt(strings.ReplaceAll(trimmed, "\n", " "), 100),
 			)
+		}
+		return nil
+	}
 
-			if !opts.ContinueOnError {
-				return result, fmt.Errorf(
-					"import stopped at statement %d: %w",
-					result.Total,
-					err,
-				)
-			}
-		} else {
-			result.Executed++
+	result.Executed++
+	if im.tx != nil {
+		im.pending++
+		if im.opts.BatchSize > 0 && im.pending >= im.opts.BatchSize {
+			return im.commit()
 		}
 	}
+	return nil
+}
 
-	return result, nil
+func (im *importer) exec(stmt string) error {
+	if im.session == nil {
+		return im.conn.Exec(stmt)
+	}
+	if im.tx == nil {
+		tx, err := im.session.BeginTx(context.Background(), nil)
+		if err != nil {
+			return fmt.Errorf("could not begin a transaction: %w", err)
+		}
+		im.tx = tx
+	}
+	_, err := im.tx.Exec(stmt)
+	return err
+}
+
+func (im *importer) commit() error {
+	if im.tx == nil {
+		return nil
+	}
+	err := im.tx.Commit()
+	if err != nil {
+		im.result.RolledBack += im.pending
+		err = fmt.Errorf("could not commit: %w", err)
+	}
+	im.tx = nil
+	im.pending = 0
+	return err
+}
+
+func (im *importer) rollback() {
+	if im.tx == nil {
+		return
+	}
+	_ = im.tx.Rollback()
+	im.result.RolledBack += im.pending
+	if im.pending > 0 {
+		fmt.Fprintf(
+			im.opts.Progress,
+			"Rolled back %d statement(s).\n",
+			im.pending,
+		)
+	}
+	im.tx = nil
+	im.pending = 0
+}
+
+// finish commits the last transaction
+func (im *importer) finish() error {
+	im.reportProgress(true)
+	return im.commit()
+}
+
+func (im *importer) close() {
+	im.rollback()
+	if im.session != nil {
+		im.session.Close()
+	}
+}
+
+func (im *importer) reportProgress(final bool) {
+	if im.opts.OnProgress == nil {
+		return
+	}
+	now := time.Now()
+	if !final && now.Sub(im.lastProgress) < 100*time.Millisecond {
+		return
+	}
+	im.lastProgress = now
+	read := im.read
+	if im.reading != nil {
+		read += im.reading.n
+	}
+	im.opts.OnProgress(ImportProgress{
+		Statements: im.result.Total,
+		Bytes:      read,
+		Size:       im.size,
+		Elapsed:    now.Sub(im.started),
+	})
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+var (
+	createTriggerPattern = regexp.MustCompile(`(?is)^\s*CREATE\s+(OR\s+REPLACE\s+)?(TEMP\w*\s+)?TRIGGER\b`)
+	blockWordPattern     = regexp.MustCompile(`(?i)\b(BEGIN|CASE|END(\s+(IF|LOOP|WHILE|REPEAT))?)\b`)
+)
+
+// inTriggerBody reports whether stmt is a CREATE TRIGGER whose BEGIN ... END
+// body is still open. CASE ... END pairs inside the body are balanced out,
+// and END IF, END LOOP and the like don't close anything.
+func inTriggerBody(stmt string) bool {
+	if !createTriggerPattern.MatchString(stmt) {
+		return false
+	}
+	depth := 0
+	for _, word := range blockWordPattern.FindAllString(stmt, -1) {
+		switch strings.ToUpper(word) {
+		case "BEGIN", "CASE":
+			depth++
+		case "END":
+			depth--
+		}
+	}
+	return depth > 0
 }
 
 // SplitSQLStatements splits a SQL string into individual statements, correctly
 // handling:
 //   - single-quoted string literals (including ” escaped quotes)
+//   - double-quoted and backtick-quoted identifiers
+//   - PostgreSQL dollar-quoted bodies ($$ ... $$, $tag$ ... $tag$)
 //   - -- single-line comments
 //   - /* */ block comments
 //
 // Each returned statement has its surrounding whitespace trimmed and does NOT
 // include the trailing semicolon.
 func SplitSQLStatements(sql string) []string {
+	var statements []string
+	scanner := newStatementScanner(strings.NewReader(sql), false)
+	for scanner.Scan() {
+		stmt, _ := scanner.Statement()
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// statementScanner reads statements one at a time, splitting them the way
+// SplitSQLStatements does. With backslashes set, as for MySQL, a backslash
+// also escapes a quote inside strings.
+type statementScanner struct {
+	r           *bufio.Reader
+	backslashes bool
+
+	line     int // line of the rune last read
+	nextLine int
+	stmt     string
+	stmtLine int
+	err      error
+
+	current   strings.Builder
+	blank     bool // nothing but whitespace in current yet
+	startLine int
+}
+
+func newStatementScanner(r io.Reader, backslashes bool) *statementScanner {
+	return &statementScanner{
+		r:           bufio.NewReaderSize(r, 64*1024),
+		backslashes: backslashes,
+		nextLine:    1,
+		blank:       true,
+	}
+}
+
+// Statement returns the statement Scan found and the line it starts on
+func (s *statementScanner) Statement() (string, int) {
+	return s.stmt, s.stmtLine
+}
+
+// Err returns the first read error other than io.EOF
+func (s *statementScanner) Err() error {
+	return s.err
+}
+
+func (s *statementScanner) next() (rune, bool) {
+	ch, _, err := s.r.ReadRune()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+		return 0, false
+	}
+	s.line = s.nextLine
+	if ch == '\n' {
+		s.nextLine++
+	}
+	return ch, true
+}
+
+// peekIs reports whether the input continues with prefix, consuming it
+// if so
+func (s *statementScanner) peekIs(prefix string) bool {
+	ahead, _ := s.r.Peek(len(prefix))
+	if string(ahead) != prefix {
+		return false
+	}
+	_, _ = s.r.Discard(len(prefix))
+	return true
+}
+
+// peekDollarTag returns the rest of a dollar-quote opener ($$ or $tag$)
+// when the '$' just read starts one. Positional parameters such as $1 are
+// rejected because a tag may not start with a digit.
+func (s *statementScanner) peekDollarTag() (string, bool) {
+	for n := 1; n <= 64; n++ {
+		ahead, _ := s.r.Peek(n)
+		if len(ahead) < n {
+			return "", false
+		}
+		ch := ahead[n-1]
+		if ch == '$' {
+			_, _ = s.r.Discard(n)
+			return "$" + string(ahead), true
+		}
+		isLetter := ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+		isDigit := ch >= '0' && ch <= '9'
+		if !isLetter && !(isDigit && n > 1) {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func (s *statementScanner) write(text string) {
+	if s.blank && strings.TrimSpace(text) != "" {
+		s.blank = false
+		s.startLine = s.line
+	}
+	s.current.WriteString(text)
+}
+
+func (s *statementScanner) writeRune(ch rune) {
+	if s.blank && !unicode.IsSpace(ch) {
+		s.blank = false
+		s.startLine = s.line
+	}
+	s.current.WriteRune(ch)
+}
+
+// emit makes current the scanned statement when it isn't blank
+func (s *statementScanner) emit() bool {
+	stmt := strings.TrimSpace(s.current.String())
+	s.current.Reset()
+	startLine := s.startLine
+	s.blank = true
+	if stmt == "" {
+		return false
+	}
+	s.stmt, s.stmtLine = stmt, startLine
+	return true
+}
+
+// Scan advances to the next statement, returning false at the end of the
+// input or on a read error
+func (s *statementScanner) Scan() bool {
 	type lexState int
 	const (
 		stateNormal       lexState = iota
 		stateInString              // inside '...'
+		stateInIdent               // inside "..." or `...`
+		stateInDollar              // inside $tag$ ... $tag$
 		stateLineComment           // after --
 		stateBlockComment          // inside /* ... */
 	)
 
 	var (
-		statements []string
-		current    strings.Builder
 		state      = stateNormal
-		runes      = []rune(sql)
-		n          = len(runes)
+		identQuote rune
+		dollarTag  string
 	)
 
-	for i := 0; i < n; i++ {
-		ch := runes[i]
+	for {
+		ch, ok := s.next()
+		if !ok {
+			break
+		}
 
 		switch state {
 
@@ -143,72 +707,121 @@ func SplitSQLStatements(sql string) []string {
 			switch ch {
 			case '\'':
 				state = stateInString
-				current.WriteRune(ch)
+				s.writeRune(ch)
+
+			case '"', '`':
+				state = stateInIdent
+				identQuote = ch
+				s.writeRune(ch)
+
+			case '$':
+				if tag, ok := s.peekDollarTag(); ok {
+					state = stateInDollar
+					dollarTag = tag
+					s.write(tag)
+				} else {
+					s.writeRune(ch)
+				}
 
 			case '-':
-				if i+1 < n && runes[i+1] == '-' {
+				if s.peekIs("-") {
 					state = stateLineComment
-					i++ // consume second '-'
 				} else {
-					current.WriteRune(ch)
+					s.writeRune(ch)
 				}
 
 			case '/':
-				if i+1 < n && runes[i+1] == '*' {
+				if s.peekIs("*") {
 					state = stateBlockComment
-					i++ // consume '*'
 				} else {
-					current.WriteRune(ch)
+					s.writeRune(ch)
 				}
 
 			case ';':
+				// Inside a trigger body the semicolon ends a statement of
+				// the body, not the CREATE TRIGGER.
+				if inTriggerBody(s.current.String()) {
+					s.writeRune(ch)
+					continue
+				}
 				// Statement boundary — emit if non-empty.
-				if stmt := strings.TrimSpace(current.String()); stmt != "" {
-					statements = append(statements, stmt)
+				if s.emit() {
+					return true
 				}
-				current.Reset()
 
 			default:
-				current.WriteRune(ch)
+				s.writeRune(ch)
 			}
 
 		case stateInString:
-			current.WriteRune(ch)
+			s.writeRune(ch)
+			if ch == '\\' && s.backslashes {
+				if escaped, ok := s.next(); ok {
+					s.writeRune(escaped)
+				}
+				continue
+			}
 			if ch == '\'' {
 				// Two consecutive single quotes inside a string are an escape
 				// sequence for a literal quote — keep both and stay in string.
-				if i+1 < n && runes[i+1] == '\'' {
-					current.WriteRune('\'')
-					i++ // consume the second quote
+				if s.peekIs("'") {
+					s.writeRune('\'')
 				} else {
 					// Closing quote — back to normal.
 					state = stateNormal
 				}
 			}
 
+		case stateInIdent:
+			s.writeRune(ch)
+			if ch == identQuote {
+				state = stateNormal
+			}
+
+		case stateInDollar:
+			if ch == '$' && s.peekIs(dollarTag[1:]) {
+				s.write(dollarTag)
+				state = stateNormal
+			} else {
+				s.writeRune(ch)
+			}
+
 		case stateLineComment:
 			// Discard everything until end-of-line; the newline itself is
 			// preserved so that line-number tracking remains meaningful.
 			if ch == '\n' {
 				state = stateNormal
-				current.WriteRune(ch)
+				s.writeRune(ch)
 			}
 
 		case stateBlockComment:
 			// Discard everything until the closing '*/'.
-			if ch == '*' && i+1 < n && runes[i+1] == '/' {
+			if ch == '*' && s.peekIs("/") {
 				state = stateNormal
-				i++ // consume '/'
 			}
 		}
 	}
 
 	// Flush any trailing content that had no terminating semicolon.
-	if stmt := strings.TrimSpace(current.String()); stmt != "" {
-		statements = append(statements, stmt)
-	}
+	return s.emit()
+}
 
-	return statements
+// dollarQuoteTag reports whether a dollar-quote opener ($$ or $tag$) starts
+// at runes[i] and returns it. Positional parameters such as $1 are rejected
+// because a tag may not start with a digit.
+func dollarQuoteTag(runes []rune, i int) (string, bool) {
+	for j := i + 1; j < len(runes); j++ {
+		ch := runes[j]
+		if ch == '$' {
+			return string(runes[i : j+1]), true
+		}
+		isLetter := ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+		isDigit := ch >= '0' && ch <= '9'
+		if !isLetter && !(isDigit && j > i+1) {
+			return "", false
+		}
+	}
+	return "", false
 }
 
 // truncateStmt returns s truncated to maxLen runes, appending "..." when cut.
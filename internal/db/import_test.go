@@ -0,0 +1,112 @@
+package db
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type scanned struct {
+	stmt string
+	line int
+}
+
+func scanAll(t *testing.T, sql string, backslashes bool) []scanned {
+	t.Helper()
+	scanner := newStatementScanner(strings.NewReader(sql), backslashes)
+	var got []scanned
+	for scanner.Scan() {
+		stmt, line := scanner.Statement()
+		got = append(got, scanned{stmt, line})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestStatementScanner(t *testing.T) {
+	tests := []struct {
+		name        string
+		sql         string
+		backslashes bool
+		want        []scanned
+	}{
+		{
+			name: "statements and their lines",
+			sql:  "SELECT 1;\n\n  SELECT 2\n  FROM t;\nSELECT 3",
+			want: []scanned{{"SELECT 1", 1}, {"SELECT 2\n  FROM t", 3}, {"SELECT 3", 5}},
+		},
+		{
+			name: "semicolons in strings and identifiers",
+			sql:  `INSERT INTO "a;b" VALUES ('x;''y'); SELECT ` + "`c;d`" + `;`,
+			want: []scanned{{`INSERT INTO "a;b" VALUES ('x;''y')`, 1}, {"SELECT `c;d`", 1}},
+		},
+		{
+			name: "comments are dropped",
+			sql:  "-- header; not a statement\nSELECT 1; /* a;\nb */ SELECT 2 -- trailing;\n;",
+			want: []scanned{{"SELECT 1", 2}, {"SELECT 2", 3}},
+		},
+		{
+			name: "dollar-quoted bodies",
+			sql:  "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END $body$ LANGUAGE plpgsql;\nSELECT $1;",
+			want: []scanned{
+				{"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END $body$ LANGUAGE plpgsql", 1},
+				{"SELECT $1", 2},
+			},
+		},
+		{
+			name: "trigger bodies",
+			sql:  "CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = n + 1; END;\nSELECT 1;",
+			want: []scanned{
+				{"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = n + 1; END", 1},
+				{"SELECT 1", 2},
+			},
+		},
+		{
+			name:        "backslash escapes for MySQL",
+			sql:         `INSERT INTO t VALUES ('it\'s; fine'); SELECT 2;`,
+			backslashes: true,
+			want:        []scanned{{`INSERT INTO t VALUES ('it\'s; fine')`, 1}, {"SELECT 2", 1}},
+		},
+		{
+			name: "only blanks and comments",
+			sql:  " ;\n-- nothing\n;;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanAll(t, tt.sql, tt.backslashes); !slices.Equal(got, tt.want) {
+				t.Errorf("scanned %q\n  got %+v\n want %+v", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementScanner_PastTheBuffer(t *testing.T) {
+	// Statements and a string longer than the reader's buffer
+	long := strings.Repeat("x;", 50*1024)
+	sql := "SELECT '" + long + "';\n" + strings.Repeat("SELECT 1;\n", 10000)
+	got := scanAll(t, sql, false)
+	if len(got) != 10001 || got[0].stmt != "SELECT '"+long+"'" {
+		t.Fatalf("scanned %d statements", len(got))
+	}
+	if last := got[len(got)-1]; last.stmt != "SELECT 1" || last.line != 10001 {
+		t.Errorf("last = %+v", last)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("disk gone") }
+
+func TestStatementScanner_ReadError(t *testing.T) {
+	scanner := newStatementScanner(failingReader{}, false)
+	if scanner.Scan() {
+		t.Error("Scan should stop on a read error")
+	}
+	if err := scanner.Err(); err == nil || err.Error() != "disk gone" {
+		t.Errorf("Err = %v", err)
+	}
+}
@@ -32,6 +32,38 @@ type ForeignKey struct {
 	ReferencedColumn string
 }
 
+// IndexInfo describes an index on a table
+type IndexInfo struct {
+	Name       string
+	Columns    []string
+	Unique     bool
+	Primary    bool
+	Method     string // e.g. "btree", "hash", "NONCLUSTERED"
+	Definition string // CREATE INDEX statement, when the database keeps one
+}
+
+// Constraint types listed by GetConstraints
+const (
+	ConstraintCheck      = "CHECK"
+	ConstraintForeignKey = "FOREIGN KEY"
+)
+
+// ConstraintInfo describes a check or foreign key constraint on a table
+type ConstraintInfo struct {
+	Name       string
+	Type       string // ConstraintCheck or ConstraintForeignKey
+	Columns    []string
+	Definition string // e.g. "CHECK (amount > 0)"
+}
+
+// TriggerInfo describes a trigger on a table
+type TriggerInfo struct {
+	Name       string
+	Timing     string // BEFORE, AFTER or INSTEAD OF
+	Event      string // e.g. "INSERT OR UPDATE"
+	Definition string // CREATE TRIGGER statement, when the database keeps one
+}
+
 func ExtractTableNameFromSQL(sqlQuery string) string {
 	normalized := strings.Join(strings.Fields(strings.ToLower(sqlQuery)), " ")
 
@@ -170,3 +202,41 @@ func HasJoinClause(sqlQuery string) bool {
 
 	return false
 }
+
+var triggerTiming = regexp.MustCompile(
+	`(?is)\b(BEFORE|AFTER|INSTEAD\s+OF)\s+(.+?)\s+ON\s`,
+)
+
+// ParseTriggerTiming reads the timing and events from a CREATE TRIGGER
+// statement, e.g. "AFTER" and "INSERT OR UPDATE".
+func ParseTriggerTiming(definition string) (timing, event string) {
+	m := triggerTiming.FindStringSubmatch(definition)
+	if m == nil {
+		return "", ""
+	}
+	return strings.ToUpper(strings.Join(strings.Fields(m[1]), " ")),
+		strings.Join(strings.Fields(m[2]), " ")
+}
+
+// RenameInDefinition replaces the object name in a CREATE INDEX or CREATE
+// TRIGGER statement, for databases that can only rename by recreating.
+func RenameInDefinition(definition, oldName, newName string) string {
+	re := regexp.MustCompile(
+		`(?i)^(\s*CREATE\s+(?:OR\s+REPLACE\s+)?(?:UNIQUE\s+|TEMP(?:ORARY)?\s+)?(?:INDEX|TRIGGER)\s+(?:IF\s+NOT\s+EXISTS\s+)?)` +
+			"[\"`\\[]?" + regexp.QuoteMeta(oldName) + "[\"`\\]]?",
+	)
+	return re.ReplaceAllStringFunc(definition, func(m string) string {
+		return re.FindStringSubmatch(m)[1] + newName
+	})
+}
+
+// splitColumnList splits a comma-separated column list from a catalog query.
+func splitColumnList(list string) []string {
+	var columns []string
+	for _, col := range strings.Split(list, ",") {
+		if col = strings.TrimSpace(col); col != "" {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
@@ -79,6 +79,10 @@ func (m *MySQLConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (m *MySQLConnection) GetDB() *sql.DB {
+	return m.db
+}
+
 func (m *MySQLConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -512,3 +516,338 @@ func (m *MySQLConnection) BuildDeleteStatement(
 func (m *MySQLConnection) GetPlaceholder(paramIndex int) string {
 	return "?"
 }
+
+func (m *MySQLConnection) GetIndexes(tableName string) ([]IndexInfo, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			INDEX_NAME,
+			GROUP_CONCAT(
+				COALESCE(COLUMN_NAME, '<expression>')
+				ORDER BY SEQ_IN_INDEX SEPARATOR ','
+			),
+			MAX(NON_UNIQUE) = 0,
+			INDEX_TYPE
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_NAME = ?
+		AND TABLE_SCHEMA = DATABASE()
+		GROUP BY INDEX_NAME, INDEX_TYPE
+		ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME
+	`
+
+	rows, err := m.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var idx IndexInfo
+		var columns string
+		if err := rows.Scan(
+			&idx.Name,
+			&columns,
+			&idx.Unique,
+			&idx.Method,
+		); err == nil {
+			idx.Columns = splitColumnList(columns)
+			idx.Primary = idx.Name == "PRIMARY"
+			indexes = append(indexes, idx)
+		}
+	}
+
+	return indexes, nil
+}
+
+func (m *MySQLConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	var constraints []ConstraintInfo
+
+	// CHECK_CONSTRAINTS only exists from MySQL 8.0.16 and MariaDB 10.2, so
+	// older servers just list their foreign keys
+	checkQuery := `
+		SELECT tc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+		FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+		JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+			ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA
+			AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+		WHERE tc.TABLE_NAME = ?
+		AND tc.TABLE_SCHEMA = DATABASE()
+		AND tc.CONSTRAINT_TYPE = 'CHECK'
+		ORDER BY tc.CONSTRAINT_NAME
+	`
+	if rows, err := m.db.Query(checkQuery, tableName); err == nil {
+		for rows.Next() {
+			var c ConstraintInfo
+			var clause string
+			if rows.Scan(&c.Name, &clause) == nil {
+				c.Type = ConstraintCheck
+				c.Definition = "CHECK (" + strings.TrimSpace(clause) + ")"
+				constraints = append(constraints, c)
+			}
+		}
+		rows.Close()
+	}
+
+	fkQuery := `
+		SELECT
+			CONSTRAINT_NAME,
+			GROUP_CONCAT(COLUMN_NAME ORDER BY ORDINAL_POSITION SEPARATOR ','),
+			REFERENCED_TABLE_NAME,
+			GROUP_CONCAT(REFERENCED_COLUMN_NAME ORDER BY ORDINAL_POSITION SEPARATOR ',')
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_NAME = ?
+		AND TABLE_SCHEMA = DATABASE()
+		AND REFERENCED_TABLE_NAME IS NOT NULL
+		GROUP BY CONSTRAINT_NAME, REFERENCED_TABLE_NAME
+		ORDER BY CONSTRAINT_NAME
+	`
+	rows, err := m.db.Query(fkQuery, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ConstraintInfo
+		var columns, refTable, refColumns string
+		if err := rows.Scan(&c.Name, &columns, &refTable, &refColumns); err != nil {
+			continue
+		}
+		c.Type = ConstraintForeignKey
+		c.Columns = splitColumnList(columns)
+		c.Definition = fmt.Sprintf(
+			"FOREIGN KEY (`%s`) REFERENCES `%s` (`%s`)",
+			strings.Join(c.Columns, "`, `"),
+			refTable,
+			strings.Join(splitColumnList(refColumns), "`, `"),
+		)
+		constraints = append(constraints, c)
+	}
+
+	return constraints, nil
+}
+
+func (m *MySQLConnection) GetTriggers(tableName string) ([]TriggerInfo, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT TRIGGER_NAME, ACTION_TIMING, EVENT_MANIPULATION, ACTION_STATEMENT
+		FROM INFORMATION_SCHEMA.TRIGGERS
+		WHERE EVENT_OBJECT_TABLE = ?
+		AND EVENT_OBJECT_SCHEMA = DATABASE()
+		ORDER BY TRIGGER_NAME
+	`
+
+	rows, err := m.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerInfo
+	for rows.Next() {
+		var t TriggerInfo
+		var statement string
+		if err := rows.Scan(&t.Name, &t.Timing, &t.Event, &statement); err != nil {
+			continue
+		}
+		t.Definition = fmt.Sprintf(
+			"CREATE TRIGGER `%s` %s %s ON `%s` FOR EACH ROW %s",
+			t.Name,
+			t.Timing,
+			t.Event,
+			tableName,
+			statement,
+		)
+		triggers = append(triggers, t)
+	}
+
+	return triggers, nil
+}
+
+func (m *MySQLConnection) BuildCreateIndexSQL(
+	tableName, indexName string,
+	columns []string,
+	unique bool,
+) string {
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf(
+		"CREATE %s `%s` ON `%s` (`%s`);",
+		kind,
+		indexName,
+		tableName,
+		strings.Join(columns, "`, `"),
+	)
+}
+
+func (m *MySQLConnection) BuildDropIndexSQL(
+	tableName string,
+	index IndexInfo,
+) string {
+	if index.Primary {
+		return fmt.Sprintf("ALTER TABLE `%s` DROP PRIMARY KEY;", tableName)
+	}
+	return fmt.Sprintf("DROP INDEX `%s` ON `%s`;", index.Name, tableName)
+}
+
+func (m *MySQLConnection) BuildRenameIndexSQL(
+	tableName string,
+	index IndexInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE `%s` RENAME INDEX `%s` TO `%s`;",
+		tableName,
+		index.Name,
+		newName,
+	)
+}
+
+func (m *MySQLConnection) BuildAddConstraintSQL(
+	tableName, constraintName, definition string,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE `%s` ADD CONSTRAINT `%s` %s;",
+		tableName,
+		constraintName,
+		definition,
+	)
+}
+
+func (m *MySQLConnection) BuildDropConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE `%s` %s;",
+		tableName,
+		mysqlDropConstraintClause(constraint),
+	)
+}
+
+// BuildRenameConstraintSQL drops and re-adds the constraint in one
+// statement, since MySQL has no RENAME CONSTRAINT.
+func (m *MySQLConnection) BuildRenameConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"ALTER TABLE `%s`\n  %s,\n  ADD CONSTRAINT `%s` %s;",
+		tableName,
+		mysqlDropConstraintClause(constraint),
+		newName,
+		constraint.Definition,
+	)
+}
+
+func mysqlDropConstraintClause(constraint ConstraintInfo) string {
+	if constraint.Type == ConstraintForeignKey {
+		return fmt.Sprintf("DROP FOREIGN KEY `%s`", constraint.Name)
+	}
+	return fmt.Sprintf("DROP CHECK `%s`", constraint.Name)
+}
+
+func (m *MySQLConnection) BuildCreateTriggerSQL(
+	tableName, triggerName string,
+) string {
+	return fmt.Sprintf(
+		"CREATE TRIGGER `%s`\nBEFORE UPDATE ON `%s`\nFOR EACH ROW\nBEGIN\nEND;",
+		triggerName,
+		tableName,
+	)
+}
+
+func (m *MySQLConnection) BuildDropTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+) string {
+	return fmt.Sprintf("DROP TRIGGER `%s`;", trigger.Name)
+}
+
+// BuildRenameTriggerSQL recreates the trigger, since MySQL can't rename one.
+func (m *MySQLConnection) BuildRenameTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"DROP TRIGGER `%s`;\n%s;",
+		trigger.Name,
+		RenameInDefinition(trigger.Definition, trigger.Name, newName),
+	)
+}
+
+func (m *MySQLConnection) BuildCreateTableSQL(def TableDefinition) string {
+	return createTableSQL(def, backtickQuote)
+}
+
+func (m *MySQLConnection) BuildRenameTableSQL(oldName, newName string) string {
+	return fmt.Sprintf("RENAME TABLE `%s` TO `%s`;", oldName, newName)
+}
+
+func (m *MySQLConnection) BuildTruncateTableSQL(tableName string) string {
+	return fmt.Sprintf("TRUNCATE TABLE `%s`;", tableName)
+}
+
+func (m *MySQLConnection) BuildDropTableSQL(tableName string) string {
+	return fmt.Sprintf("DROP TABLE `%s`;", tableName)
+}
+
+// BuildCloneTableSQL uses CREATE TABLE ... LIKE, which keeps indexes and
+// constraints other than foreign keys.
+func (m *MySQLConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	stmt := fmt.Sprintf("CREATE TABLE `%s` LIKE `%s`;", target, source)
+	if withData {
+		stmt += fmt.Sprintf("\nINSERT INTO `%s` SELECT * FROM `%s`;", target, source)
+	}
+	return stmt
+}
+
+// GetTableDDL returns SHOW CREATE TABLE, which already carries the keys,
+// foreign keys and AUTO_INCREMENT position
+func (m *MySQLConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	if m.db == nil {
+		return TableDDL{}, fmt.Errorf("database not open")
+	}
+
+	var name, create string
+	err := m.db.QueryRow("SHOW CREATE TABLE "+backtickQuote(tableName)).
+		Scan(&name, &create)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("SHOW CREATE TABLE failed: %w", err)
+	}
+	return TableDDL{Create: create + ";"}, nil
+}
+
+func (m *MySQLConnection) GetViewDDL(viewName string) (string, error) {
+	if m.db == nil {
+		return "", fmt.Errorf("database not open")
+	}
+
+	var name, create, charset, collation string
+	err := m.db.QueryRow("SHOW CREATE VIEW "+backtickQuote(viewName)).
+		Scan(&name, &create, &charset, &collation)
+	if err != nil {
+		return "", fmt.Errorf("SHOW CREATE VIEW failed: %w", err)
+	}
+	return create + ";", nil
+}
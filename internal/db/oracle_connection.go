@@ -81,6 +81,10 @@ func (oc *OracleConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (oc *OracleConnection) GetDB() *sql.DB {
+	return oc.db
+}
+
 func (oc *OracleConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -776,3 +780,291 @@ func (oc *OracleConnection) BuildDeleteStatement(
 func (oc *OracleConnection) GetPlaceholder(paramIndex int) string {
 	return fmt.Sprintf(":%d", paramIndex)
 }
+
+func (oc *OracleConnection) GetIndexes(tableName string) ([]IndexInfo, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			i.index_name,
+			(
+				SELECT LISTAGG(ic.column_name, ',')
+					WITHIN GROUP (ORDER BY ic.column_position)
+				FROM all_ind_columns ic
+				WHERE ic.index_owner = i.owner
+				AND ic.index_name = i.index_name
+			),
+			i.uniqueness,
+			(
+				SELECT COUNT(*)
+				FROM all_constraints c
+				WHERE c.owner = i.table_owner
+				AND c.index_name = i.index_name
+				AND c.constraint_type = 'P'
+			),
+			i.index_type
+		FROM all_indexes i
+		WHERE i.table_name = :1
+		AND i.table_owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+		ORDER BY i.index_name
+	`
+
+	rows, err := oc.db.Query(query, strings.ToUpper(tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var idx IndexInfo
+		var columns sql.NullString
+		var uniqueness string
+		var primary int
+		if err := rows.Scan(
+			&idx.Name,
+			&columns,
+			&uniqueness,
+			&primary,
+			&idx.Method,
+		); err == nil {
+			idx.Columns = splitColumnList(columns.String)
+			idx.Unique = uniqueness == "UNIQUE"
+			idx.Primary = primary > 0
+			indexes = append(indexes, idx)
+		}
+	}
+
+	return indexes, nil
+}
+
+func (oc *OracleConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			c.constraint_name,
+			c.constraint_type,
+			(
+				SELECT LISTAGG(cc.column_name, ',')
+					WITHIN GROUP (ORDER BY cc.position)
+				FROM all_cons_columns cc
+				WHERE cc.owner = c.owner
+				AND cc.constraint_name = c.constraint_name
+			),
+			c.search_condition_vc,
+			r.table_name,
+			(
+				SELECT LISTAGG(rc.column_name, ',')
+					WITHIN GROUP (ORDER BY rc.position)
+				FROM all_cons_columns rc
+				WHERE rc.owner = r.owner
+				AND rc.constraint_name = r.constraint_name
+			)
+		FROM all_constraints c
+		LEFT JOIN all_constraints r
+			ON r.owner = c.r_owner
+			AND r.constraint_name = c.r_constraint_name
+		WHERE c.table_name = :1
+		AND c.owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+		AND c.constraint_type IN ('C', 'R')
+		ORDER BY c.constraint_type, c.constraint_name
+	`
+
+	rows, err := oc.db.Query(query, strings.ToUpper(tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var constraints []ConstraintInfo
+	for rows.Next() {
+		var c ConstraintInfo
+		var kind string
+		var columns, condition, refTable, refColumns sql.NullString
+		if err := rows.Scan(
+			&c.Name,
+			&kind,
+			&columns,
+			&condition,
+			&refTable,
+			&refColumns,
+		); err != nil {
+			continue
+		}
+		c.Columns = splitColumnList(columns.String)
+		if kind == "R" {
+			c.Type = ConstraintForeignKey
+			c.Definition = fmt.Sprintf(
+				"FOREIGN KEY (%s) REFERENCES %s (%s)",
+				strings.Join(c.Columns, ", "),
+				refTable.String,
+				strings.Join(splitColumnList(refColumns.String), ", "),
+			)
+		} else {
+			// NOT NULL columns are check constraints too; those belong to
+			// the columns tab
+			if strings.HasSuffix(
+				strings.ToUpper(condition.String),
+				" IS NOT NULL",
+			) && len(c.Columns) == 1 {
+				continue
+			}
+			c.Type = ConstraintCheck
+			c.Definition = "CHECK (" + condition.String + ")"
+		}
+		constraints = append(constraints, c)
+	}
+
+	return constraints, nil
+}
+
+func (oc *OracleConnection) GetTriggers(
+	tableName string,
+) ([]TriggerInfo, error) {
+	if oc.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT trigger_name, trigger_type, triggering_event
+		FROM all_triggers
+		WHERE table_name = :1
+		AND table_owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+		ORDER BY trigger_name
+	`
+
+	rows, err := oc.db.Query(query, strings.ToUpper(tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerInfo
+	for rows.Next() {
+		var t TriggerInfo
+		var triggerType string
+		if err := rows.Scan(&t.Name, &triggerType, &t.Event); err != nil {
+			continue
+		}
+		// trigger_type reads e.g. "BEFORE EACH ROW" or "AFTER STATEMENT"
+		t.Timing = strings.TrimSpace(
+			strings.TrimSuffix(
+				strings.TrimSuffix(triggerType, " EACH ROW"),
+				" STATEMENT",
+			),
+		)
+		triggers = append(triggers, t)
+	}
+
+	return triggers, nil
+}
+
+func (oc *OracleConnection) BuildCreateTriggerSQL(
+	tableName, triggerName string,
+) string {
+	return fmt.Sprintf(
+		"CREATE OR REPLACE TRIGGER %s\nBEFORE UPDATE ON %s\nFOR EACH ROW\nBEGIN\n  NULL;\nEND;",
+		triggerName,
+		tableName,
+	)
+}
+
+func (oc *OracleConnection) BuildDropTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+) string {
+	return fmt.Sprintf("DROP TRIGGER %s;", trigger.Name)
+}
+
+func (oc *OracleConnection) BuildRenameTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+	newName string,
+) string {
+	return fmt.Sprintf("ALTER TRIGGER %s RENAME TO %s;", trigger.Name, newName)
+}
+
+// oracleDDL tidies DBMS_METADATA output into a statement for the current
+// schema: the owner prefix is dropped so the dump loads into any schema
+func oracleDDL(ddl, owner string) string {
+	ddl = strings.ReplaceAll(strings.TrimSpace(ddl), `"`+owner+`".`, "")
+	return strings.TrimRight(ddl, ";") + ";"
+}
+
+// GetTableDDL returns DBMS_METADATA.GET_DDL for the table and for the
+// indexes that don't back one of its constraints
+func (oc *OracleConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	if oc.db == nil {
+		return TableDDL{}, fmt.Errorf("database is not open")
+	}
+
+	name := strings.ToUpper(tableName)
+	var create, owner string
+	err := oc.db.QueryRow(`
+		SELECT
+			DBMS_METADATA.GET_DDL('TABLE', :1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')),
+			SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+		FROM dual
+	`, name).Scan(&create, &owner)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("DBMS_METADATA.GET_DDL failed: %w", err)
+	}
+
+	rows, err := oc.db.Query(`
+		SELECT DBMS_METADATA.GET_DDL('INDEX', i.index_name, i.owner)
+		FROM all_indexes i
+		WHERE i.table_name = :1
+		AND i.table_owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+		AND i.generated = 'N'
+		AND NOT EXISTS (
+			SELECT 1
+			FROM all_constraints c
+			WHERE c.owner = i.table_owner
+			AND c.index_name = i.index_name
+		)
+		ORDER BY i.index_name
+	`, name)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var after []string
+	for rows.Next() {
+		var ddl string
+		if err := rows.Scan(&ddl); err != nil {
+			return TableDDL{}, err
+		}
+		after = append(after, oracleDDL(ddl, owner))
+	}
+
+	return TableDDL{
+		Create: oracleDDL(create, owner),
+		After:  strings.Join(after, "\n"),
+	}, nil
+}
+
+func (oc *OracleConnection) GetViewDDL(viewName string) (string, error) {
+	if oc.db == nil {
+		return "", fmt.Errorf("database is not open")
+	}
+
+	var ddl, owner string
+	err := oc.db.QueryRow(`
+		SELECT
+			DBMS_METADATA.GET_DDL('VIEW', :1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')),
+			SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+		FROM dual
+	`, strings.ToUpper(viewName)).Scan(&ddl, &owner)
+	if err != nil {
+		return "", fmt.Errorf("DBMS_METADATA.GET_DDL failed: %w", err)
+	}
+	return oracleDDL(ddl, owner), nil
+}
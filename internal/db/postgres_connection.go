@@ -3,9 +3,10 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type PostgresConnection struct {
@@ -75,6 +76,10 @@ func (p *PostgresConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (p *PostgresConnection) GetDB() *sql.DB {
+	return p.db
+}
+
 func (p *PostgresConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -559,3 +564,381 @@ func (c *PostgresConnection) BuildDeleteStatement(
 func (p *PostgresConnection) GetPlaceholder(paramIndex int) string {
 	return fmt.Sprintf("$%d", paramIndex)
 }
+
+func (p *PostgresConnection) GetIndexes(tableName string) ([]IndexInfo, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			i.relname,
+			ARRAY(
+				SELECT pg_get_indexdef(ix.indexrelid, k, true)
+				FROM generate_series(1, ix.indnatts) AS k
+				ORDER BY k
+			),
+			ix.indisunique,
+			ix.indisprimary,
+			am.amname,
+			pg_get_indexdef(ix.indexrelid)
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = i.relam
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE t.relname = $1
+		  AND n.nspname = current_schema()
+		ORDER BY ix.indisprimary DESC, i.relname
+	`
+
+	rows, err := p.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var idx IndexInfo
+		if err := rows.Scan(
+			&idx.Name,
+			pq.Array(&idx.Columns),
+			&idx.Unique,
+			&idx.Primary,
+			&idx.Method,
+			&idx.Definition,
+		); err == nil {
+			indexes = append(indexes, idx)
+		}
+	}
+
+	return indexes, nil
+}
+
+func (p *PostgresConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			c.conname,
+			c.contype,
+			ARRAY(
+				SELECT a.attname
+				FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a
+					ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			),
+			pg_get_constraintdef(c.oid, true)
+		FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE t.relname = $1
+		  AND n.nspname = current_schema()
+		  AND c.contype IN ('c', 'f')
+		ORDER BY c.contype, c.conname
+	`
+
+	rows, err := p.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var constraints []ConstraintInfo
+	for rows.Next() {
+		var c ConstraintInfo
+		var contype string
+		if err := rows.Scan(
+			&c.Name,
+			&contype,
+			pq.Array(&c.Columns),
+			&c.Definition,
+		); err != nil {
+			continue
+		}
+		c.Type = ConstraintCheck
+		if contype == "f" {
+			c.Type = ConstraintForeignKey
+		}
+		constraints = append(constraints, c)
+	}
+
+	return constraints, nil
+}
+
+func (p *PostgresConnection) GetTriggers(tableName string) ([]TriggerInfo, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT tg.tgname, pg_get_triggerdef(tg.oid, true)
+		FROM pg_trigger tg
+		JOIN pg_class t ON t.oid = tg.tgrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE t.relname = $1
+		  AND n.nspname = current_schema()
+		  AND NOT tg.tgisinternal
+		ORDER BY tg.tgname
+	`
+
+	rows, err := p.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerInfo
+	for rows.Next() {
+		var t TriggerInfo
+		if err := rows.Scan(&t.Name, &t.Definition); err != nil {
+			continue
+		}
+		t.Timing, t.Event = ParseTriggerTiming(t.Definition)
+		triggers = append(triggers, t)
+	}
+
+	return triggers, nil
+}
+
+// BuildCloneTableSQL uses LIKE ... INCLUDING ALL, which keeps defaults,
+// constraints other than foreign keys, and indexes.
+func (p *PostgresConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	stmt := fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING ALL);", target, source)
+	if withData {
+		stmt += fmt.Sprintf("\nINSERT INTO %s SELECT * FROM %s;", target, source)
+	}
+	return stmt
+}
+
+var pgNextvalPattern = regexp.MustCompile(`nextval\('([^']+)'::regclass\)`)
+
+// GetTableDDL rebuilds the table from the catalog: columns with their
+// defaults, identities and sequences, and the primary key, unique, check and
+// exclusion constraints. Foreign keys, indexes, triggers and sequence
+// positions follow the data.
+func (p *PostgresConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	if p.db == nil {
+		return TableDDL{}, fmt.Errorf("database is not open")
+	}
+
+	var oid int64
+	err := p.db.QueryRow(`
+		SELECT t.oid
+		FROM pg_class t
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE t.relname = $1
+		  AND n.nspname = current_schema()
+		  AND t.relkind IN ('r', 'p')
+	`, tableName).Scan(&oid)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("table %q not found: %w", tableName, err)
+	}
+
+	table := pq.QuoteIdentifier(tableName)
+	var before, lines, after []string
+
+	rows, err := p.db.Query(`
+		SELECT
+			a.attname,
+			format_type(a.atttypid, a.atttypmod),
+			a.attnotnull,
+			COALESCE(pg_get_expr(d.adbin, d.adrelid), ''),
+			a.attidentity::text,
+			a.attgenerated::text,
+			COALESCE(pg_get_serial_sequence($2, a.attname), '')
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = $1
+		  AND a.attnum > 0
+		  AND NOT a.attisdropped
+		ORDER BY a.attnum
+	`, oid, table)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("failed to query columns: %w", err)
+	}
+
+	// Sequences feeding the columns; identity names the identity column
+	type sequenceUse struct{ name, owner, identity string }
+	var sequences []sequenceUse
+	for rows.Next() {
+		var name, dataType, def, identity, generated, ownedSeq string
+		var notNull bool
+		if err := rows.Scan(&name, &dataType, &notNull, &def, &identity, &generated, &ownedSeq); err != nil {
+			rows.Close()
+			return TableDDL{}, err
+		}
+		column := pq.QuoteIdentifier(name)
+		line := column + " " + dataType
+
+		switch {
+		case identity != "":
+			// Rows are loaded with their ids, so an ALWAYS identity is
+			// restored after the data
+			line += " GENERATED BY DEFAULT AS IDENTITY"
+			if identity == "a" {
+				after = append(after, fmt.Sprintf(
+					"ALTER TABLE %s ALTER COLUMN %s SET GENERATED ALWAYS;",
+					table, column,
+				))
+			}
+			sequences = append(sequences, sequenceUse{name: ownedSeq, identity: name})
+		case generated == "s":
+			line += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", def)
+		case def != "":
+			line += " DEFAULT " + def
+			if match := pgNextvalPattern.FindStringSubmatch(def); match != nil {
+				owner := ""
+				if ownedSeq != "" {
+					owner = table + "." + column
+				}
+				sequences = append(sequences, sequenceUse{name: match[1], owner: owner})
+			}
+		}
+		if notNull {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return TableDDL{}, err
+	}
+
+	created := map[string]bool{}
+	for _, seq := range sequences {
+		if seq.name == "" {
+			continue
+		}
+		var lastValue int64
+		var isCalled bool
+		err := p.db.QueryRow("SELECT last_value, is_called FROM "+seq.name).
+			Scan(&lastValue, &isCalled)
+		if err != nil {
+			return TableDDL{}, fmt.Errorf("failed to read sequence %s: %w", seq.name, err)
+		}
+
+		target := "'" + strings.ReplaceAll(seq.name, "'", "''") + "'"
+		if seq.identity != "" {
+			// The identity column creates its own sequence
+			target = fmt.Sprintf(
+				"pg_get_serial_sequence('%s', '%s')",
+				strings.ReplaceAll(table, "'", "''"),
+				strings.ReplaceAll(seq.identity, "'", "''"),
+			)
+		} else if !created[seq.name] {
+			created[seq.name] = true
+			before = append(before, fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s;", seq.name))
+		}
+		if seq.owner != "" {
+			after = append(after, fmt.Sprintf("ALTER SEQUENCE %s OWNED BY %s;", seq.name, seq.owner))
+		}
+		after = append(after, fmt.Sprintf(
+			"SELECT setval(%s, %d, %t);", target, lastValue, isCalled,
+		))
+	}
+
+	conRows, err := p.db.Query(`
+		SELECT conname, contype::text, pg_get_constraintdef(oid)
+		FROM pg_constraint
+		WHERE conrelid = $1
+		  AND contype IN ('p', 'u', 'c', 'x', 'f')
+		ORDER BY contype = 'p' DESC, conname
+	`, oid)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	for conRows.Next() {
+		var name, conType, def string
+		if err := conRows.Scan(&name, &conType, &def); err != nil {
+			conRows.Close()
+			return TableDDL{}, err
+		}
+		constraint := fmt.Sprintf("CONSTRAINT %s %s", pq.QuoteIdentifier(name), def)
+		if conType == "f" {
+			// Added after the data so rows load in any order
+			after = append(after, fmt.Sprintf("ALTER TABLE %s ADD %s;", table, constraint))
+		} else {
+			lines = append(lines, constraint)
+		}
+	}
+	conRows.Close()
+
+	// Indexes that don't back a constraint, then triggers with their functions
+	objRows, err := p.db.Query(`
+		SELECT pg_get_indexdef(ix.indexrelid) || ';'
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		WHERE ix.indrelid = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM pg_constraint c
+			WHERE c.conrelid = ix.indrelid AND c.conindid = ix.indexrelid
+		  )
+		UNION ALL
+		SELECT pg_get_functiondef(tg.tgfoid) || ';' || E'\n' || pg_get_triggerdef(tg.oid) || ';'
+		FROM pg_trigger tg
+		WHERE tg.tgrelid = $1
+		  AND NOT tg.tgisinternal
+	`, oid)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("failed to query indexes and triggers: %w", err)
+	}
+	for objRows.Next() {
+		var def string
+		if err := objRows.Scan(&def); err != nil {
+			objRows.Close()
+			return TableDDL{}, err
+		}
+		after = append(after, def)
+	}
+	objRows.Close()
+
+	before = append(before, fmt.Sprintf(
+		"CREATE TABLE %s (\n    %s\n);",
+		table,
+		strings.Join(lines, ",\n    "),
+	))
+	return TableDDL{
+		Create: strings.Join(before, "\n"),
+		After:  strings.Join(after, "\n"),
+	}, nil
+}
+
+func (p *PostgresConnection) GetViewDDL(viewName string) (string, error) {
+	if p.db == nil {
+		return "", fmt.Errorf("database is not open")
+	}
+
+	var kind, def string
+	err := p.db.QueryRow(`
+		SELECT c.relkind::text, pg_get_viewdef(c.oid, true)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relname = $1
+		  AND n.nspname = current_schema()
+		  AND c.relkind IN ('v', 'm')
+	`, viewName).Scan(&kind, &def)
+	if err != nil {
+		return "", fmt.Errorf("view %q not found: %w", viewName, err)
+	}
+
+	create := "CREATE OR REPLACE VIEW"
+	if kind == "m" {
+		create = "CREATE MATERIALIZED VIEW"
+	}
+	return fmt.Sprintf(
+		"%s %s AS\n%s;",
+		create,
+		pq.QuoteIdentifier(viewName),
+		strings.TrimRight(strings.TrimSpace(def), ";"),
+	), nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"strconv"
+	"strings"
 )
 
 type Query struct {
@@ -11,6 +12,16 @@ type Query struct {
 	TableName   string            `yaml:"table_name,omitempty"`
 	PrimaryKeys []string          `yaml:"primary_keys,omitempty"`
 	Metadata    map[string]string `yaml:"metadata,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	// Folder is a slash-separated path such as billing/monthly
+	Folder string `yaml:"folder,omitempty"`
+	// Assert is what pam check expects of the query's result, such as
+	// "rows = 0"; empty means no rows
+	Assert string `yaml:"assert,omitempty"`
+	// Source is the file a project query was loaded from; empty for
+	// queries stored in the global config
+	Source string `yaml:"-"`
 }
 
 func FindQueryWithSelector(queries map[string]Query, selector string) (Query, bool) {
@@ -22,6 +33,59 @@ func FindQueryWithSelector(queries map[string]Query, selector string) (Query, bo
 		}
 		return Query{}, false
 	}
-	q, ok := queries[selector]
-	return q, ok
+	if q, ok := queries[selector]; ok {
+		return q, true
+	}
+	for _, q := range queries {
+		if q.Folder != "" && q.Path() == selector {
+			return q, true
+		}
+	}
+	return Query{}, false
+}
+
+// Path is the query's name inside its folder, e.g. billing/invoices.
+func (q Query) Path() string {
+	if q.Folder == "" {
+		return q.Name
+	}
+	return q.Folder + "/" + q.Name
+}
+
+// HasTag reports whether the query is tagged tag, ignoring case.
+func (q Query) HasTag(tag string) bool {
+	for _, t := range q.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// InFolder reports whether the query is in folder or one of its
+// subfolders.
+func (q Query) InFolder(folder string) bool {
+	folder = strings.Trim(folder, "/")
+	return folder == "" || q.Folder == folder || strings.HasPrefix(q.Folder, folder+"/")
+}
+
+// ParseTags splits a comma-separated tag list, dropping blanks.
+func ParseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SplitQueryPath splits a selector such as billing/invoices into its folder
+// and name.
+func SplitQueryPath(path string) (folder, name string) {
+	path = strings.Trim(path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
 }
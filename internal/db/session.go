@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Session pins a single physical connection from the pool so that a series
+// of statements share session state (temp tables, variables, search_path,
+// open transactions). It must be closed to return the connection.
+type Session struct {
+	conn *sql.Conn
+}
+
+// OpenSession checks out one connection from an already-open
+// DatabaseConnection.
+func OpenSession(dc DatabaseConnection) (*Session, error) {
+	pool := dc.GetDB()
+	if pool == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	conn, err := pool.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	return &Session{conn: conn}, nil
+}
+
+func (s *Session) ExecQuery(sql string, args ...any) (*sql.Rows, error) {
+	return s.conn.QueryContext(context.Background(), sql, args...)
+}
+
+// Exec runs a statement and returns the number of affected rows, or -1 when
+// the driver cannot report it.
+func (s *Session) Exec(sql string, args ...any) (int64, error) {
+	res, err := s.conn.ExecContext(context.Background(), sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return -1, nil
+	}
+	return affected, nil
+}
+
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
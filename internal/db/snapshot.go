@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// exportSession reads table data for an export. Sessions opened together
+// by openExportSessions share one consistent snapshot where the database
+// supports it.
+type exportSession struct {
+	conn *sql.Conn // nil reads through the connection's pool
+	db   DatabaseConnection
+}
+
+func (s *exportSession) query(query string) (*sql.Rows, error) {
+	if s.conn == nil {
+		return s.db.ExecQuery(query)
+	}
+	return s.conn.QueryContext(context.Background(), query)
+}
+
+func (s *exportSession) exec(query string) error {
+	_, err := s.conn.ExecContext(context.Background(), query)
+	return err
+}
+
+// openExportSessions opens n sessions for an export's workers. On
+// PostgreSQL they are REPEATABLE READ transactions sharing an exported
+// snapshot; on MySQL, consistent-snapshot transactions started together
+// under a brief global read lock. snapshot describes the consistency
+// obtained, empty when every session reads the live tables. release ends
+// the transactions and returns the sessions to the pool.
+func openExportSessions(
+	conn DatabaseConnection,
+	n int,
+) (sessions []*exportSession, snapshot string, release func()) {
+	pool := conn.GetDB()
+	if pool == nil {
+		session := &exportSession{db: conn}
+		sessions = make([]*exportSession, n)
+		for i := range sessions {
+			sessions[i] = session
+		}
+		return sessions, "", func() {}
+	}
+
+	ctx := context.Background()
+	open := n
+	if limit := pool.Stats().MaxOpenConnections; limit > 0 {
+		// Holding every allowed connection would block the pool: keep one free
+		open = min(n, max(limit-1, 0))
+	}
+	for i := 0; i < open; i++ {
+		c, err := pool.Conn(ctx)
+		if err != nil {
+			break
+		}
+		session := &exportSession{conn: c, db: conn}
+		for _, stmt := range sessionSetupSQL(conn) {
+			// Open applies these to one pooled connection only
+			_ = session.exec(stmt)
+		}
+		sessions = append(sessions, session)
+	}
+	if len(sessions) == 0 {
+		return openExportSessions(&poolOnly{conn}, n)
+	}
+	// Fewer connections than workers: let workers share them
+	for i := len(sessions); i < n; i++ {
+		sessions = append(sessions, sessions[i%len(sessions)])
+	}
+
+	inTransaction := false
+	switch dialectOf(conn) {
+	case "postgres":
+		snapshot, inTransaction = postgresSnapshot(sessions)
+	case "mysql":
+		snapshot, inTransaction = mysqlSnapshot(sessions)
+	}
+
+	release = func() {
+		for _, s := range uniqueSessions(sessions) {
+			if inTransaction {
+				_ = s.exec("ROLLBACK")
+			}
+			s.conn.Close()
+		}
+	}
+	return sessions, snapshot, release
+}
+
+// poolOnly hides a connection's pool so its sessions use ExecQuery
+type poolOnly struct {
+	DatabaseConnection
+}
+
+func (poolOnly) GetDB() *sql.DB { return nil }
+
+// sessionSetupSQL repeats what Open sets up for the connection's session
+func sessionSetupSQL(conn DatabaseConnection) []string {
+	if conn.GetSchema() == "" {
+		return nil
+	}
+	switch dialectOf(conn) {
+	case "postgres":
+		return []string{fmt.Sprintf("SET search_path TO %s", conn.GetSchema())}
+	case "snowflake":
+		return []string{fmt.Sprintf("USE SCHEMA %s", conn.GetSchema())}
+	}
+	return nil
+}
+
+// uniqueSessions lists each session once, as workers may share them
+func uniqueSessions(sessions []*exportSession) []*exportSession {
+	seen := map[*exportSession]bool{}
+	var unique []*exportSession
+	for _, s := range sessions {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
+
+func postgresSnapshot(sessions []*exportSession) (string, bool) {
+	unique := uniqueSessions(sessions)
+	const begin = "BEGIN TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"
+
+	rollback := func(upTo int) {
+		for _, s := range unique[:upTo] {
+			_ = s.exec("ROLLBACK")
+		}
+	}
+	if err := unique[0].exec(begin); err != nil {
+		return "", false
+	}
+	var id string
+	err := unique[0].conn.QueryRowContext(
+		context.Background(),
+		"SELECT pg_export_snapshot()",
+	).Scan(&id)
+	if err != nil {
+		rollback(1)
+		return "", false
+	}
+	for i, s := range unique[1:] {
+		if err := s.exec(begin); err != nil {
+			rollback(i + 1)
+			return "", false
+		}
+		if err := s.exec(fmt.Sprintf("SET TRANSACTION SNAPSHOT '%s'", id)); err != nil {
+			rollback(i + 2)
+			return "", false
+		}
+	}
+	return "repeatable read, snapshot " + id, true
+}
+
+func mysqlSnapshot(sessions []*exportSession) (string, bool) {
+	unique := uniqueSessions(sessions)
+
+	// The lock makes the transactions start at the same point; without the
+	// privilege for it, each session is only consistent with itself
+	locked := len(unique) == 1 || unique[0].exec("FLUSH TABLES WITH READ LOCK") == nil
+	started := 0
+	for _, s := range unique {
+		_ = s.exec("SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ")
+		if s.exec("START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY") != nil {
+			break
+		}
+		started++
+	}
+	if locked && len(unique) > 1 {
+		_ = unique[0].exec("UNLOCK TABLES")
+	}
+
+	switch {
+	case started < len(unique):
+		for _, s := range unique[:started] {
+			_ = s.exec("ROLLBACK")
+		}
+		return "", false
+	case !locked:
+		return "consistent snapshot per session", true
+	}
+	return "consistent snapshot", true
+}
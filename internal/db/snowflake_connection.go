@@ -166,6 +166,10 @@ func (s *SnowflakeConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (s *SnowflakeConnection) GetDB() *sql.DB {
+	return s.db
+}
+
 // scanShowColumns runs after a Snowflake SHOW command and extracts named
 // columns from the result set. SHOW commands return a fixed set of columns
 // whose names are stable but whose position can vary between Snowflake
@@ -443,3 +447,38 @@ func (s *SnowflakeConnection) ApplyRowLimit(sql string, limit int) string {
 	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sql), ";"))
 	return fmt.Sprintf("%s\nLIMIT %d", trimmed, limit)
 }
+
+// BuildCloneTableSQL uses a zero-copy clone, or LIKE for the structure only.
+func (s *SnowflakeConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	if !withData {
+		return fmt.Sprintf("CREATE TABLE %s LIKE %s;", target, source)
+	}
+	return fmt.Sprintf("CREATE TABLE %s CLONE %s;", target, source)
+}
+
+func (s *SnowflakeConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	if s.db == nil {
+		return TableDDL{}, fmt.Errorf("database is not open")
+	}
+
+	var ddl string
+	if err := s.db.QueryRow("SELECT GET_DDL('TABLE', ?)", tableName).Scan(&ddl); err != nil {
+		return TableDDL{}, fmt.Errorf("GET_DDL failed: %w", err)
+	}
+	return TableDDL{Create: strings.TrimSpace(ddl)}, nil
+}
+
+func (s *SnowflakeConnection) GetViewDDL(viewName string) (string, error) {
+	if s.db == nil {
+		return "", fmt.Errorf("database is not open")
+	}
+
+	var ddl string
+	if err := s.db.QueryRow("SELECT GET_DDL('VIEW', ?)", viewName).Scan(&ddl); err != nil {
+		return "", fmt.Errorf("GET_DDL failed: %w", err)
+	}
+	return strings.TrimSpace(ddl), nil
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 
 	_ "modernc.org/sqlite"
@@ -65,6 +66,10 @@ func (s *SQLiteConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (s *SQLiteConnection) GetDB() *sql.DB {
+	return s.db
+}
+
 func (s *SQLiteConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -104,7 +109,7 @@ func (s *SQLiteConnection) GetTableMetadata(
 		metadata.Columns = append(metadata.Columns, name)
 		metadata.ColumnTypes = append(metadata.ColumnTypes, colType)
 
-		if pk == 1 {
+		if pk > 0 {
 			metadata.PrimaryKeys = append(metadata.PrimaryKeys, name)
 		}
 	}
@@ -161,7 +166,7 @@ func (s *SQLiteConnection) GetColumnDetails(
 		}
 
 		extra := ""
-		if pk == 1 && strings.ToUpper(colType) == "INTEGER" {
+		if pk > 0 && strings.ToUpper(colType) == "INTEGER" {
 			extra = "AUTOINCREMENT"
 		}
 
@@ -170,7 +175,7 @@ func (s *SQLiteConnection) GetColumnDetails(
 			DataType:     colType,
 			Nullable:     nullable,
 			DefaultValue: defaultVal,
-			IsPrimaryKey: pk == 1,
+			IsPrimaryKey: pk > 0,
 			OrdinalPos:   cid + 1,
 			Extra:        extra,
 		}
@@ -436,3 +441,354 @@ func (s *SQLiteConnection) BuildDeleteStatement(
 func (s *SQLiteConnection) GetPlaceholder(paramIndex int) string {
 	return "?"
 }
+
+func (s *SQLiteConnection) GetIndexes(tableName string) ([]IndexInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA index_list(%s)", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query index list: %w", err)
+	}
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			continue
+		}
+		indexes = append(indexes, IndexInfo{
+			Name:    name,
+			Unique:  unique == 1,
+			Primary: origin == "pk",
+			Method:  "btree",
+		})
+	}
+	rows.Close()
+
+	// Columns and definitions need queries of their own, which SQLite
+	// can't run while index_list is still being read
+	for i := range indexes {
+		infoRows, err := s.db.Query(
+			fmt.Sprintf("PRAGMA index_info(%s)", indexes[i].Name),
+		)
+		if err == nil {
+			for infoRows.Next() {
+				var seqno, cid int
+				var colName sql.NullString
+				if infoRows.Scan(&seqno, &cid, &colName) == nil {
+					col := colName.String
+					if !colName.Valid {
+						col = "<expression>"
+					}
+					indexes[i].Columns = append(indexes[i].Columns, col)
+				}
+			}
+			infoRows.Close()
+		}
+
+		var definition sql.NullString
+		if s.db.QueryRow(
+			"SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?",
+			indexes[i].Name,
+		).Scan(&definition) == nil {
+			indexes[i].Definition = definition.String
+		}
+	}
+
+	return indexes, nil
+}
+
+func (s *SQLiteConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	var createSQL string
+	err := s.db.QueryRow(
+		"SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?",
+		tableName,
+	).Scan(&createSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table definition: %w", err)
+	}
+	constraints := sqliteCheckConstraints(createSQL)
+
+	rows, err := s.db.Query(
+		fmt.Sprintf("PRAGMA foreign_key_list(%s)", tableName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	// One row per column; rows of a multi-column key share the same id
+	type fkParts struct {
+		table, onUpdate, onDelete string
+		from, to                  []string
+	}
+	var ids []int
+	parts := map[int]*fkParts{}
+	for rows.Next() {
+		var id, seq int
+		var table, from, onUpdate, onDelete, match string
+		var to sql.NullString
+		if err := rows.Scan(
+			&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match,
+		); err != nil {
+			continue
+		}
+		p, ok := parts[id]
+		if !ok {
+			p = &fkParts{table: table, onUpdate: onUpdate, onDelete: onDelete}
+			parts[id] = p
+			ids = append(ids, id)
+		}
+		p.from = append(p.from, from)
+		if to.Valid {
+			p.to = append(p.to, to.String)
+		}
+	}
+
+	for _, id := range ids {
+		p := parts[id]
+		definition := fmt.Sprintf(
+			"FOREIGN KEY (%s) REFERENCES %s",
+			strings.Join(p.from, ", "),
+			p.table,
+		)
+		if len(p.to) > 0 {
+			definition += fmt.Sprintf(" (%s)", strings.Join(p.to, ", "))
+		}
+		if p.onDelete != "" && p.onDelete != "NO ACTION" {
+			definition += " ON DELETE " + p.onDelete
+		}
+		if p.onUpdate != "" && p.onUpdate != "NO ACTION" {
+			definition += " ON UPDATE " + p.onUpdate
+		}
+		constraints = append(constraints, ConstraintInfo{
+			Type:       ConstraintForeignKey,
+			Columns:    p.from,
+			Definition: definition,
+		})
+	}
+
+	return constraints, nil
+}
+
+var sqliteCheckStart = regexp.MustCompile(
+	"(?i)(?:\\bCONSTRAINT\\s+([\"`\\[]?\\w+[\"`\\]]?)\\s+)?\\bCHECK\\s*\\(",
+)
+
+// sqliteCheckConstraints extracts the CHECK clauses from a CREATE TABLE
+// statement, since SQLite has no catalog of them.
+func sqliteCheckConstraints(createSQL string) []ConstraintInfo {
+	var constraints []ConstraintInfo
+	for _, loc := range sqliteCheckStart.FindAllStringSubmatchIndex(createSQL, -1) {
+		open := loc[1] - 1
+		depth, end := 0, -1
+		var quote byte
+		for i := open; i < len(createSQL) && end < 0; i++ {
+			c := createSQL[i]
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			continue
+		}
+
+		name := ""
+		if loc[2] >= 0 {
+			name = strings.Trim(createSQL[loc[2]:loc[3]], "\"`[]")
+		}
+		constraints = append(constraints, ConstraintInfo{
+			Name:       name,
+			Type:       ConstraintCheck,
+			Definition: "CHECK " + createSQL[open:end+1],
+		})
+	}
+	return constraints
+}
+
+func (s *SQLiteConnection) GetTriggers(tableName string) ([]TriggerInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not open")
+	}
+
+	rows, err := s.db.Query(
+		"SELECT name, sql FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? ORDER BY name",
+		tableName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerInfo
+	for rows.Next() {
+		var t TriggerInfo
+		if err := rows.Scan(&t.Name, &t.Definition); err != nil {
+			continue
+		}
+		t.Timing, t.Event = ParseTriggerTiming(t.Definition)
+		triggers = append(triggers, t)
+	}
+
+	return triggers, nil
+}
+
+func (s *SQLiteConnection) BuildRenameIndexSQL(
+	tableName string,
+	index IndexInfo,
+	newName string,
+) string {
+	if index.Definition == "" {
+		return fmt.Sprintf(
+			"-- SQLite created index '%s' for a constraint; it can't be renamed.",
+			index.Name,
+		)
+	}
+	return fmt.Sprintf(
+		"-- SQLite can't rename indexes, so the index is recreated.\nDROP INDEX %s;\n%s;",
+		index.Name,
+		RenameInDefinition(index.Definition, index.Name, newName),
+	)
+}
+
+func (s *SQLiteConnection) BuildAddConstraintSQL(
+	tableName, constraintName, definition string,
+) string {
+	return fmt.Sprintf(
+		"-- SQLite can't add constraints to an existing table.\n-- Recreate '%s' with the constraint instead:\n-- CONSTRAINT %s %s",
+		tableName,
+		constraintName,
+		definition,
+	)
+}
+
+func (s *SQLiteConnection) BuildDropConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+) string {
+	return fmt.Sprintf(
+		"-- SQLite can't drop constraints from an existing table.\n-- Recreate '%s' without:\n-- %s",
+		tableName,
+		constraint.Definition,
+	)
+}
+
+func (s *SQLiteConnection) BuildRenameConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"-- SQLite can't rename constraints of an existing table.\n-- Recreate '%s' with:\n-- CONSTRAINT %s %s",
+		tableName,
+		newName,
+		constraint.Definition,
+	)
+}
+
+func (s *SQLiteConnection) BuildCreateTriggerSQL(
+	tableName, triggerName string,
+) string {
+	return fmt.Sprintf(
+		"CREATE TRIGGER %s\nAFTER UPDATE ON %s\nFOR EACH ROW\nBEGIN\n  SELECT 1;\nEND;",
+		triggerName,
+		tableName,
+	)
+}
+
+func (s *SQLiteConnection) BuildDropTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+) string {
+	return fmt.Sprintf("DROP TRIGGER %s;", trigger.Name)
+}
+
+func (s *SQLiteConnection) BuildRenameTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"-- SQLite can't rename triggers, so the trigger is recreated.\nDROP TRIGGER %s;\n%s;",
+		trigger.Name,
+		RenameInDefinition(trigger.Definition, trigger.Name, newName),
+	)
+}
+
+func (s *SQLiteConnection) BuildTruncateTableSQL(tableName string) string {
+	return fmt.Sprintf("DELETE FROM %s;", tableName)
+}
+
+// GetTableDDL returns the statements SQLite recorded in sqlite_master
+func (s *SQLiteConnection) GetTableDDL(tableName string) (TableDDL, error) {
+	if s.db == nil {
+		return TableDDL{}, fmt.Errorf("database not open")
+	}
+
+	rows, err := s.db.Query(
+		`SELECT type, sql FROM sqlite_master
+		WHERE tbl_name = ? AND type IN ('table', 'index', 'trigger') AND sql IS NOT NULL
+		ORDER BY type = 'trigger', rowid`,
+		tableName,
+	)
+	if err != nil {
+		return TableDDL{}, fmt.Errorf("failed to query sqlite_master: %w", err)
+	}
+	defer rows.Close()
+
+	var ddl TableDDL
+	var after []string
+	for rows.Next() {
+		var objType, sql string
+		if err := rows.Scan(&objType, &sql); err != nil {
+			return TableDDL{}, err
+		}
+		if objType == "table" {
+			ddl.Create = sql + ";"
+		} else {
+			after = append(after, sql+";")
+		}
+	}
+	if ddl.Create == "" {
+		return TableDDL{}, fmt.Errorf("table %q not found", tableName)
+	}
+	ddl.After = strings.Join(after, "\n")
+	return ddl, nil
+}
+
+func (s *SQLiteConnection) GetViewDDL(viewName string) (string, error) {
+	if s.db == nil {
+		return "", fmt.Errorf("database not open")
+	}
+
+	var sql string
+	err := s.db.QueryRow(
+		"SELECT sql FROM sqlite_master WHERE type = 'view' AND name = ?",
+		viewName,
+	).Scan(&sql)
+	if err != nil {
+		return "", fmt.Errorf("failed to read view %q: %w", viewName, err)
+	}
+	return sql + ";", nil
+}
@@ -71,6 +71,10 @@ func (s *SQLServerConnection) Exec(sql string, args ...any) error {
 	return err
 }
 
+func (s *SQLServerConnection) GetDB() *sql.DB {
+	return s.db
+}
+
 func (s *SQLServerConnection) GetTableMetadata(
 	tableName string,
 ) (*TableMetadata, error) {
@@ -657,3 +661,269 @@ func (s *SQLServerConnection) ApplyRowLimit(sql string, limit int) string {
 		strings.TrimRight(sql, ";"),
 		limit)
 }
+
+func (s *SQLServerConnection) GetIndexes(
+	tableName string,
+) ([]IndexInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			i.name,
+			STUFF((
+				SELECT ',' + c.name
+				FROM sys.index_columns ic
+				JOIN sys.columns c
+					ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+				WHERE ic.object_id = i.object_id
+				  AND ic.index_id = i.index_id
+				  AND ic.is_included_column = 0
+				ORDER BY ic.key_ordinal
+				FOR XML PATH('')
+			), 1, 1, ''),
+			i.is_unique,
+			i.is_primary_key,
+			i.type_desc
+		FROM sys.indexes i
+		WHERE i.object_id = OBJECT_ID(QUOTENAME(SCHEMA_NAME()) + '.' + QUOTENAME(@p1))
+		  AND i.name IS NOT NULL
+		ORDER BY i.is_primary_key DESC, i.name
+	`
+
+	rows, err := s.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var idx IndexInfo
+		var columns sql.NullString
+		if err := rows.Scan(
+			&idx.Name,
+			&columns,
+			&idx.Unique,
+			&idx.Primary,
+			&idx.Method,
+		); err == nil {
+			idx.Columns = splitColumnList(columns.String)
+			indexes = append(indexes, idx)
+		}
+	}
+
+	return indexes, nil
+}
+
+func (s *SQLServerConnection) GetConstraints(
+	tableName string,
+) ([]ConstraintInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			cc.name,
+			'C',
+			COALESCE(COL_NAME(cc.parent_object_id, cc.parent_column_id), ''),
+			cc.definition,
+			''
+		FROM sys.check_constraints cc
+		WHERE cc.parent_object_id = OBJECT_ID(QUOTENAME(SCHEMA_NAME()) + '.' + QUOTENAME(@p1))
+		UNION ALL
+		SELECT
+			fk.name,
+			'F',
+			STUFF((
+				SELECT ',' + COL_NAME(fkc.parent_object_id, fkc.parent_column_id)
+				FROM sys.foreign_key_columns fkc
+				WHERE fkc.constraint_object_id = fk.object_id
+				ORDER BY fkc.constraint_column_id
+				FOR XML PATH('')
+			), 1, 1, ''),
+			OBJECT_NAME(fk.referenced_object_id),
+			STUFF((
+				SELECT ',' + COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id)
+				FROM sys.foreign_key_columns fkc
+				WHERE fkc.constraint_object_id = fk.object_id
+				ORDER BY fkc.constraint_column_id
+				FOR XML PATH('')
+			), 1, 1, '')
+		FROM sys.foreign_keys fk
+		WHERE fk.parent_object_id = OBJECT_ID(QUOTENAME(SCHEMA_NAME()) + '.' + QUOTENAME(@p1))
+		ORDER BY 2, 1
+	`
+
+	rows, err := s.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query constraints: %w", err)
+	}
+	defer rows.Close()
+
+	var constraints []ConstraintInfo
+	for rows.Next() {
+		var c ConstraintInfo
+		var kind, columns, detail, refColumns string
+		if err := rows.Scan(
+			&c.Name,
+			&kind,
+			&columns,
+			&detail,
+			&refColumns,
+		); err != nil {
+			continue
+		}
+		c.Columns = splitColumnList(columns)
+		if kind == "F" {
+			c.Type = ConstraintForeignKey
+			c.Definition = fmt.Sprintf(
+				"FOREIGN KEY (%s) REFERENCES %s (%s)",
+				strings.Join(c.Columns, ", "),
+				detail,
+				strings.Join(splitColumnList(refColumns), ", "),
+			)
+		} else {
+			c.Type = ConstraintCheck
+			c.Definition = "CHECK " + detail
+		}
+		constraints = append(constraints, c)
+	}
+
+	return constraints, nil
+}
+
+func (s *SQLServerConnection) GetTriggers(
+	tableName string,
+) ([]TriggerInfo, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database is not open")
+	}
+
+	query := `
+		SELECT
+			tr.name,
+			tr.is_instead_of_trigger,
+			STUFF((
+				SELECT ' OR ' + te.type_desc
+				FROM sys.trigger_events te
+				WHERE te.object_id = tr.object_id
+				FOR XML PATH('')
+			), 1, 4, ''),
+			COALESCE(OBJECT_DEFINITION(tr.object_id), '')
+		FROM sys.triggers tr
+		WHERE tr.parent_id = OBJECT_ID(QUOTENAME(SCHEMA_NAME()) + '.' + QUOTENAME(@p1))
+		ORDER BY tr.name
+	`
+
+	rows, err := s.db.Query(query, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerInfo
+	for rows.Next() {
+		var t TriggerInfo
+		var insteadOf bool
+		var events sql.NullString
+		if err := rows.Scan(
+			&t.Name,
+			&insteadOf,
+			&events,
+			&t.Definition,
+		); err != nil {
+			continue
+		}
+		t.Timing = "AFTER"
+		if insteadOf {
+			t.Timing = "INSTEAD OF"
+		}
+		t.Event = events.String
+		triggers = append(triggers, t)
+	}
+
+	return triggers, nil
+}
+
+func (s *SQLServerConnection) BuildDropIndexSQL(
+	tableName string,
+	index IndexInfo,
+) string {
+	if index.Primary {
+		return fmt.Sprintf(
+			"ALTER TABLE %s DROP CONSTRAINT %s;",
+			tableName,
+			index.Name,
+		)
+	}
+	return fmt.Sprintf("DROP INDEX %s ON %s;", index.Name, tableName)
+}
+
+func (s *SQLServerConnection) BuildRenameIndexSQL(
+	tableName string,
+	index IndexInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"EXEC sp_rename '%s.%s', '%s', 'INDEX';",
+		tableName,
+		index.Name,
+		newName,
+	)
+}
+
+func (s *SQLServerConnection) BuildRenameConstraintSQL(
+	tableName string,
+	constraint ConstraintInfo,
+	newName string,
+) string {
+	return fmt.Sprintf(
+		"EXEC sp_rename '%s', '%s', 'OBJECT';",
+		constraint.Name,
+		newName,
+	)
+}
+
+func (s *SQLServerConnection) BuildCreateTriggerSQL(
+	tableName, triggerName string,
+) string {
+	return fmt.Sprintf(
+		"CREATE TRIGGER %s ON %s\nAFTER INSERT, UPDATE\nAS\nBEGIN\n  SET NOCOUNT ON;\nEND;",
+		triggerName,
+		tableName,
+	)
+}
+
+func (s *SQLServerConnection) BuildDropTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+) string {
+	return fmt.Sprintf("DROP TRIGGER %s;", trigger.Name)
+}
+
+func (s *SQLServerConnection) BuildRenameTriggerSQL(
+	tableName string,
+	trigger TriggerInfo,
+	newName string,
+) string {
+	return fmt.Sprintf("EXEC sp_rename '%s', '%s';", trigger.Name, newName)
+}
+
+func (s *SQLServerConnection) BuildRenameTableSQL(
+	oldName, newName string,
+) string {
+	return fmt.Sprintf("EXEC sp_rename '%s', '%s';", oldName, newName)
+}
+
+func (s *SQLServerConnection) BuildCloneTableSQL(
+	source, target string,
+	withData bool,
+) string {
+	if !withData {
+		return fmt.Sprintf("SELECT TOP 0 * INTO %s FROM %s;", target, source)
+	}
+	return fmt.Sprintf("SELECT * INTO %s FROM %s;", target, source)
+}
@@ -0,0 +1,998 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // keywords and bare identifiers
+	tokIdent                   // quoted identifier; text is the name
+	tokString                  // string literal; text is the value
+	tokNumber                  // numeric literal
+	tokSpace                   // whitespace
+	tokPunct                   // any other single character
+	tokRaw                     // passed through as is, e.g. $$ bodies
+)
+
+type sqlToken struct {
+	kind tokenKind
+	text string
+	gap  bool // whitespace came before it, kept by significant
+}
+
+// tokenizeSQL splits one statement into tokens, reading string escapes and
+// identifier quotes the way dialect writes them
+func tokenizeSQL(sql, dialect string) []sqlToken {
+	runes := []rune(sql)
+	n := len(runes)
+	var tokens []sqlToken
+
+	isWordRune := func(ch rune) bool {
+		return ch == '_' || ch == '$' || ch == '@' || ch == '#' ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') ||
+			(ch >= '0' && ch <= '9') || ch > 127
+	}
+
+	for i := 0; i < n; {
+		ch := runes[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			j := i
+			for j < n && (runes[j] == ' ' || runes[j] == '\t' || runes[j] == '\n' || runes[j] == '\r') {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: tokSpace, text: string(runes[i:j])})
+			i = j
+
+		case ch == '\'':
+			var value strings.Builder
+			j := i + 1
+			for j < n {
+				c := runes[j]
+				if c == '\\' && backslashEscapes(dialect) && j+1 < n {
+					value.WriteString(unescapeBackslash(runes[j+1]))
+					j += 2
+					continue
+				}
+				if c == '\'' {
+					if j+1 < n && runes[j+1] == '\'' {
+						value.WriteRune('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				value.WriteRune(c)
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: tokString, text: value.String()})
+			i = j + 1
+
+		case ch == '"' || ch == '`' || (ch == '[' && dialect == "sqlserver"):
+			closing := ch
+			if ch == '[' {
+				closing = ']'
+			}
+			var name strings.Builder
+			j := i + 1
+			for j < n {
+				if runes[j] == closing {
+					if j+1 < n && runes[j+1] == closing {
+						name.WriteRune(closing)
+						j += 2
+						continue
+					}
+					break
+				}
+				name.WriteRune(runes[j])
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: tokIdent, text: name.String()})
+			i = j + 1
+
+		case ch == '$':
+			if tag, ok := dollarQuoteTag(runes, i); ok {
+				rest := string(runes[i+len([]rune(tag)):])
+				end := strings.Index(rest, tag)
+				if end < 0 {
+					end = len(rest)
+				} else {
+					end += len(tag)
+				}
+				body := tag + rest[:end]
+				tokens = append(tokens, sqlToken{kind: tokRaw, text: body})
+				i += len([]rune(body))
+				continue
+			}
+			tokens = append(tokens, sqlToken{kind: tokPunct, text: "$"})
+			i++
+
+		case ch >= '0' && ch <= '9' || (ch == '.' && i+1 < n && runes[i+1] >= '0' && runes[i+1] <= '9'):
+			j := i
+			for j < n && (runes[j] >= '0' && runes[j] <= '9' || runes[j] == '.' ||
+				runes[j] == 'e' || runes[j] == 'E' ||
+				((runes[j] == '-' || runes[j] == '+') && (runes[j-1] == 'e' || runes[j-1] == 'E'))) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: tokNumber, text: string(runes[i:j])})
+			i = j
+
+		case isWordRune(ch):
+			j := i
+			for j < n && isWordRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{kind: tokWord, text: string(runes[i:j])})
+			i = j
+
+		default:
+			tokens = append(tokens, sqlToken{kind: tokPunct, text: string(ch)})
+			i++
+		}
+	}
+	return tokens
+}
+
+// unescapeBackslash resolves a MySQL backslash escape
+func unescapeBackslash(ch rune) string {
+	switch ch {
+	case '0':
+		return "\x00"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case 'b':
+		return "\b"
+	case 'Z':
+		return "\x1a"
+	case '%', '_':
+		// Kept for LIKE patterns
+		return `\` + string(ch)
+	}
+	return string(ch)
+}
+
+// renderTokens writes tokens back as SQL for dialect
+func renderTokens(tokens []sqlToken, dialect string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		switch t.kind {
+		case tokIdent:
+			b.WriteString(QuoteIdentifierFor(dialect, t.text))
+		case tokString:
+			b.WriteString(QuoteStringFor(dialect, t.text))
+		default:
+			b.WriteString(t.text)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func isWord(t sqlToken, words ...string) bool {
+	if t.kind != tokWord {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// significant drops whitespace tokens, marking the tokens it preceded
+func significant(tokens []sqlToken) []sqlToken {
+	var out []sqlToken
+	gap := false
+	for _, t := range tokens {
+		if t.kind == tokSpace {
+			gap = true
+			continue
+		}
+		t.gap = t.gap || gap
+		gap = false
+		out = append(out, t)
+	}
+	return out
+}
+
+// splitTopLevel splits tokens at commas outside parentheses
+func splitTopLevel(tokens []sqlToken) [][]sqlToken {
+	var parts [][]sqlToken
+	var current []sqlToken
+	depth := 0
+	for _, t := range tokens {
+		if t.kind == tokPunct {
+			switch t.text {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			case ",":
+				if depth == 0 {
+					parts = append(parts, current)
+					current = nil
+					continue
+				}
+			}
+		}
+		current = append(current, t)
+	}
+	if len(significant(current)) > 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open
+func matchingParen(tokens []sqlToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != tokPunct {
+			continue
+		}
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// identName returns the identifier a token spells, quoted or not
+func identName(t sqlToken) string {
+	return t.text
+}
+
+// translateFragment translates a piece of SQL such as a constraint
+// definition: identifiers and strings re-quoted, casts and boolean
+// keywords adjusted
+func translateFragment(sql, source, target string) string {
+	t := &Translator{source: source, target: target}
+	return t.fragment(tokenizeSQL(sql, source))
+}
+
+func (t *Translator) fragment(tokens []sqlToken) string {
+	tokens = t.rewrite(quoteKeyNames(tokens))
+	var out []sqlToken
+	for i := 0; i < len(tokens); i++ {
+		// DuckDB has no referential actions and Oracle no ON UPDATE
+		if isWord(tokens[i], "ON") {
+			words := significant(tokens[i+1:])
+			if len(words) > 1 && isWord(words[0], "DELETE", "UPDATE") &&
+				(t.target == "duckdb" || (t.target == "oracle" && isWord(words[0], "UPDATE"))) {
+				skip := 2
+				if isWord(words[1], "SET", "NO") {
+					skip = 3
+				}
+				for skip > 0 && i+1 < len(tokens) {
+					i++
+					if tokens[i].kind != tokSpace {
+						skip--
+					}
+				}
+				continue
+			}
+		}
+		out = append(out, tokens[i])
+	}
+	return renderTokens(out, t.target)
+}
+
+// quoteKeyNames marks the bare names of a foreign key as identifiers, the
+// columns after FOREIGN KEY and the table and columns after REFERENCES, so
+// they keep their case where the target folds unquoted names
+func quoteKeyNames(tokens []sqlToken) []sqlToken {
+	out := make([]sqlToken, len(tokens))
+	copy(out, tokens)
+
+	// nextSignificant skips whitespace from i
+	nextSignificant := func(i int) int {
+		for i < len(out) && out[i].kind == tokSpace {
+			i++
+		}
+		return i
+	}
+	// names marks the words of the parenthesised list at i
+	names := func(i int) {
+		if i >= len(out) || out[i].text != "(" {
+			return
+		}
+		for j := i + 1; j < matchingParen(out, i); j++ {
+			if out[j].kind == tokWord {
+				out[j].kind = tokIdent
+			}
+		}
+	}
+
+	for i, tok := range out {
+		switch {
+		case isWord(tok, "KEY") && i > 0:
+			names(nextSignificant(i + 1))
+		case isWord(tok, "REFERENCES"):
+			j := nextSignificant(i + 1)
+			for j < len(out) && (out[j].kind == tokWord || out[j].kind == tokIdent) {
+				out[j].kind = tokIdent
+				if j+1 < len(out) && out[j+1].text == "." {
+					j += 2
+					continue
+				}
+				j++
+				break
+			}
+			names(nextSignificant(j))
+		}
+	}
+	return out
+}
+
+// Translator rewrites a dump written for one dialect so another can run
+// it: types, identifier quoting, string escapes, boolean literals and
+// auto-increment syntax. It remembers the tables it has created so later
+// INSERTs can be adjusted to them.
+type Translator struct {
+	source, target string
+	tables         map[string]*translatedTable
+	deferred       []string
+	// nextKeys holds, by lower-case table name, the value after the
+	// highest key inserted into its auto-increment column
+	nextKeys map[string]int64
+}
+
+type translatedTable struct {
+	columns  []string
+	kinds    map[string]columnType // by lower-case column name
+	auto     string                // auto-increment column
+	identity bool                  // auto is a SQL Server identity column
+}
+
+// NewTranslator prepares a translation between two dialects
+func NewTranslator(source, target string) (*Translator, error) {
+	src, err := NormalizeDialect(source)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := NormalizeDialect(target)
+	if err != nil {
+		return nil, err
+	}
+	return &Translator{
+		source:   src,
+		target:   dst,
+		tables:   map[string]*translatedTable{},
+		nextKeys: map[string]int64{},
+	}, nil
+}
+
+// TranslateScript converts a whole dump, followed by its deferred
+// statements. It reads the dump twice: the first pass learns the highest
+// key inserted into each auto-increment column, so counters the target
+// fixes at creation, like DuckDB's sequences, start past them.
+func (t *Translator) TranslateScript(statements []string) []string {
+	t.learnKeys(statements)
+	var out []string
+	for _, stmt := range statements {
+		out = append(out, t.Translate(stmt)...)
+	}
+	return append(out, t.deferred...)
+}
+
+// learnKeys is TranslateScript's first pass: it translates the statements
+// for the keys they insert, leaving the translator otherwise fresh
+func (t *Translator) learnKeys(statements []string) {
+	for _, stmt := range statements {
+		t.Translate(stmt)
+	}
+	t.tables = map[string]*translatedTable{}
+	t.deferred = nil
+}
+
+// Translate converts one statement. It may return several statements, or
+// none for session settings that only mean something to the source.
+func (t *Translator) Translate(stmt string) []string {
+	tokens := tokenizeSQL(stmt, t.source)
+	words := significant(tokens)
+	if len(words) == 0 {
+		return nil
+	}
+	if t.source == t.target {
+		return []string{stmt}
+	}
+	if t.sourceOnly(words) {
+		return nil
+	}
+
+	switch {
+	case isWord(words[0], "CREATE") && createsTable(words):
+		return t.translateCreateTable(tokens)
+	case isWord(words[0], "CREATE") && (isWord(words[1], "INDEX") ||
+		len(words) > 2 && isWord(words[1], "UNIQUE") && isWord(words[2], "INDEX")):
+		return []string{t.translateIndex(words)}
+	case isWord(words[0], "INSERT", "REPLACE"):
+		return []string{t.translateInsert(tokens)}
+	}
+	return []string{renderTokens(t.rewrite(tokens), t.target)}
+}
+
+// sourceOnly reports statements that configure the source's session or
+// objects the target gets another way: LOCK TABLES, SET, PRAGMA, sequences
+// behind serial columns, transactions.
+func (t *Translator) sourceOnly(words []sqlToken) bool {
+	first := words[0]
+	switch {
+	case isWord(first, "LOCK", "UNLOCK", "SET", "USE", "PRAGMA", "GO",
+		"DELIMITER", "BEGIN", "COMMIT", "START", "ROLLBACK", "END"):
+		return true
+	case isWord(first, "ALTER") && len(words) > 1 && isWord(words[1], "SEQUENCE"):
+		return true
+	case isWord(first, "CREATE") && len(words) > 1 && isWord(words[1], "SEQUENCE"):
+		return true
+	case isWord(first, "SELECT") && len(words) > 1 &&
+		(isWord(words[1], "setval", "pg_catalog")):
+		return true
+	case isWord(first, "ALTER") && len(words) > 4 &&
+		isWord(words[len(words)-1], "KEYS") &&
+		isWord(words[len(words)-2], "DISABLE", "ENABLE"):
+		return true
+	}
+	return false
+}
+
+func createsTable(words []sqlToken) bool {
+	for _, w := range words[1:] {
+		if isWord(w, "TABLE") {
+			return true
+		}
+		if !isWord(w, "TEMPORARY", "TEMP", "GLOBAL", "LOCAL", "OR", "REPLACE", "UNLOGGED") {
+			return false
+		}
+	}
+	return false
+}
+
+// rewrite applies the token-level translations: PostgreSQL casts are
+// dropped, boolean keywords become the target's literals and SQL Server's
+// N'...' strings lose their prefix
+func (t *Translator) rewrite(tokens []sqlToken) []sqlToken {
+	castsOK := t.target == "postgres" || t.target == "duckdb"
+	typeWord := func(tok sqlToken) bool {
+		return isWord(tok, "precision", "varying", "without", "with", "time", "zone")
+	}
+	var out []sqlToken
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind == tokPunct && tok.text == ":" &&
+			i+2 < len(tokens) && tokens[i+1].text == ":" && tokens[i+2].kind == tokWord {
+			// Find the end of ::type, ::type(n), ::double precision and ::type[]
+			j := i + 3
+			for j < len(tokens) && (typeWord(tokens[j]) ||
+				(tokens[j].kind == tokSpace && j+1 < len(tokens) && typeWord(tokens[j+1]))) {
+				j++
+			}
+			if j < len(tokens) && tokens[j].text == "(" {
+				j = matchingParen(tokens, j) + 1
+			}
+			for j+1 < len(tokens) && tokens[j].text == "[" && tokens[j+1].text == "]" {
+				j += 2
+			}
+			if castsOK {
+				var spelled strings.Builder
+				for _, part := range spaced(tokens[i+2 : j]) {
+					spelled.WriteString(part.text)
+				}
+				out = append(out, tokens[i], tokens[i+1], sqlToken{
+					kind: tokWord,
+					text: TranslateType(spelled.String(), t.source, t.target),
+				})
+			}
+			i = j - 1
+			continue
+		}
+		if isWord(tok, "TRUE", "FALSE") {
+			tok.text = booleanLiteralFor(t.target, strings.EqualFold(tok.text, "TRUE"))
+		}
+		// SQL Server's N'...' strings are plain strings elsewhere
+		if isWord(tok, "N") && t.target != "sqlserver" &&
+			i+1 < len(tokens) && tokens[i+1].kind == tokString {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// translateIndex drops the index method, which only PostgreSQL writes
+// between the table and its columns, and MySQL's column prefix lengths
+func (t *Translator) translateIndex(words []sqlToken) string {
+	var out []sqlToken
+	for i := 0; i < len(words); i++ {
+		if t.target != "postgres" && isWord(words[i], "USING") && i+1 < len(words) {
+			i++
+			continue
+		}
+		if t.source == "mysql" && words[i].text == "(" && i > 0 &&
+			words[i-1].kind == tokIdent && i+2 < len(words) &&
+			words[i+1].kind == tokNumber && words[i+2].text == ")" {
+			i += 2
+			continue
+		}
+		out = append(out, words[i])
+	}
+	return renderTokens(spaced(t.rewrite(out)), t.target)
+}
+
+// columnStopWords end a column's type in CREATE TABLE
+var columnStopWords = []string{
+	"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "AUTO_INCREMENT",
+	"AUTOINCREMENT", "IDENTITY", "GENERATED", "REFERENCES", "CHECK", "COLLATE",
+	"CHARSET", "COMMENT", "CONSTRAINT", "ON", "AS", "KEY",
+}
+
+func (t *Translator) translateCreateTable(tokens []sqlToken) []string {
+	words := significant(tokens)
+	open := -1
+	for i, w := range words {
+		if w.kind == tokPunct && w.text == "(" {
+			open = i
+			break
+		}
+	}
+	if open < 2 {
+		// CREATE TABLE ... AS SELECT and the like
+		return []string{renderTokens(t.rewrite(tokens), t.target)}
+	}
+	tableName := identName(words[open-1])
+	closing := matchingParen(words, open)
+
+	// AUTO_INCREMENT=N among MySQL's table options gives the next id
+	var next int64
+	for i := closing + 1; i+2 < len(words); i++ {
+		if isWord(words[i], "AUTO_INCREMENT") && words[i+1].text == "=" {
+			next, _ = strconv.ParseInt(words[i+2].text, 10, 64)
+		}
+	}
+
+	def := TableDefinition{Name: tableName}
+	table := &translatedTable{kinds: map[string]columnType{}}
+	var constraints, before, after []string
+	var autoColumns []string
+	integerColumns := map[string]bool{}
+	quote := func(s string) string { return QuoteIdentifierFor(t.target, s) }
+
+	for _, item := range splitTopLevel(words[open+1 : closing]) {
+		constraintName := ""
+		if isWord(item[0], "CONSTRAINT") && len(item) > 2 {
+			constraintName = identName(item[1])
+			item = item[2:]
+		}
+		named := func(body string) string {
+			if constraintName == "" {
+				return body
+			}
+			return "CONSTRAINT " + quote(constraintName) + " " + body
+		}
+
+		switch {
+		case isWord(item[0], "PRIMARY"):
+			cols := parenNames(item)
+			for _, col := range cols {
+				for i := range def.Columns {
+					if strings.EqualFold(def.Columns[i].Name, col) {
+						def.Columns[i].PrimaryKey = true
+					}
+				}
+			}
+			// A lone INTEGER key is SQLite's rowid, which numbers itself
+			if len(cols) == 1 && integerColumns[strings.ToLower(cols[0])] {
+				autoColumns = append(autoColumns, cols[0])
+			}
+
+		case isWord(item[0], "UNIQUE"):
+			cols := parenNames(item)
+			if len(cols) == 1 {
+				for i := range def.Columns {
+					if strings.EqualFold(def.Columns[i].Name, cols[0]) {
+						def.Columns[i].Unique = true
+					}
+				}
+			} else if len(cols) > 1 {
+				if constraintName == "" && len(item) > 2 && !isWord(item[1], "KEY", "INDEX") && item[1].text != "(" {
+					constraintName = identName(item[1])
+				} else if constraintName == "" && len(item) > 3 && item[2].text != "(" {
+					constraintName = identName(item[2])
+				}
+				constraints = append(constraints, named("UNIQUE ("+quoteList(cols, quote)+")"))
+			}
+
+		case isWord(item[0], "KEY", "INDEX"):
+			cols := parenNames(item)
+			indexName := tableName + "_" + strings.Join(cols, "_") + "_idx"
+			if len(item) > 1 && item[1].text != "(" {
+				indexName = identName(item[1])
+			}
+			after = append(after, fmt.Sprintf(
+				"CREATE INDEX %s ON %s (%s);",
+				quote(indexName), quote(tableName), quoteList(cols, quote),
+			))
+
+		case isWord(item[0], "FULLTEXT", "SPATIAL"):
+			// MySQL only
+
+		case isWord(item[0], "FOREIGN", "CHECK"):
+			if t.target != "clickhouse" {
+				constraints = append(constraints, named(t.fragment(spaced(item))))
+			}
+
+		default:
+			col, kind, auto, extra := t.parseColumn(item, quote)
+			if t.source == "sqlite" && len(item) > 1 && isWord(item[1], "INTEGER") {
+				integerColumns[strings.ToLower(col.Name)] = true
+			}
+			table.columns = append(table.columns, col.Name)
+			table.kinds[strings.ToLower(col.Name)] = kind
+			if auto {
+				autoColumns = append(autoColumns, col.Name)
+			}
+			if t.target != "clickhouse" {
+				constraints = append(constraints, extra...)
+			}
+			def.Columns = append(def.Columns, col)
+		}
+	}
+
+	for _, colName := range autoColumns {
+		for i := range def.Columns {
+			col := &def.Columns[i]
+			if col.Name != colName {
+				continue
+			}
+			var b, a []string
+			col.DataType, col.Default, b, a = autoIncrement(
+				t.target, tableName, col.Name, col.DataType,
+				max(next, t.nextKeys[strings.ToLower(tableName)]),
+			)
+			before = append(before, b...)
+			t.deferred = append(t.deferred, a...)
+			table.auto = col.Name
+			table.identity = t.target == "sqlserver"
+		}
+	}
+	t.tables[strings.ToLower(tableName)] = table
+
+	statements := append(before, renderCreateTable(def, constraints, t.target))
+	return append(statements, after...)
+}
+
+// parseColumn reads one column definition of a CREATE TABLE. Column-level
+// foreign keys and checks come back as table constraints.
+func (t *Translator) parseColumn(
+	item []sqlToken,
+	quote func(string) string,
+) (col ColumnDefinition, kind columnType, auto bool, constraints []string) {
+	col.Name = identName(item[0])
+	col.Nullable = true
+
+	i := 1
+	var typeTokens []sqlToken
+	for ; i < len(item); i++ {
+		tok := item[i]
+		if isWord(tok, columnStopWords...) ||
+			(isWord(tok, "CHARACTER") && i+1 < len(item) && isWord(item[i+1], "SET")) {
+			break
+		}
+		if tok.text == "(" {
+			end := matchingParen(item, i)
+			typeTokens = append(typeTokens, item[i:end+1]...)
+			i = end
+			continue
+		}
+		typeTokens = append(typeTokens, tok)
+	}
+	var typeText []string
+	for _, tok := range typeTokens {
+		typeText = append(typeText, tok.text)
+	}
+	dataType := strings.NewReplacer(" ( ", "(", " ( ", "(", " )", ")", " , ", ",").
+		Replace(strings.Join(typeText, " "))
+	dataType = strings.ReplaceAll(dataType, " (", "(")
+	kind = parseColumnType(dataType, t.source)
+	lowerType := strings.ToLower(dataType)
+	auto = strings.Contains(lowerType, "serial")
+
+	// until collects an expression up to the next column keyword
+	until := func(start int) int {
+		j := start
+		for j < len(item) {
+			if item[j].text == "(" {
+				j = matchingParen(item, j) + 1
+				continue
+			}
+			if isWord(item[j], columnStopWords...) {
+				break
+			}
+			j++
+		}
+		return j
+	}
+
+	for i < len(item) {
+		tok := item[i]
+		switch {
+		case isWord(tok, "NOT") && i+1 < len(item) && isWord(item[i+1], "NULL"):
+			col.Nullable = false
+			i += 2
+		case isWord(tok, "NULL"):
+			i++
+		case isWord(tok, "DEFAULT"):
+			end := until(i + 1)
+			raw := renderTokens(spaced(item[i+1:end]), t.source)
+			if strings.HasPrefix(strings.ToLower(raw), "nextval") {
+				auto = true
+			} else if value, ok := translateDefault(raw, kind, t.source, t.target); ok {
+				col.Default = value
+			}
+			i = end
+		case isWord(tok, "PRIMARY"):
+			col.PrimaryKey = true
+			if t.source == "sqlite" && strings.EqualFold(dataType, "INTEGER") {
+				auto = true
+			}
+			i += 2
+			if i < len(item) && isWord(item[i], "ASC", "DESC") {
+				i++
+			}
+		case isWord(tok, "UNIQUE"):
+			col.Unique = true
+			i++
+			if i < len(item) && isWord(item[i], "KEY") {
+				i++
+			}
+		case isWord(tok, "AUTO_INCREMENT", "AUTOINCREMENT"):
+			auto = true
+			i++
+		case isWord(tok, "IDENTITY"):
+			auto = true
+			i++
+			if i < len(item) && item[i].text == "(" {
+				i = matchingParen(item, i) + 1
+			}
+		case isWord(tok, "GENERATED"):
+			end := i + 1
+			for end < len(item) && !isWord(item[end], "IDENTITY", "AS") {
+				end++
+			}
+			if end+1 < len(item) && isWord(item[end], "AS") && isWord(item[end+1], "IDENTITY") {
+				end++
+			}
+			if end < len(item) && isWord(item[end], "IDENTITY") {
+				auto = true
+				end++
+				if end < len(item) && item[end].text == "(" {
+					end = matchingParen(item, end) + 1
+				}
+				i = end
+				continue
+			}
+			// A computed column keeps its values as plain data
+			i = until(end + 1)
+			for i < len(item) && isWord(item[i], "STORED", "VIRTUAL", "PERSISTED") {
+				i++
+			}
+		case isWord(tok, "AS"):
+			i = until(i + 1)
+			for i < len(item) && isWord(item[i], "STORED", "VIRTUAL", "PERSISTED") {
+				i++
+			}
+		case isWord(tok, "REFERENCES"):
+			end := i + 1
+			for end < len(item) && !isWord(item[end], "NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "CHECK", "CONSTRAINT", "COLLATE", "COMMENT") {
+				end++
+			}
+			constraints = append(constraints, fmt.Sprintf(
+				"FOREIGN KEY (%s) %s",
+				quote(col.Name),
+				t.fragment(spaced(item[i:end])),
+			))
+			i = end
+		case isWord(tok, "CHECK"):
+			end := i + 1
+			if end < len(item) && item[end].text == "(" {
+				end = matchingParen(item, end) + 1
+			}
+			constraints = append(constraints, t.fragment(spaced(item[i:end])))
+			i = end
+		case isWord(tok, "ON") && i+1 < len(item) && isWord(item[i+1], "UPDATE"):
+			// MySQL's ON UPDATE CURRENT_TIMESTAMP
+			i = until(i + 2)
+		case isWord(tok, "CONSTRAINT"):
+			i += 2
+		default:
+			// COLLATE x, CHARACTER SET x, COMMENT 'x' and unknown options
+			i++
+			if i < len(item) && isWord(tok, "CHARACTER") {
+				i++
+			}
+			if i < len(item) && !isWord(item[i], columnStopWords...) {
+				i++
+			}
+		}
+	}
+
+	if auto && kind.kind != kindInteger && kind.kind != kindBigInt && kind.kind != kindSmallInt {
+		kind = columnType{kind: kindInteger}
+	}
+	col.DataType = renderColumnType(kind, t.target)
+	if col.PrimaryKey {
+		col.Nullable = false
+	}
+	return col, kind, auto, constraints
+}
+
+// parenNames returns the column names in the first parenthesised list,
+// dropping MySQL prefix lengths such as name(10)
+func parenNames(item []sqlToken) []string {
+	open := -1
+	for i, tok := range item {
+		if tok.text == "(" {
+			open = i
+			break
+		}
+	}
+	if open < 0 {
+		return nil
+	}
+	closing := matchingParen(item, open)
+	var names []string
+	for _, part := range splitTopLevel(item[open+1 : closing]) {
+		part = significant(part)
+		if len(part) > 0 && (part[0].kind == tokIdent || part[0].kind == tokWord) {
+			names = append(names, identName(part[0]))
+		}
+	}
+	return names
+}
+
+// spaced puts a single space back wherever significant dropped whitespace
+func spaced(tokens []sqlToken) []sqlToken {
+	var out []sqlToken
+	for i, tok := range tokens {
+		if i > 0 && tok.gap {
+			out = append(out, sqlToken{kind: tokSpace, text: " "})
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// translateInsert re-quotes an INSERT and turns the 1/0 of boolean columns
+// into the target's literals. Into a SQL Server identity column, the
+// statement is wrapped in SET IDENTITY_INSERT.
+func (t *Translator) translateInsert(tokens []sqlToken) string {
+	tokens = t.rewrite(tokens)
+	words := significant(tokens)
+
+	// INSERT [IGNORE|OR x] INTO table [(columns)] VALUES (...), (...)
+	i := 1
+	ignore := false
+	for i < len(words) && !isWord(words[i], "INTO") {
+		if isWord(words[i], "IGNORE") {
+			ignore = true
+		}
+		i++
+	}
+	if i+1 >= len(words) {
+		return renderTokens(tokens, t.target)
+	}
+	// Like CREATE TABLE, drop the schema and quote the names, so they
+	// match the translated table wherever the target folds case
+	if i+3 < len(words) && words[i+2].text == "." {
+		words = append(words[:i+1], words[i+3:]...)
+		words[i+1].gap = true
+	}
+	words[i+1].kind = tokIdent
+	tableName := identName(words[i+1])
+	table := t.tables[strings.ToLower(tableName)]
+
+	columns := []string(nil)
+	if table != nil {
+		columns = table.columns
+	}
+	valuesAt := i + 2
+	if valuesAt < len(words) && words[valuesAt].text == "(" {
+		closing := matchingParen(words, valuesAt)
+		for j := valuesAt + 1; j < closing; j++ {
+			if words[j].kind == tokWord && (words[j-1].text == "(" || words[j-1].text == ",") {
+				words[j].kind = tokIdent
+			}
+		}
+		columns = nil
+		for _, part := range splitTopLevel(words[valuesAt+1 : closing]) {
+			if part = significant(part); len(part) > 0 {
+				columns = append(columns, identName(part[0]))
+			}
+		}
+		valuesAt = closing + 1
+	}
+
+	if table != nil {
+		// Rewrite literals in boolean columns, tuple by tuple
+		depth, column := 0, 0
+		for j := valuesAt; j < len(words); j++ {
+			tok := words[j]
+			switch {
+			case tok.text == "(":
+				depth++
+				if depth == 1 {
+					column = 0
+				}
+			case tok.text == ")":
+				depth--
+			case tok.text == "," && depth == 1:
+				column++
+			case depth == 1 && column < len(columns) &&
+				table.kinds[strings.ToLower(columns[column])].kind == kindBoolean:
+				if value, ok := parseBool(tok); ok {
+					words[j].kind = tokWord
+					words[j].text = booleanLiteralFor(t.target, value)
+				}
+			case depth == 1 && column < len(columns) && tok.kind == tokNumber &&
+				strings.EqualFold(columns[column], table.auto):
+				if key, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+					lower := strings.ToLower(tableName)
+					t.nextKeys[lower] = max(t.nextKeys[lower], key+1)
+				}
+			}
+		}
+	}
+
+	// Drop MySQL's IGNORE; SQLite and PostgreSQL have their own forms
+	var out []sqlToken
+	for _, w := range words {
+		if isWord(w, "IGNORE") && ignore {
+			if t.target == "sqlite" {
+				out = append(out, sqlToken{kind: tokWord, text: "OR IGNORE", gap: true})
+			}
+			continue
+		}
+		out = append(out, w)
+	}
+	stmt := renderTokens(spaced(out), t.target)
+	if ignore && t.target == "postgres" {
+		stmt += " ON CONFLICT DO NOTHING"
+	}
+
+	if table != nil && table.identity {
+		for _, c := range columns {
+			if strings.EqualFold(c, table.auto) {
+				q := QuoteIdentifierFor(t.target, tableName)
+				return fmt.Sprintf(
+					"SET IDENTITY_INSERT %s ON;\n%s;\nSET IDENTITY_INSERT %s OFF",
+					q, stmt, q,
+				)
+			}
+		}
+	}
+	return stmt
+}
+
+func parseBool(tok sqlToken) (bool, bool) {
+	switch strings.ToLower(tok.text) {
+	case "1", "true", "t":
+		return true, tok.kind != tokIdent
+	case "0", "false", "f":
+		return false, tok.kind != tokIdent
+	}
+	return false, false
+}
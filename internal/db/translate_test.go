@@ -0,0 +1,183 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+var allDialects = []string{
+	"postgres", "mysql", "sqlite", "sqlserver", "oracle", "duckdb",
+	"clickhouse", "firebird", "snowflake",
+}
+
+// dumpsBySource is the same table as each source writes it in a dump
+var dumpsBySource = map[string][]string{
+	"postgres": {
+		`SET client_encoding = 'UTF8'`,
+		`CREATE TABLE public.users (id serial NOT NULL, name character varying(40) NOT NULL, active boolean DEFAULT true, score numeric(8,2), PRIMARY KEY (id))`,
+		`INSERT INTO public.users (id, name, active, score) VALUES (1, 'O''Brien', true, 1.50)`,
+	},
+	"mysql": {
+		"LOCK TABLES `users` WRITE",
+		"CREATE TABLE `users` (`id` int NOT NULL AUTO_INCREMENT, `name` varchar(40) NOT NULL, `active` tinyint(1) DEFAULT '1', `score` decimal(8,2) DEFAULT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB AUTO_INCREMENT=2",
+		"INSERT INTO `users` VALUES (1,'O\\'Brien',1,1.50)",
+	},
+	"sqlite": {
+		`PRAGMA foreign_keys=OFF`,
+		`CREATE TABLE "users" (id INTEGER PRIMARY KEY, name VARCHAR(40) NOT NULL, active BOOLEAN DEFAULT 1, score NUMERIC(8,2))`,
+		`INSERT INTO "users" VALUES (1, 'O''Brien', 1, 1.50)`,
+	},
+	"sqlserver": {
+		`SET NOCOUNT ON`,
+		`CREATE TABLE [users] ([id] INT IDENTITY(1,1) NOT NULL PRIMARY KEY, [name] NVARCHAR(40) NOT NULL, [active] BIT DEFAULT ((1)), [score] DECIMAL(8,2))`,
+		`INSERT INTO [users] ([id], [name], [active], [score]) VALUES (1, N'O''Brien', 1, 1.50)`,
+	},
+}
+
+// translatedByTarget is what every source's dump should turn into
+var translatedByTarget = map[string]struct {
+	name, active, score, id, truth string
+}{
+	"postgres":   {"varchar(40) NOT NULL", "boolean DEFAULT TRUE", "numeric(8,2)", "integer GENERATED BY DEFAULT AS IDENTITY", "TRUE"},
+	"mysql":      {"VARCHAR(40) NOT NULL", "TINYINT(1) DEFAULT TRUE", "DECIMAL(8,2)", "INT AUTO_INCREMENT", "TRUE"},
+	"sqlite":     {"VARCHAR(40) NOT NULL", "BOOLEAN DEFAULT TRUE", "NUMERIC(8,2)", "INTEGER", "TRUE"},
+	"sqlserver":  {"NVARCHAR(40) NOT NULL", "BIT DEFAULT 1", "DECIMAL(8,2)", "INT IDENTITY(", "1"},
+	"oracle":     {"VARCHAR2(40) NOT NULL", "NUMBER(1) DEFAULT 1", "NUMBER(8,2)", "NUMBER(10) GENERATED BY DEFAULT AS IDENTITY", "1"},
+	"duckdb":     {"VARCHAR(40) NOT NULL", "BOOLEAN DEFAULT TRUE", "DECIMAL(8,2)", "INTEGER DEFAULT nextval('users_id_seq')", "TRUE"},
+	"clickhouse": {"String", "Nullable(Bool) DEFAULT TRUE", "Nullable(Decimal(8,2))", "Int32", "TRUE"},
+	"firebird":   {"VARCHAR(40) NOT NULL", "BOOLEAN DEFAULT TRUE", "NUMERIC(8,2)", "INTEGER GENERATED BY DEFAULT AS IDENTITY", "TRUE"},
+	"snowflake":  {"VARCHAR(40) NOT NULL", "BOOLEAN DEFAULT TRUE", "NUMBER(8,2)", "NUMBER(10,0) AUTOINCREMENT", "TRUE"},
+}
+
+func TestTranslator_EveryPair(t *testing.T) {
+	for source, dump := range dumpsBySource {
+		for _, target := range allDialects {
+			t.Run(source+"_to_"+target, func(t *testing.T) {
+				tr, err := NewTranslator(source, target)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := strings.Join(tr.TranslateScript(dump), "\n")
+				if source == target {
+					if got != strings.Join(dump, "\n") {
+						t.Errorf("same-dialect dump changed:\n%s", got)
+					}
+					return
+				}
+
+				want := translatedByTarget[target]
+				q := func(name string) string { return QuoteIdentifierFor(target, name) }
+				for _, part := range []string{
+					"CREATE TABLE " + q("users") + " (",
+					q("id") + " " + want.id,
+					q("name") + " " + want.name,
+					q("active") + " " + want.active,
+					q("score") + " " + want.score,
+					"INSERT INTO " + q("users"),
+				} {
+					if !strings.Contains(got, part) {
+						t.Errorf("missing %q in:\n%s", part, got)
+					}
+				}
+				// Dumps differ in the spacing of their values
+				if row := "(1,'O''Brien'," + want.truth + ",1.50)"; !strings.Contains(strings.ReplaceAll(got, " ", ""), row) {
+					t.Errorf("missing the row %s in:\n%s", row, got)
+				}
+				for _, leftover := range []string{"public.", "N'", "client_encoding", "NOCOUNT", "LOCK TABLES", "PRAGMA", `\'`} {
+					if strings.Contains(got, leftover) {
+						t.Errorf("source-only %q left in:\n%s", leftover, got)
+					}
+				}
+				if target == "sqlserver" && !strings.Contains(got, "SET IDENTITY_INSERT [users] ON;") {
+					t.Errorf("an insert into the identity column needs IDENTITY_INSERT:\n%s", got)
+				}
+			})
+		}
+	}
+}
+
+func TestTranslator_NextKeyFromInserts(t *testing.T) {
+	tr, err := NewTranslator("postgres", "duckdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := tr.TranslateScript([]string{
+		`CREATE TABLE t (id serial PRIMARY KEY)`,
+		`INSERT INTO t (id) VALUES (1), (41)`,
+	})
+	if !strings.Contains(got[0], `CREATE SEQUENCE IF NOT EXISTS "t_id_seq" START WITH 42;`) {
+		t.Errorf("sequence = %q", got[0])
+	}
+}
+
+func TestTranslateType(t *testing.T) {
+	tests := []struct {
+		dataType, source, target, want string
+	}{
+		{"tinyint(1)", "mysql", "postgres", "boolean"},
+		{"int unsigned", "mysql", "sqlserver", "INT"},
+		{"character varying(255)", "postgres", "oracle", "VARCHAR2(255)"},
+		{"varchar(5000)", "postgres", "sqlserver", "NVARCHAR(MAX)"},
+		{"NVARCHAR(MAX)", "sqlserver", "mysql", "LONGTEXT"},
+		{"NUMBER(1)", "oracle", "postgres", "boolean"},
+		{"NUMBER(9)", "oracle", "mysql", "INT"},
+		{"NUMBER(10)", "oracle", "mysql", "BIGINT"},
+		{"NUMBER(12,2)", "oracle", "duckdb", "DECIMAL(12,2)"},
+		{"numeric", "postgres", "mysql", "DECIMAL(65,30)"},
+		{"Nullable(String)", "clickhouse", "postgres", "text"},
+		{"Decimal64(4)", "clickhouse", "postgres", "numeric(38,4)"},
+		{"REAL", "sqlite", "postgres", "double precision"},
+		{"timestamp with time zone", "postgres", "sqlserver", "DATETIMEOFFSET"},
+		{"jsonb", "postgres", "snowflake", "VARIANT"},
+		{"uuid", "postgres", "firebird", "CHAR(36)"},
+		{"bytea", "postgres", "clickhouse", "String"},
+		{"integer[]", "postgres", "duckdb", "VARCHAR"},
+	}
+	for _, tt := range tests {
+		if got := TranslateType(tt.dataType, tt.source, tt.target); got != tt.want {
+			t.Errorf("TranslateType(%q, %s, %s) = %q, want %q", tt.dataType, tt.source, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestTranslateDefault(t *testing.T) {
+	tests := []struct {
+		def, source, target string
+		col                 columnType
+		want                string
+		ok                  bool
+	}{
+		{"((0))", "sqlserver", "postgres", columnType{kind: kindBoolean}, "FALSE", true},
+		{"'active'::character varying", "postgres", "mysql", columnType{kind: kindString}, "'active'", true},
+		{"now()", "postgres", "sqlite", columnType{kind: kindTimestamp}, "CURRENT_TIMESTAMP", true},
+		{"pending", "mysql", "postgres", columnType{kind: kindString}, "'pending'", true},
+		{`'a\b'`, "postgres", "mysql", columnType{kind: kindText}, `'a\\b'`, true},
+		{"gen_random_uuid()", "postgres", "mysql", columnType{kind: kindUUID}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := translateDefault(tt.def, tt.col, tt.source, tt.target)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("translateDefault(%q, %s, %s) = %q, %v, want %q, %v", tt.def, tt.source, tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNewTranslator_UnknownDialect(t *testing.T) {
+	if _, err := NewTranslator("postgres", "access"); err == nil {
+		t.Error("expected an error for an unknown dialect")
+	}
+}
+
+func TestQuoteIdentifierFor(t *testing.T) {
+	cases := []struct{ dialect, name, want string }{
+		{"mysql", "order", "`order`"},
+		{"clickhouse", "a`b", "`a``b`"},
+		{"sqlserver", "a]b", "[a]]b]"},
+		{"postgres", `Na"me`, `"Na""me"`},
+	}
+	for _, c := range cases {
+		if got := QuoteIdentifierFor(c.dialect, c.name); got != c.want {
+			t.Errorf("QuoteIdentifierFor(%s, %s) = %s, want %s", c.dialect, c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+// Package federate runs one query across several configured connections by
+// attaching them to an in-process DuckDB session.
+package federate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Mode tells how a connection was made available to DuckDB
+type Mode string
+
+const (
+	// ModeScanner reads through a DuckDB scanner extension (or natively for
+	// DuckDB files), so filters run against the live database
+	ModeScanner Mode = "scanner"
+	// ModeMaterialized copies the referenced tables into DuckDB first
+	ModeMaterialized Mode = "materialized"
+)
+
+// TableRef is a table referenced as <connection>.[schema.]table
+type TableRef struct {
+	Schema string
+	Table  string
+}
+
+func (t TableRef) String() string {
+	if t.Schema != "" {
+		return t.Schema + "." + t.Table
+	}
+	return t.Table
+}
+
+// Attachment reports how one connection was attached.
+type Attachment struct {
+	Alias  string
+	DbType string
+	Mode   Mode
+	Tables []TableRef
+	Rows   int64
+	// Fallback is why a scanner could not be used, when it was tried
+	Fallback string
+}
+
+var (
+	identPart  = `(?:"((?:[^"]|"")+)"|([A-Za-z_][A-Za-z0-9_$]*))`
+	qualified  = regexp.MustCompile(identPart + `\s*\.\s*` + identPart + `(?:\s*\.\s*` + identPart + `)?`)
+	literalsRe = regexp.MustCompile(`'(?:[^']|'')*'|--[^\n]*|/\*(?s:.*?)\*/`)
+)
+
+func unquote(quoted, bare string) string {
+	if quoted != "" {
+		return strings.ReplaceAll(quoted, `""`, `"`)
+	}
+	return bare
+}
+
+// References finds the tables of sql qualified with one of the given
+// connection names, grouped by connection. String literals and comments are
+// ignored. A two-part name is connection.table, a three-part name
+// connection.schema.table.
+func References(sql string, connections []string) map[string][]TableRef {
+	known := map[string]bool{}
+	for _, c := range connections {
+		known[c] = true
+	}
+
+	clean := literalsRe.ReplaceAllStringFunc(sql, func(s string) string {
+		return strings.Repeat(" ", len(s))
+	})
+
+	refs := map[string][]TableRef{}
+	seen := map[string]bool{}
+	for _, m := range qualified.FindAllStringSubmatch(clean, -1) {
+		alias := unquote(m[1], m[2])
+		if !known[alias] {
+			continue
+		}
+		ref := TableRef{Table: unquote(m[3], m[4])}
+		if third := unquote(m[5], m[6]); third != "" {
+			ref = TableRef{Schema: ref.Table, Table: third}
+		}
+		key := alias + "\x00" + ref.String()
+		if !seen[key] {
+			seen[key] = true
+			refs[alias] = append(refs[alias], ref)
+		}
+	}
+	return refs
+}
+
+// QuoteIdent quotes an identifier for DuckDB.
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// scannerAttach returns the DuckDB extension and ATTACH target for engines
+// DuckDB can read directly. An empty extension means native DuckDB.
+func scannerAttach(dbType, connString string) (ext, target string, ok bool) {
+	switch dbType {
+	case "postgres", "postgresql":
+		return "postgres", connString, true
+	case "mysql", "mariadb":
+		dsn, err := mysqlDSN(connString)
+		if err != nil {
+			return "", "", false
+		}
+		return "mysql", dsn, true
+	case "sqlite", "sqlite3":
+		return "sqlite", sqlitePath(connString), true
+	case "duckdb":
+		return "", connString, true
+	}
+	return "", "", false
+}
+
+// mysqlDSN converts a go-sql-driver DSN (user:pass@tcp(host:port)/db) to
+// the key=value form of DuckDB's mysql extension.
+func mysqlDSN(connString string) (string, error) {
+	cfg, err := mysql.ParseDSN(connString)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{}
+	add := func(key, value string) {
+		if value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%s", key, value))
+		}
+	}
+	host, port := cfg.Addr, ""
+	if i := strings.LastIndex(cfg.Addr, ":"); i >= 0 {
+		host, port = cfg.Addr[:i], cfg.Addr[i+1:]
+	}
+	if cfg.Net == "unix" {
+		add("socket", cfg.Addr)
+	} else {
+		add("host", host)
+		add("port", port)
+	}
+	add("user", cfg.User)
+	add("password", cfg.Passwd)
+	add("database", cfg.DBName)
+	return strings.Join(parts, " "), nil
+}
+
+// sqlitePath strips the file: prefix and URI parameters the Go driver
+// accepts but ATTACH does not.
+func sqlitePath(connString string) string {
+	path := strings.TrimPrefix(connString, "file:")
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// columnType is the part of *sql.ColumnType duckType reads
+type columnType interface {
+	DatabaseTypeName() string
+	DecimalSize() (precision, scale int64, ok bool)
+}
+
+// duckType maps a source column type to a DuckDB type. Anything not
+// recognized is kept as text rather than guessed.
+func duckType(col columnType) string {
+	t := strings.ToLower(col.DatabaseTypeName())
+	switch {
+	case t == "":
+		return "VARCHAR"
+	case strings.Contains(t, "bool") || t == "bit":
+		return "BOOLEAN"
+	case strings.Contains(t, "interval"):
+		return "VARCHAR"
+	case strings.Contains(t, "int") || strings.Contains(t, "serial"):
+		return "BIGINT"
+	case strings.Contains(t, "numeric") || strings.Contains(t, "decimal") ||
+		strings.Contains(t, "money") || strings.Contains(t, "number"):
+		// Exact values must stay exact: DOUBLE would round them, so
+		// without a known precision they are kept as text
+		precision, scale, ok := col.DecimalSize()
+		if !ok || precision <= 0 || precision > 38 || scale < 0 || scale > precision {
+			return "VARCHAR"
+		}
+		return fmt.Sprintf("DECIMAL(38, %d)", scale)
+	case strings.Contains(t, "float") || strings.Contains(t, "double") ||
+		strings.Contains(t, "real"):
+		return "DOUBLE"
+	case strings.Contains(t, "timestamp") || strings.Contains(t, "datetime"):
+		return "TIMESTAMP"
+	case t == "date":
+		return "DATE"
+	case strings.Contains(t, "time"):
+		return "TIME"
+	case strings.Contains(t, "blob") || strings.Contains(t, "binary") ||
+		t == "bytea" || t == "image":
+		return "BLOB"
+	}
+	return "VARCHAR"
+}
+
+func sortedAliases(refs map[string][]TableRef) []string {
+	aliases := make([]string, 0, len(refs))
+	for a := range refs {
+		aliases = append(aliases, a)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Session is an in-memory DuckDB database with connections attached under
+// their configured names.
+type Session struct {
+	duck        db.DatabaseConnection
+	materialize bool
+	attached    map[string]*Attachment
+}
+
+// NewSession opens an in-memory DuckDB database. With materialize set,
+// scanner extensions are skipped and every table is copied.
+func NewSession(materialize bool) (*Session, error) {
+	duck, err := db.NewDuckDBConnection("federate", "")
+	if err != nil {
+		return nil, err
+	}
+	if err := duck.Open(); err != nil {
+		return nil, err
+	}
+	// ATTACH and LOAD are per database, but keep everything on one
+	// connection so session state is never split across a pool
+	duck.GetDB().SetMaxOpenConns(1)
+
+	return &Session{
+		duck:        duck,
+		materialize: materialize,
+		attached:    map[string]*Attachment{},
+	}, nil
+}
+
+// Connection is the DuckDB connection the federated query runs on.
+func (s *Session) Connection() db.DatabaseConnection {
+	return s.duck
+}
+
+func (s *Session) Close() error {
+	return s.duck.Close()
+}
+
+// Attach makes every connection referenced by sql available under its name,
+// skipping what is already attached. conns maps connection names to their
+// (unopened) connections. It returns the attachments that changed, in name
+// order, so callers can report them.
+func (s *Session) Attach(sql string, conns map[string]db.DatabaseConnection) ([]*Attachment, error) {
+	names := make([]string, 0, len(conns))
+	for name := range conns {
+		names = append(names, name)
+	}
+	refs := References(sql, names)
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("the query references no configured connection; qualify tables as <connection>.<table>")
+	}
+
+	var changed []*Attachment
+	for _, alias := range sortedAliases(refs) {
+		conn := conns[alias]
+		att, ok := s.attached[alias]
+		if !ok {
+			att = &Attachment{Alias: alias, DbType: conn.GetDbType()}
+			if !s.materialize {
+				if ext, target, ok := scannerAttach(conn.GetDbType(), conn.GetConnString()); ok {
+					if err := s.attachScanner(alias, ext, target); err != nil {
+						att.Fallback = err.Error()
+					} else {
+						att.Mode = ModeScanner
+					}
+				}
+			}
+			if att.Mode == "" {
+				if err := s.exec(fmt.Sprintf("ATTACH ':memory:' AS %s", QuoteIdent(alias))); err != nil {
+					return nil, fmt.Errorf("could not attach %s: %w", alias, err)
+				}
+				att.Mode = ModeMaterialized
+			}
+			s.attached[alias] = att
+		}
+
+		if att.Mode == ModeScanner {
+			if !ok {
+				att.Tables = refs[alias]
+				changed = append(changed, att)
+			}
+			continue
+		}
+
+		var missing []TableRef
+		for _, ref := range refs[alias] {
+			if !containsRef(att.Tables, ref) {
+				missing = append(missing, ref)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if err := s.materializeTables(att, conn, missing); err != nil {
+			return nil, err
+		}
+		changed = append(changed, att)
+	}
+	return changed, nil
+}
+
+func containsRef(refs []TableRef, ref TableRef) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Session) exec(sql string) error {
+	return s.duck.Exec(sql)
+}
+
+// attachScanner loads ext (installing it when the build allows downloads)
+// and attaches target read-only.
+func (s *Session) attachScanner(alias, ext, target string) error {
+	options := "READ_ONLY"
+	if ext != "" {
+		if err := s.exec("LOAD " + ext); err != nil {
+			if err := s.exec("INSTALL " + ext); err != nil {
+				return fmt.Errorf("%s extension unavailable: %w", ext, err)
+			}
+			if err := s.exec("LOAD " + ext); err != nil {
+				return fmt.Errorf("%s extension unavailable: %w", ext, err)
+			}
+		}
+		options = "TYPE " + ext + ", READ_ONLY"
+	}
+	return s.exec(fmt.Sprintf("ATTACH %s AS %s (%s)", quoteLiteral(target), QuoteIdent(alias), options))
+}
+
+// materializeTables copies tables from conn into the in-memory database
+// attached as att.Alias, reading them with the connection's own driver.
+func (s *Session) materializeTables(att *Attachment, conn db.DatabaseConnection, tables []TableRef) error {
+	if err := conn.Open(); err != nil {
+		return fmt.Errorf("could not open connection to %s/%s: %w", conn.GetDbType(), conn.GetName(), err)
+	}
+	defer conn.Close()
+
+	for _, ref := range tables {
+		n, err := s.copyTable(att.Alias, conn, ref)
+		if err != nil {
+			return fmt.Errorf("could not copy %s.%s: %w", att.Alias, ref, err)
+		}
+		att.Tables = append(att.Tables, ref)
+		att.Rows += n
+	}
+	return nil
+}
+
+func (s *Session) copyTable(alias string, conn db.DatabaseConnection, ref TableRef) (int64, error) {
+	rows, err := conn.ExecQuery("SELECT * FROM " + sourceName(conn.GetDbType(), ref))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+
+	schema := "main"
+	if ref.Schema != "" {
+		schema = ref.Schema
+		if err := s.exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s.%s", QuoteIdent(alias), QuoteIdent(schema))); err != nil {
+			return 0, err
+		}
+	}
+	target := QuoteIdent(alias) + "." + QuoteIdent(schema) + "." + QuoteIdent(ref.Table)
+
+	defs := make([]string, len(types))
+	marks := make([]string, len(types))
+	for i, t := range types {
+		defs[i] = QuoteIdent(t.Name()) + " " + duckType(t)
+		marks[i] = "?"
+	}
+	if err := s.exec(fmt.Sprintf("CREATE TABLE %s (%s)", target, strings.Join(defs, ", "))); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.duck.GetDB().Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", target, strings.Join(marks, ", ")))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	values := make([]any, len(types))
+	ptrs := make([]any, len(types))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return 0, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+var simpleIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// sourceName renders ref for the source engine, quoting only names that
+// need it.
+func sourceName(dbType string, ref TableRef) string {
+	quote := func(name string) string {
+		if simpleIdent.MatchString(name) {
+			return name
+		}
+		if dbType == "mysql" || dbType == "mariadb" {
+			return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+		}
+		return QuoteIdent(name)
+	}
+	if ref.Schema != "" {
+		return quote(ref.Schema) + "." + quote(ref.Table)
+	}
+	return quote(ref.Table)
+}
@@ -0,0 +1,134 @@
+package federate
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestReferences(t *testing.T) {
+	sql := `SELECT o.id, c.name, 'pg.fake' AS s
+FROM pg.orders o
+JOIN "my sql".public."Customers" c ON c.id = o.customer_id -- mysql.ignored
+JOIN pg.orders o2 ON o2.id = o.id
+/* pg.comment */`
+
+	got := References(sql, []string{"pg", "my sql", "mysql"})
+	want := map[string][]TableRef{
+		"pg":     {{Table: "orders"}},
+		"my sql": {{Schema: "public", Table: "Customers"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScannerAttach(t *testing.T) {
+	ext, target, ok := scannerAttach("mysql", "root:secret@tcp(db:3307)/shop?parseTime=true")
+	if !ok || ext != "mysql" || target != "host=db port=3307 user=root password=secret database=shop" {
+		t.Errorf("mysql attach = %q %q %v", ext, target, ok)
+	}
+	if _, target, _ := scannerAttach("sqlite", "file:/tmp/a.db?_fk=1"); target != "/tmp/a.db" {
+		t.Errorf("sqlite target = %q", target)
+	}
+	if _, _, ok := scannerAttach("oracle", "oracle://x"); ok {
+		t.Error("oracle has no scanner and should be materialized")
+	}
+}
+
+func TestSession_Materialize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "src.db")
+	src, err := db.NewSQLiteConnection("src", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Open(); err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		"CREATE TABLE orders (id INTEGER, amount REAL, status TEXT)",
+		"INSERT INTO orders VALUES (1, 9.5, 'paid'), (2, 20, 'failed'), (3, NULL, 'paid')",
+	} {
+		if err := src.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src.Close()
+
+	session, err := NewSession(true)
+	if err != nil {
+		t.Skipf("duckdb unavailable: %v", err)
+	}
+	defer session.Close()
+
+	conns := map[string]db.DatabaseConnection{"src": src}
+	query := "SELECT status, sum(amount) FROM src.orders GROUP BY status ORDER BY status"
+	attached, err := session.Attach(query, conns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attached) != 1 || attached[0].Mode != ModeMaterialized || attached[0].Rows != 3 {
+		t.Fatalf("attached = %+v", attached)
+	}
+
+	rows, err := session.Connection().ExecQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var status string
+		var total float64
+		if err := rows.Scan(&status, &total); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, status)
+		if status == "failed" && total != 20 {
+			t.Errorf("failed total = %v, want 20", total)
+		}
+	}
+	if !reflect.DeepEqual(got, []string{"failed", "paid"}) {
+		t.Errorf("groups = %v", got)
+	}
+
+	// The same tables are not copied twice
+	if again, err := session.Attach(query, conns); err != nil || len(again) != 0 {
+		t.Errorf("second attach = %+v, %v", again, err)
+	}
+}
+
+type fakeColumn struct {
+	name             string
+	precision, scale int64
+	sized            bool
+}
+
+func (c fakeColumn) DatabaseTypeName() string { return c.name }
+
+func (c fakeColumn) DecimalSize() (int64, int64, bool) {
+	return c.precision, c.scale, c.sized
+}
+
+func TestDuckType(t *testing.T) {
+	for _, tc := range []struct {
+		col  fakeColumn
+		want string
+	}{
+		{fakeColumn{name: "NUMERIC", precision: 12, scale: 2, sized: true}, "DECIMAL(38, 2)"},
+		{fakeColumn{name: "DECIMAL", precision: 38, scale: 0, sized: true}, "DECIMAL(38, 0)"},
+		{fakeColumn{name: "NUMERIC"}, "VARCHAR"},
+		{fakeColumn{name: "NUMBER", precision: 60, scale: 4, sized: true}, "VARCHAR"},
+		{fakeColumn{name: "MONEY"}, "VARCHAR"},
+		{fakeColumn{name: "FLOAT8"}, "DOUBLE"},
+		{fakeColumn{name: "INT4"}, "BIGINT"},
+		{fakeColumn{name: "TIMESTAMPTZ"}, "TIMESTAMP"},
+	} {
+		if got := duckType(tc.col); got != tc.want {
+			t.Errorf("duckType(%+v) = %s, want %s", tc.col, got, tc.want)
+		}
+	}
+}
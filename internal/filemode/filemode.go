@@ -0,0 +1,331 @@
+// Package filemode queries local CSV, Parquet and JSON files in an
+// ephemeral in-memory database, without a configured connection.
+package filemode
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// Scratch is the name of the pseudo-connection file-mode queries are saved to
+const Scratch = "scratch"
+
+// MemoryConnString opens an in-memory database for both DuckDB and SQLite
+const MemoryConnString = ":memory:"
+
+// Session is an in-memory DuckDB database, or SQLite when DuckDB is not
+// compiled in. SQLite cannot read files itself, so CSV and TSV files are
+// loaded into tables on demand and only those formats are supported.
+type Session struct {
+	conn   db.DatabaseConnection
+	loaded map[string]string // file path → SQLite table
+}
+
+// Open starts a session, preferring DuckDB.
+func Open() (*Session, error) {
+	if duck, err := db.NewDuckDBConnection(Scratch, MemoryConnString); err == nil {
+		return open(duck)
+	}
+	lite, err := db.NewSQLiteConnection(Scratch, MemoryConnString)
+	if err != nil {
+		return nil, err
+	}
+	return open(lite)
+}
+
+func open(conn db.DatabaseConnection) (*Session, error) {
+	if err := conn.Open(); err != nil {
+		return nil, err
+	}
+	// Every pooled connection would get its own empty in-memory database
+	conn.GetDB().SetMaxOpenConns(1)
+	return &Session{conn: conn, loaded: map[string]string{}}, nil
+}
+
+// Connection is the session's database as a regular connection. Open and
+// Close are no-ops so the session outlives each executor run, and queries
+// go through Prepare first. Code that runs statements on GetDB itself, like
+// scripts, calls its Prepare method.
+func (s *Session) Connection() db.DatabaseConnection {
+	return &sessionConn{DatabaseConnection: s.conn, session: s}
+}
+
+type sessionConn struct {
+	db.DatabaseConnection
+	session *Session
+}
+
+func (c *sessionConn) Open() error  { return nil }
+func (c *sessionConn) Close() error { return nil }
+
+func (c *sessionConn) Prepare(query string) (string, error) {
+	return c.session.Prepare(query)
+}
+
+func (c *sessionConn) ExecQuery(query string, args ...any) (*sql.Rows, error) {
+	query, err := c.session.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.DatabaseConnection.ExecQuery(query, args...)
+}
+
+func (c *sessionConn) Exec(query string, args ...any) error {
+	query, err := c.session.Prepare(query)
+	if err != nil {
+		return err
+	}
+	return c.DatabaseConnection.Exec(query, args...)
+}
+
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
+
+// DbType is the engine backing the session, "duckdb" or "sqlite".
+func (s *Session) DbType() string {
+	return s.conn.GetDbType()
+}
+
+// Format is the file format recognized from a path's extension, ignoring a
+// trailing .gz: csv, tsv, parquet or json. It is empty when unrecognized.
+func Format(path string) string {
+	ext := strings.ToLower(filepath.Ext(strings.TrimSuffix(strings.ToLower(path), ".gz")))
+	switch ext {
+	case ".csv", ".txt":
+		return "csv"
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".parquet", ".pq":
+		return "parquet"
+	case ".json", ".jsonl", ".ndjson":
+		return "json"
+	}
+	return ""
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// FileSQL returns a SELECT over the whole file, with column types inferred
+// by the engine.
+func (s *Session) FileSQL(path string) (string, error) {
+	format := Format(path)
+	if format == "" {
+		return "", fmt.Errorf("unsupported file type %q: use .csv, .tsv, .parquet, .json or .jsonl", filepath.Ext(path))
+	}
+
+	// SQLite loads the file when Prepare sees the reference
+	if s.DbType() != "duckdb" {
+		return "SELECT * FROM " + quoteLiteral(path), nil
+	}
+
+	switch format {
+	case "parquet":
+		return fmt.Sprintf("SELECT * FROM read_parquet(%s)", quoteLiteral(path)), nil
+	case "json":
+		return fmt.Sprintf("SELECT * FROM read_json_auto(%s)", quoteLiteral(path)), nil
+	case "tsv":
+		return fmt.Sprintf("SELECT * FROM read_csv_auto(%s, delim = '\\t')", quoteLiteral(path)), nil
+	}
+	return fmt.Sprintf("SELECT * FROM read_csv_auto(%s)", quoteLiteral(path)), nil
+}
+
+// fileRef matches a quoted file path, optionally wrapped in one of DuckDB's
+// read_csv functions, as written in FROM 'events.csv'
+var fileRef = regexp.MustCompile(`(?i)(?:read_csv(?:_auto)?\s*\(\s*)?'((?:[^']|'')+\.(?:csv|txt|tsv|tab|parquet|pq|json|jsonl|ndjson)(?:\.gz)?)'(?:\s*\))?`)
+
+// Prepare makes the files referenced in sql queryable. DuckDB reads them
+// directly, so sql is returned unchanged; SQLite gets each file loaded into
+// a table and the reference replaced with the table name.
+func (s *Session) Prepare(sql string) (string, error) {
+	if s.DbType() == "duckdb" {
+		return sql, nil
+	}
+
+	var loadErr error
+	rewritten := fileRef.ReplaceAllStringFunc(sql, func(match string) string {
+		path := strings.ReplaceAll(fileRef.FindStringSubmatch(match)[1], "''", "'")
+		table, err := s.load(path)
+		if err != nil && loadErr == nil {
+			loadErr = err
+		}
+		return quoteIdent(table)
+	})
+	return rewritten, loadErr
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+var unsafeTableChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// sampleRows is how many rows column types are inferred from. SQLite keeps
+// a later value that does not fit the type as it is, so a wrong guess only
+// costs sorting and comparing as text.
+const sampleRows = 1000
+
+// load copies a CSV or TSV file into a SQLite table named after the file,
+// streaming the rows after the sample the column types come from.
+func (s *Session) load(path string) (string, error) {
+	if table, ok := s.loaded[path]; ok {
+		return table, nil
+	}
+
+	format := Format(path)
+	if format != "csv" && format != "tsv" {
+		return "", fmt.Errorf("reading %s files needs DuckDB: build pam with CGO_ENABLED=1", format)
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		return "", fmt.Errorf("compressed files need DuckDB: build pam with CGO_ENABLED=1")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if format == "tsv" {
+		r.Comma = '\t'
+	}
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return "", fmt.Errorf("could not read header of %s: %w", path, err)
+	}
+	var sample [][]string
+	for len(sample) < sampleRows {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not read %s: %w", path, err)
+		}
+		sample = append(sample, record)
+	}
+
+	table := s.tableName(path)
+	types := inferTypes(len(header), sample)
+	defs := make([]string, len(header))
+	marks := make([]string, len(header))
+	for i, name := range header {
+		defs[i] = quoteIdent(name) + " " + types[i]
+		marks[i] = "?"
+	}
+	if err := s.conn.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(table), strings.Join(defs, ", "))); err != nil {
+		return "", err
+	}
+
+	tx, err := s.conn.GetDB().Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", quoteIdent(table), strings.Join(marks, ", ")))
+	if err != nil {
+		return "", err
+	}
+	defer stmt.Close()
+
+	values := make([]any, len(header))
+	insert := func(record []string) error {
+		for i := range values {
+			values[i] = nil
+			if i < len(record) && record[i] != "" {
+				values[i] = record[i]
+			}
+		}
+		_, err := stmt.Exec(values...)
+		return err
+	}
+	for _, record := range sample {
+		if err := insert(record); err != nil {
+			return "", err
+		}
+	}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not read %s: %w", path, err)
+		}
+		if err := insert(record); err != nil {
+			return "", err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	s.loaded[path] = table
+	return table, nil
+}
+
+// tableName derives a table name from the file name, unique in the session.
+func (s *Session) tableName(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	for Format(base) != "" || strings.HasSuffix(base, ".gz") {
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	name := strings.Trim(unsafeTableChars.ReplaceAllString(base, "_"), "_")
+	if name == "" {
+		name = "file"
+	}
+
+	taken := map[string]bool{}
+	for _, t := range s.loaded {
+		taken[t] = true
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	return unique
+}
+
+// inferTypes picks INTEGER or REAL for columns whose non-empty values in
+// the sampled records all parse as such, and TEXT otherwise.
+func inferTypes(columns int, records [][]string) []string {
+	types := make([]string, columns)
+	for i := range types {
+		isInt, isReal, seen := true, true, false
+		for _, record := range records {
+			if i >= len(record) || record[i] == "" {
+				continue
+			}
+			seen = true
+			if _, err := strconv.ParseInt(record[i], 10, 64); err != nil {
+				isInt = false
+			}
+			if _, err := strconv.ParseFloat(record[i], 64); err != nil {
+				isReal = false
+			}
+		}
+		switch {
+		case seen && isInt:
+			types[i] = "INTEGER"
+		case seen && isReal:
+			types[i] = "REAL"
+		default:
+			types[i] = "TEXT"
+		}
+	}
+	return types
+}
@@ -0,0 +1,134 @@
+package filemode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"events.parquet":   "parquet",
+		"dump.CSV":         "csv",
+		"logs.ndjson":      "json",
+		"data.tsv.gz":      "tsv",
+		"archive.zip":      "",
+		"/tmp/no-ext-file": "",
+	}
+	for path, want := range tests {
+		if got := Format(path); got != want {
+			t.Errorf("Format(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestSession_SQLiteLoadsCSV(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "My Orders.csv")
+	content := "id,amount,status\n1,9.5,paid\n2,,\"late, very\"\n3,20,paid\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lite, err := db.NewSQLiteConnection(Scratch, MemoryConnString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := open(lite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	query, err := session.FileSQL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := session.Connection()
+	// The executors open and close connections around every run
+	conn.Close()
+
+	rows, err := conn.ExecQuery(query + " WHERE amount IS NOT NULL ORDER BY amount DESC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns, columnTypes, data, err := db.FormatTableDataWithTypes(rows)
+	rows.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(columns, ",") != "id,amount,status" {
+		t.Errorf("columns = %v", columns)
+	}
+	if strings.Join(columnTypes, ",") != "INTEGER,REAL,TEXT" {
+		t.Errorf("inferred types = %v", columnTypes)
+	}
+	if len(data) != 2 || data[0][1] != "20" || data[1][2] != "paid" {
+		t.Errorf("data = %v", data)
+	}
+
+	// Referencing the file again reuses the loaded table
+	rewritten, err := session.Prepare("SELECT count(*) FROM read_csv_auto('" + path + "')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewritten != `SELECT count(*) FROM "my_orders"` {
+		t.Errorf("rewritten = %q", rewritten)
+	}
+
+	if _, err := session.Prepare("SELECT * FROM 'events.parquet'"); err == nil {
+		t.Error("parquet should need DuckDB")
+	}
+}
+
+func TestSession_SQLiteStreamsPastTheSample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.csv")
+	var b strings.Builder
+	b.WriteString("n,label\n")
+	for i := range sampleRows + 500 {
+		fmt.Fprintf(&b, "%d,row %d\n", i, i)
+	}
+	// Past the sample, so the column stays INTEGER and keeps the value
+	b.WriteString("late,row late\n")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lite, err := db.NewSQLiteConnection(Scratch, MemoryConnString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := open(lite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	rows, err := session.Connection().ExecQuery("SELECT count(*), max(typeof(n)) FROM '" + path + "'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, columnTypes, data, err := db.FormatTableDataWithTypes(rows)
+	rows.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0][0] != fmt.Sprint(sampleRows+501) || data[0][1] != "text" {
+		t.Errorf("data = %v, types = %v", data, columnTypes)
+	}
+
+	rows, err = lite.ExecQuery("SELECT type FROM pragma_table_info('big') WHERE name = 'n'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, data, err = db.FormatTableDataWithTypes(rows)
+	rows.Close()
+	if err != nil || data[0][0] != "INTEGER" {
+		t.Errorf("n type = %v, %v", data, err)
+	}
+}
@@ -0,0 +1,342 @@
+// Package mask replaces sensitive values with stand-ins before data leaves
+// pam. Masking is deterministic: with the same key, a value always masks to
+// the same output, so joins and repeated values survive in a masked extract.
+package mask
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Masking strategies
+const (
+	Redact     = "redact"      // a fixed placeholder
+	Hash       = "hash"        // a keyed hash of the value
+	FakeEmail  = "fake_email"  // a made-up address at example.com
+	FakeName   = "fake_name"   // a made-up first and last name
+	FakePhone  = "fake_phone"  // other digits in the same layout
+	DateShift  = "date_shift"  // the date moved by up to Days days
+	KeepFormat = "keep_format" // other letters and digits, same punctuation
+)
+
+// Strategies lists every strategy, in the order help shows them
+var Strategies = []string{
+	Redact, Hash, FakeEmail, FakeName, FakePhone, DateShift, KeepFormat,
+}
+
+// RedactedValue is what the redact strategy writes
+const RedactedValue = "[redacted]"
+
+const defaultShiftDays = 365
+
+// Rule masks the columns it matches. Table and Column are names or glob
+// patterns such as "*email*", compared case-insensitively; an empty Table
+// matches every table, including results whose table isn't known.
+type Rule struct {
+	Table    string `yaml:"table,omitempty"`
+	Column   string `yaml:"column"`
+	Strategy string `yaml:"strategy"`
+	Days     int    `yaml:"days,omitempty"` // date_shift range, default 365
+}
+
+// Config is the masking section of the config file
+type Config struct {
+	// Key seeds the hashes every strategy derives its output from; the same
+	// key gives the same masked values across runs.
+	Key string `yaml:"key,omitempty"`
+	// RedactSensitive redacts password, secret and token columns that no
+	// rule matches.
+	RedactSensitive bool   `yaml:"redact_sensitive,omitempty"`
+	Rules           []Rule `yaml:"rules,omitempty"`
+}
+
+// Func masks one value
+type Func func(string) string
+
+// Masker applies a Config's rules. A nil Masker masks nothing.
+type Masker struct {
+	key       []byte
+	rules     []Rule
+	sensitive bool
+}
+
+// Active is the masker built from the config file, used wherever data is
+// copied or exported
+var Active *Masker
+
+// Init sets Active from the config's masking section
+func Init(cfg *Config) error {
+	masker, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	Active = masker
+	return nil
+}
+
+// New validates cfg and builds its masker; a nil or empty cfg gives a nil
+// masker
+func New(cfg *Config) (*Masker, error) {
+	if cfg == nil || (len(cfg.Rules) == 0 && !cfg.RedactSensitive) {
+		return nil, nil
+	}
+	for i, rule := range cfg.Rules {
+		if rule.Column == "" {
+			return nil, fmt.Errorf("masking rule %d has no column", i+1)
+		}
+		if !validStrategy(rule.Strategy) {
+			return nil, fmt.Errorf(
+				"masking rule %d: unknown strategy %q (use %s)",
+				i+1,
+				rule.Strategy,
+				strings.Join(Strategies, ", "),
+			)
+		}
+		for _, pattern := range []string{rule.Table, rule.Column} {
+			if _, err := path.Match(strings.ToLower(pattern), ""); err != nil {
+				return nil, fmt.Errorf("masking rule %d: bad pattern %q", i+1, pattern)
+			}
+		}
+	}
+	key := cfg.Key
+	if key == "" {
+		key = "pam"
+	}
+	return &Masker{
+		key:       []byte(key),
+		rules:     cfg.Rules,
+		sensitive: cfg.RedactSensitive,
+	}, nil
+}
+
+func validStrategy(strategy string) bool {
+	for _, s := range Strategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether m masks anything
+func (m *Masker) Enabled() bool {
+	return m != nil
+}
+
+// Column returns the mask for a column of table, or nil when the column is
+// left alone. table may be empty when a result's table isn't known.
+func (m *Masker) Column(table, column string) Func {
+	if m == nil {
+		return nil
+	}
+	table, column = strings.ToLower(table), strings.ToLower(column)
+	// A qualified table matches rules written with or without its schema
+	bare := table[strings.LastIndex(table, ".")+1:]
+	for _, rule := range m.rules {
+		if rule.Table != "" {
+			pattern := strings.ToLower(rule.Table)
+			if !matches(pattern, table) && !matches(pattern, bare) {
+				continue
+			}
+		}
+		if matches(strings.ToLower(rule.Column), column) {
+			return m.strategy(rule)
+		}
+	}
+	if m.sensitive && IsSensitiveColumn(column) {
+		return func(value string) string { return RedactedValue }
+	}
+	return nil
+}
+
+func matches(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// Rows returns rows with table's masked columns replaced, leaving rows
+// itself unchanged. NULL and empty values stay as they are.
+func (m *Masker) Rows(table string, columns []string, rows [][]string) [][]string {
+	funcs := make([]Func, len(columns))
+	masked := false
+	for i, column := range columns {
+		funcs[i] = m.Column(table, column)
+		masked = masked || funcs[i] != nil
+	}
+	if !masked {
+		return rows
+	}
+
+	out := make([][]string, len(rows))
+	for r, row := range rows {
+		out[r] = make([]string, len(row))
+		for i, value := range row {
+			if i < len(funcs) && funcs[i] != nil {
+				value = Apply(funcs[i], value)
+			}
+			out[r][i] = value
+		}
+	}
+	return out
+}
+
+// Apply runs mask on value, passing NULL and empty values through
+func Apply(mask Func, value string) string {
+	if mask == nil || value == "" || value == "NULL" {
+		return value
+	}
+	return mask(value)
+}
+
+// IsSensitiveColumn reports whether a column name looks like it holds
+// passwords, secrets or tokens
+func IsSensitiveColumn(name string) bool {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if n == "" {
+		return false
+	}
+
+	if strings.Contains(n, "password") ||
+		strings.Contains(n, "passwd") ||
+		strings.Contains(n, "pwd") ||
+		strings.Contains(n, "secret") ||
+		strings.Contains(n, "token") ||
+		strings.Contains(n, "apikey") ||
+		strings.Contains(n, "api_key") {
+		return true
+	}
+
+	return false
+}
+
+func (m *Masker) strategy(rule Rule) Func {
+	switch rule.Strategy {
+	case Redact:
+		return func(string) string { return RedactedValue }
+	case Hash:
+		return func(value string) string { return hex.EncodeToString(m.sum(value)[:16]) }
+	case FakeEmail:
+		return m.fakeEmail
+	case FakeName:
+		return m.fakeName
+	case FakePhone:
+		return func(value string) string { return m.keepFormat(value, true) }
+	case DateShift:
+		days := rule.Days
+		if days <= 0 {
+			days = defaultShiftDays
+		}
+		return func(value string) string { return m.shiftDate(value, days) }
+	case KeepFormat:
+		return func(value string) string { return m.keepFormat(value, false) }
+	}
+	return nil
+}
+
+func (m *Masker) sum(value string) []byte {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// stream yields numbers derived from value's hash, as many as asked for
+type stream struct {
+	m     *Masker
+	value string
+	block []byte
+	next  int
+	round int
+}
+
+func (m *Masker) stream(value string) *stream {
+	return &stream{m: m, value: value}
+}
+
+func (s *stream) intn(n int) int {
+	if s.next+4 > len(s.block) {
+		s.block = s.m.sum(fmt.Sprintf("%d\x00%s", s.round, s.value))
+		s.round++
+		s.next = 0
+	}
+	v := binary.BigEndian.Uint32(s.block[s.next:])
+	s.next += 4
+	return int(v % uint32(n))
+}
+
+var (
+	firstNames = []string{
+		"Alex", "Ana", "Ben", "Carla", "Daniel", "Elena", "Felix", "Grace",
+		"Hugo", "Iris", "Jonas", "Julia", "Leo", "Lucia", "Marco", "Maya",
+		"Nina", "Omar", "Paula", "Rafael", "Sara", "Theo", "Vera", "Yuri",
+	}
+	lastNames = []string{
+		"Almeida", "Becker", "Costa", "Dubois", "Evans", "Fischer", "Garcia",
+		"Hansen", "Ito", "Jensen", "Klein", "Lopez", "Moreau", "Novak",
+		"Olsen", "Patel", "Rossi", "Santos", "Tanaka", "Weber", "Young",
+	}
+)
+
+func (m *Masker) fakeName(value string) string {
+	s := m.stream(value)
+	return firstNames[s.intn(len(firstNames))] + " " + lastNames[s.intn(len(lastNames))]
+}
+
+func (m *Masker) fakeEmail(value string) string {
+	s := m.stream(value)
+	first := strings.ToLower(firstNames[s.intn(len(firstNames))])
+	last := strings.ToLower(lastNames[s.intn(len(lastNames))])
+	return fmt.Sprintf("%s.%s%d@example.com", first, last, s.intn(10000))
+}
+
+// keepFormat replaces each digit with another digit and, unless
+// digitsOnly, each letter with another letter of the same case
+func (m *Masker) keepFormat(value string, digitsOnly bool) string {
+	s := m.stream(value)
+	var b strings.Builder
+	for _, ch := range value {
+		switch {
+		case unicode.IsDigit(ch):
+			b.WriteByte(byte('0' + s.intn(10)))
+		case digitsOnly:
+			b.WriteRune(ch)
+		case unicode.IsUpper(ch):
+			b.WriteByte(byte('A' + s.intn(26)))
+		case unicode.IsLetter(ch):
+			b.WriteByte(byte('a' + s.intn(26)))
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
+
+// dateLayouts are the forms shiftDate recognizes and writes back
+var dateLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST", // how drivers' time.Time prints
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// shiftDate moves a date by up to days days either way, keeping its
+// layout. Values that aren't dates are hashed instead.
+func (m *Masker) shiftDate(value string, days int) string {
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		shift := m.stream(value).intn(2*days+1) - days
+		return t.AddDate(0, 0, shift).Format(layout)
+	}
+	return hex.EncodeToString(m.sum(value)[:16])
+}
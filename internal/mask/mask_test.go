@@ -0,0 +1,154 @@
+package mask
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newMasker(t *testing.T, cfg *Config) *Masker {
+	t.Helper()
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestNew_RejectsUnknownStrategy(t *testing.T) {
+	_, err := New(&Config{Rules: []Rule{{Column: "email", Strategy: "scramble"}}})
+	if err == nil || !strings.Contains(err.Error(), "scramble") {
+		t.Fatalf("err = %v, want unknown strategy", err)
+	}
+}
+
+func TestNew_EmptyConfigMasksNothing(t *testing.T) {
+	m := newMasker(t, &Config{Key: "k"})
+	if m.Enabled() || m.Column("users", "password") != nil {
+		t.Fatal("an empty config should give a nil masker")
+	}
+	rows := [][]string{{"a"}}
+	if got := m.Rows("users", []string{"email"}, rows); &got[0] != &rows[0] {
+		t.Error("a nil masker should return rows unchanged")
+	}
+}
+
+func TestColumn_Matching(t *testing.T) {
+	m := newMasker(t, &Config{Rules: []Rule{
+		{Table: "users", Column: "email", Strategy: Redact},
+		{Column: "*phone*", Strategy: Redact},
+	}})
+
+	tests := []struct {
+		table, column string
+		masked        bool
+	}{
+		{"users", "email", true},
+		{"USERS", "Email", true},
+		{"public.users", "email", true},
+		{"orders", "email", false},
+		{"", "email", false},
+		{"orders", "mobile_phone", true},
+		{"", "phone", true},
+		{"users", "name", false},
+	}
+	for _, tt := range tests {
+		if got := m.Column(tt.table, tt.column) != nil; got != tt.masked {
+			t.Errorf("Column(%q, %q) masked = %v, want %v", tt.table, tt.column, got, tt.masked)
+		}
+	}
+}
+
+func TestColumn_RedactSensitive(t *testing.T) {
+	m := newMasker(t, &Config{RedactSensitive: true})
+	if f := m.Column("users", "password_hash"); f == nil || f("x") != RedactedValue {
+		t.Error("password_hash should be redacted")
+	}
+	if m.Column("users", "email") != nil {
+		t.Error("email is not sensitive")
+	}
+}
+
+func TestStrategies_AreDeterministic(t *testing.T) {
+	cfg := &Config{Key: "secret"}
+	for _, strategy := range Strategies {
+		cfg.Rules = append(cfg.Rules, Rule{Column: strategy, Strategy: strategy})
+	}
+	a, b := newMasker(t, cfg), newMasker(t, cfg)
+	other := newMasker(t, &Config{Key: "another", Rules: cfg.Rules})
+
+	for _, strategy := range Strategies {
+		value := "Jane Doe 2024-02-29"
+		first := a.Column("", strategy)(value)
+		if second := b.Column("", strategy)(value); first != second {
+			t.Errorf("%s: %q then %q for the same value", strategy, first, second)
+		}
+		if first == value {
+			t.Errorf("%s left %q unchanged", strategy, value)
+		}
+		if strategy != Redact && other.Column("", strategy)(value) == first {
+			t.Errorf("%s: a different key gave the same output", strategy)
+		}
+	}
+}
+
+func TestStrategies_Formats(t *testing.T) {
+	m := newMasker(t, &Config{Rules: []Rule{
+		{Column: "email", Strategy: FakeEmail},
+		{Column: "name", Strategy: FakeName},
+		{Column: "phone", Strategy: FakePhone},
+		{Column: "born", Strategy: DateShift, Days: 10},
+		{Column: "code", Strategy: KeepFormat},
+		{Column: "hash", Strategy: Hash},
+	}})
+
+	checks := []struct {
+		column, value string
+		pattern       string
+	}{
+		{"email", "jane@corp.com", `^[a-z]+\.[a-z]+\d+@example\.com$`},
+		{"name", "Jane Doe", `^[A-Z][a-z]+ [A-Z][a-z]+$`},
+		{"phone", "+1 (555) 010-9999", `^\+\d \(\d{3}\) \d{3}-\d{4}$`},
+		{"born", "1990-05-17", `^\d{4}-\d{2}-\d{2}$`},
+		{"code", "AB-12cd", `^[A-Z]{2}-\d{2}[a-z]{2}$`},
+		{"hash", "anything", `^[0-9a-f]{32}$`},
+	}
+	for _, c := range checks {
+		got := m.Column("", c.column)(c.value)
+		if !regexp.MustCompile(c.pattern).MatchString(got) {
+			t.Errorf("%s: %q masked to %q, want %s", c.column, c.value, got, c.pattern)
+		}
+	}
+}
+
+func TestDateShift_StaysInRange(t *testing.T) {
+	m := newMasker(t, &Config{Rules: []Rule{{Column: "d", Strategy: DateShift, Days: 3}}})
+	shift := m.Column("", "d")
+	for _, value := range []string{"2024-01-10", "2024-01-11", "2024-01-12", "2024-01-13"} {
+		got := shift(value)
+		if got < "2024-01-07" || got > "2024-01-16" {
+			t.Errorf("%s shifted to %s, more than 3 days", value, got)
+		}
+	}
+	if got := shift("2024-01-10 08:30:00 +0000 UTC"); !strings.HasSuffix(got, "08:30:00 +0000 UTC") {
+		t.Errorf("timestamp layout lost: %q", got)
+	}
+}
+
+func TestRows_KeepsNullsAndInput(t *testing.T) {
+	m := newMasker(t, &Config{Rules: []Rule{{Column: "email", Strategy: Redact}}})
+	rows := [][]string{{"1", "a@b.c"}, {"2", "NULL"}, {"3", ""}}
+
+	got := m.Rows("users", []string{"id", "email"}, rows)
+	want := [][]string{{"1", RedactedValue}, {"2", "NULL"}, {"3", ""}}
+	for i := range want {
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("row %d col %d = %q, want %q", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+	if rows[0][1] != "a@b.c" {
+		t.Error("Rows modified its input")
+	}
+}
@@ -0,0 +1,213 @@
+// Package migrate applies schema migrations kept as .sql files in a
+// directory. Each file is named <version>_<name>.sql, where the version is
+// a timestamp, and holds an up and a down section:
+//
+//	-- migrate:up
+//	CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL);
+//
+//	-- migrate:down
+//	DROP TABLE users;
+//
+// Applied versions are recorded in a pam_migrations table together with a
+// checksum of their file, so a migration edited after it ran is caught.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// DefaultDir is where migrations are looked for, relative to the project
+// root or the working directory
+const DefaultDir = "migrations"
+
+// VersionLayout is the timestamp new migrations are versioned with
+const VersionLayout = "20060102150405"
+
+// Section is the up or down half of a migration
+type Section struct {
+	SQL string
+	// Line is the line of the file the section's SQL starts on
+	Line int
+	// NoTransaction runs the section outside a transaction, for statements
+	// such as CREATE INDEX CONCURRENTLY that refuse to run in one. It is set
+	// with "-- migrate:up transaction:false".
+	NoTransaction bool
+}
+
+// Migration is one migration file
+type Migration struct {
+	Version  string
+	Name     string
+	Path     string
+	Checksum string
+	Up       Section
+	Down     Section
+}
+
+// ID is the migration's file name without the extension
+func (m Migration) ID() string {
+	return m.Version + "_" + m.Name
+}
+
+var (
+	fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+	markerPattern   = regexp.MustCompile(`(?i)^--\s*migrate:(up|down)\b(.*)$`)
+)
+
+// Load reads the migrations in dir, ordered by version. A missing dir holds
+// no migrations.
+func Load(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	seen := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".sql") {
+			continue
+		}
+		m := fileNamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf(
+				"%s: migration files are named <version>_<name>.sql",
+				entry.Name(),
+			)
+		}
+		if other, ok := seen[m[1]]; ok {
+			return nil, fmt.Errorf("%s and %s share version %s", other, entry.Name(), m[1])
+		}
+		seen[m[1]] = entry.Name()
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		migration, err := Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		migration.Version, migration.Name, migration.Path = m[1], m[2], path
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return versionLess(migrations[i].Version, migrations[j].Version)
+	})
+	return migrations, nil
+}
+
+// versionLess orders versions numerically, so 9_a comes before 10_b
+func versionLess(a, b string) bool {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// Parse reads a migration file's sections. SQL before the first marker is
+// an error; comments there are fine.
+func Parse(content string) (Migration, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	m := Migration{Checksum: Checksum(content)}
+
+	var current *Section
+	var preamble, body []string
+	found := map[string]bool{}
+	flush := func() {
+		if current != nil {
+			current.SQL = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+	for i, line := range strings.Split(content, "\n") {
+		marker := markerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if marker == nil {
+			if current == nil {
+				preamble = append(preamble, line)
+			} else {
+				if len(body) == 0 && strings.TrimSpace(line) == "" {
+					current.Line = i + 2
+					continue
+				}
+				body = append(body, line)
+			}
+			continue
+		}
+
+		direction := strings.ToLower(marker[1])
+		if found[direction] {
+			return m, fmt.Errorf("more than one migrate:%s section", direction)
+		}
+		found[direction] = true
+		flush()
+		current = &m.Up
+		if direction == "down" {
+			current = &m.Down
+		}
+		current.Line = i + 2
+		for _, option := range strings.Fields(marker[2]) {
+			switch strings.ToLower(option) {
+			case "transaction:false":
+				current.NoTransaction = true
+			case "transaction:true":
+			default:
+				return m, fmt.Errorf("unknown migrate:%s option %q", direction, option)
+			}
+		}
+	}
+	flush()
+
+	if !found["up"] {
+		return m, fmt.Errorf("no -- migrate:up section")
+	}
+	if len(db.SplitSQLStatements(strings.Join(preamble, "\n"))) > 0 {
+		return m, fmt.Errorf("SQL before the first -- migrate: marker")
+	}
+	return m, nil
+}
+
+// Checksum is the SHA-256 of a migration file, with line endings normalized
+func Checksum(content string) string {
+	sum := sha256.Sum256([]byte(strings.ReplaceAll(content, "\r\n", "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+var nameCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes an empty migration named name to dir, versioned by now,
+// and returns its path
+func Create(dir, name string, now time.Time) (string, error) {
+	name = strings.Trim(nameCleaner.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", fmt.Errorf("migration name must contain letters or digits")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, now.UTC().Format(VersionLayout)+"_"+name+".sql")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+	content := "-- migrate:up\n\n\n-- migrate:down\n\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,243 @@
+package migrate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse(`-- adds users
+
+-- migrate:up
+CREATE TABLE users (id INTEGER);
+CREATE INDEX users_id ON users (id);
+
+-- migrate:down transaction:false
+DROP TABLE users;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(m.Up.SQL, "CREATE TABLE") || m.Up.Line != 4 || m.Up.NoTransaction {
+		t.Errorf("up = %+v", m.Up)
+	}
+	if m.Down.SQL != "DROP TABLE users;" || m.Down.Line != 8 || !m.Down.NoTransaction {
+		t.Errorf("down = %+v", m.Down)
+	}
+
+	for content, want := range map[string]string{
+		"CREATE TABLE a (id INT);":                     "no -- migrate:up",
+		"DROP TABLE x;\n-- migrate:up\nSELECT 1;":      "before the first",
+		"-- migrate:up\nSELECT 1;\n-- migrate:up\n":    "more than one",
+		"-- migrate:up transaction:maybe\nSELECT 1;\n": "unknown",
+	} {
+		if _, err := Parse(content); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) err = %v, want %q", content, err, want)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"10_b.sql":  "-- migrate:up\nSELECT 1;",
+		"9_a.sql":   "-- migrate:up\nSELECT 1;",
+		"notes.txt": "not a migration",
+	})
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].ID() != "9_a" || migrations[1].ID() != "10_b" {
+		t.Fatalf("migrations = %+v", migrations)
+	}
+
+	writeFiles(t, dir, map[string]string{"9_c.sql": "-- migrate:up\n"})
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "share version 9") {
+		t.Errorf("err = %v, want a duplicate version", err)
+	}
+
+	if migrations, err := Load(filepath.Join(dir, "none")); err != nil || migrations != nil {
+		t.Errorf("missing dir = %v, %v", migrations, err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "migrations")
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	path, err := Create(dir, "Add Users!", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "20260304050607_add_users.sql" {
+		t.Errorf("path = %s", path)
+	}
+	migrations, err := Load(dir)
+	if err != nil || len(migrations) != 1 || migrations[0].Name != "add_users" {
+		t.Fatalf("migrations = %+v, %v", migrations, err)
+	}
+	if _, err := Create(dir, "add_users", now); err == nil {
+		t.Error("creating the same migration twice should fail")
+	}
+}
+
+func openSQLite(t *testing.T) db.DatabaseConnection {
+	t.Helper()
+	conn, err := db.NewSQLiteConnection("migrate", filepath.Join(t.TempDir(), "app.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func states(t *testing.T, r *Runner, migrations []Migration) string {
+	t.Helper()
+	statuses, err := r.Status(migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, s := range statuses {
+		out = append(out, s.Version+":"+s.State)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestRunner_UpDown(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"1_users.sql": `-- migrate:up
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);
+-- migrate:down
+DROP TABLE users;
+`,
+		"2_orders.sql": `-- migrate:up
+CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER);
+INSERT INTO orders VALUES (1, 1);
+-- migrate:down
+DROP TABLE orders;
+`,
+	})
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := openSQLite(t)
+	r := NewRunner(conn)
+
+	if got := states(t, r, migrations); got != "1:pending 2:pending" {
+		t.Errorf("before = %s", got)
+	}
+	if n, err := r.Up(migrations, 1); err != nil || n != 1 {
+		t.Fatalf("Up(1) = %d, %v", n, err)
+	}
+	if n, err := r.Up(migrations, 0); err != nil || n != 1 {
+		t.Fatalf("Up = %d, %v", n, err)
+	}
+	if got := states(t, r, migrations); got != "1:applied 2:applied" {
+		t.Errorf("after up = %s", got)
+	}
+
+	if n, err := r.Down(migrations, 1); err != nil || n != 1 {
+		t.Fatalf("Down = %d, %v", n, err)
+	}
+	if got := states(t, r, migrations); got != "1:applied 2:pending" {
+		t.Errorf("after down = %s", got)
+	}
+	if _, err := conn.ExecQuery("SELECT * FROM orders"); err == nil {
+		t.Error("orders should have been dropped")
+	}
+
+	// An edited migration blocks further runs
+	migrations[0].Checksum = "edited"
+	if got := states(t, r, migrations); got != "1:modified 2:pending" {
+		t.Errorf("after edit = %s", got)
+	}
+	if _, err := r.Up(migrations, 0); err == nil || !strings.Contains(err.Error(), "1_users") {
+		t.Errorf("err = %v, want the edited migration named", err)
+	}
+
+	// A deleted file shows as missing
+	if got := states(t, r, migrations[1:]); got != "1:missing 2:pending" {
+		t.Errorf("without the file = %s", got)
+	}
+}
+
+func TestRunner_FailedMigrationRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"1_broken.sql": `-- migrate:up
+CREATE TABLE things (id INTEGER);
+
+INSERT INTO missing_table VALUES (1);
+`,
+	})
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := openSQLite(t)
+	r := NewRunner(conn)
+
+	_, err = r.Up(migrations, 0)
+	if err == nil || !strings.Contains(err.Error(), "1_broken.sql:4") {
+		t.Fatalf("err = %v, want the failing line", err)
+	}
+	if _, err := conn.ExecQuery("SELECT * FROM things"); err == nil {
+		t.Error("the migration's first statement should have been rolled back")
+	}
+	if got := states(t, r, migrations); got != "1:pending" {
+		t.Errorf("state = %s", got)
+	}
+}
+
+func TestRunner_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"1_users.sql": "-- migrate:up\nCREATE TABLE users (id INTEGER);\n",
+	})
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := openSQLite(t)
+	r := NewRunner(conn)
+	var out bytes.Buffer
+	r.DryRun, r.Out = true, &out
+
+	if _, err := r.Up(migrations, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"CREATE TABLE pam_migrations",
+		"-- 1_users (up, in a transaction)",
+		"CREATE TABLE users (id INTEGER);",
+		"INSERT INTO pam_migrations",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry run output lacks %q:\n%s", want, out.String())
+		}
+	}
+	if tables, _ := conn.GetTables(); len(tables) != 0 {
+		t.Errorf("dry run created %v", tables)
+	}
+}
@@ -0,0 +1,344 @@
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// Table records the applied migrations
+const Table = "pam_migrations"
+
+// States a migration can be in
+const (
+	StatePending  = "pending"  // not applied yet
+	StateApplied  = "applied"  // applied, file unchanged
+	StateModified = "modified" // applied, but the file changed since
+	StateMissing  = "missing"  // applied, but its file is gone
+)
+
+// Status is a migration and whether it has been applied. Migration is nil
+// for a missing file, AppliedAt empty for a pending one.
+type Status struct {
+	Version   string
+	Name      string
+	State     string
+	AppliedAt string
+	Migration *Migration
+}
+
+// Runner applies migrations over a connection
+type Runner struct {
+	conn    db.DatabaseConnection
+	dialect string
+
+	// DryRun prints the SQL to Out instead of running it
+	DryRun bool
+	Out    io.Writer
+	// Done, when set, is called after each migration runs
+	Done func(m Migration, up bool, elapsed time.Duration)
+}
+
+// NewRunner makes a runner for conn
+func NewRunner(conn db.DatabaseConnection) *Runner {
+	dialect, err := db.NormalizeDialect(conn.GetDbType())
+	if err != nil {
+		dialect = conn.GetDbType()
+	}
+	return &Runner{conn: conn, dialect: dialect, Out: io.Discard}
+}
+
+// transactionalDDL reports whether the dialect can roll back schema changes.
+// MySQL and Oracle commit at every DDL statement, Snowflake too, and
+// ClickHouse has no transactions.
+func transactionalDDL(dialect string) bool {
+	switch dialect {
+	case "postgres", "sqlite", "sqlserver", "duckdb", "firebird":
+		return true
+	}
+	return false
+}
+
+// createTableSQL is the DDL for the tracking table in the runner's dialect
+func (r *Runner) createTableSQL() string {
+	text, stamp := "VARCHAR(255)", "TIMESTAMP"
+	switch r.dialect {
+	case "clickhouse":
+		return "CREATE TABLE " + Table + " (version String, name String, checksum String, applied_at DateTime) ENGINE = MergeTree ORDER BY version"
+	case "mysql":
+		stamp = "DATETIME"
+	case "sqlserver":
+		text, stamp = "NVARCHAR(255)", "DATETIME2"
+	case "oracle":
+		text = "VARCHAR2(255)"
+	}
+	return fmt.Sprintf(
+		"CREATE TABLE %s (version %s NOT NULL PRIMARY KEY, name %s NOT NULL, checksum %s NOT NULL, applied_at %s NOT NULL)",
+		Table, text, text, text, stamp,
+	)
+}
+
+func (r *Runner) recordSQL(m Migration) string {
+	now := "CURRENT_TIMESTAMP"
+	if r.dialect == "clickhouse" {
+		now = "now()"
+	}
+	return fmt.Sprintf(
+		"INSERT INTO %s (version, name, checksum, applied_at) VALUES (%s, %s, %s, %s)",
+		Table,
+		db.QuoteStringFor(r.dialect, m.Version),
+		db.QuoteStringFor(r.dialect, m.Name),
+		db.QuoteStringFor(r.dialect, m.Checksum),
+		now,
+	)
+}
+
+func (r *Runner) forgetSQL(version string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE version = %s", Table, db.QuoteStringFor(r.dialect, version))
+}
+
+// exists reports whether the tracking table has been created
+func (r *Runner) exists() (bool, error) {
+	tables, err := r.conn.GetTables()
+	if err != nil {
+		return false, fmt.Errorf("could not list tables: %w", err)
+	}
+	return slices.ContainsFunc(tables, func(t string) bool {
+		return strings.EqualFold(t, Table) || strings.HasSuffix(strings.ToLower(t), "."+Table)
+	}), nil
+}
+
+// ensure creates the tracking table when it doesn't exist. In a dry run
+// the statement is printed instead.
+func (r *Runner) ensure() error {
+	ok, err := r.exists()
+	if err != nil || ok {
+		return err
+	}
+	if r.DryRun {
+		fmt.Fprintf(r.Out, "%s;\n\n", r.createTableSQL())
+		return nil
+	}
+	if err := r.conn.Exec(r.createTableSQL()); err != nil {
+		return fmt.Errorf("could not create %s: %w", Table, err)
+	}
+	return nil
+}
+
+type applied struct {
+	version, name, checksum, at string
+}
+
+// applied reads the tracking table, empty when it doesn't exist yet
+func (r *Runner) applied() ([]applied, error) {
+	ok, err := r.exists()
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := r.conn.ExecQuery(
+		"SELECT version, name, checksum, applied_at FROM " + Table + " ORDER BY version",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", Table, err)
+	}
+	defer rows.Close()
+	_, data, err := db.FormatTableData(rows)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", Table, err)
+	}
+	out := make([]applied, 0, len(data))
+	for _, row := range data {
+		if len(row) >= 4 {
+			out = append(out, applied{row[0], row[1], row[2], formatTime(row[3])})
+		}
+	}
+	return out, nil
+}
+
+// formatTime drops the zone drivers print after a timestamp
+func formatTime(value string) string {
+	if t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", value); err == nil {
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return value
+}
+
+// Status lists every migration, from the files and the tracking table,
+// ordered by version
+func (r *Runner) Status(migrations []Migration) ([]Status, error) {
+	rows, err := r.applied()
+	if err != nil {
+		return nil, err
+	}
+	done := map[string]applied{}
+	for _, row := range rows {
+		done[row.version] = row
+	}
+
+	var statuses []Status
+	for i := range migrations {
+		m := &migrations[i]
+		s := Status{Version: m.Version, Name: m.Name, State: StatePending, Migration: m}
+		if row, ok := done[m.Version]; ok {
+			s.State, s.AppliedAt = StateApplied, row.at
+			if row.checksum != m.Checksum {
+				s.State = StateModified
+			}
+			delete(done, m.Version)
+		}
+		statuses = append(statuses, s)
+	}
+	for _, row := range rows {
+		if _, ok := done[row.version]; ok {
+			statuses = append(statuses, Status{
+				Version:   row.version,
+				Name:      row.name,
+				State:     StateMissing,
+				AppliedAt: row.at,
+			})
+		}
+	}
+	slices.SortStableFunc(statuses, func(a, b Status) int {
+		switch {
+		case versionLess(a.Version, b.Version):
+			return -1
+		case versionLess(b.Version, a.Version):
+			return 1
+		}
+		return 0
+	})
+	return statuses, nil
+}
+
+// checkModified refuses to go on while an applied migration was edited
+func checkModified(statuses []Status) error {
+	var edited []string
+	for _, s := range statuses {
+		if s.State == StateModified {
+			edited = append(edited, s.Version+"_"+s.Name)
+		}
+	}
+	if len(edited) > 0 {
+		return fmt.Errorf(
+			"%s changed after being applied (checksum differs); restore the file or write a new migration",
+			strings.Join(edited, ", "),
+		)
+	}
+	return nil
+}
+
+// Up applies pending migrations in version order, at most steps of them
+// when steps is positive, and returns how many ran
+func (r *Runner) Up(migrations []Migration, steps int) (int, error) {
+	statuses, err := r.Status(migrations)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkModified(statuses); err != nil {
+		return 0, err
+	}
+	if err := r.ensure(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, s := range statuses {
+		if s.State != StatePending {
+			continue
+		}
+		if steps > 0 && count == steps {
+			break
+		}
+		m := *s.Migration
+		if err := r.run(m, m.Up, "up", r.recordSQL(m)); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, and
+// returns how many ran
+func (r *Runner) Down(migrations []Migration, steps int) (int, error) {
+	statuses, err := r.Status(migrations)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkModified(statuses); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(statuses) - 1; i >= 0 && count < steps; i-- {
+		s := statuses[i]
+		switch s.State {
+		case StatePending:
+			continue
+		case StateMissing:
+			return count, fmt.Errorf(
+				"%s_%s was applied but its file is missing, so it can't be reverted",
+				s.Version, s.Name,
+			)
+		}
+		m := *s.Migration
+		if m.Down.SQL == "" {
+			return count, fmt.Errorf("%s has no migrate:down section", m.ID())
+		}
+		if err := r.run(m, m.Down, "down", r.forgetSQL(m.Version)); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// run executes a section followed by the statement that records it, in
+// one transaction when the database can roll back DDL
+func (r *Runner) run(m Migration, section Section, direction, record string) error {
+	transactional := transactionalDDL(r.dialect) && !section.NoTransaction
+	if r.DryRun {
+		mode := "in a transaction"
+		if !transactional {
+			mode = "without a transaction"
+		}
+		fmt.Fprintf(r.Out, "-- %s (%s, %s)\n", m.ID(), direction, mode)
+		for _, stmt := range db.SplitSQLStatements(section.SQL) {
+			fmt.Fprintf(r.Out, "%s;\n", stmt)
+		}
+		fmt.Fprintf(r.Out, "%s;\n\n", record)
+		return nil
+	}
+
+	start := time.Now()
+	script := section.SQL + "\n;\n" + record + ";\n"
+	result, err := db.ImportSQL(r.conn, strings.NewReader(script), db.ImportOptions{
+		SingleTransaction: transactional,
+	})
+	if err != nil {
+		location := m.Path
+		if result != nil && len(result.Errors) > 0 {
+			failed := result.Errors[0]
+			err = failed.Err
+			if failed.Line > 0 {
+				location = fmt.Sprintf("%s:%d", m.Path, section.Line+failed.Line-1)
+			}
+		}
+		err = fmt.Errorf("%s %s failed at %s: %w", m.ID(), direction, location, err)
+		if !transactional && result != nil && result.Executed > 0 {
+			err = fmt.Errorf(
+				"%w; %d statement(s) before it ran without a transaction and were not undone",
+				err, result.Executed,
+			)
+		}
+		return err
+	}
+	if r.Done != nil {
+		r.Done(m, direction == "up", time.Since(start))
+	}
+	return nil
+}
@@ -14,15 +14,36 @@ type InputModel struct {
 	sql           string
 	missingParams []string
 	defaults      map[string]string
+	types         map[string]Type
 	currentValues map[string]string
 	cursorIndex   int
+	submitted     bool
 	aborted       bool
+
+	// Parameters with a lookup query get a searchable picker; pick is the
+	// highlighted suggestion, or -1
+	lookups     map[string]string
+	lookup      LookupFunc
+	suggestions map[string][]Suggestion
+	lookupErrs  map[string]error
+	loading     bool
+	pick        int
+}
+
+// suggestionRows is how many suggestions the picker shows at once
+const suggestionRows = 6
+
+type lookupsLoadedMsg struct {
+	suggestions map[string][]Suggestion
+	errs        map[string]error
 }
 
 func NewInputModel(
 	sql string,
 	missingParams []string,
 	defaults map[string]string,
+	types map[string]Type,
+	lookup LookupFunc,
 ) InputModel {
 	currentValues := make(map[string]string)
 	for _, param := range missingParams {
@@ -37,36 +58,111 @@ func NewInputModel(
 		sql:           sql,
 		missingParams: missingParams,
 		defaults:      defaults,
+		types:         types,
 		currentValues: currentValues,
 		cursorIndex:   0,
 		aborted:       false,
+		lookups:       ExtractLookups(sql),
+		lookup:        lookup,
+		pick:          -1,
 	}
 }
 
+// Init loads the lookups of the prompted parameters, one after another
+// since they share a connection.
 func (m InputModel) Init() tea.Cmd {
-	return nil
+	if m.lookup == nil {
+		return nil
+	}
+	pending := map[string]string{}
+	for _, param := range m.missingParams {
+		if sql, ok := m.lookups[param]; ok {
+			pending[param] = sql
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	lookup := m.lookup
+	return func() tea.Msg {
+		msg := lookupsLoadedMsg{suggestions: map[string][]Suggestion{}, errs: map[string]error{}}
+		for param, sql := range pending {
+			suggestions, err := lookup(sql)
+			if err != nil {
+				msg.errs[param] = err
+				continue
+			}
+			msg.suggestions[param] = suggestions
+		}
+		return msg
+	}
+}
+
+// filtered returns the focused parameter's suggestions matching its value.
+func (m InputModel) filtered(param string) []Suggestion {
+	return FilterSuggestions(m.suggestions[param], m.currentValues[param])
 }
 
 func (m InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case lookupsLoadedMsg:
+		m.suggestions, m.lookupErrs = msg.suggestions, msg.errs
+		return m, nil
+
 	case tea.KeyMsg:
+		currentParam := m.missingParams[m.cursorIndex]
+		matches := m.filtered(currentParam)
+
 		switch msg.String() {
-		case "ctrl+c", "esc", "q":
+		case "ctrl+c", "esc":
 			m.aborted = true
 			return m, tea.Quit
 
 		case "enter":
-			// Submit and quit
+			// A highlighted suggestion is picked before submitting
+			if m.pick >= 0 && m.pick < len(matches) && matches[m.pick].Value != m.currentValues[currentParam] {
+				m.currentValues[currentParam] = matches[m.pick].Value
+				m.pick = 0
+				return m, nil
+			}
+
+			// Submit and quit, unless a typed value is invalid
+			for i, param := range m.missingParams {
+				if m.validationError(param) != nil {
+					m.submitted = true
+					m.cursorIndex = i
+					return m, nil
+				}
+			}
 			return m, tea.Quit
 
-		case "down":
+		case "down", "tab":
+			if msg.String() == "down" && m.pick < len(matches)-1 && m.pick < suggestionRows-1 {
+				m.pick++
+				break
+			}
 			if m.cursorIndex < len(m.missingParams)-1 {
 				m.cursorIndex++
+				m.pick = -1
 			}
 
-		case "up":
+		case "up", "shift+tab":
+			if msg.String() == "up" && m.pick >= 0 {
+				m.pick--
+				break
+			}
 			if m.cursorIndex > 0 {
 				m.cursorIndex--
+				m.pick = -1
+			}
+
+		case "left", "right", " ":
+			// Enums and booleans are chosen rather than typed
+			if choices := m.choices(currentParam); choices != nil {
+				m.currentValues[currentParam] = cycle(choices, m.currentValues[currentParam], msg.String() == "left")
+			} else if msg.String() == " " {
+				m.currentValues[currentParam] += " "
 			}
 
 		case "backspace":
@@ -75,18 +171,84 @@ func (m InputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(currentVal) > 0 {
 				m.currentValues[currentParam] = currentVal[:len(currentVal)-1]
 			}
+			m.pick = m.firstPick(currentParam)
 
 		default:
 			// Handle regular character input
-			currentParam := m.missingParams[m.cursorIndex]
-			// Allow typing any character
-			m.currentValues[currentParam] += msg.String()
+			if msg.Type != tea.KeyRunes {
+				break
+			}
+			m.currentValues[currentParam] += string(msg.Runes)
+			m.pick = m.firstPick(currentParam)
 		}
 	}
 
 	return m, nil
 }
 
+// firstPick highlights the best suggestion once something is typed.
+func (m InputModel) firstPick(param string) int {
+	if m.currentValues[param] == "" || len(m.filtered(param)) == 0 {
+		return -1
+	}
+	return 0
+}
+
+// choices are the values an enum or bool parameter cycles through.
+func (m InputModel) choices(param string) []string {
+	t, ok := m.types[param]
+	if !ok || t.List {
+		return nil
+	}
+	switch t.Base {
+	case TypeEnum:
+		return t.Values
+	case TypeBool:
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+func cycle(choices []string, current string, back bool) string {
+	for i, c := range choices {
+		if c == current {
+			if back {
+				return choices[(i+len(choices)-1)%len(choices)]
+			}
+			return choices[(i+1)%len(choices)]
+		}
+	}
+	return choices[0]
+}
+
+// validationError reports why a typed parameter's value is not usable.
+func (m InputModel) validationError(param string) error {
+	t, ok := m.types[param]
+	if !ok {
+		return nil
+	}
+	value := m.currentValues[param]
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("a %s value is required", t)
+	}
+	return t.Validate(value)
+}
+
+// typeHint describes the expected input next to a parameter's name.
+func typeHint(t Type) string {
+	switch {
+	case t.List:
+		return fmt.Sprintf("%s, comma-separated", t.Base)
+	case t.Base == TypeDate:
+		return "date, YYYY-MM-DD"
+	case t.Base == TypeTimestamp:
+		return "timestamp, YYYY-MM-DD HH:MM:SS"
+	case t.Base == TypeEnum || t.Base == TypeBool:
+		return t.String() + ", ←/→ to choose"
+	}
+	return t.String()
+}
+
 func (m InputModel) View() string {
 	var b strings.Builder
 
@@ -95,7 +257,7 @@ func (m InputModel) View() string {
 	b.WriteString("\n")
 
 	// SQL display (formatted with line breaks and syntax highlighting)
-	formattedSQL := parser.FormatSQLWithLineBreaks(m.sql)
+	formattedSQL := parser.FormatSQLWithLineBreaks(StripLookups(m.sql))
 	highlightedSQL := parser.HighlightSQL(formattedSQL)
 	b.WriteString(highlightedSQL)
 	b.WriteString("\n\n")
@@ -103,6 +265,10 @@ func (m InputModel) View() string {
 	// Parameter input fields
 	for i, param := range m.missingParams {
 		currentValue := m.currentValues[param]
+		hint := ""
+		if t, ok := m.types[param]; ok {
+			hint = " " + styles.Faint.Render("("+typeHint(t)+")")
+		}
 
 		// Style differently for focused vs unfocused
 		if i == m.cursorIndex {
@@ -116,7 +282,16 @@ func (m InputModel) View() string {
 				Foreground(lipgloss.Color(styles.ActiveScheme.Muted)).
 				Render(currentValue + "▏")
 
-			b.WriteString(prompt + inputBox + "\n")
+			b.WriteString(prompt + inputBox + hint + "\n")
+
+			// Mistakes show once the value is complete enough to judge,
+			// or after a rejected submit
+			// While the picker has matches, the value is a search
+			searching := currentValue != "" && len(m.filtered(param)) > 0
+			if err := m.validationError(param); err != nil && (m.submitted || (currentValue != "" && !searching)) {
+				b.WriteString(styles.Error.Render("  ✗ "+err.Error()) + "\n")
+			}
+			b.WriteString(m.pickerView(param))
 		} else {
 			// Unfocused field
 			prompt := lipgloss.NewStyle().
@@ -127,18 +302,55 @@ func (m InputModel) View() string {
 				Foreground(lipgloss.Color(styles.ActiveScheme.Muted)).
 				Render(currentValue)
 
-			b.WriteString(prompt + inputBox + "\n")
+			b.WriteString(prompt + inputBox + hint + "\n")
 		}
 	}
 
 	b.WriteString("\n")
 	b.WriteString(
-		styles.Faint.Render("↑: up  ↓: down  Enter: submit  Esc/q: cancel"),
+		styles.Faint.Render("↑/↓: move  Tab: next  ←/→: choose  Enter: pick/submit  Esc: cancel"),
 	)
 
 	return b.String()
 }
 
+// pickerView lists the focused parameter's lookup suggestions.
+func (m InputModel) pickerView(param string) string {
+	if _, ok := m.lookups[param]; !ok || m.lookup == nil {
+		return ""
+	}
+	if err := m.lookupErrs[param]; err != nil {
+		return styles.Faint.Render("  lookup failed: "+err.Error()) + "\n"
+	}
+	all, loaded := m.suggestions[param]
+	if !loaded {
+		return styles.Faint.Render("  loading suggestions…") + "\n"
+	}
+
+	matches := FilterSuggestions(all, m.currentValues[param])
+	if len(matches) == 0 {
+		return styles.Faint.Render(fmt.Sprintf("  no matches in %d suggestions", len(all))) + "\n"
+	}
+
+	var b strings.Builder
+	for i, s := range matches {
+		if i == suggestionRows {
+			b.WriteString(styles.Faint.Render(fmt.Sprintf("    … %d more, type to narrow", len(matches)-suggestionRows)) + "\n")
+			break
+		}
+		line := s.Value
+		if s.Label != "" {
+			line += "  " + styles.Faint.Render(s.Label)
+		}
+		if i == m.pick {
+			b.WriteString(styles.Success.Render("  › ") + line + "\n")
+		} else {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
 func (m InputModel) GetValues() map[string]string {
 	return m.currentValues
 }
@@ -151,8 +363,10 @@ func CollectParameters(
 	sql string,
 	missingParams []string,
 	defaults map[string]string,
+	types map[string]Type,
+	lookup LookupFunc,
 ) (map[string]string, error) {
-	model := NewInputModel(sql, missingParams, defaults)
+	model := NewInputModel(sql, missingParams, defaults, types, lookup)
 	program := tea.NewProgram(model)
 
 	finalModel, err := program.Run()
@@ -0,0 +1,229 @@
+package params
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// Suggestion is one candidate value from a lookup query: its first column,
+// labelled by the others.
+type Suggestion struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+}
+
+// LookupFunc runs a lookup query for the parameter prompt.
+type LookupFunc func(sql string) ([]Suggestion, error)
+
+// LookupCacheTTL is how long lookup results are reused across pam
+// invocations, e.g. between completions in one shell session.
+const LookupCacheTTL = 5 * time.Minute
+
+// MaxSuggestions caps the rows read from a lookup query
+const MaxSuggestions = 1000
+
+var lookupLine = regexp.MustCompile(`(?m)^\s*--\s*@param\s+([A-Za-z_][A-Za-z0-9_]*)\s+lookup\s*:\s*(.+?)\s*;?\s*$`)
+
+// ExtractLookups returns the lookup query declared for each parameter with
+// a comment such as:
+//
+//	-- @param customer_id lookup: SELECT id, name FROM customers
+func ExtractLookups(sql string) map[string]string {
+	lookups := make(map[string]string)
+	for _, m := range lookupLine.FindAllStringSubmatch(sql, -1) {
+		lookups[m[1]] = m[2]
+	}
+	return lookups
+}
+
+// IsLookupLine reports whether line is a lookup declaration, which editors
+// keep as part of the query's SQL rather than as a comment.
+func IsLookupLine(line string) bool {
+	return lookupLine.MatchString(line)
+}
+
+// StripLookups removes the lookup declarations from sql, for display.
+func StripLookups(sql string) string {
+	return strings.TrimSpace(lookupLine.ReplaceAllString(sql, ""))
+}
+
+var (
+	lookupMu    sync.Mutex
+	lookupCache = map[string][]Suggestion{}
+)
+
+// RunLookup runs a lookup query on conn, opening it if needed. Results are
+// kept for the rest of the process and cached on disk for LookupCacheTTL.
+func RunLookup(conn db.DatabaseConnection, sql string) ([]Suggestion, error) {
+	key := lookupKey(conn, sql)
+
+	lookupMu.Lock()
+	cached, ok := lookupCache[key]
+	lookupMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+	if cached, ok := readLookupCache(key); ok {
+		lookupMu.Lock()
+		lookupCache[key] = cached
+		lookupMu.Unlock()
+		return cached, nil
+	}
+
+	// Use an open connection as is, e.g. in the shell; otherwise open one
+	// just for the lookup
+	if conn.GetDB() == nil || conn.Ping() != nil {
+		if err := conn.Open(); err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+	}
+
+	rows, err := conn.ExecQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	_, data, err := db.FormatTableData(rows)
+	if err != nil {
+		return nil, err
+	}
+	suggestions := make([]Suggestion, 0, min(len(data), MaxSuggestions))
+	for _, row := range data {
+		if len(row) == 0 || len(suggestions) == MaxSuggestions {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{
+			Value: row[0],
+			Label: strings.Join(row[1:], " · "),
+		})
+	}
+
+	lookupMu.Lock()
+	lookupCache[key] = suggestions
+	lookupMu.Unlock()
+	writeLookupCache(key, suggestions)
+	return suggestions, nil
+}
+
+func lookupKey(conn db.DatabaseConnection, sql string) string {
+	sum := sha256.Sum256([]byte(conn.GetDbType() + "\x00" + conn.GetConnString() + "\x00" + sql))
+	return hex.EncodeToString(sum[:12])
+}
+
+func lookupCachePath(key string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pam", "lookups", key+".json")
+}
+
+func readLookupCache(key string) ([]Suggestion, bool) {
+	path := lookupCachePath(key)
+	if path == "" {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > LookupCacheTTL {
+		return nil, false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var suggestions []Suggestion
+	if err := json.Unmarshal(content, &suggestions); err != nil {
+		return nil, false
+	}
+	return suggestions, true
+}
+
+// writeLookupCache is best effort; a lookup works without its cache.
+func writeLookupCache(key string, suggestions []Suggestion) {
+	path := lookupCachePath(key)
+	if path == "" {
+		return
+	}
+	content, err := json.Marshal(suggestions)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, content, 0o600)
+}
+
+// FilterSuggestions keeps the suggestions whose value or label contains the
+// letters of query in order, best matches first.
+func FilterSuggestions(suggestions []Suggestion, query string) []Suggestion {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return suggestions
+	}
+
+	type scored struct {
+		s     Suggestion
+		score int
+	}
+	var matches []scored
+	for _, s := range suggestions {
+		score, ok := fuzzyScore(strings.ToLower(s.Value+" "+s.Label), query)
+		if ok {
+			matches = append(matches, scored{s, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	result := make([]Suggestion, len(matches))
+	for i, m := range matches {
+		result[i] = m.s
+	}
+	return result
+}
+
+// fuzzyScore matches query as a subsequence of text. Contiguous runs and
+// a match at the start score higher.
+func fuzzyScore(text, query string) (int, bool) {
+	if strings.HasPrefix(text, query) {
+		return 1000, true
+	}
+	if strings.Contains(text, query) {
+		return 500, true
+	}
+
+	runes := []rune(text)
+	score, run, ti := 0, 0, 0
+	for _, qc := range query {
+		found := false
+		for ti < len(runes) {
+			tc := runes[ti]
+			ti++
+			if tc == qc {
+				run++
+				score += run
+				found = true
+				break
+			}
+			run = 0
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return score, true
+}
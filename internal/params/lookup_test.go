@@ -0,0 +1,82 @@
+package params
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestExtractLookups(t *testing.T) {
+	sql := `-- @param customer_id lookup: SELECT id, name FROM customers;
+--@param status lookup:SELECT DISTINCT status FROM orders
+-- just a comment: not a lookup
+SELECT * FROM orders WHERE customer_id = :customer_id AND status = :status`
+
+	want := map[string]string{
+		"customer_id": "SELECT id, name FROM customers",
+		"status":      "SELECT DISTINCT status FROM orders",
+	}
+	if got := ExtractLookups(sql); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := StripLookups(sql); got != "-- just a comment: not a lookup\nSELECT * FROM orders WHERE customer_id = :customer_id AND status = :status" {
+		t.Errorf("StripLookups = %q", got)
+	}
+}
+
+func TestFilterSuggestions(t *testing.T) {
+	all := []Suggestion{{"1", "Acme Corp"}, {"2", "Globex"}, {"3", "Initech"}, {"12", "Umbrella"}}
+
+	values := func(s []Suggestion) []string {
+		var v []string
+		for _, x := range s {
+			v = append(v, x.Value)
+		}
+		return v
+	}
+	if got := values(FilterSuggestions(all, "")); len(got) != 4 {
+		t.Errorf("empty query should keep everything, got %v", got)
+	}
+	if got := values(FilterSuggestions(all, "glx")); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Errorf("fuzzy match = %v", got)
+	}
+	if got := values(FilterSuggestions(all, "1")); !reflect.DeepEqual(got, []string{"1", "12"}) {
+		t.Errorf("prefix matches first, got %v", got)
+	}
+}
+
+func TestRunLookup_Caches(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("HOME", dir)
+
+	conn, err := db.NewSQLiteConnection("lookup", filepath.Join(dir, "l.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Open()
+	if err := conn.Exec("CREATE TABLE c (id INTEGER, name TEXT); INSERT INTO c VALUES (1, 'Acme'), (2, 'Globex')"); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	lookup := "SELECT id, name FROM c ORDER BY id"
+	got, err := RunLookup(conn, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Suggestion{{"1", "Acme"}, {"2", "Globex"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A new process would read the disk cache rather than the database
+	lookupCache = map[string][]Suggestion{}
+	conn.Open()
+	conn.Exec("DELETE FROM c")
+	conn.Close()
+	if again, _ := RunLookup(conn, lookup); len(again) != 2 {
+		t.Errorf("expected cached results, got %v", again)
+	}
+}
@@ -1,5 +1,7 @@
 package params
 
+import "strings"
+
 func ExtractParameters(sql string) map[string]string {
 	params := make(map[string]string)
 	matches := findSafeParamMatches(sql)
@@ -14,3 +16,19 @@ func ExtractParameters(sql string) map[string]string {
 
 	return params
 }
+
+// ApplyDefaults gives parameters without an inline default the one from
+// defaults, as if the SQL had been written with :name|'value'.
+func ApplyDefaults(sql string, defaults map[string]string) string {
+	return replaceSafeMatches(sql, findSafeParamMatches(sql), func(m paramMatch) string {
+		def, ok := defaults[m.name]
+		if m.hasDefault || !ok || def == "" {
+			return sql[m.start:m.end]
+		}
+		name := ":" + m.name
+		if m.typeSpec != "" {
+			name += ":" + m.typeSpec
+		}
+		return name + "|'" + strings.ReplaceAll(def, "'", "''") + "'"
+	})
+}
@@ -1,6 +1,7 @@
 package params
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -103,3 +104,22 @@ func TestExtractParameters_Duplicates(t *testing.T) {
 		t.Errorf("expected 1 unique param, got %d", len(params))
 	}
 }
+
+func TestApplyDefaults(t *testing.T) {
+	sql := "SELECT * FROM t WHERE s = :status AND n < :limit|5 AND o = :owner AND note = ':status'"
+	got := ApplyDefaults(sql, map[string]string{"status": "it's", "limit": "10"})
+
+	params := ExtractParameters(got)
+	if params["status"] != "it's" {
+		t.Errorf("status default = %q, want it's (sql %q)", params["status"], got)
+	}
+	if params["limit"] != "5" {
+		t.Errorf("an inline default should win, got %q", params["limit"])
+	}
+	if params["owner"] != "" {
+		t.Errorf("owner should stay required, got %q", params["owner"])
+	}
+	if !strings.HasSuffix(got, "note = ':status'") {
+		t.Errorf("string literals must not change: %q", got)
+	}
+}
@@ -48,15 +48,21 @@ func ValidateCLIValues(cliValues, paramDefs map[string]string) error {
 
 // Reserved flags that cannot be used as parameter names
 var reservedFlags = map[string]bool{
-	"edit":    true,
-	"last":    true,
-	"l":       true,
-	"help":    true,
-	"h":       true,
-	"version": true,
-	"v":       true,
-	"format":  true,
-	"f":       true,
+	"edit":      true,
+	"last":      true,
+	"l":         true,
+	"help":      true,
+	"h":         true,
+	"version":   true,
+	"v":         true,
+	"format":    true,
+	"f":         true,
+	"chart":     true,
+	"file-mode": true,
+	"group":     true,
+	"parallel":  true,
+	"yes":       true,
+	"y":         true,
 }
 
 func ValidateParamNames(paramDefs map[string]string) error {
@@ -6,12 +6,14 @@ type paramMatch struct {
 	start      int
 	end        int
 	name       string
+	typeSpec   string
 	defaultVal string
 	hasDefault bool
 }
 
-// findSafeParamMatches scans SQL and returns :param or :param|default
-// matches that are outside string literals, comments, and :: type casts
+// findSafeParamMatches scans SQL and returns :param, :param:type and
+// :param|default matches that are outside string literals, comments, and
+// :: type casts
 func findSafeParamMatches(sql string) []paramMatch {
 	var result []paramMatch
 	n := len(sql)
@@ -64,6 +66,25 @@ func findSafeParamMatches(sql string) []paramMatch {
 
 			m := paramMatch{start: start, name: name}
 
+			// :name:type, where type may be enum(a,b) and end in []
+			if i+1 < n && sql[i] == ':' && isIdentStart(sql[i+1]) {
+				typeStart := i + 1
+				i++
+				for i < n && isIdentChar(sql[i]) {
+					i++
+				}
+				if i < n && sql[i] == '(' {
+					if end := strings.IndexByte(sql[i:], ')'); end >= 0 {
+						i += end + 1
+					}
+				}
+				if i+1 < n && sql[i] == '[' && sql[i+1] == ']' {
+					i += 2
+				}
+				m.typeSpec = sql[typeStart:i]
+			}
+			isList := strings.HasSuffix(m.typeSpec, "[]")
+
 			if i < n && sql[i] == '|' {
 				m.hasDefault = true
 				i++ // skip |
@@ -92,9 +113,9 @@ func findSafeParamMatches(sql string) []paramMatch {
 					}
 					m.defaultVal = def.String()
 				} else {
-					// unquoted default
+					// unquoted default; a list default keeps its commas
 					defStart := i
-					for i < n && sql[i] != ' ' && sql[i] != '\t' && sql[i] != '\n' && sql[i] != '\r' && sql[i] != ')' && sql[i] != ',' {
+					for i < n && sql[i] != ' ' && sql[i] != '\t' && sql[i] != '\n' && sql[i] != '\r' && sql[i] != ')' && (sql[i] != ',' || isList) {
 						i++
 					}
 					m.defaultVal = sql[defStart:i]
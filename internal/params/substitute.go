@@ -7,6 +7,10 @@ import (
 	"github.com/caiolandgraf/pam/internal/db"
 )
 
+// SubstituteParameters replaces parameters with the connection's
+// placeholders and returns the values to bind. Typed parameters are
+// converted to Go values; list parameters expand to one placeholder per
+// item, wrapped in parentheses unless the SQL already has them.
 func SubstituteParameters(
 	sql string,
 	paramValues map[string]string,
@@ -22,27 +26,47 @@ func SubstituteParameters(
 		return sql, []any{}, nil
 	}
 
+	types, err := ExtractTypes(sql)
+	if err != nil {
+		return "", nil, err
+	}
+
 	var orderedValues []any
-	paramIndex := make(map[string]int)
+	paramIndexes := make(map[string][]int)
 	currentIndex := 1
 
 	for _, m := range matches {
-		if _, exists := paramIndex[m.name]; !exists {
-			if value, ok := paramValues[m.name]; ok {
-				paramIndex[m.name] = currentIndex
-				orderedValues = append(orderedValues, value)
-				currentIndex++
-			} else {
+		if _, exists := paramIndexes[m.name]; !exists {
+			value, ok := paramValues[m.name]
+			if !ok {
 				return "", nil, fmt.Errorf("missing value for parameter: %s", m.name)
 			}
+
+			values := []any{value}
+			if t, typed := types[m.name]; typed {
+				values, err = t.Convert(value, conn.GetDbType())
+				if err != nil {
+					return "", nil, fmt.Errorf("parameter %s (%s): %w", m.name, t, err)
+				}
+			}
+			for _, v := range values {
+				paramIndexes[m.name] = append(paramIndexes[m.name], currentIndex)
+				orderedValues = append(orderedValues, v)
+				currentIndex++
+			}
 		}
 	}
 
 	result := replaceSafeMatches(sql, matches, func(m paramMatch) string {
-		if idx, ok := paramIndex[m.name]; ok {
-			return conn.GetPlaceholder(idx)
+		indexes, ok := paramIndexes[m.name]
+		if !ok {
+			return sql[m.start:m.end]
 		}
-		return sql[m.start:m.end]
+		placeholders := make([]string, len(indexes))
+		for i, idx := range indexes {
+			placeholders[i] = conn.GetPlaceholder(idx)
+		}
+		return listOrScalar(sql, m, types[m.name].List, placeholders)
 	})
 
 	return result, orderedValues, nil
@@ -50,18 +74,44 @@ func SubstituteParameters(
 
 func GenerateDisplaySQL(sql string, paramValues map[string]string) string {
 	matches := findSafeParamMatches(sql)
+	types, _ := ExtractTypes(sql)
 
 	return replaceSafeMatches(sql, matches, func(m paramMatch) string {
-		if value, ok := paramValues[m.name]; ok {
-			if isNumeric(value) {
-				return value
+		value, ok := paramValues[m.name]
+		if !ok {
+			return sql[m.start:m.end]
+		}
+		if t, typed := types[m.name]; typed {
+			if values, err := t.Convert(value, ""); err == nil {
+				literals := make([]string, len(values))
+				for i, v := range values {
+					literals[i] = literal(v)
+				}
+				return listOrScalar(sql, m, t.List, literals)
 			}
-			return "'" + strings.ReplaceAll(value, "'", "''") + "'"
 		}
-		return sql[m.start:m.end]
+		if isNumeric(value) {
+			return value
+		}
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
 	})
 }
 
+// listOrScalar joins the items replacing m. A list gets its own
+// parentheses unless it already sits inside IN ( ... ).
+func listOrScalar(sql string, m paramMatch, list bool, items []string) string {
+	joined := strings.Join(items, ", ")
+	if !list {
+		return joined
+	}
+	before := strings.TrimRight(sql[:m.start], " \t\r\n")
+	after := strings.TrimLeft(sql[m.end:], " \t\r\n")
+	if strings.HasSuffix(before, "(") && strings.HasPrefix(after, ")") {
+		return joined
+	}
+	return "(" + joined + ")"
+}
+
 // replaceSafeMatches rebuilds SQL by replacing each paramMatch using fn
 func replaceSafeMatches(sql string, matches []paramMatch, fn func(paramMatch) string) string {
 	if len(matches) == 0 {
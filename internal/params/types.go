@@ -0,0 +1,244 @@
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is a parameter's declared type, written after its name:
+//
+//	:since:date|2024-01-01
+//	:ids:int[]
+//	:status:enum(open,closed)
+//
+// Untyped parameters are bound as strings, as before.
+type Type struct {
+	Base   string
+	List   bool
+	Values []string // allowed values of an enum
+}
+
+// Base types; aliases are normalized by ParseType
+const (
+	TypeText      = "text"
+	TypeInt       = "int"
+	TypeFloat     = "float"
+	TypeBool      = "bool"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+	TypeEnum      = "enum"
+)
+
+var typeAliases = map[string]string{
+	"text": TypeText, "string": TypeText, "str": TypeText,
+	"int": TypeInt, "integer": TypeInt, "bigint": TypeInt,
+	"float": TypeFloat, "number": TypeFloat, "numeric": TypeFloat, "decimal": TypeFloat,
+	"bool": TypeBool, "boolean": TypeBool,
+	"date": TypeDate, "timestamp": TypeTimestamp, "datetime": TypeTimestamp,
+}
+
+var dateLayouts = []string{"2006-01-02"}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseType parses a type annotation such as int, date[] or enum(a,b).
+func ParseType(spec string) (Type, error) {
+	var t Type
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutSuffix(spec, "[]"); ok {
+		t.List = true
+		spec = rest
+	}
+
+	if inner, ok := strings.CutPrefix(strings.ToLower(spec), "enum("); ok && strings.HasSuffix(inner, ")") {
+		t.Base = TypeEnum
+		for _, v := range strings.Split(spec[len("enum("):len(spec)-1], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				t.Values = append(t.Values, v)
+			}
+		}
+		if len(t.Values) == 0 {
+			return t, fmt.Errorf("enum needs at least one value")
+		}
+		return t, nil
+	}
+
+	base, ok := typeAliases[strings.ToLower(spec)]
+	if !ok {
+		return t, fmt.Errorf("unknown type %q", spec)
+	}
+	t.Base = base
+	return t, nil
+}
+
+func (t Type) String() string {
+	s := t.Base
+	if t.Base == TypeEnum {
+		s = "enum(" + strings.Join(t.Values, ",") + ")"
+	}
+	if t.List {
+		s += "[]"
+	}
+	return s
+}
+
+// Items splits a list value on commas. A scalar value is its only item.
+func (t Type) Items(value string) []string {
+	if !t.List {
+		return []string{value}
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Validate reports why value is not a valid t.
+func (t Type) Validate(value string) error {
+	items := t.Items(value)
+	if t.List && len(items) == 0 {
+		return fmt.Errorf("expected a comma-separated list of %s", t.Base)
+	}
+	for _, item := range items {
+		if _, err := t.convert(item, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert turns value into the Go values bound for t, one per list item.
+// SQLite gets dates as ISO strings, which is how it stores them.
+func (t Type) Convert(value, dbType string) ([]any, error) {
+	items := t.Items(value)
+	if t.List && len(items) == 0 {
+		return nil, fmt.Errorf("expected a comma-separated list of %s", t.Base)
+	}
+	result := make([]any, len(items))
+	for i, item := range items {
+		v, err := t.convert(item, dbType)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+func (t Type) convert(value, dbType string) (any, error) {
+	value = strings.TrimSpace(value)
+	switch t.Base {
+	case TypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return n, nil
+	case TypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return f, nil
+	case TypeBool:
+		switch strings.ToLower(value) {
+		case "true", "t", "yes", "y", "1":
+			return true, nil
+		case "false", "f", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%q is not true or false", value)
+	case TypeDate, TypeTimestamp:
+		layouts, format := dateLayouts, "2006-01-02"
+		if t.Base == TypeTimestamp {
+			layouts, format = timestampLayouts, "2006-01-02 15:04:05"
+		}
+		for _, layout := range layouts {
+			if ts, err := time.Parse(layout, value); err == nil {
+				if dbType == "sqlite" {
+					return ts.Format(format), nil
+				}
+				return ts, nil
+			}
+		}
+		if t.Base == TypeDate {
+			return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD)", value)
+		}
+		return nil, fmt.Errorf("%q is not a timestamp (YYYY-MM-DD HH:MM:SS)", value)
+	case TypeEnum:
+		for _, allowed := range t.Values {
+			if value == allowed {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not one of %s", value, strings.Join(t.Values, ", "))
+	}
+	return value, nil
+}
+
+// literal renders a converted value for display SQL.
+func literal(v any) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return "'" + v.Format("2006-01-02") + "'"
+		}
+		return "'" + v.Format("2006-01-02 15:04:05") + "'"
+	}
+	return "'" + strings.ReplaceAll(fmt.Sprint(v), "'", "''") + "'"
+}
+
+// ExtractTypes returns the declared type of each typed parameter in sql. A
+// parameter may be annotated at any of its occurrences, but not with two
+// different types.
+func ExtractTypes(sql string) (map[string]Type, error) {
+	types := make(map[string]Type)
+	for _, m := range findSafeParamMatches(sql) {
+		if m.typeSpec == "" {
+			continue
+		}
+		t, err := ParseType(m.typeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", m.name, err)
+		}
+		if prev, ok := types[m.name]; ok && prev.String() != t.String() {
+			return nil, fmt.Errorf("parameter %s is declared as both %s and %s", m.name, prev, t)
+		}
+		types[m.name] = t
+	}
+	return types, nil
+}
+
+// ValidateValues checks every non-empty value against its parameter's type.
+func ValidateValues(types map[string]Type, values map[string]string) error {
+	for name, t := range types {
+		value, ok := values[name]
+		if !ok || value == "" {
+			continue
+		}
+		if err := t.Validate(value); err != nil {
+			return fmt.Errorf("parameter %s (%s): %w", name, t, err)
+		}
+	}
+	return nil
+}
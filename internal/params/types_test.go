@@ -0,0 +1,115 @@
+package params
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+		err  bool
+	}{
+		{"int", "int", false},
+		{"integer[]", "int[]", false},
+		{"datetime", "timestamp", false},
+		{"enum(open, closed)", "enum(open,closed)", false},
+		{"enum(a,b)[]", "enum(a,b)[]", false},
+		{"enum()", "", true},
+		{"uuid", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseType(tt.spec)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseType(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("ParseType(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestExtractTypes(t *testing.T) {
+	sql := "SELECT * FROM t WHERE d >= :since:date|2024-01-01 AND id IN (:ids:int[]|1,2) AND s = :status:enum(open,closed) AND n = :name AND x = :since"
+	types, err := ExtractTypes(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(types) != 3 || types["since"].Base != TypeDate || !types["ids"].List || types["status"].Base != TypeEnum {
+		t.Errorf("types = %+v", types)
+	}
+
+	defaults := ExtractParameters(sql)
+	if defaults["since"] != "2024-01-01" || defaults["ids"] != "1,2" {
+		t.Errorf("defaults = %v", defaults)
+	}
+
+	if _, err := ExtractTypes("SELECT :a:int, :a:text"); err == nil {
+		t.Error("expected an error for conflicting types")
+	}
+	if types, _ := ExtractTypes("SELECT :a::int"); len(types) != 0 {
+		t.Errorf(":: cast should not be a type, got %v", types)
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	types, _ := ExtractTypes("SELECT :n:int, :d:date, :s:enum(a,b), :ids:int[], :ok:bool")
+	valid := map[string]string{"n": "5", "d": "2024-02-29", "s": "a", "ids": "1, 2,3", "ok": "yes"}
+	if err := ValidateValues(types, valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for name, value := range map[string]string{"n": "five", "d": "2024-02-30", "s": "c", "ids": "1,x", "ok": "maybe"} {
+		values := map[string]string{name: value}
+		if err := ValidateValues(types, values); err == nil {
+			t.Errorf("%s=%q should be invalid", name, value)
+		}
+	}
+}
+
+func TestSubstituteParameters_Typed(t *testing.T) {
+	pg, _ := db.NewPostgresConnection("pg", "postgres://localhost/x")
+	sql := "SELECT * FROM t WHERE id IN (:ids:int[]) AND d >= :since:date AND active = :on:bool AND other IN :ids"
+	values := map[string]string{"ids": "3,4,5", "since": "2024-01-01", "on": "true"}
+
+	got, args, err := SubstituteParameters(sql, values, pg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SELECT * FROM t WHERE id IN ($1, $2, $3) AND d >= $4 AND active = $5 AND other IN ($1, $2, $3)"
+	if got != want {
+		t.Errorf("sql = %q\nwant  %q", got, want)
+	}
+	wantArgs := []any{int64(3), int64(4), int64(5), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %#v", args)
+	}
+
+	display := GenerateDisplaySQL(sql, values)
+	if want := "SELECT * FROM t WHERE id IN (3, 4, 5) AND d >= '2024-01-01' AND active = TRUE AND other IN (3, 4, 5)"; display != want {
+		t.Errorf("display = %q", display)
+	}
+
+	lite, _ := db.NewSQLiteConnection("lite", ":memory:")
+	_, args, err = SubstituteParameters("SELECT :d:date", map[string]string{"d": "2024-03-01"}, lite)
+	if err != nil || !reflect.DeepEqual(args, []any{"2024-03-01"}) {
+		t.Errorf("sqlite dates should bind as strings, got %#v (%v)", args, err)
+	}
+
+	if _, _, err := SubstituteParameters("SELECT :n:int", map[string]string{"n": "x"}, pg); err == nil {
+		t.Error("expected a conversion error")
+	}
+}
+
+func TestApplyDefaults_KeepsType(t *testing.T) {
+	got := ApplyDefaults("SELECT :ids:int[]", map[string]string{"ids": "1,2"})
+	if want := "SELECT :ids:int[]|'1,2'"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
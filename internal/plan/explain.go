@@ -0,0 +1,162 @@
+package plan
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// Supported reports whether plan visualization is available for dbType.
+func Supported(dbType string) bool {
+	switch dbType {
+	case "postgres", "postgresql", "mysql", "mariadb", "sqlite", "sqlite3", "duckdb", "sqlserver", "mssql":
+		return true
+	}
+	return false
+}
+
+// ExplainSQL returns the dialect-specific EXPLAIN statement for query.
+// SQL Server has no EXPLAIN statement; its plan is requested with
+// SET SHOWPLAN_XML, so the query is returned unchanged.
+func ExplainSQL(dbType, query string, analyze bool) (string, error) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+
+	switch dbType {
+	case "postgres", "postgresql":
+		if analyze {
+			return "EXPLAIN (FORMAT JSON, ANALYZE) " + query, nil
+		}
+		return "EXPLAIN (FORMAT JSON) " + query, nil
+	case "mysql", "mariadb":
+		return "EXPLAIN FORMAT=JSON " + query, nil
+	case "sqlite", "sqlite3":
+		return "EXPLAIN QUERY PLAN " + query, nil
+	case "duckdb":
+		if analyze {
+			return "EXPLAIN (ANALYZE, FORMAT JSON) " + query, nil
+		}
+		return "EXPLAIN (FORMAT JSON) " + query, nil
+	case "sqlserver", "mssql":
+		return query, nil
+	}
+	return "", fmt.Errorf("query plans are not supported for %s", dbType)
+}
+
+// Run explains query on an open connection and parses the result. When
+// analyze is set the query is actually executed on dialects that support it
+// (Postgres, DuckDB), so timings and actual row counts are available.
+func Run(conn db.DatabaseConnection, query string, args []any, analyze bool) (*Plan, error) {
+	dbType := conn.GetDbType()
+	explainSQL, err := ExplainSQL(dbType, query, analyze)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := db.OpenSession(conn)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	p := &Plan{DbType: dbType, SQL: query}
+
+	switch dbType {
+	case "postgres", "postgresql":
+		raw, err := scanSingleValue(session, explainSQL, args)
+		if err != nil {
+			return nil, err
+		}
+		err = parsePostgres(raw, p)
+		if err != nil {
+			return nil, err
+		}
+		p.Analyzed = analyze
+
+	case "mysql", "mariadb":
+		raw, err := scanSingleValue(session, explainSQL, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := parseMySQL(raw, p); err != nil {
+			return nil, err
+		}
+
+	case "sqlite", "sqlite3":
+		rows, err := session.ExecQuery(explainSQL, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to explain query: %w", err)
+		}
+		_, _, data, err := db.FormatTableDataWithTypes(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plan: %w", err)
+		}
+		if err := parseSQLite(data, p); err != nil {
+			return nil, err
+		}
+
+	case "duckdb":
+		rows, err := session.ExecQuery(explainSQL, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to explain query: %w", err)
+		}
+		_, _, data, err := db.FormatTableDataWithTypes(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plan: %w", err)
+		}
+		if len(data) == 0 || len(data[0]) == 0 {
+			return nil, fmt.Errorf("explain returned no plan")
+		}
+		// EXPLAIN returns (explain_key, explain_value); the JSON is the last column
+		row := data[len(data)-1]
+		if err := parseDuckDB(row[len(row)-1], p); err != nil {
+			return nil, err
+		}
+		p.Analyzed = analyze
+
+	case "sqlserver", "mssql":
+		if _, err := session.Exec("SET SHOWPLAN_XML ON"); err != nil {
+			return nil, fmt.Errorf("failed to enable showplan: %w", err)
+		}
+		raw, scanErr := scanSingleValue(session, explainSQL, args)
+		if _, err := session.Exec("SET SHOWPLAN_XML OFF"); err != nil && scanErr == nil {
+			scanErr = fmt.Errorf("failed to disable showplan: %w", err)
+		}
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		if err := parseSQLServer(raw, p); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Root == nil {
+		return nil, fmt.Errorf("explain returned no plan")
+	}
+
+	p.Analyze()
+	return p, nil
+}
+
+// scanSingleValue runs a statement that returns its plan as one text value.
+func scanSingleValue(session *db.Session, query string, args []any) (string, error) {
+	rows, err := session.ExecQuery(query, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	var sb strings.Builder
+	for rows.Next() {
+		var value sql.NullString
+		if err := rows.Scan(&value); err != nil {
+			return "", fmt.Errorf("failed to read plan: %w", err)
+		}
+		sb.WriteString(value.String)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to read plan: %w", err)
+	}
+	return sb.String(), nil
+}
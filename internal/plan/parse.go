@@ -0,0 +1,559 @@
+package plan
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ---------------------------------------------------------------------------
+// PostgreSQL: EXPLAIN (FORMAT JSON [, ANALYZE])
+// ---------------------------------------------------------------------------
+
+func parsePostgres(raw string, p *Plan) error {
+	var doc []map[string]any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return fmt.Errorf("failed to parse postgres plan: %w", err)
+	}
+	if len(doc) == 0 {
+		return fmt.Errorf("explain returned no plan")
+	}
+
+	root, ok := doc[0]["Plan"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("postgres plan has no root node")
+	}
+
+	p.PlanningTimeMs = toFloat(doc[0]["Planning Time"])
+	p.ExecutionTimeMs = toFloat(doc[0]["Execution Time"])
+	p.Root = postgresNode(root)
+	return nil
+}
+
+func postgresNode(obj map[string]any) *Node {
+	n := newNode(toString(obj["Node Type"]))
+	n.Relation = toString(obj["Relation Name"])
+	if n.Relation == "" {
+		n.Relation = toString(obj["CTE Name"])
+	}
+	n.Cost = toFloat(obj["Total Cost"])
+	n.EstRows = toFloat(obj["Plan Rows"])
+	n.Loops = toFloat(obj["Actual Loops"])
+
+	// Actual values are reported per loop
+	loops := n.Loops
+	if loops <= 0 {
+		loops = 1
+	}
+	if rows := toFloat(obj["Actual Rows"]); rows >= 0 {
+		n.ActualRows = rows * loops
+	}
+	if t := toFloat(obj["Actual Total Time"]); t >= 0 {
+		n.TimeMs = t * loops
+	}
+	if removed := toFloat(obj["Rows Removed by Filter"]); removed >= 0 && n.ActualRows >= 0 {
+		n.RowsScanned = n.ActualRows + removed*loops
+	}
+
+	n.SeqScan = isSeqScanOperation(n.Operation)
+
+	var details []string
+	if jt := toString(obj["Join Type"]); jt != "" {
+		details = append(details, jt+" join")
+	}
+	if idx := toString(obj["Index Name"]); idx != "" {
+		details = append(details, "using "+idx)
+	}
+	for _, key := range []string{"Index Cond", "Hash Cond", "Merge Cond", "Join Filter", "Filter", "Recheck Cond"} {
+		if v := toString(obj[key]); v != "" {
+			details = append(details, strings.ToLower(key)+": "+v)
+		}
+	}
+	if keys, ok := obj["Sort Key"].([]any); ok {
+		details = append(details, "sort key: "+joinAny(keys))
+	}
+	n.Detail = strings.Join(details, ", ")
+
+	if plans, ok := obj["Plans"].([]any); ok {
+		for _, child := range plans {
+			if c, ok := child.(map[string]any); ok {
+				n.Children = append(n.Children, postgresNode(c))
+			}
+		}
+	}
+	return n
+}
+
+// ---------------------------------------------------------------------------
+// MySQL: EXPLAIN FORMAT=JSON
+// ---------------------------------------------------------------------------
+
+// mysqlOperations maps the JSON keys that introduce an operator to its name.
+var mysqlOperations = map[string]string{
+	"query_block":                "Query Block",
+	"nested_loop":                "Nested Loop",
+	"ordering_operation":         "Sort",
+	"grouping_operation":         "Group",
+	"duplicates_removal":         "Distinct",
+	"windowing":                  "Window",
+	"union_result":               "Union",
+	"materialized_from_subquery": "Materialize",
+	"attached_subqueries":        "Subquery",
+	"optimized_away_subqueries":  "Subquery",
+	"query_specifications":       "Union Branch",
+}
+
+func parseMySQL(raw string, p *Plan) error {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return fmt.Errorf("failed to parse mysql plan: %w", err)
+	}
+
+	qb, ok := doc["query_block"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("mysql plan has no query_block")
+	}
+
+	p.Root = mysqlObject("Query Block", qb)
+	fillInclusiveCost(p.Root)
+	return nil
+}
+
+func mysqlObject(operation string, obj map[string]any) *Node {
+	n := newNode(operation)
+	if info, ok := obj["cost_info"].(map[string]any); ok {
+		n.Cost = toFloat(info["query_cost"])
+		if n.Cost < 0 {
+			n.Cost = toFloat(info["sort_cost"])
+		}
+	}
+	if toString(obj["using_filesort"]) == "true" {
+		n.Detail = "using filesort"
+	}
+	if toString(obj["using_temporary_table"]) == "true" {
+		n.Detail = strings.TrimPrefix(n.Detail+", using temporary", ", ")
+	}
+	n.Children = mysqlChildren(obj)
+	return n
+}
+
+func mysqlChildren(obj map[string]any) []*Node {
+	var children []*Node
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := obj[key]
+		if key == "table" {
+			if t, ok := value.(map[string]any); ok {
+				children = append(children, mysqlTable(t))
+			}
+			continue
+		}
+
+		operation, known := mysqlOperations[key]
+		if !known {
+			continue
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			children = append(children, mysqlObject(operation, v))
+		case []any:
+			group := newNode(operation)
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					if qb, ok := m["query_block"].(map[string]any); ok {
+						group.Children = append(group.Children, mysqlObject("Query Block", qb))
+					} else {
+						group.Children = append(group.Children, mysqlChildren(m)...)
+					}
+				}
+			}
+			children = append(children, group)
+		}
+	}
+
+	return children
+}
+
+func mysqlTable(t map[string]any) *Node {
+	access := toString(t["access_type"])
+	n := newNode(mysqlAccessName(access))
+	n.Relation = toString(t["table_name"])
+	n.SeqScan = access == "ALL"
+	n.EstRows = toFloat(t["rows_produced_per_join"])
+	n.RowsScanned = toFloat(t["rows_examined_per_scan"])
+
+	if info, ok := t["cost_info"].(map[string]any); ok {
+		read, eval := toFloat(info["read_cost"]), toFloat(info["eval_cost"])
+		if read >= 0 || eval >= 0 {
+			n.Cost = max(read, 0) + max(eval, 0)
+		}
+	}
+
+	var details []string
+	if key := toString(t["key"]); key != "" {
+		details = append(details, "using "+key)
+	}
+	if cond := toString(t["attached_condition"]); cond != "" {
+		details = append(details, "filter: "+cond)
+	}
+	n.Detail = strings.Join(details, ", ")
+
+	n.Children = mysqlChildren(t)
+	return n
+}
+
+func mysqlAccessName(access string) string {
+	switch access {
+	case "ALL":
+		return "Full Table Scan"
+	case "index":
+		return "Full Index Scan"
+	case "range":
+		return "Index Range Scan"
+	case "ref", "eq_ref", "ref_or_null", "fulltext":
+		return "Index Lookup"
+	case "const", "system":
+		return "Constant Lookup"
+	case "":
+		return "Table"
+	}
+	return "Table (" + access + ")"
+}
+
+// fillInclusiveCost sets the cost of nodes without one to the sum of their
+// children, so grouping nodes (nested loops, sorts) carry a total.
+func fillInclusiveCost(n *Node) float64 {
+	sum := 0.0
+	known := false
+	for _, c := range n.Children {
+		if cost := fillInclusiveCost(c); cost >= 0 {
+			sum += cost
+			known = true
+		}
+	}
+	if n.Cost < 0 && known {
+		n.Cost = sum
+	}
+	return n.Cost
+}
+
+// ---------------------------------------------------------------------------
+// SQLite: EXPLAIN QUERY PLAN (id, parent, notused, detail)
+// ---------------------------------------------------------------------------
+
+func parseSQLite(data [][]string, p *Plan) error {
+	root := newNode("Query Plan")
+	nodes := map[string]*Node{"0": root}
+
+	for _, row := range data {
+		if len(row) < 4 {
+			return fmt.Errorf("unexpected sqlite plan row: %v", row)
+		}
+		id, parent, detail := row[0], row[1], row[3]
+
+		n := sqliteNode(detail)
+		nodes[id] = n
+		if parentNode, ok := nodes[parent]; ok {
+			parentNode.Children = append(parentNode.Children, n)
+		} else {
+			root.Children = append(root.Children, n)
+		}
+	}
+
+	p.Root = root
+	return nil
+}
+
+func sqliteNode(detail string) *Node {
+	fields := strings.Fields(detail)
+	if len(fields) == 0 {
+		return newNode(detail)
+	}
+
+	verb := strings.ToUpper(fields[0])
+	if verb != "SCAN" && verb != "SEARCH" {
+		return newNode(detail)
+	}
+
+	rest := fields[1:]
+	if len(rest) > 0 && strings.EqualFold(rest[0], "TABLE") {
+		rest = rest[1:]
+	}
+
+	n := newNode(strings.ToUpper(verb[:1]) + strings.ToLower(verb[1:]))
+	if len(rest) > 0 {
+		n.Relation = rest[0]
+		n.Detail = strings.Join(rest[1:], " ")
+	}
+	n.SeqScan = verb == "SCAN" && !strings.Contains(strings.ToUpper(detail), "INDEX")
+	return n
+}
+
+// ---------------------------------------------------------------------------
+// DuckDB: EXPLAIN ([ANALYZE,] FORMAT JSON)
+// ---------------------------------------------------------------------------
+
+func parseDuckDB(raw string, p *Plan) error {
+	var doc any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return fmt.Errorf("failed to parse duckdb plan: %w", err)
+	}
+
+	switch v := doc.(type) {
+	case []any:
+		// Plain EXPLAIN: a list of root operators
+		if len(v) == 1 {
+			if m, ok := v[0].(map[string]any); ok {
+				p.Root = duckdbNode(m)
+			}
+		} else {
+			p.Root = newNode("Query Plan")
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					p.Root.Children = append(p.Root.Children, duckdbNode(m))
+				}
+			}
+		}
+	case map[string]any:
+		// EXPLAIN ANALYZE: profiling output with a query-level root
+		p.Root = duckdbNode(v)
+		if p.Root.Operation == "" {
+			p.Root.Operation = "Query"
+		}
+		if latency := toFloat(v["latency"]); latency >= 0 {
+			p.ExecutionTimeMs = latency * 1000
+		}
+	default:
+		return fmt.Errorf("unexpected duckdb plan format")
+	}
+
+	if p.Root != nil {
+		duckdbInclusiveTime(p.Root)
+	}
+	return nil
+}
+
+func duckdbNode(obj map[string]any) *Node {
+	name := toString(obj["operator_name"])
+	if name == "" {
+		name = toString(obj["name"])
+	}
+	if name == "" {
+		name = toString(obj["operator_type"])
+	}
+	n := newNode(strings.TrimSpace(name))
+	n.SeqScan = isSeqScanOperation(n.Operation)
+
+	if t := toFloat(obj["operator_timing"]); t >= 0 {
+		n.TimeMs = t * 1000 // self time in seconds; made inclusive later
+	}
+	n.ActualRows = toFloat(obj["operator_cardinality"])
+	n.RowsScanned = toFloat(obj["operator_rows_scanned"])
+	if n.RowsScanned <= 0 {
+		n.RowsScanned = -1
+	}
+
+	if extra, ok := obj["extra_info"].(map[string]any); ok {
+		n.Extra = map[string]string{}
+		for k, v := range extra {
+			switch k {
+			case "Table":
+				n.Relation = toString(v)
+			case "Estimated Cardinality":
+				n.EstRows = toFloat(v)
+			default:
+				if s := anyToString(v); s != "" {
+					n.Extra[k] = s
+				}
+			}
+		}
+		n.Detail = joinExtra(n.Extra)
+	}
+
+	if children, ok := obj["children"].([]any); ok {
+		for _, child := range children {
+			if c, ok := child.(map[string]any); ok {
+				n.Children = append(n.Children, duckdbNode(c))
+			}
+		}
+	}
+	return n
+}
+
+// duckdbInclusiveTime converts DuckDB's per-operator timings into inclusive
+// times so they compare like other dialects.
+func duckdbInclusiveTime(n *Node) float64 {
+	total := n.TimeMs
+	for _, c := range n.Children {
+		if t := duckdbInclusiveTime(c); t >= 0 {
+			total = max(total, 0) + t
+		}
+	}
+	n.TimeMs = total
+	return total
+}
+
+// ---------------------------------------------------------------------------
+// SQL Server: SET SHOWPLAN_XML ON
+// ---------------------------------------------------------------------------
+
+type xmlElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Children []xmlElement `xml:",any"`
+}
+
+func (e xmlElement) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseSQLServer(raw string, p *Plan) error {
+	var doc xmlElement
+	if err := xml.Unmarshal([]byte(raw), &doc); err != nil {
+		return fmt.Errorf("failed to parse showplan xml: %w", err)
+	}
+
+	var roots []*Node
+	var findRoots func(e xmlElement)
+	findRoots = func(e xmlElement) {
+		if e.XMLName.Local == "RelOp" {
+			roots = append(roots, sqlServerRelOp(e))
+			return
+		}
+		for _, c := range e.Children {
+			findRoots(c)
+		}
+	}
+	findRoots(doc)
+
+	switch len(roots) {
+	case 0:
+		return fmt.Errorf("showplan contains no operators")
+	case 1:
+		p.Root = roots[0]
+	default:
+		p.Root = newNode("Batch")
+		p.Root.Children = roots
+		fillInclusiveCost(p.Root)
+	}
+	return nil
+}
+
+func sqlServerRelOp(e xmlElement) *Node {
+	n := newNode(e.attr("PhysicalOp"))
+	if logical := e.attr("LogicalOp"); logical != "" && logical != n.Operation {
+		n.Detail = logical
+	}
+	n.Cost = parseFloat(e.attr("EstimatedTotalSubtreeCost"))
+	n.EstRows = parseFloat(e.attr("EstimateRows"))
+	n.RowsScanned = parseFloat(e.attr("TableCardinality"))
+	if n.RowsScanned < 0 {
+		n.RowsScanned = parseFloat(e.attr("EstimatedRowsRead"))
+	}
+	n.SeqScan = n.Operation == "Table Scan" || n.Operation == "Clustered Index Scan"
+
+	// Walk this operator's subtree, stopping at nested operators
+	var walk func(el xmlElement)
+	walk = func(el xmlElement) {
+		for _, c := range el.Children {
+			switch c.XMLName.Local {
+			case "RelOp":
+				n.Children = append(n.Children, sqlServerRelOp(c))
+			case "Object":
+				if n.Relation == "" {
+					n.Relation = strings.Trim(c.attr("Table"), "[]")
+					if idx := strings.Trim(c.attr("Index"), "[]"); idx != "" {
+						n.Detail = strings.TrimPrefix(n.Detail+", using "+idx, ", ")
+					}
+				}
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(e)
+	return n
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// toFloat converts JSON numbers and numeric strings; -1 means unknown.
+func toFloat(v any) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case int:
+		return float64(x)
+	case string:
+		return parseFloat(x)
+	}
+	return -1
+}
+
+func parseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return -1
+	}
+	return f
+}
+
+func toString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return ""
+}
+
+func anyToString(v any) string {
+	if list, ok := v.([]any); ok {
+		return joinAny(list)
+	}
+	return toString(v)
+}
+
+func joinAny(list []any) string {
+	parts := make([]string, 0, len(list))
+	for _, item := range list {
+		if s := toString(item); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinExtra(extra map[string]string) string {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, strings.ToLower(k)+": "+strings.ReplaceAll(extra[k], "\n", " "))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,216 @@
+package plan
+
+import "testing"
+
+func TestParsePostgres(t *testing.T) {
+	raw := `[{"Plan": {"Node Type": "Hash Join", "Join Type": "Inner", "Total Cost": 250.5,
+		"Plan Rows": 100, "Actual Rows": 90, "Actual Total Time": 12.0, "Actual Loops": 1,
+		"Hash Cond": "(o.user_id = u.id)",
+		"Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 200, "Plan Rows": 50000,
+			 "Actual Rows": 50000, "Actual Total Time": 9.0, "Actual Loops": 1, "Rows Removed by Filter": 0},
+			{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8.3,
+			 "Plan Rows": 1, "Actual Rows": 1, "Actual Total Time": 0.01, "Actual Loops": 90}
+		]},
+		"Planning Time": 0.2, "Execution Time": 12.5}]`
+
+	p := &Plan{}
+	if err := parsePostgres(raw, p); err != nil {
+		t.Fatalf("parsePostgres() error = %v", err)
+	}
+	p.Analyze()
+
+	if p.Root.Operation != "Hash Join" || len(p.Root.Children) != 2 {
+		t.Fatalf("root = %s with %d children, want Hash Join with 2", p.Root.Operation, len(p.Root.Children))
+	}
+	if p.ExecutionTimeMs != 12.5 {
+		t.Errorf("ExecutionTimeMs = %v, want 12.5", p.ExecutionTimeMs)
+	}
+
+	scan := p.Root.Children[0]
+	if !scan.SeqScan || !scan.LargeSeqScan {
+		t.Errorf("orders scan SeqScan=%v LargeSeqScan=%v, want both true", scan.SeqScan, scan.LargeSeqScan)
+	}
+	if !scan.Hot {
+		t.Errorf("orders scan should be flagged as the most expensive node")
+	}
+
+	lookup := p.Root.Children[1]
+	if lookup.ActualRows != 90 {
+		t.Errorf("index scan ActualRows = %v, want 90 (rows x loops)", lookup.ActualRows)
+	}
+	if lookup.SeqScan {
+		t.Errorf("index scan must not be a seq scan")
+	}
+}
+
+func TestParseMySQL(t *testing.T) {
+	raw := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "1210.50"},
+		"ordering_operation": {"using_filesort": true,
+			"nested_loop": [
+				{"table": {"table_name": "o", "access_type": "ALL", "rows_examined_per_scan": 20000,
+					"rows_produced_per_join": 2000, "cost_info": {"read_cost": "1000.00", "eval_cost": "200.00"}}},
+				{"table": {"table_name": "u", "access_type": "eq_ref", "key": "PRIMARY",
+					"rows_examined_per_scan": 1, "rows_produced_per_join": 2000,
+					"cost_info": {"read_cost": "5.00", "eval_cost": "5.50"}}}
+			]}}}`
+
+	p := &Plan{}
+	if err := parseMySQL(raw, p); err != nil {
+		t.Fatalf("parseMySQL() error = %v", err)
+	}
+	p.Analyze()
+
+	if p.Root.Cost != 1210.5 {
+		t.Errorf("root cost = %v, want 1210.5", p.Root.Cost)
+	}
+
+	sortNode := p.Root.Children[0]
+	if sortNode.Operation != "Sort" || sortNode.Detail != "using filesort" {
+		t.Fatalf("first child = %q (%q), want Sort using filesort", sortNode.Operation, sortNode.Detail)
+	}
+
+	loop := sortNode.Children[0]
+	if loop.Operation != "Nested Loop" || len(loop.Children) != 2 {
+		t.Fatalf("nested loop = %q with %d children", loop.Operation, len(loop.Children))
+	}
+	if loop.Cost != 1210.5 {
+		t.Errorf("nested loop cost = %v, want sum of children 1210.5", loop.Cost)
+	}
+
+	full := loop.Children[0]
+	if full.Operation != "Full Table Scan" || !full.LargeSeqScan || !full.Hot {
+		t.Errorf("o: op=%q large=%v hot=%v", full.Operation, full.LargeSeqScan, full.Hot)
+	}
+	if loop.Children[1].Operation != "Index Lookup" {
+		t.Errorf("u: op=%q, want Index Lookup", loop.Children[1].Operation)
+	}
+}
+
+func TestParseSQLite(t *testing.T) {
+	data := [][]string{
+		{"3", "0", "0", "SCAN a"},
+		{"5", "0", "0", "SEARCH b USING INDEX bi (a_id=?)"},
+		{"7", "0", "0", "SCAN TABLE c USING COVERING INDEX ci"},
+		{"9", "0", "0", "USE TEMP B-TREE FOR ORDER BY"},
+	}
+
+	p := &Plan{}
+	if err := parseSQLite(data, p); err != nil {
+		t.Fatalf("parseSQLite() error = %v", err)
+	}
+
+	tests := []struct {
+		op, relation string
+		seqScan      bool
+	}{
+		{"Scan", "a", true},
+		{"Search", "b", false},
+		{"Scan", "c", false},
+		{"USE TEMP B-TREE FOR ORDER BY", "", false},
+	}
+
+	if len(p.Root.Children) != len(tests) {
+		t.Fatalf("got %d nodes, want %d", len(p.Root.Children), len(tests))
+	}
+	for i, tt := range tests {
+		n := p.Root.Children[i]
+		if n.Operation != tt.op || n.Relation != tt.relation || n.SeqScan != tt.seqScan {
+			t.Errorf("node %d = {%q %q %v}, want {%q %q %v}", i, n.Operation, n.Relation, n.SeqScan, tt.op, tt.relation, tt.seqScan)
+		}
+	}
+}
+
+func TestParseDuckDBAnalyze(t *testing.T) {
+	raw := `{"latency": 0.004, "children": [
+		{"operator_name": "PROJECTION", "operator_timing": 0.001, "operator_cardinality": 3,
+		 "extra_info": {"Projections": ["id", "name"]},
+		 "children": [
+			{"operator_name": "SEQ_SCAN ", "operator_timing": 0.002, "operator_cardinality": 3,
+			 "operator_rows_scanned": 20000, "extra_info": {"Table": "users", "Estimated Cardinality": "3"}, "children": []}
+		 ]}
+	]}`
+
+	p := &Plan{}
+	if err := parseDuckDB(raw, p); err != nil {
+		t.Fatalf("parseDuckDB() error = %v", err)
+	}
+	p.Analyze()
+
+	proj := p.Root.Children[0]
+	if proj.TimeMs != 3 {
+		t.Errorf("projection inclusive time = %v, want 3", proj.TimeMs)
+	}
+	scan := proj.Children[0]
+	if scan.Operation != "SEQ_SCAN" || scan.Relation != "users" || !scan.LargeSeqScan {
+		t.Errorf("scan = {%q %q large=%v}", scan.Operation, scan.Relation, scan.LargeSeqScan)
+	}
+	if proj.Detail != "projections: id, name" {
+		t.Errorf("projection detail = %q", proj.Detail)
+	}
+}
+
+func TestParseSQLServer(t *testing.T) {
+	raw := `<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan"><BatchSequence><Batch><Statements>
+		<StmtSimple StatementText="select"><QueryPlan>
+			<RelOp PhysicalOp="Nested Loops" LogicalOp="Inner Join" EstimateRows="10" EstimatedTotalSubtreeCost="0.5">
+				<NestedLoops>
+					<RelOp PhysicalOp="Clustered Index Scan" LogicalOp="Clustered Index Scan" EstimateRows="50000"
+						EstimatedTotalSubtreeCost="0.4" TableCardinality="50000">
+						<IndexScan><Object Database="[db]" Schema="[dbo]" Table="[orders]" Index="[PK_orders]"/></IndexScan>
+					</RelOp>
+					<RelOp PhysicalOp="Index Seek" LogicalOp="Index Seek" EstimateRows="1" EstimatedTotalSubtreeCost="0.01">
+						<IndexScan><Object Table="[users]" Index="[PK_users]"/></IndexScan>
+					</RelOp>
+				</NestedLoops>
+			</RelOp>
+		</QueryPlan></StmtSimple>
+	</Statements></Batch></BatchSequence></ShowPlanXML>`
+
+	p := &Plan{}
+	if err := parseSQLServer(raw, p); err != nil {
+		t.Fatalf("parseSQLServer() error = %v", err)
+	}
+	p.Analyze()
+
+	if p.Root.Operation != "Nested Loops" || len(p.Root.Children) != 2 {
+		t.Fatalf("root = %q with %d children", p.Root.Operation, len(p.Root.Children))
+	}
+	scan := p.Root.Children[0]
+	if scan.Relation != "orders" || !scan.LargeSeqScan || !scan.Hot {
+		t.Errorf("orders scan = {%q large=%v hot=%v}", scan.Relation, scan.LargeSeqScan, scan.Hot)
+	}
+	if seek := p.Root.Children[1]; seek.Relation != "users" || seek.SeqScan {
+		t.Errorf("users seek = {%q seq=%v}", seek.Relation, seek.SeqScan)
+	}
+}
+
+func TestExplainSQL(t *testing.T) {
+	tests := []struct {
+		dbType  string
+		analyze bool
+		want    string
+	}{
+		{"postgres", true, "EXPLAIN (FORMAT JSON, ANALYZE) SELECT 1"},
+		{"postgres", false, "EXPLAIN (FORMAT JSON) SELECT 1"},
+		{"mysql", true, "EXPLAIN FORMAT=JSON SELECT 1"},
+		{"sqlite", false, "EXPLAIN QUERY PLAN SELECT 1"},
+		{"duckdb", true, "EXPLAIN (ANALYZE, FORMAT JSON) SELECT 1"},
+		{"sqlserver", false, "SELECT 1"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExplainSQL(tt.dbType, "SELECT 1;", tt.analyze)
+		if err != nil {
+			t.Errorf("ExplainSQL(%s) error = %v", tt.dbType, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExplainSQL(%s, %v) = %q, want %q", tt.dbType, tt.analyze, got, tt.want)
+		}
+	}
+
+	if _, err := ExplainSQL("oracle", "SELECT 1", false); err == nil {
+		t.Errorf("ExplainSQL(oracle) should return an error")
+	}
+}
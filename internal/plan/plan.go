@@ -0,0 +1,156 @@
+// Package plan runs dialect-specific EXPLAIN statements and normalizes their
+// output into a common tree that can be rendered in the terminal.
+package plan
+
+import "strings"
+
+// LargeTableRows is the row count from which a sequential scan is flagged.
+const LargeTableRows = 10000
+
+// hotShare is the fraction of the total (time or cost) from which a node's
+// own work is highlighted as expensive.
+const hotShare = 0.2
+
+// Node is one operator of a query plan. Numeric fields are negative when the
+// dialect does not report them.
+type Node struct {
+	Operation   string            `json:"operation"`
+	Relation    string            `json:"relation,omitempty"`
+	Detail      string            `json:"detail,omitempty"`
+	Cost        float64           `json:"cost"`        // estimated total cost, including children
+	EstRows     float64           `json:"est_rows"`    // planner row estimate
+	ActualRows  float64           `json:"actual_rows"` // rows produced (ANALYZE only)
+	RowsScanned float64           `json:"rows_scanned"`
+	TimeMs      float64           `json:"time_ms"` // inclusive time (ANALYZE only)
+	Loops       float64           `json:"loops"`
+	SeqScan     bool              `json:"seq_scan"`
+	Extra       map[string]string `json:"extra,omitempty"`
+	Children    []*Node           `json:"children,omitempty"`
+
+	// Derived by Analyze
+	SelfCost     float64 `json:"self_cost"`
+	SelfTimeMs   float64 `json:"self_time_ms"`
+	Hot          bool    `json:"hot"`
+	LargeSeqScan bool    `json:"large_seq_scan"`
+}
+
+// Plan is a parsed query plan.
+type Plan struct {
+	DbType          string  `json:"db_type"`
+	SQL             string  `json:"sql"`
+	Analyzed        bool    `json:"analyzed"`
+	PlanningTimeMs  float64 `json:"planning_time_ms"`
+	ExecutionTimeMs float64 `json:"execution_time_ms"`
+	Root            *Node   `json:"root"`
+}
+
+func newNode(operation string) *Node {
+	return &Node{
+		Operation:   operation,
+		Cost:        -1,
+		EstRows:     -1,
+		ActualRows:  -1,
+		RowsScanned: -1,
+		TimeMs:      -1,
+		Loops:       -1,
+	}
+}
+
+// Walk calls fn for every node in depth-first order.
+func (n *Node) Walk(fn func(node *Node, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *Node) walk(fn func(node *Node, depth int), depth int) {
+	fn(n, depth)
+	for _, c := range n.Children {
+		c.walk(fn, depth+1)
+	}
+}
+
+// Rows returns the best known row count: actual when analyzed, else estimate.
+func (n *Node) Rows() float64 {
+	if n.ActualRows >= 0 {
+		return n.ActualRows
+	}
+	return n.EstRows
+}
+
+// Analyze derives self cost/time and flags expensive nodes and sequential
+// scans on large tables.
+func (p *Plan) Analyze() {
+	if p.Root == nil {
+		return
+	}
+
+	p.Root.Walk(func(n *Node, _ int) {
+		n.SelfCost = selfValue(n, func(c *Node) float64 { return c.Cost })
+		n.SelfTimeMs = selfValue(n, func(c *Node) float64 { return c.TimeMs })
+	})
+
+	useTime := p.Root.TimeMs > 0
+	total := p.Root.Cost
+	if useTime {
+		total = p.Root.TimeMs
+	}
+
+	var hottest *Node
+	p.Root.Walk(func(n *Node, _ int) {
+		self := n.SelfCost
+		if useTime {
+			self = n.SelfTimeMs
+		}
+		if total > 0 && self >= total*hotShare {
+			n.Hot = true
+		}
+		if self > 0 && (hottest == nil || self > selfOf(hottest, useTime)) {
+			hottest = n
+		}
+
+		scanned := n.RowsScanned
+		if scanned < 0 {
+			scanned = n.Rows()
+		}
+		n.LargeSeqScan = n.SeqScan && scanned >= LargeTableRows
+	})
+
+	if hottest != nil {
+		hottest.Hot = true
+	}
+}
+
+func selfOf(n *Node, useTime bool) float64 {
+	if useTime {
+		return n.SelfTimeMs
+	}
+	return n.SelfCost
+}
+
+// selfValue subtracts the children's inclusive value from the node's own.
+func selfValue(n *Node, value func(*Node) float64) float64 {
+	v := value(n)
+	if v < 0 {
+		return -1
+	}
+	for _, c := range n.Children {
+		if cv := value(c); cv > 0 {
+			v -= cv
+		}
+	}
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// isSeqScanOperation reports whether an operator name denotes a full scan.
+func isSeqScanOperation(op string) bool {
+	upper := strings.ToUpper(op)
+	switch {
+	case strings.Contains(upper, "SEQ SCAN"), strings.Contains(upper, "SEQ_SCAN"):
+		return true
+	case upper == "TABLE SCAN", upper == "TABLE_SCAN", upper == "FULL SCAN":
+		return true
+	}
+	return false
+}
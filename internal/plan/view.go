@@ -0,0 +1,419 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/parser"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// metricsWidth is the width of the right-hand cost/rows/time columns.
+const metricsWidth = 44
+
+type line struct {
+	node   *Node
+	prefix string
+}
+
+// Model is the interactive plan tree viewer.
+type Model struct {
+	plan       *Plan
+	collapsed  map[*Node]bool
+	lines      []line
+	cursor     int
+	offset     int
+	width      int
+	height     int
+	showDetail bool
+}
+
+func NewModel(p *Plan) Model {
+	m := Model{
+		plan:       p,
+		collapsed:  map[*Node]bool{},
+		showDetail: true,
+	}
+	m.lines = m.buildLines()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.clampOffset()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.lines)-1 {
+				m.cursor++
+			}
+		case "g", "home":
+			m.cursor = 0
+		case "G", "end":
+			m.cursor = len(m.lines) - 1
+		case "enter", " ":
+			m = m.toggle(m.currentNode(), !m.collapsed[m.currentNode()])
+		case "h", "left":
+			m = m.toggle(m.currentNode(), true)
+		case "l", "right":
+			m = m.toggle(m.currentNode(), false)
+		case "n":
+			m = m.jumpToFlagged()
+		case "d":
+			m.showDetail = !m.showDetail
+		}
+		m = m.clampOffset()
+	}
+
+	return m, nil
+}
+
+func (m Model) currentNode() *Node {
+	if m.cursor < 0 || m.cursor >= len(m.lines) {
+		return nil
+	}
+	return m.lines[m.cursor].node
+}
+
+func (m Model) toggle(n *Node, collapse bool) Model {
+	if n == nil || len(n.Children) == 0 {
+		return m
+	}
+	m.collapsed[n] = collapse
+	m.lines = m.buildLines()
+	return m
+}
+
+// jumpToFlagged moves the cursor to the next hot node or large seq scan.
+func (m Model) jumpToFlagged() Model {
+	for i := 1; i <= len(m.lines); i++ {
+		idx := (m.cursor + i) % len(m.lines)
+		if n := m.lines[idx].node; n.Hot || n.LargeSeqScan {
+			m.cursor = idx
+			return m
+		}
+	}
+	return m
+}
+
+func (m Model) visibleLines() int {
+	// header (3) + column header (1) + footer (2) + detail pane
+	reserved := 6
+	if m.showDetail {
+		reserved += 4
+	}
+	return max(m.height-reserved, 3)
+}
+
+func (m Model) clampOffset() Model {
+	visible := m.visibleLines()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+	return m
+}
+
+func (m Model) buildLines() []line {
+	var lines []line
+	var walk func(n *Node, prefix, childPrefix string)
+	walk = func(n *Node, prefix, childPrefix string) {
+		lines = append(lines, line{node: n, prefix: prefix})
+		if m.collapsed[n] {
+			return
+		}
+		for i, c := range n.Children {
+			if i == len(n.Children)-1 {
+				walk(c, childPrefix+"└── ", childPrefix+"    ")
+			} else {
+				walk(c, childPrefix+"├── ", childPrefix+"│   ")
+			}
+		}
+	}
+	if m.plan.Root != nil {
+		walk(m.plan.Root, "", "")
+	}
+	return lines
+}
+
+func (m Model) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("◆ Query plan"))
+	b.WriteString(" " + styles.Faint.Render(summary(m.plan)))
+	b.WriteString("\n")
+	sql := strings.Join(strings.Fields(m.plan.SQL), " ")
+	if len(sql) > m.width-2 && m.width > 5 {
+		sql = sql[:m.width-5] + "..."
+	}
+	b.WriteString(parser.HighlightSQL(sql))
+	b.WriteString("\n")
+	b.WriteString(styles.Separator.Render(strings.Repeat("─", max(m.width-1, 1))))
+	b.WriteString("\n")
+
+	treeWidth := max(m.width-metricsWidth-1, 20)
+	b.WriteString(styles.TableHeader.Render(padRight("Operation", treeWidth)))
+	b.WriteString(styles.TableHeader.Render(metricsHeader()))
+	b.WriteString("\n")
+
+	end := min(m.offset+m.visibleLines(), len(m.lines))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.renderLine(m.lines[i], i == m.cursor, treeWidth))
+		b.WriteString("\n")
+	}
+
+	if m.showDetail {
+		b.WriteString(m.renderDetail())
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.renderFooter())
+	return b.String()
+}
+
+func (m Model) renderLine(l line, selected bool, treeWidth int) string {
+	n := l.node
+
+	label := n.Operation
+	if n.Relation != "" {
+		label += " on " + n.Relation
+	}
+	if len(n.Children) > 0 && m.collapsed[n] {
+		label += fmt.Sprintf(" (+%d)", countNodes(n)-1)
+	}
+
+	marker := "  "
+	switch {
+	case n.LargeSeqScan:
+		marker = "⚠ "
+	case n.Hot:
+		marker = "▲ "
+	}
+
+	text := padRight(truncate(marker+l.prefix+label, treeWidth), treeWidth)
+	metrics := m.metricsColumns(n)
+
+	if selected {
+		return styles.TableSelected.Render(text + metrics)
+	}
+
+	var styled string
+	switch {
+	case n.LargeSeqScan, n.Hot:
+		styled = styles.Error.Render(text)
+	case n.SeqScan:
+		styled = styles.SearchMatch.Render(text)
+	default:
+		styled = styles.TableCell.Render(text)
+	}
+	return styled + styles.Faint.Render(metrics)
+}
+
+func metricsHeader() string {
+	return fmt.Sprintf("%10s %10s %10s %10s", "cost", "rows", "time ms", "self %")
+}
+
+func (m Model) metricsColumns(n *Node) string {
+	rows := formatMetric(n.EstRows)
+	if n.ActualRows >= 0 {
+		rows = formatMetric(n.ActualRows)
+	}
+	return fmt.Sprintf(
+		"%10s %10s %10s %10s",
+		formatMetric(n.Cost),
+		rows,
+		formatMetric(n.TimeMs),
+		formatShare(m.plan, n),
+	)
+}
+
+func (m Model) renderDetail() string {
+	n := m.currentNode()
+	if n == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Separator.Render(strings.Repeat("─", max(m.width-1, 1))))
+	b.WriteString("\n")
+
+	var facts []string
+	if n.EstRows >= 0 {
+		facts = append(facts, "est rows "+formatMetric(n.EstRows))
+	}
+	if n.ActualRows >= 0 {
+		facts = append(facts, "actual rows "+formatMetric(n.ActualRows))
+	}
+	if n.RowsScanned >= 0 {
+		facts = append(facts, "rows scanned "+formatMetric(n.RowsScanned))
+	}
+	if n.Loops >= 0 {
+		facts = append(facts, "loops "+formatMetric(n.Loops))
+	}
+	if n.SelfTimeMs >= 0 && n.TimeMs >= 0 {
+		facts = append(facts, "self time "+formatMetric(n.SelfTimeMs)+"ms")
+	} else if n.SelfCost >= 0 && n.Cost >= 0 {
+		facts = append(facts, "self cost "+formatMetric(n.SelfCost))
+	}
+	if n.LargeSeqScan {
+		facts = append(facts, styles.Error.Render("sequential scan on a large table"))
+	} else if n.Hot {
+		facts = append(facts, styles.Error.Render("expensive node"))
+	}
+
+	b.WriteString(styles.Title.Render(n.Operation) + " " + styles.Faint.Render(strings.Join(facts, " · ")))
+	b.WriteString("\n")
+
+	detail := n.Detail
+	if detail == "" {
+		detail = "—"
+	}
+	wrapped := lipgloss.NewStyle().Width(max(m.width-2, 10)).Render(detail)
+	detailLines := strings.Split(wrapped, "\n")
+	if len(detailLines) > 2 {
+		detailLines = detailLines[:2]
+		detailLines[1] = truncate(detailLines[1], max(m.width-5, 5)) + "..."
+	}
+	b.WriteString(styles.Faint.Render(strings.Join(detailLines, "\n")))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m Model) renderFooter() string {
+	key := func(k, desc string) string {
+		return styles.TableHeader.Render(k) + styles.Faint.Render(desc)
+	}
+	legend := styles.Error.Render("▲") + styles.Faint.Render(" expensive  ") +
+		styles.Error.Render("⚠") + styles.Faint.Render(fmt.Sprintf(" seq scan ≥ %d rows", LargeTableRows))
+
+	return strings.Join([]string{
+		key("jk", " move"),
+		key("enter", " fold"),
+		key("h/l", " collapse/expand"),
+		key("n", "ext hotspot"),
+		key("d", "etail"),
+		key("q", "uit"),
+	}, "  ") + "   " + legend
+}
+
+// Render runs the interactive plan viewer.
+func Render(p *Plan) error {
+	program := tea.NewProgram(NewModel(p), tea.WithAltScreen())
+	_, err := program.Run()
+	return err
+}
+
+// RenderText returns a non-interactive rendering of the plan tree, used when
+// output is piped or --format text is requested.
+func RenderText(p *Plan) string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("◆ Query plan") + " " + styles.Faint.Render(summary(p)) + "\n")
+
+	m := NewModel(p)
+	treeWidth := 60
+	b.WriteString(styles.TableHeader.Render(padRight("Operation", treeWidth) + metricsHeader()))
+	b.WriteString("\n")
+	for _, l := range m.lines {
+		b.WriteString(m.renderLine(l, false, treeWidth))
+		if l.node.Detail != "" {
+			b.WriteString("\n" + strings.Repeat(" ", len([]rune(l.prefix))+4) + styles.Faint.Render(truncate(l.node.Detail, 100)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func summary(p *Plan) string {
+	parts := []string{p.DbType}
+	if p.Analyzed {
+		parts = append(parts, "analyzed")
+	} else {
+		parts = append(parts, "estimated")
+	}
+	if p.PlanningTimeMs > 0 {
+		parts = append(parts, fmt.Sprintf("planning %.2fms", p.PlanningTimeMs))
+	}
+	if p.ExecutionTimeMs > 0 {
+		parts = append(parts, fmt.Sprintf("execution %.2fms", p.ExecutionTimeMs))
+	}
+	if p.Root != nil {
+		parts = append(parts, fmt.Sprintf("%d nodes", countNodes(p.Root)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func countNodes(n *Node) int {
+	count := 0
+	n.Walk(func(*Node, int) { count++ })
+	return count
+}
+
+// formatShare returns the node's own work as a share of the plan total.
+func formatShare(p *Plan, n *Node) string {
+	if p.Root == nil {
+		return "-"
+	}
+	if p.Root.TimeMs > 0 && n.SelfTimeMs >= 0 {
+		return fmt.Sprintf("%.1f", n.SelfTimeMs/p.Root.TimeMs*100)
+	}
+	if p.Root.Cost > 0 && n.SelfCost >= 0 {
+		return fmt.Sprintf("%.1f", n.SelfCost/p.Root.Cost*100)
+	}
+	return "-"
+}
+
+func formatMetric(v float64) string {
+	switch {
+	case v < 0:
+		return "-"
+	case v >= 1e9:
+		return fmt.Sprintf("%.1fG", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case v >= 1e4:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	case v == float64(int64(v)):
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func padRight(s string, width int) string {
+	if pad := width - lipgloss.Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
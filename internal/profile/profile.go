@@ -0,0 +1,324 @@
+// Package profile computes column statistics, either over rows already
+// loaded in memory or on the server through generated aggregate queries.
+package profile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultTopN is how many frequent values a report lists
+	DefaultTopN = 5
+	// DefaultBuckets is the histogram resolution for numeric columns. Columns
+	// with no more distinct values than buckets get no histogram; their top
+	// values already show the distribution.
+	DefaultBuckets = 8
+)
+
+// Source tells where statistics were computed
+const (
+	SourceLoaded = "loaded"
+	SourceServer = "server"
+)
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type Bucket struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int64   `json:"count"`
+}
+
+// ColumnStats is the profile of a single column. Min, Max and Avg are empty
+// when the column has no non-null values or the database cannot compare
+// its type.
+type ColumnStats struct {
+	Column    string       `json:"column"`
+	Type      string       `json:"type,omitempty"`
+	Numeric   bool         `json:"numeric"`
+	Rows      int64        `json:"rows"`
+	Nulls     int64        `json:"nulls"`
+	Distinct  int64        `json:"distinct"`
+	Min       string       `json:"min,omitempty"`
+	Max       string       `json:"max,omitempty"`
+	Avg       *float64     `json:"avg,omitempty"`
+	Top       []ValueCount `json:"top,omitempty"`
+	Histogram []Bucket     `json:"histogram,omitempty"`
+	Source    string       `json:"source"`
+}
+
+// IsNumericType reports whether a database type name holds numbers.
+func IsNumericType(typeName string) bool {
+	t := strings.ToLower(typeName)
+	for _, prefix := range []string{"bool", "bit", "interval", "point"} {
+		if strings.HasPrefix(t, prefix) {
+			return false
+		}
+	}
+	for _, part := range []string{
+		"int", "numeric", "decimal", "number", "float", "double", "real",
+		"money", "serial", "hugeint",
+	} {
+		if strings.Contains(t, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// Compute profiles values already loaded in memory. Cells equal to "NULL"
+// count as nulls, matching how result sets are formatted for display.
+func Compute(column, typeName string, values []string, topN, buckets int) ColumnStats {
+	stats := ColumnStats{
+		Column: column,
+		Type:   typeName,
+		Rows:   int64(len(values)),
+		Source: SourceLoaded,
+	}
+
+	counts := map[string]int64{}
+	var numbers []float64
+	allNumeric := true
+
+	for _, v := range values {
+		if v == "NULL" {
+			stats.Nulls++
+			continue
+		}
+		counts[v]++
+		if f, ok := parseNumber(v); ok {
+			numbers = append(numbers, f)
+		} else {
+			allNumeric = false
+		}
+	}
+	stats.Distinct = int64(len(counts))
+
+	nonNull := stats.Rows - stats.Nulls
+	// Digits stored as text (zip codes, phone numbers) are not averaged
+	stats.Numeric = nonNull > 0 && allNumeric && !isTextType(typeName)
+
+	if nonNull > 0 {
+		if stats.Numeric {
+			lo, hi, sum := numbers[0], numbers[0], 0.0
+			for _, f := range numbers {
+				lo = math.Min(lo, f)
+				hi = math.Max(hi, f)
+				sum += f
+			}
+			avg := sum / float64(len(numbers))
+			stats.Min = formatNumber(lo)
+			stats.Max = formatNumber(hi)
+			stats.Avg = &avg
+			if stats.Distinct > int64(buckets) {
+				stats.Histogram = histogram(numbers, lo, hi, buckets)
+			}
+		} else {
+			first := true
+			for v := range counts {
+				if first || v < stats.Min {
+					stats.Min = v
+				}
+				if first || v > stats.Max {
+					stats.Max = v
+				}
+				first = false
+			}
+		}
+	}
+
+	for v, c := range counts {
+		stats.Top = append(stats.Top, ValueCount{Value: v, Count: c})
+	}
+	sortTop(stats.Top)
+	if len(stats.Top) > topN {
+		stats.Top = stats.Top[:topN]
+	}
+
+	return stats
+}
+
+func isTextType(typeName string) bool {
+	t := strings.ToLower(typeName)
+	for _, part := range []string{"char", "text", "string", "clob", "uuid"} {
+		if strings.Contains(t, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortTop(top []ValueCount) {
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Value < top[j].Value
+	})
+}
+
+// BucketBounds splits [lo, hi] into n equal-width ranges.
+func BucketBounds(lo, hi float64, n int) []Bucket {
+	if n < 1 {
+		n = 1
+	}
+	if hi <= lo {
+		return []Bucket{{Low: lo, High: hi}}
+	}
+	width := (hi - lo) / float64(n)
+	bounds := make([]Bucket, n)
+	for i := range bounds {
+		bounds[i] = Bucket{Low: lo + float64(i)*width, High: lo + float64(i+1)*width}
+	}
+	bounds[n-1].High = hi
+	return bounds
+}
+
+func bucketIndex(bounds []Bucket, f float64) int {
+	for i := range bounds[:len(bounds)-1] {
+		if f < bounds[i].High {
+			return i
+		}
+	}
+	return len(bounds) - 1
+}
+
+func histogram(numbers []float64, lo, hi float64, n int) []Bucket {
+	bounds := BucketBounds(lo, hi, n)
+	for _, f := range numbers {
+		bounds[bucketIndex(bounds, f)].Count++
+	}
+	return bounds
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// FormatFloat renders an average or bucket bound compactly.
+func FormatFloat(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+// Bar draws a horizontal bar of count relative to peak, padded with spaces
+// to width cells.
+func Bar(count, peak int64, width int) string {
+	n := 0
+	if peak > 0 && count > 0 {
+		n = int(math.Round(float64(count) / float64(peak) * float64(width)))
+		n = min(max(n, 1), width)
+	}
+	return strings.Repeat("█", n) + strings.Repeat(" ", width-n)
+}
+
+// Percent formats part as a share of total.
+func Percent(part, total int64) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)/float64(total)*100)
+}
+
+// Markdown renders a table profile as a Markdown report.
+func Markdown(table string, columns []ColumnStats) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Profile: %s\n\n", table)
+	if len(columns) > 0 {
+		fmt.Fprintf(&b, "%d rows, %d columns\n\n", columns[0].Rows, len(columns))
+	}
+
+	b.WriteString("| Column | Type | Nulls | Distinct | Min | Max | Avg |\n")
+	b.WriteString("|--------|------|-------|----------|-----|-----|-----|\n")
+	for _, c := range columns {
+		avg := ""
+		if c.Avg != nil {
+			avg = FormatFloat(*c.Avg)
+		}
+		fmt.Fprintf(
+			&b,
+			"| %s | %s | %d (%s) | %d | %s | %s | %s |\n",
+			mdEscape(c.Column),
+			mdEscape(c.Type),
+			c.Nulls,
+			Percent(c.Nulls, c.Rows),
+			c.Distinct,
+			mdEscape(truncate(c.Min, 40)),
+			mdEscape(truncate(c.Max, 40)),
+			avg,
+		)
+	}
+
+	for _, c := range columns {
+		if len(c.Top) == 0 && len(c.Histogram) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", c.Column)
+
+		if len(c.Top) > 0 {
+			b.WriteString("\n| Top value | Count | Share |\n")
+			b.WriteString("|-----------|-------|-------|\n")
+			for _, t := range c.Top {
+				fmt.Fprintf(
+					&b,
+					"| %s | %d | %s |\n",
+					mdEscape(truncate(t.Value, 60)),
+					t.Count,
+					Percent(t.Count, c.Rows),
+				)
+			}
+		}
+
+		if len(c.Histogram) > 0 {
+			var peak int64
+			for _, h := range c.Histogram {
+				peak = max(peak, h.Count)
+			}
+			b.WriteString("\n```\n")
+			for _, h := range c.Histogram {
+				fmt.Fprintf(
+					&b,
+					"%12s – %-12s %s %d\n",
+					FormatFloat(h.Low),
+					FormatFloat(h.High),
+					Bar(h.Count, peak, 20),
+					h.Count,
+				)
+			}
+			b.WriteString("```\n")
+		}
+	}
+
+	return b.String()
+}
+
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
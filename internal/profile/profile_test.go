@@ -0,0 +1,93 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompute_Numeric(t *testing.T) {
+	values := []string{"10", "20", "20", "NULL", "50", "100"}
+	s := Compute("amount", "NUMERIC", values, 2, 3)
+
+	if s.Rows != 6 || s.Nulls != 1 || s.Distinct != 4 {
+		t.Errorf("rows/nulls/distinct = %d/%d/%d, want 6/1/4", s.Rows, s.Nulls, s.Distinct)
+	}
+	if !s.Numeric || s.Min != "10" || s.Max != "100" {
+		t.Errorf("numeric=%v min=%q max=%q", s.Numeric, s.Min, s.Max)
+	}
+	if s.Avg == nil || *s.Avg != 40 {
+		t.Errorf("avg = %v, want 40", s.Avg)
+	}
+	if len(s.Top) != 2 || s.Top[0].Value != "20" || s.Top[0].Count != 2 {
+		t.Errorf("top = %+v, want 20 (2) first", s.Top)
+	}
+
+	var counts []int64
+	for _, b := range s.Histogram {
+		counts = append(counts, b.Count)
+	}
+	want := []int64{3, 1, 1}
+	for i := range want {
+		if i >= len(counts) || counts[i] != want[i] {
+			t.Fatalf("histogram counts = %v, want %v", counts, want)
+		}
+	}
+}
+
+func TestCompute_FewDistinctValues(t *testing.T) {
+	s := Compute("id", "INTEGER", []string{"1", "2", "3"}, 5, 8)
+
+	if len(s.Histogram) != 0 {
+		t.Errorf("3 distinct values should not get an 8-bucket histogram")
+	}
+}
+
+func TestCompute_Text(t *testing.T) {
+	s := Compute("zip", "VARCHAR", []string{"01310", "20040", "01310"}, 5, 8)
+
+	if s.Numeric || s.Avg != nil || len(s.Histogram) != 0 {
+		t.Errorf("digits stored as text should not be treated as numbers: %+v", s)
+	}
+	if s.Min != "01310" || s.Max != "20040" {
+		t.Errorf("min/max = %q/%q", s.Min, s.Max)
+	}
+}
+
+func TestCompute_AllNull(t *testing.T) {
+	s := Compute("deleted_at", "", []string{"NULL", "NULL"}, 5, 8)
+
+	if s.Nulls != 2 || s.Distinct != 0 || s.Min != "" || len(s.Top) != 0 {
+		t.Errorf("all-null column = %+v", s)
+	}
+}
+
+func TestServerSQL(t *testing.T) {
+	got := SummarySQL("orders", `"amount"`, true, true)
+	want := `SELECT COUNT(*), COUNT("amount"), COUNT(DISTINCT "amount"), MIN("amount"), MAX("amount"), AVG("amount" * 1.0) FROM orders`
+	if got != want {
+		t.Errorf("SummarySQL =\n%s\nwant\n%s", got, want)
+	}
+
+	hist := HistogramSQL("orders", "x", BucketBounds(0, 30, 3))
+	if !strings.Contains(hist, "CASE WHEN x < 10 THEN 0 WHEN x < 20 THEN 1 ELSE 2 END") {
+		t.Errorf("HistogramSQL = %s", hist)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	avg := 2.5
+	md := Markdown("orders", []ColumnStats{
+		{Column: "amount", Type: "int", Rows: 4, Nulls: 1, Distinct: 2, Min: "1", Max: "4", Avg: &avg,
+			Top: []ValueCount{{Value: "a|b", Count: 2}}},
+	})
+
+	for _, want := range []string{
+		"# Profile: orders",
+		"| amount | int | 1 (25.0%) | 2 | 1 | 4 | 2.50 |",
+		"| a\\|b | 2 | 50.0% |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+// RenderColumn renders a column profile for the terminal: summary fields,
+// top values and, for numeric columns, a histogram.
+func RenderColumn(s ColumnStats, source string) string {
+	var b strings.Builder
+
+	title := "◆ " + s.Column
+	if s.Type != "" {
+		title += " (" + s.Type + ")"
+	}
+	b.WriteString(styles.Title.Render(title))
+	if source != "" {
+		b.WriteString(styles.Faint.Render("  " + source))
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Separator.Render(strings.Repeat("─", 50)))
+	b.WriteString("\n")
+
+	field := func(label, value string) {
+		b.WriteString(styles.TableHeader.Render(fmt.Sprintf("%-10s", label)))
+		b.WriteString(" " + value + "\n")
+	}
+	field("Rows", fmt.Sprintf("%d", s.Rows))
+	field("Nulls", fmt.Sprintf("%d (%s)", s.Nulls, Percent(s.Nulls, s.Rows)))
+	if s.Distinct >= 0 {
+		field("Distinct", fmt.Sprintf("%d", s.Distinct))
+	}
+	if s.Min != "" || s.Max != "" {
+		field("Min", truncate(oneLine(s.Min), 40))
+		field("Max", truncate(oneLine(s.Max), 40))
+	}
+	if s.Avg != nil {
+		field("Avg", FormatFloat(*s.Avg))
+	}
+
+	if len(s.Top) > 0 {
+		b.WriteString("\n" + styles.Title.Render("Top values") + "\n")
+		peak := s.Top[0].Count
+		for _, t := range s.Top {
+			fmt.Fprintf(
+				&b,
+				"  %-20s %s %s\n",
+				truncate(oneLine(t.Value), 20),
+				styles.TableSelected.Render(Bar(t.Count, peak, 20)),
+				styles.Faint.Render(fmt.Sprintf("%d (%s)", t.Count, Percent(t.Count, s.Rows))),
+			)
+		}
+	}
+
+	if len(s.Histogram) > 0 {
+		b.WriteString("\n" + styles.Title.Render("Histogram") + "\n")
+		var peak int64
+		for _, h := range s.Histogram {
+			peak = max(peak, h.Count)
+		}
+		for _, h := range s.Histogram {
+			fmt.Fprintf(
+				&b,
+				"  %10s – %-10s %s %s\n",
+				FormatFloat(h.Low),
+				FormatFloat(h.High),
+				styles.TableSelected.Render(Bar(h.Count, peak, 20)),
+				styles.Faint.Render(fmt.Sprintf("%d", h.Count)),
+			)
+		}
+	}
+
+	return b.String()
+}
+
+func oneLine(s string) string {
+	return strings.ReplaceAll(s, "\n", " ")
+}
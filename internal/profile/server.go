@@ -0,0 +1,165 @@
+package profile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// SummarySQL builds the aggregate query for row, null and distinct counts.
+// With minMax it also selects MIN/MAX, and AVG when the column is numeric.
+func SummarySQL(table, col string, minMax, numeric bool) string {
+	exprs := []string{
+		"COUNT(*)",
+		fmt.Sprintf("COUNT(%s)", col),
+		fmt.Sprintf("COUNT(DISTINCT %s)", col),
+	}
+	if minMax {
+		exprs = append(exprs, fmt.Sprintf("MIN(%s)", col), fmt.Sprintf("MAX(%s)", col))
+		if numeric {
+			// * 1.0 keeps integer averages from being truncated (SQL Server)
+			exprs = append(exprs, fmt.Sprintf("AVG(%s * 1.0)", col))
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), table)
+}
+
+// TopSQL builds the query for the most frequent non-null values.
+func TopSQL(table, col string) string {
+	return fmt.Sprintf(
+		"SELECT %s, COUNT(*) AS cnt FROM %s WHERE %s IS NOT NULL GROUP BY %s ORDER BY COUNT(*) DESC",
+		col, table, col, col,
+	)
+}
+
+// HistogramSQL counts values per bucket with a portable CASE expression.
+func HistogramSQL(table, col string, bounds []Bucket) string {
+	var c strings.Builder
+	c.WriteString("CASE")
+	for i, b := range bounds[:len(bounds)-1] {
+		fmt.Fprintf(&c, " WHEN %s < %s THEN %d", col, formatNumber(b.High), i)
+	}
+	fmt.Fprintf(&c, " ELSE %d END", len(bounds)-1)
+
+	bucket := c.String()
+	return fmt.Sprintf(
+		"SELECT %s, COUNT(*) FROM %s WHERE %s IS NOT NULL GROUP BY %s",
+		bucket, table, col, bucket,
+	)
+}
+
+func queryRows(conn db.DatabaseConnection, sql string) ([][]string, error) {
+	rows, err := conn.ExecQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	_, data, err := db.FormatTableData(rows)
+	return data, err
+}
+
+func parseCount(s string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		f, _ := parseNumber(s)
+		return int64(f)
+	}
+	return n
+}
+
+// Server profiles a column of table with aggregate queries, so the whole
+// table is covered rather than only the rows loaded. Types the database
+// cannot compare or count distinctly degrade to the counts it can compute;
+// Distinct is -1 when unknown.
+func Server(
+	conn db.DatabaseConnection,
+	table, column, typeName string,
+	topN, buckets int,
+) (ColumnStats, error) {
+	dialect, err := db.NormalizeDialect(conn.GetDbType())
+	if err != nil {
+		dialect = conn.GetDbType()
+	}
+	col := db.QuoteIdentifierFor(dialect, column)
+	numeric := IsNumericType(typeName)
+
+	stats := ColumnStats{
+		Column:   column,
+		Type:     typeName,
+		Numeric:  numeric,
+		Distinct: -1,
+		Source:   SourceServer,
+	}
+
+	data, err := queryRows(conn, SummarySQL(table, col, true, numeric))
+	minMax := err == nil
+	if err != nil {
+		data, err = queryRows(conn, SummarySQL(table, col, false, false))
+	}
+	if err != nil {
+		// e.g. COUNT(DISTINCT) over JSON or LOB columns
+		data, err = queryRows(
+			conn,
+			fmt.Sprintf("SELECT COUNT(*), COUNT(%s) FROM %s", col, table),
+		)
+	}
+	if err != nil {
+		return stats, err
+	}
+	if len(data) == 0 || len(data[0]) < 2 {
+		return stats, fmt.Errorf("no result for %s", column)
+	}
+
+	row := data[0]
+	stats.Rows = parseCount(row[0])
+	stats.Nulls = stats.Rows - parseCount(row[1])
+	if len(row) > 2 {
+		stats.Distinct = parseCount(row[2])
+	}
+	if minMax {
+		if row[3] != "NULL" {
+			stats.Min = row[3]
+		}
+		if row[4] != "NULL" {
+			stats.Max = row[4]
+		}
+		if numeric && len(row) > 5 {
+			if avg, ok := parseNumber(row[5]); ok {
+				stats.Avg = &avg
+			}
+		}
+	}
+
+	if topN > 0 && stats.Rows > stats.Nulls {
+		if top, err := queryRows(conn, conn.ApplyRowLimit(TopSQL(table, col), topN)); err == nil {
+			for _, r := range top {
+				stats.Top = append(stats.Top, ValueCount{Value: r[0], Count: parseCount(r[1])})
+			}
+		}
+	}
+
+	lo, okLo := parseNumber(stats.Min)
+	hi, okHi := parseNumber(stats.Max)
+	if numeric && okLo && okHi && buckets > 0 &&
+		(stats.Distinct < 0 || stats.Distinct > int64(buckets)) {
+		bounds := BucketBounds(lo, hi, buckets)
+		if len(bounds) == 1 {
+			// One bucket holds every value; no query needed
+			bounds[0].Count = stats.Rows - stats.Nulls
+			stats.Histogram = bounds
+		} else if hist, err := queryRows(conn, HistogramSQL(table, col, bounds)); err == nil {
+			for _, r := range hist {
+				i := int(parseCount(r[0]))
+				if i >= 0 && i < len(bounds) {
+					bounds[i].Count = parseCount(r[1])
+				}
+			}
+			stats.Histogram = bounds
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,107 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/chart"
+	"github.com/caiolandgraf/pam/internal/styles"
+)
+
+const (
+	defaultChartHeight = 20
+	defaultSVGWidth    = 900
+	defaultSVGHeight   = 450
+)
+
+// ExecuteChart runs a SELECT and draws its result as a chart, printed to
+// the terminal or written as SVG.
+func ExecuteChart(params ExecutionParams, opts ChartOptions) error {
+	if err := params.Connection.Open(); err != nil {
+		return fmt.Errorf("could not open connection to %s/%s: %w", params.Connection.GetDbType(), params.Connection.GetName(), err)
+	}
+	defer params.Connection.Close()
+
+	if len(params.Statements) > 1 {
+		return fmt.Errorf("charts need a single SELECT statement, got %d", len(params.Statements))
+	}
+	if !IsSelectQuery(params.Query.SQL) {
+		return fmt.Errorf("charts need a SELECT query")
+	}
+
+	kind, err := chart.ParseKind(opts.Kind)
+	if err != nil {
+		return err
+	}
+
+	columns, columnTypes, data, err := fetchResult(params.Query.SQL, params)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		fmt.Fprintln(os.Stderr, "No results found")
+		return nil
+	}
+
+	d, err := chart.Build(columns, columnTypes, data, opts.X, opts.Y)
+	if err != nil {
+		return err
+	}
+
+	if opts.SVG {
+		width, height := opts.Width, opts.Height
+		if width == 0 {
+			width = defaultSVGWidth
+		}
+		if height == 0 {
+			height = defaultSVGHeight
+		}
+
+		path := opts.Output
+		if path == "" {
+			path = chartFileName(params.Query.Name)
+		}
+		title := params.Query.Name
+		if strings.HasPrefix(title, "<") {
+			title = ""
+		}
+		svg := chart.SVG(kind, d, width, height, title)
+		if err := os.WriteFile(path, []byte(svg), 0o644); err != nil {
+			return fmt.Errorf("could not write chart: %w", err)
+		}
+		fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Chart written to %s", path)))
+		return nil
+	}
+
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = terminalWidth()
+	}
+	if height == 0 {
+		height = defaultChartHeight
+	}
+	fmt.Println(chart.Render(kind, d, width, height))
+	return nil
+}
+
+// terminalWidth reads $COLUMNS, which most shells export, falling back to 80.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// chartFileName derives the default SVG path from the query name.
+func chartFileName(queryName string) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(queryName, "_"), "_")
+	if name == "" || strings.HasPrefix(queryName, "<") {
+		name = "chart"
+	}
+	return name + ".svg"
+}
@@ -3,7 +3,7 @@ package run
 import "strings"
 
 func IsSelectQuery(sql string) bool {
-	upper := strings.ToUpper(strings.TrimSpace(sql))
+	upper := strings.ToUpper(stripLeadingComments(sql))
 	keywords := []string{"SELECT", "WITH", "SHOW", "DESCRIBE", "DESC", "EXPLAIN", "PRAGMA"}
 
 	for _, kw := range keywords {
@@ -15,7 +15,7 @@ func IsSelectQuery(sql string) bool {
 }
 
 func IsLikelySQL(s string) bool {
-	upper := strings.ToUpper(strings.TrimSpace(s))
+	upper := strings.ToUpper(stripLeadingComments(s))
 	keywords := []string{
 		"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER", "TRUNCATE",
 		"WITH", "SHOW", "DESCRIBE", "DESC", "EXPLAIN", "GRANT", "REVOKE",
@@ -29,3 +29,110 @@ func IsLikelySQL(s string) bool {
 	}
 	return false
 }
+
+// stripLeadingComments drops the comments before the first statement, such
+// as a parameter's "-- @param ... lookup:" declaration.
+func stripLeadingComments(sql string) string {
+	sql = strings.TrimSpace(sql)
+	for {
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			end := strings.IndexByte(sql, '\n')
+			if end < 0 {
+				return ""
+			}
+			sql = strings.TrimSpace(sql[end+1:])
+		case strings.HasPrefix(sql, "/*"):
+			end := strings.Index(sql, "*/")
+			if end < 0 {
+				return ""
+			}
+			sql = strings.TrimSpace(sql[end+2:])
+		default:
+			return sql
+		}
+	}
+}
+
+// writeKeywords modify data or schema wherever they appear in a statement
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true,
+	"REPLACE": true, "TRUNCATE": true, "DROP": true, "ALTER": true, "CREATE": true,
+	"INTO": true, "GRANT": true, "REVOKE": true, "CALL": true, "EXEC": true,
+	"EXECUTE": true, "ANALYZE": true, "VACUUM": true,
+}
+
+// writeFunctions are write keywords that are also string functions, such
+// as replace(name, 'a', 'b'); called, they only read
+var writeFunctions = map[string]bool{"REPLACE": true, "INSERT": true}
+
+// IsReadOnlyQuery is a stricter IsSelectQuery for running a statement
+// unattended: a query such as WITH ... DELETE ... RETURNING reads like a
+// SELECT but writes, so any write keyword outside literals and comments
+// makes it false. SELECT ... FOR UPDATE and calls to string functions such
+// as replace() still count as reads.
+func IsReadOnlyQuery(sql string) bool {
+	if !IsSelectQuery(sql) {
+		return false
+	}
+	words := sqlWords(sql)
+	for i, word := range words {
+		if word == "UPDATE" && i > 0 && (words[i-1] == "FOR" || words[i-1] == "KEY") {
+			continue
+		}
+		// PRAGMA name = value sets rather than reads
+		if word == "=" && words[0] == "PRAGMA" {
+			return false
+		}
+		if writeFunctions[word] && i+1 < len(words) && words[i+1] == "(" {
+			continue
+		}
+		if writeKeywords[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// sqlWords splits sql into upper-cased words, "=" signs and opening
+// parentheses, skipping quoted strings, quoted identifiers and comments
+func sqlWords(sql string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			flush()
+			for i++; i < len(sql) && sql[i] != c; i++ {
+			}
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			flush()
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			flush()
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return words
+			}
+			i += end + 3
+		case c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			word.WriteByte(c)
+		case c == '=' || c == '(':
+			flush()
+			words = append(words, string(c))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
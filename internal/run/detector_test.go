@@ -0,0 +1,33 @@
+package run
+
+import "testing"
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	for sql, want := range map[string]bool{
+		"SELECT * FROM orders":                                             true,
+		"-- @param id\nselect * from orders where id = :id":                true,
+		"WITH recent AS (SELECT * FROM orders) SELECT * FROM recent":       true,
+		"SELECT * FROM orders WHERE note = 'delete me' -- update later":    true,
+		`SELECT "insert" FROM audit`:                                       true,
+		"SELECT * FROM orders FOR UPDATE":                                  true,
+		"SELECT * FROM orders FOR NO KEY UPDATE":                           true,
+		"PRAGMA table_info(orders)":                                        true,
+		"PRAGMA journal_mode = WAL":                                        false,
+		"WITH gone AS (DELETE FROM orders RETURNING *) SELECT * FROM gone": false,
+		"WITH t AS (SELECT 1) UPDATE orders SET total = 0 RETURNING id":    false,
+		"SELECT * INTO archive FROM orders":                                false,
+		"EXPLAIN ANALYZE SELECT * FROM orders":                             false,
+		"UPDATE orders SET total = 0":                                      false,
+		"DROP TABLE orders":                                                false,
+		"SELECT replace(name,'a','b') FROM users":                          true,
+		"SELECT REPLACE (name, 'a', 'b') FROM users":                       true,
+		"SELECT insert(name, 1, 2, 'x'), upper(name) FROM users":           true,
+		"SELECT count(*), coalesce(total, 0) FROM orders":                  true,
+		"SELECT replace(name, 'a', 'b') INTO copy FROM users":              false,
+		"WITH t AS (INSERT INTO log VALUES (1) RETURNING *) SELECT 1":      false,
+	} {
+		if got := IsReadOnlyQuery(sql); got != want {
+			t.Errorf("IsReadOnlyQuery(%q) = %v, want %v", sql, got, want)
+		}
+	}
+}
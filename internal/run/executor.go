@@ -10,6 +10,7 @@ import (
 
 	"github.com/caiolandgraf/pam/internal/config"
 	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/mask"
 	"github.com/caiolandgraf/pam/internal/parser"
 	"github.com/caiolandgraf/pam/internal/spinner"
 	"github.com/caiolandgraf/pam/internal/styles"
@@ -24,8 +25,9 @@ type ExecutionParams struct {
 	Config       *config.Config
 	SaveCallback SaveQueryCallback
 	OnRerun      func(editedSQL string) error
-	Args         []any  // Arguments for parameterized queries
-	DisplaySQL   string // Human-readable SQL with values substituted (for TUI display)
+	Args         []any       // Arguments for parameterized queries
+	DisplaySQL   string      // Human-readable SQL with values substituted (for TUI display)
+	Statements   []Statement // Set when Query.SQL is a multi-statement script
 }
 
 func ExecuteSelect(sql, queryName string, params ExecutionParams) error {
@@ -83,8 +85,9 @@ func ExecuteSelect(sql, queryName string, params ExecutionParams) error {
 
 	// Create query object
 	q := db.Query{
-		Name: queryName,
-		SQL:  sql,
+		Name:     queryName,
+		SQL:      sql,
+		Metadata: params.Query.Metadata,
 	}
 	if params.Query.Id != 0 {
 		q.Id = params.Query.Id
@@ -172,6 +175,10 @@ func ExecuteExport(params ExecutionParams, format string) error {
 }
 
 func ExecuteExportWithOpenConn(params ExecutionParams, format string) error {
+	if len(params.Statements) > 1 {
+		return executeExportScript(params, format)
+	}
+
 	if IsSelectQuery(params.Query.SQL) {
 		return executeExportSelect(params.Query.SQL, params, format)
 	}
@@ -200,26 +207,9 @@ func executeExportSelect(sql string, params ExecutionParams, format string) erro
 		tableName, _ = extractMetadata(params.Connection, params.Query)
 	}
 
-	// Apply row limit
-	if params.Config.DefaultRowLimit > 0 {
-		sql = params.Connection.ApplyRowLimit(sql, params.Config.DefaultRowLimit)
-	}
-
-	// Execute query
-	var err error
-	var rows any
-	if params.Args != nil && len(params.Args) > 0 {
-		rows, err = params.Connection.ExecQuery(sql, params.Args...)
-	} else {
-		rows, err = params.Connection.ExecQuery(sql)
-	}
-	if err != nil {
-		return fmt.Errorf("query execution failed: %w", err)
-	}
-
-	columns, _, data, err := db.FormatTableDataWithTypes(rows.(*stdlib.Rows))
+	columns, _, data, err := fetchResult(sql, params)
 	if err != nil {
-		return fmt.Errorf("formatting failed: %w", err)
+		return err
 	}
 
 	if len(data) == 0 {
@@ -234,6 +224,7 @@ func executeExportSelect(sql string, params ExecutionParams, format string) erro
 		TableName: tableName,
 	}
 
+	data = maskedData(sql, columns, data)
 	content, err := table.FormatExport(columns, data, format, opts)
 	if err != nil {
 		return fmt.Errorf("export failed: %w", err)
@@ -243,7 +234,36 @@ func executeExportSelect(sql string, params ExecutionParams, format string) erro
 	return nil
 }
 
+// fetchResult runs a SELECT for non-interactive output, applying the
+// default row limit.
+func fetchResult(sql string, params ExecutionParams) ([]string, []string, [][]string, error) {
+	if params.Config.DefaultRowLimit > 0 {
+		sql = params.Connection.ApplyRowLimit(sql, params.Config.DefaultRowLimit)
+	}
+
+	var err error
+	var rows any
+	if params.Args != nil && len(params.Args) > 0 {
+		rows, err = params.Connection.ExecQuery(sql, params.Args...)
+	} else {
+		rows, err = params.Connection.ExecQuery(sql)
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("query execution failed: %w", err)
+	}
+	defer rows.(*stdlib.Rows).Close()
+
+	columns, columnTypes, data, err := db.FormatTableDataWithTypes(rows.(*stdlib.Rows))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("formatting failed: %w", err)
+	}
+	return columns, columnTypes, data, nil
+}
+
 func ExecuteWithOpenConn(params ExecutionParams) error {
+	if len(params.Statements) > 1 {
+		return ExecuteScript(params)
+	}
 	if IsSelectQuery(params.Query.SQL) {
 		return ExecuteSelect(params.Query.SQL, params.Query.Name, params)
 	}
@@ -251,6 +271,16 @@ func ExecuteWithOpenConn(params ExecutionParams) error {
 	return nil
 }
 
+// maskedData applies the configured masking rules to a result before it is
+// written out. Table rules match the table the query selects from.
+func maskedData(sql string, columns []string, data [][]string) [][]string {
+	tableName := db.ExtractTableNameFromSQL(sql)
+	if tableName == "" {
+		tableName = db.ExtractPrimaryTableFromJoin(sql)
+	}
+	return mask.Active.Rows(tableName, columns, data)
+}
+
 func extractMetadata(
 	conn db.DatabaseConnection,
 	query db.Query,
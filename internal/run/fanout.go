@@ -0,0 +1,300 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/spinner"
+	"github.com/caiolandgraf/pam/internal/styles"
+	"github.com/caiolandgraf/pam/internal/table"
+)
+
+// ConnectionColumn is prepended to fan-out results with the member name
+const ConnectionColumn = "_connection"
+
+// DefaultParallelism caps how many group members are queried at once
+const DefaultParallelism = 4
+
+// FanOutTarget is one group member with the query prepared for its dialect.
+type FanOutTarget struct {
+	Connection db.DatabaseConnection
+	SQL        string
+	Args       []any
+}
+
+// FanOutFailure is a member whose query failed; the others still run.
+type FanOutFailure struct {
+	Connection string
+	Err        error
+}
+
+// FanOutResult is the merged result of a fan-out run.
+type FanOutResult struct {
+	Columns     []string
+	ColumnTypes []string
+	Data        [][]string
+	Failures    []FanOutFailure
+	Succeeded   int
+	// Executed names the members the statement ran on, in target order
+	Executed []string
+}
+
+type memberResult struct {
+	columns     []string
+	columnTypes []string
+	data        [][]string
+	err         error
+}
+
+// FanOutParams configures ExecuteFanOut. Rerun rebuilds the targets for SQL
+// edited in the table view.
+type FanOutParams struct {
+	Group       string
+	Query       db.Query
+	Targets     []FanOutTarget
+	Parallelism int
+	Config      *config.Config
+	Format      string
+	// AllowWrites lets statements other than reads run on the members; the
+	// caller sets it once the user has confirmed
+	AllowWrites bool
+	// Rerun rebuilds the targets for edited SQL and must confirm a write
+	// itself
+	Rerun func(editedSQL string) ([]FanOutTarget, error)
+}
+
+// FanOut runs each target's query on its own connection, at most
+// parallelism at a time, and merges the results in target order.
+func FanOut(targets []FanOutTarget, parallelism, rowLimit int) FanOutResult {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	results := make([]memberResult, len(targets))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target FanOutTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = runMember(target, rowLimit)
+		}(i, target)
+	}
+	wg.Wait()
+
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.Connection.GetName()
+	}
+	return mergeResults(names, results)
+}
+
+func runMember(target FanOutTarget, rowLimit int) memberResult {
+	conn := target.Connection
+	if err := conn.Open(); err != nil {
+		return memberResult{err: fmt.Errorf("could not open connection: %w", err)}
+	}
+	defer conn.Close()
+
+	if !IsSelectQuery(target.SQL) {
+		if err := conn.Exec(target.SQL, target.Args...); err != nil {
+			return memberResult{err: err}
+		}
+		return memberResult{}
+	}
+
+	sql := target.SQL
+	if rowLimit > 0 {
+		sql = conn.ApplyRowLimit(sql, rowLimit)
+	}
+	rows, err := conn.ExecQuery(sql, target.Args...)
+	if err != nil {
+		return memberResult{err: err}
+	}
+	defer rows.Close()
+
+	columns, columnTypes, data, err := db.FormatTableDataWithTypes(rows)
+	return memberResult{columns: columns, columnTypes: columnTypes, data: data, err: err}
+}
+
+// mergeResults unions the members' columns by name, in order of first
+// appearance, behind a leading _connection column. A column a member does
+// not return is NULL in its rows.
+func mergeResults(names []string, results []memberResult) FanOutResult {
+	merged := FanOutResult{
+		Columns:     []string{ConnectionColumn},
+		ColumnTypes: []string{"TEXT"},
+	}
+	index := map[string]int{}
+
+	for i, r := range results {
+		if r.err != nil {
+			merged.Failures = append(merged.Failures, FanOutFailure{Connection: names[i], Err: r.err})
+			continue
+		}
+		merged.Succeeded++
+		merged.Executed = append(merged.Executed, names[i])
+
+		positions := make([]int, len(r.columns))
+		for j, col := range r.columns {
+			pos, ok := index[col]
+			if !ok {
+				pos = len(merged.Columns)
+				index[col] = pos
+				merged.Columns = append(merged.Columns, col)
+				merged.ColumnTypes = append(merged.ColumnTypes, "")
+			}
+			if merged.ColumnTypes[pos] == "" && j < len(r.columnTypes) {
+				merged.ColumnTypes[pos] = r.columnTypes[j]
+			}
+			positions[j] = pos
+		}
+
+		for _, row := range r.data {
+			out := make([]string, len(merged.Columns))
+			for k := range out {
+				out[k] = "NULL"
+			}
+			out[0] = names[i]
+			for j, value := range row {
+				out[positions[j]] = value
+			}
+			merged.Data = append(merged.Data, out)
+		}
+	}
+
+	// Rows merged before a later member added columns are shorter
+	for i, row := range merged.Data {
+		for len(row) < len(merged.Columns) {
+			row = append(row, "NULL")
+		}
+		merged.Data[i] = row
+	}
+	return merged
+}
+
+// failureSummary is a one-line status for the table view.
+func failureSummary(failures []FanOutFailure) string {
+	names := make([]string, len(failures))
+	for i, f := range failures {
+		names[i] = f.Connection
+	}
+	return styles.Error.Render(fmt.Sprintf("✗ %d failed: %s", len(failures), strings.Join(names, ", ")))
+}
+
+func reportFailures(failures []FanOutFailure) {
+	for _, f := range failures {
+		fmt.Fprintln(os.Stderr, styles.Error.Render(fmt.Sprintf("✗ %s: %v", f.Connection, f.Err)))
+	}
+}
+
+// reportWrite lists the members a write ran on, and warns when it only ran
+// on some of them, since the group is then out of step
+func reportWrite(w io.Writer, res FanOutResult) {
+	for _, name := range res.Executed {
+		fmt.Fprintf(w, "%s %s\n", styles.Success.Render("✓"), name)
+	}
+	if len(res.Failures) > 0 && len(res.Executed) > 0 {
+		fmt.Fprintln(os.Stderr, styles.Error.Render(fmt.Sprintf(
+			"The statement ran on %s but not on the %d failed connection(s); the group is now inconsistent",
+			strings.Join(res.Executed, ", "), len(res.Failures),
+		)))
+	}
+}
+
+// ExecuteFanOut runs a query against every member of a group and shows the
+// merged result in the table view, or prints it with params.Format.
+// Failures are reported per connection on stderr; the run only fails when
+// every member does. A statement that writes is refused unless
+// params.AllowWrites is set.
+func ExecuteFanOut(params FanOutParams) error {
+	if !params.AllowWrites && !IsReadOnlyQuery(params.Query.SQL) {
+		return fmt.Errorf("refusing to run a write on every connection of group '%s' without confirmation", params.Group)
+	}
+	rowLimit := params.Config.DefaultRowLimit
+
+	if params.Format != "" {
+		start := time.Now()
+		res := FanOut(params.Targets, params.Parallelism, rowLimit)
+		reportFailures(res.Failures)
+		fmt.Fprintf(os.Stderr, "%d/%d connections in %.2fs\n", res.Succeeded, len(params.Targets), time.Since(start).Seconds())
+		if res.Succeeded == 0 {
+			return fmt.Errorf("query failed on every connection in group '%s'", params.Group)
+		}
+		if !IsSelectQuery(params.Query.SQL) {
+			reportWrite(os.Stderr, res)
+			return nil
+		}
+		if len(res.Data) == 0 {
+			fmt.Fprintln(os.Stderr, "No results found")
+			return nil
+		}
+
+		data := maskedData(params.Query.SQL, res.Columns, res.Data)
+		content, err := table.FormatExport(res.Columns, data, params.Format, table.FormatOptions{
+			QueryName: params.Query.Name,
+			DbType:    "group",
+			DbName:    params.Group,
+		})
+		if err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
+		fmt.Print(content)
+		return nil
+	}
+
+	targets := params.Targets
+	query := params.Query
+	for {
+		start := time.Now()
+		done := make(chan struct{})
+		go spinner.CircleWaitWithTimer(done)
+		res := FanOut(targets, params.Parallelism, rowLimit)
+		done <- struct{}{}
+		fmt.Print("\r\033[2K")
+		elapsed := time.Since(start)
+
+		reportFailures(res.Failures)
+		if res.Succeeded == 0 {
+			return fmt.Errorf("query failed on every connection in group '%s'", params.Group)
+		}
+		if !IsSelectQuery(query.SQL) {
+			reportWrite(os.Stdout, res)
+			fmt.Println(styles.Success.Render(fmt.Sprintf("✓ Executed on %d/%d connections in %.2fs", res.Succeeded, len(targets), elapsed.Seconds())))
+			return nil
+		}
+		if len(res.Data) == 0 {
+			fmt.Println("No results found")
+			return nil
+		}
+
+		statusMessage := ""
+		if len(res.Failures) > 0 {
+			statusMessage = failureSummary(res.Failures)
+		}
+
+		// The first member stands in for export metadata; without a table
+		// name the merged rows are read-only
+		model, err := table.Render(res.Columns, res.ColumnTypes, res.Data, elapsed, targets[0].Connection, "", "", query, params.Config.DefaultColumnWidth, params.Config.UIVisibility, nil, statusMessage)
+		if err != nil {
+			return fmt.Errorf("error rendering table: %w", err)
+		}
+		if !model.ShouldRerunQuery() || params.Rerun == nil {
+			return nil
+		}
+
+		query.SQL = model.GetEditedQuery().SQL
+		targets, err = params.Rerun(query.SQL)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package run
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestMergeResults(t *testing.T) {
+	res := mergeResults(
+		[]string{"a", "b", "c"},
+		[]memberResult{
+			{columns: []string{"id", "ok"}, columnTypes: []string{"INTEGER", "INTEGER"}, data: [][]string{{"1", "1"}}},
+			{err: errors.New("no such table")},
+			{columns: []string{"id", "note"}, columnTypes: []string{"INTEGER", "TEXT"}, data: [][]string{{"2", "x"}}},
+		},
+	)
+
+	if want := []string{ConnectionColumn, "id", "ok", "note"}; !reflect.DeepEqual(res.Columns, want) {
+		t.Errorf("columns = %v, want %v", res.Columns, want)
+	}
+	if want := []string{"TEXT", "INTEGER", "INTEGER", "TEXT"}; !reflect.DeepEqual(res.ColumnTypes, want) {
+		t.Errorf("types = %v, want %v", res.ColumnTypes, want)
+	}
+	want := [][]string{{"a", "1", "1", "NULL"}, {"c", "2", "NULL", "x"}}
+	if !reflect.DeepEqual(res.Data, want) {
+		t.Errorf("data = %v, want %v", res.Data, want)
+	}
+	if res.Succeeded != 2 || len(res.Failures) != 1 || res.Failures[0].Connection != "b" {
+		t.Errorf("succeeded=%d failures=%+v", res.Succeeded, res.Failures)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(res.Executed, want) {
+		t.Errorf("executed = %v, want %v", res.Executed, want)
+	}
+}
+
+func TestExecuteFanOut_RefusesUnconfirmedWrites(t *testing.T) {
+	for _, sql := range []string{
+		"DELETE FROM orders",
+		"WITH gone AS (DELETE FROM orders RETURNING *) SELECT * FROM gone",
+	} {
+		err := ExecuteFanOut(FanOutParams{Group: "tenants", Query: db.Query{SQL: sql}})
+		if err == nil {
+			t.Errorf("%q ran without confirmation", sql)
+		}
+	}
+}
+
+func TestFanOut_ReportsFailuresWithoutAborting(t *testing.T) {
+	dir := t.TempDir()
+	var targets []FanOutTarget
+	for _, name := range []string{"t1", "t2", "broken"} {
+		conn, err := db.NewSQLiteConnection(name, filepath.Join(dir, name+".db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != "broken" {
+			conn.Open()
+			if err := conn.Exec("CREATE TABLE health (ok INTEGER); INSERT INTO health VALUES (1)"); err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+		}
+		targets = append(targets, FanOutTarget{Connection: conn, SQL: "SELECT ok FROM health"})
+	}
+
+	res := FanOut(targets, 2, 10)
+	if res.Succeeded != 2 || len(res.Failures) != 1 || res.Failures[0].Connection != "broken" {
+		t.Fatalf("succeeded=%d failures=%+v", res.Succeeded, res.Failures)
+	}
+	if want := [][]string{{"t1", "1"}, {"t2", "1"}}; !reflect.DeepEqual(res.Data, want) {
+		t.Errorf("rows should keep member order, got %v", res.Data)
+	}
+}
@@ -0,0 +1,287 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/parser"
+	"github.com/caiolandgraf/pam/internal/spinner"
+	"github.com/caiolandgraf/pam/internal/styles"
+	"github.com/caiolandgraf/pam/internal/table"
+)
+
+// Statement is one statement of a multi-statement script, with parameters
+// already substituted for the target connection.
+type Statement struct {
+	SQL        string
+	Args       []any
+	DisplaySQL string // Human-readable SQL with values substituted
+}
+
+// StatementResult is the outcome of running one script statement.
+type StatementResult struct {
+	Index        int
+	SQL          string
+	IsSelect     bool
+	Columns      []string
+	ColumnTypes  []string
+	Data         [][]string
+	RowsAffected int64 // -1 when the driver cannot report it
+	Elapsed      time.Duration
+	Err          error
+}
+
+// Preparer is implemented by connections that rewrite a statement before
+// running it, such as file mode's, which loads the files a query reads.
+type Preparer interface {
+	Prepare(sql string) (string, error)
+}
+
+// SplitScript splits SQL text into statements. A single statement (with or
+// without a trailing semicolon) yields a slice of length one.
+func SplitScript(sql string) []string {
+	return db.SplitSQLStatements(sql)
+}
+
+// runScript executes statements in order on a single pinned connection so
+// session state carries over between them. onResult is called after each
+// statement; execution stops at the first failing statement.
+func runScript(
+	params ExecutionParams,
+	applyRowLimit bool,
+	onResult func(StatementResult),
+) ([]StatementResult, error) {
+	// The pinned connection may be the pool's only one, so statements are
+	// prepared before it is checked out
+	statements := make([]Statement, len(params.Statements))
+	prepareErrs := make([]error, len(params.Statements))
+	copy(statements, params.Statements)
+	if p, ok := params.Connection.(Preparer); ok {
+		for i := range statements {
+			statements[i].SQL, prepareErrs[i] = p.Prepare(statements[i].SQL)
+		}
+	}
+
+	session, err := db.OpenSession(params.Connection)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	results := make([]StatementResult, 0, len(statements))
+
+	for i, stmt := range statements {
+		res := StatementResult{
+			Index:    i + 1,
+			SQL:      stmt.DisplaySQL,
+			IsSelect: IsSelectQuery(stmt.SQL),
+		}
+		if res.SQL == "" {
+			res.SQL = params.Statements[i].SQL
+		}
+
+		start := time.Now()
+		if prepareErrs[i] != nil {
+			res.Err = prepareErrs[i]
+		} else if res.IsSelect {
+			sql := stmt.SQL
+			if applyRowLimit && params.Config.DefaultRowLimit > 0 {
+				sql = params.Connection.ApplyRowLimit(sql, params.Config.DefaultRowLimit)
+			}
+
+			rows, queryErr := session.ExecQuery(sql, stmt.Args...)
+			if queryErr != nil {
+				res.Err = queryErr
+			} else {
+				res.Columns, res.ColumnTypes, res.Data, res.Err = db.FormatTableDataWithTypes(rows)
+			}
+		} else {
+			res.RowsAffected, res.Err = session.Exec(stmt.SQL, stmt.Args...)
+		}
+		res.Elapsed = time.Since(start)
+
+		results = append(results, res)
+		if onResult != nil {
+			onResult(res)
+		}
+
+		if res.Err != nil {
+			return results, fmt.Errorf("statement %d failed: %w", res.Index, res.Err)
+		}
+	}
+
+	return results, nil
+}
+
+// ExecuteScript runs a multi-statement script and shows every result set in
+// its own tab of the table viewer. Per-statement timing and errors are
+// printed once the viewer closes.
+func ExecuteScript(params ExecutionParams) error {
+	done := make(chan struct{})
+	go spinner.CircleWaitWithTimer(done)
+
+	applyRowLimit := params.Query.Id != 0 || params.Query.Name != ""
+	results, scriptErr := runScript(params, applyRowLimit, nil)
+
+	done <- struct{}{}
+	fmt.Print("\r\033[2K")
+
+	if results == nil && scriptErr != nil {
+		return scriptErr
+	}
+
+	var tabs []table.Tab
+	var tabResults []StatementResult
+	for _, res := range results {
+		if !res.IsSelect || res.Err != nil {
+			continue
+		}
+
+		tableName, primaryKey := "", ""
+		if metadata, err := db.InferTableMetadata(params.Connection, db.Query{SQL: res.SQL}); err == nil && metadata != nil {
+			tableName = metadata.TableName
+			if len(metadata.PrimaryKeys) > 0 {
+				primaryKey = metadata.PrimaryKeys[0]
+			}
+		}
+
+		q := db.Query{Name: params.Query.Name, SQL: res.SQL, Id: params.Query.Id}
+		model := table.New(res.Columns, res.ColumnTypes, res.Data, res.Elapsed, params.Connection, tableName, primaryKey, q, params.Config.DefaultColumnWidth, params.Config.UIVisibility)
+		tabs = append(tabs, table.Tab{Title: resultTitle(res, tableName), Model: model})
+		tabResults = append(tabResults, res)
+	}
+
+	if len(tabs) > 0 {
+		statusMessage := ""
+		if scriptErr != nil {
+			statusMessage = styles.Error.Render("✗ " + scriptErr.Error())
+		}
+
+		final, err := table.RenderTabs(tabs, params.SaveCallback, statusMessage)
+		if err != nil {
+			return fmt.Errorf("error rendering table: %w", err)
+		}
+
+		active := final.Active()
+		if active.ShouldRerunQuery() && params.OnRerun != nil {
+			edited := tabResults[final.ActiveIndex()]
+			return params.OnRerun(rebuildScript(params.Statements, edited.Index, active.GetEditedQuery().SQL))
+		}
+	}
+
+	printScriptSummary(results, len(params.Statements))
+	return scriptErr
+}
+
+// executeExportScript runs a multi-statement script in --format mode. Result
+// sets are written to stdout one after another; progress goes to stderr so
+// the output stays pipeable.
+func executeExportScript(params ExecutionParams, format string) error {
+	first := true
+	total := len(params.Statements)
+
+	_, err := runScript(params, true, func(res StatementResult) {
+		if res.Err != nil {
+			fmt.Fprintf(os.Stderr, "✗ [%d/%d] failed after %.2fs: %v\n", res.Index, total, res.Elapsed.Seconds(), res.Err)
+			return
+		}
+
+		if !res.IsSelect {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s in %.2fs\n", res.Index, total, affectedLabel(res.RowsAffected), res.Elapsed.Seconds())
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "[%d/%d] %d row(s) in %.2fs\n", res.Index, total, len(res.Data), res.Elapsed.Seconds())
+		if len(res.Data) == 0 {
+			return
+		}
+
+		tableName := ""
+		if metadata, err := db.InferTableMetadata(params.Connection, db.Query{SQL: res.SQL}); err == nil && metadata != nil {
+			tableName = metadata.TableName
+		}
+		opts := table.FormatOptions{
+			QueryName: params.Query.Name,
+			DbType:    params.Connection.GetDbType(),
+			DbName:    params.Connection.GetName(),
+			TableName: tableName,
+		}
+
+		data := maskedData(res.SQL, res.Columns, res.Data)
+		content, err := table.FormatExport(res.Columns, data, format, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ [%d/%d] export failed: %v\n", res.Index, total, err)
+			return
+		}
+
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		fmt.Print(content)
+	})
+
+	return err
+}
+
+func printScriptSummary(results []StatementResult, total int) {
+	for _, res := range results {
+		label := fmt.Sprintf("[%d/%d]", res.Index, total)
+		switch {
+		case res.Err != nil:
+			fmt.Println(styles.Error.Render(fmt.Sprintf("✗ %s failed after %.2fs: %v", label, res.Elapsed.Seconds(), res.Err)))
+			fmt.Println(parser.HighlightSQL(res.SQL))
+		case res.IsSelect:
+			fmt.Println(styles.Success.Render(fmt.Sprintf("✓ %s %d row(s) in %.2fs", label, len(res.Data), res.Elapsed.Seconds())) + " " + styles.Faint.Render(firstLine(res.SQL)))
+		default:
+			fmt.Println(styles.Success.Render(fmt.Sprintf("✓ %s %s in %.2fs", label, affectedLabel(res.RowsAffected), res.Elapsed.Seconds())) + " " + styles.Faint.Render(firstLine(res.SQL)))
+		}
+	}
+
+	if skipped := total - len(results); skipped > 0 {
+		fmt.Println(styles.Faint.Render(fmt.Sprintf("%d statement(s) not executed", skipped)))
+	}
+}
+
+// rebuildScript replaces statement index (1-based) with editedSQL and joins
+// the script back together, so an edit in one tab re-runs the whole script.
+func rebuildScript(statements []Statement, index int, editedSQL string) string {
+	parts := make([]string, len(statements))
+	for i, stmt := range statements {
+		sql := stmt.DisplaySQL
+		if sql == "" {
+			sql = stmt.SQL
+		}
+		if i+1 == index {
+			sql = strings.TrimSuffix(strings.TrimSpace(editedSQL), ";")
+		}
+		parts[i] = sql + ";"
+	}
+	return strings.Join(parts, "\n")
+}
+
+func resultTitle(res StatementResult, tableName string) string {
+	if tableName != "" {
+		return tableName
+	}
+	title := []rune(firstLine(res.SQL))
+	if len(title) > 20 {
+		return string(title[:17]) + "..."
+	}
+	return string(title)
+}
+
+func affectedLabel(n int64) string {
+	if n < 0 {
+		return "executed"
+	}
+	return fmt.Sprintf("%d row(s) affected", n)
+}
+
+func firstLine(sql string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(sql), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,70 @@
+package run
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// preparingConn rewrites the file reference the way file mode does, on
+// a pool of a single connection like file mode's
+type preparingConn struct {
+	db.DatabaseConnection
+}
+
+func (c preparingConn) Prepare(sql string) (string, error) {
+	if err := c.Exec("CREATE TABLE IF NOT EXISTS loaded (id INTEGER)"); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(sql, "'data.csv'", "loaded"), nil
+}
+
+func TestRunScript_PreparesStatements(t *testing.T) {
+	conn, err := db.NewSQLiteConnection("script", filepath.Join(t.TempDir(), "script.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.GetDB().SetMaxOpenConns(1)
+
+	params := ExecutionParams{
+		Connection: preparingConn{conn},
+		Config:     &config.Config{},
+		Statements: []Statement{
+			{SQL: "INSERT INTO 'data.csv' VALUES (1), (2)"},
+			{SQL: "SELECT count(*) FROM 'data.csv'"},
+		},
+	}
+	results, err := runScript(params, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[1].Data[0][0] != "2" {
+		t.Errorf("results = %+v", results)
+	}
+	if results[1].SQL != "SELECT count(*) FROM 'data.csv'" {
+		t.Errorf("a result shows the statement as written, got %q", results[1].SQL)
+	}
+}
+
+func TestResultTitle(t *testing.T) {
+	tests := []struct {
+		sql, table, want string
+	}{
+		{"SELECT 1", "", "SELECT 1"},
+		{"SELECT * FROM users WHERE id = 1", "", "SELECT * FROM use..."},
+		{"SELECT 'ação', 'ñandú' FROM t", "", "SELECT 'ação', 'ñ..."},
+		{"SELECT * FROM users", "users", "users"},
+	}
+	for _, tt := range tests {
+		if got := resultTitle(StatementResult{SQL: tt.sql}, tt.table); got != tt.want {
+			t.Errorf("resultTitle(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,29 @@ type Flags struct {
 	LastQuery    bool
 	Selector     string
 	ExportFormat string
+	FileMode     bool
+	Group        string
+	Parallel     int
+	// Yes skips the confirmation before a write runs on a whole group
+	Yes   bool
+	Chart ChartOptions
+}
+
+// ChartOptions are the --chart flags of pam run. X, Y, Output, Width and
+// Height are only read when a chart is requested, so they stay usable as
+// parameter names otherwise.
+type ChartOptions struct {
+	Kind   string
+	X      string
+	Y      []string
+	Output string
+	Width  int
+	Height int
+	SVG    bool
+}
+
+func (o ChartOptions) Enabled() bool {
+	return o.Kind != "" || o.SVG
 }
 
 type ResolvedQuery struct {
@@ -0,0 +1,355 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kind is how a column's values are generated
+type kind int
+
+const (
+	kindText kind = iota
+	kindInt
+	kindDecimal
+	kindFloat
+	kindBool
+	kindDate
+	kindTimestamp
+	kindTime
+	kindUUID
+	kindJSON
+	kindBinary
+	kindEnum
+)
+
+// columnType is what generation needs to know of a declared type
+type columnType struct {
+	kind      kind
+	length    int     // maximum characters for text, 0 when unbounded
+	precision int     // total digits for decimals
+	scale     int     // digits after the point for decimals
+	max       float64 // largest value an integer column holds
+	values    []string
+}
+
+var (
+	typeArgsPattern = regexp.MustCompile(`\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\)`)
+	enumPattern     = regexp.MustCompile(`(?i)^enum\s*\((.*)\)$`)
+)
+
+// parseType classifies a declared column type, such as "varchar(40)" or
+// "numeric(10,2)"
+func parseType(declared string) columnType {
+	t := strings.ToLower(strings.TrimSpace(declared))
+	var args []int
+	if m := typeArgsPattern.FindStringSubmatch(t); m != nil {
+		for _, arg := range m[1:] {
+			if n, err := strconv.Atoi(arg); err == nil {
+				args = append(args, n)
+			}
+		}
+	}
+	arg := func(i, fallback int) int {
+		if i < len(args) {
+			return args[i]
+		}
+		return fallback
+	}
+
+	if m := enumPattern.FindStringSubmatch(strings.TrimSpace(declared)); m != nil {
+		var values []string
+		for _, v := range strings.Split(m[1], ",") {
+			v = strings.TrimSpace(v)
+			values = append(values, strings.ReplaceAll(strings.Trim(v, "'"), "''", "'"))
+		}
+		return columnType{kind: kindEnum, values: values}
+	}
+
+	has := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(t, w) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("bool") || t == "bit" || t == "bit(1)" || t == "tinyint(1)":
+		return columnType{kind: kindBool}
+	case has("uuid", "uniqueidentifier"):
+		return columnType{kind: kindUUID}
+	case has("json"):
+		return columnType{kind: kindJSON}
+	case has("blob", "bytea", "binary", "image", "raw"):
+		return columnType{kind: kindBinary}
+	case has("timestamp", "datetime"):
+		return columnType{kind: kindTimestamp}
+	case has("date"):
+		return columnType{kind: kindDate}
+	case strings.HasPrefix(t, "time"):
+		return columnType{kind: kindTime}
+	case has("tinyint"):
+		return columnType{kind: kindInt, max: 127}
+	case has("smallint", "int2"):
+		return columnType{kind: kindInt, max: 32767}
+	case has("mediumint"):
+		return columnType{kind: kindInt, max: 8388607}
+	case has("bigint", "int8", "serial", "int", "long"):
+		return columnType{kind: kindInt, max: math.MaxInt32}
+	case has("decimal", "numeric", "number", "money"):
+		precision, scale := arg(0, 10), arg(1, 2)
+		if strings.HasPrefix(t, "number") && len(args) == 0 {
+			precision, scale = 10, 0
+		}
+		if scale == 0 {
+			return columnType{kind: kindInt, max: math.Pow(10, float64(min(precision, 9))) - 1}
+		}
+		return columnType{kind: kindDecimal, precision: precision, scale: scale}
+	case has("float", "double", "real"):
+		return columnType{kind: kindFloat}
+	}
+	length := 0
+	if has("char", "text", "string", "clob") {
+		length = arg(0, 0)
+	}
+	return columnType{kind: kindText, length: length}
+}
+
+// generator makes values for one column. Values are strings as a query
+// result holds them, "NULL" for a null.
+type generator struct {
+	rng  *rand.Rand
+	name string // lower-case column name
+	typ  columnType
+}
+
+var (
+	firstNames = []string{
+		"Alice", "Bruno", "Carla", "David", "Emma", "Felipe", "Grace", "Henry",
+		"Isabel", "James", "Julia", "Kenji", "Laura", "Lucas", "Maria", "Noah",
+		"Olivia", "Pedro", "Rosa", "Samuel", "Sofia", "Thomas", "Vera", "William",
+	}
+	lastNames = []string{
+		"Anderson", "Barbosa", "Clark", "Dias", "Evans", "Ferreira", "Garcia",
+		"Hughes", "Ito", "Johnson", "Klein", "Lima", "Martin", "Nguyen",
+		"Oliveira", "Parker", "Rodrigues", "Silva", "Taylor", "Walker", "Young",
+	}
+	cities = []string{
+		"Amsterdam", "Berlin", "Buenos Aires", "Chicago", "Denver", "Lisbon",
+		"London", "Madrid", "Montreal", "Osaka", "Paris", "Porto Alegre",
+		"Rome", "São Paulo", "Seattle", "Sydney", "Toronto", "Vienna",
+	}
+	countries = []string{
+		"Argentina", "Australia", "Brazil", "Canada", "France", "Germany",
+		"Italy", "Japan", "Netherlands", "Portugal", "Spain", "United Kingdom",
+		"United States",
+	}
+	countryCodes = []string{"AR", "AU", "BR", "CA", "DE", "ES", "FR", "GB", "IT", "JP", "NL", "PT", "US"}
+	streets      = []string{
+		"Main St", "Oak Ave", "Maple Dr", "Park Rd", "Cedar Ln", "Elm St",
+		"Lake View", "Hill Rd", "River St", "Sunset Blvd",
+	}
+	companies = []string{
+		"Acme", "Globex", "Initech", "Umbrella", "Stark", "Wayne", "Hooli",
+		"Vandelay", "Soylent", "Wonka", "Cyberdyne", "Tyrell",
+	}
+	companySuffixes = []string{"Inc", "LLC", "Ltd", "Group", "Labs", "Co"}
+	words           = []string{
+		"account", "balance", "customer", "delivery", "estimate", "feature",
+		"invoice", "order", "payment", "product", "quality", "report",
+		"request", "review", "service", "shipment", "status", "support",
+		"update", "value", "quick", "new", "late", "final", "open", "small",
+	}
+	statuses   = []string{"active", "inactive", "pending", "archived"}
+	colors     = []string{"red", "green", "blue", "yellow", "black", "white", "orange", "purple"}
+	currencies = []string{"USD", "EUR", "GBP", "BRL", "JPY", "CAD"}
+)
+
+// seedEpoch anchors generated dates, so the same seed gives the same rows
+// whenever it runs
+var seedEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (g *generator) pick(list []string) string {
+	return list[g.rng.IntN(len(list))]
+}
+
+// value makes one value
+func (g *generator) value() string {
+	switch g.typ.kind {
+	case kindInt:
+		return strconv.FormatInt(g.intValue(), 10)
+	case kindDecimal:
+		return g.decimalValue()
+	case kindFloat:
+		return strconv.FormatFloat(math.Round(g.rng.Float64()*100000)/100, 'f', -1, 64)
+	case kindBool:
+		return strconv.FormatBool(g.rng.IntN(2) == 1)
+	case kindDate:
+		return g.timeValue().Format("2006-01-02")
+	case kindTimestamp:
+		return g.timeValue().Format("2006-01-02 15:04:05")
+	case kindTime:
+		return fmt.Sprintf("%02d:%02d:%02d", g.rng.IntN(24), g.rng.IntN(60), g.rng.IntN(60))
+	case kindUUID:
+		return g.uuid()
+	case kindJSON:
+		return fmt.Sprintf(`{"%s": %d}`, g.pick(words), g.rng.IntN(1000))
+	case kindBinary:
+		return ""
+	case kindEnum:
+		return g.pick(g.typ.values)
+	}
+	return g.fit(g.text())
+}
+
+// intValue picks a number in a range the column's name suggests
+func (g *generator) intValue() int64 {
+	low, high := int64(1), int64(100000)
+	switch {
+	case g.is("age"):
+		low, high = 18, 90
+	case g.is("year"):
+		low, high = 1990, int64(seedEpoch.Year())
+	case g.is("qty", "quantity", "count", "stock"):
+		low, high = 1, 100
+	case g.is("rating", "score", "stars"):
+		low, high = 1, 5
+	case g.is("price", "amount", "total", "cost"):
+		low, high = 1, 1000
+	}
+	high = min(high, int64(g.typ.max))
+	if high < low {
+		low = 0
+	}
+	return low + g.rng.Int64N(high-low+1)
+}
+
+func (g *generator) decimalValue() string {
+	maxWhole := math.Pow(10, float64(max(g.typ.precision-g.typ.scale, 0))) - 1
+	high := min(1000, maxWhole)
+	if g.is("rate", "ratio", "percent", "discount") {
+		high = min(1, maxWhole)
+	}
+	v := g.rng.Float64() * high
+	return strconv.FormatFloat(v, 'f', g.typ.scale, 64)
+}
+
+func (g *generator) timeValue() time.Time {
+	// Within the five years before the epoch
+	span := int64(5 * 365 * 24 * time.Hour / time.Second)
+	return seedEpoch.Add(-time.Duration(g.rng.Int64N(span)) * time.Second)
+}
+
+func (g *generator) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.rng.IntN(256))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// is reports whether the column's name contains one of words
+func (g *generator) is(words ...string) bool {
+	for _, w := range words {
+		if strings.Contains(g.name, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// text makes a string fitting the column's name
+func (g *generator) text() string {
+	first, last := g.pick(firstNames), g.pick(lastNames)
+	switch {
+	case g.is("email", "mail"):
+		return fmt.Sprintf(
+			"%s.%s%d@example.com",
+			strings.ToLower(first),
+			strings.ToLower(last),
+			g.rng.IntN(1000),
+		)
+	case g.is("first_name", "firstname", "given"):
+		return first
+	case g.is("last_name", "lastname", "surname", "family"):
+		return last
+	case g.is("username", "login", "handle"):
+		return fmt.Sprintf("%s%s%d", strings.ToLower(first[:1]), strings.ToLower(last), g.rng.IntN(100))
+	case g.is("phone", "mobile", "fax"):
+		return fmt.Sprintf("+1 %03d-%03d-%04d", 200+g.rng.IntN(800), g.rng.IntN(1000), g.rng.IntN(10000))
+	case g.is("country_code", "iso"):
+		return g.pick(countryCodes)
+	case g.is("country"):
+		return g.pick(countries)
+	case g.is("city", "town"):
+		return g.pick(cities)
+	case g.is("zip", "postal", "postcode"):
+		return fmt.Sprintf("%05d", g.rng.IntN(100000))
+	case g.is("address", "street"):
+		return fmt.Sprintf("%d %s", 1+g.rng.IntN(9999), g.pick(streets))
+	case g.is("url", "website", "link"):
+		return fmt.Sprintf("https://%s.example.com/%s", strings.ToLower(g.pick(companies)), g.pick(words))
+	case g.is("company", "organization", "employer"):
+		return g.pick(companies) + " " + g.pick(companySuffixes)
+	case g.is("currency"):
+		return g.pick(currencies)
+	case g.is("color", "colour"):
+		return g.pick(colors)
+	case g.is("status", "state"):
+		return g.pick(statuses)
+	case g.is("name"):
+		return first + " " + last
+	case g.is("title", "subject", "label"):
+		return capitalize(g.sentence(2 + g.rng.IntN(3)))
+	case g.is("description", "comment", "note", "body", "bio", "text", "message", "content"):
+		return capitalize(g.sentence(6+g.rng.IntN(10))) + "."
+	case g.is("code", "sku", "ref"):
+		return fmt.Sprintf("%c%c-%05d", 'A'+rune(g.rng.IntN(26)), 'A'+rune(g.rng.IntN(26)), g.rng.IntN(100000))
+	}
+	return g.sentence(1 + g.rng.IntN(3))
+}
+
+func (g *generator) sentence(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = g.pick(words)
+	}
+	return strings.Join(parts, " ")
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// fit cuts text to the column's length
+func (g *generator) fit(text string) string {
+	if g.typ.length > 0 {
+		if runes := []rune(text); len(runes) > g.typ.length {
+			return string(runes[:g.typ.length])
+		}
+	}
+	return text
+}
+
+// unique makes a value distinct from the ones taken by appending a
+// counter, for columns whose generated values run out
+func (g *generator) unique(value string, attempt int) string {
+	suffix := strconv.Itoa(attempt)
+	if g.typ.kind != kindText {
+		return value
+	}
+	if g.typ.length > 0 && len([]rune(value))+len(suffix) > g.typ.length {
+		runes := []rune(value)
+		keep := max(g.typ.length-len(suffix), 0)
+		value = string(runes[:min(keep, len(runes))])
+	}
+	return value + suffix
+}
@@ -0,0 +1,661 @@
+// Package seed fills tables with made-up rows shaped by their schema: each
+// column gets values of its type and length, foreign keys point at real
+// parent rows and unique columns stay unique. The same seed against the
+// same database gives the same rows.
+package seed
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// NullRate is the share of values left NULL in nullable columns
+const NullRate = 0.1
+
+// maxAttempts bounds the retries for a row that repeats a unique value
+const maxAttempts = 100
+
+// poolLimit bounds how many parent keys a foreign key draws from
+const poolLimit = 10000
+
+// column is one column the seeder writes
+type column struct {
+	name     string
+	dataType string
+	nullable bool
+	gen      generator
+
+	seq  bool  // numbered from the column's current maximum
+	next int64 // the next number of a seq column
+
+	ref    *db.ForeignKey
+	pool   []string // parent keys ref draws from
+	unique bool     // the column alone is unique
+}
+
+// Table is a table the plan seeds and how many rows it gets
+type Table struct {
+	Name string
+	Rows int
+
+	columns []*column
+	auto    string // the column numbering itself, left out of the inserts
+	uniques [][]int
+	taken   []map[string]bool
+
+	// keys keeps generated values of the columns other seeded tables
+	// reference, for output that isn't inserted as it goes
+	keys map[string][]string
+	done int
+}
+
+// Columns returns the names of the columns the seeder writes
+func (t *Table) Columns() []string {
+	names := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func (t *Table) types() []string {
+	types := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		types[i] = c.dataType
+	}
+	return types
+}
+
+// Plan seeds a table and the empty tables its foreign keys need, parents
+// first
+type Plan struct {
+	Tables []*Table
+
+	conn    db.DatabaseConnection
+	dialect string
+	rng     *rand.Rand
+	// inserted is set while rows go straight into the database, where
+	// parent keys are read back rather than remembered
+	inserted bool
+}
+
+// New plans rows for table. An empty table referenced by a foreign key is
+// seeded first with a tenth as many rows, at least one.
+func New(conn db.DatabaseConnection, table string, rows int, seed uint64) (*Plan, error) {
+	if rows < 1 {
+		return nil, fmt.Errorf("row count must be at least 1")
+	}
+	dialect, err := db.NormalizeDialect(conn.GetDbType())
+	if err != nil {
+		dialect = conn.GetDbType()
+	}
+	p := &Plan{
+		conn:    conn,
+		dialect: dialect,
+		rng:     rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+	}
+	if err := p.add(table, rows, map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Plan) table(name string) *Table {
+	for _, t := range p.Tables {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// add plans table after the empty parents it references
+func (p *Plan) add(name string, rows int, visiting map[string]bool) error {
+	t, err := p.load(name, rows)
+	if err != nil {
+		return err
+	}
+	visiting[strings.ToLower(name)] = true
+	for _, c := range t.columns {
+		if c.ref == nil || strings.EqualFold(c.ref.ReferencedTable, name) {
+			continue
+		}
+		parent := c.ref.ReferencedTable
+		if p.table(parent) != nil || visiting[strings.ToLower(parent)] {
+			// Already planned, or a cycle: nullable columns fall back to
+			// NULL when the parent is still empty
+			continue
+		}
+		count, err := p.count(parent)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			if err := p.add(parent, max(1, rows/10), visiting); err != nil {
+				return err
+			}
+		}
+	}
+	p.Tables = append(p.Tables, t)
+	return nil
+}
+
+// load reads what generation needs to know of a table
+func (p *Plan) load(name string, rows int) (*Table, error) {
+	details, err := p.conn.GetColumnDetails(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read columns of %s: %w", name, err)
+	}
+	if len(details) == 0 {
+		return nil, fmt.Errorf("table %s not found or has no columns", name)
+	}
+	fks, err := p.conn.GetForeignKeys(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read foreign keys of %s: %w", name, err)
+	}
+	uniqueCols, err := p.conn.GetUniqueConstraints(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read unique constraints of %s: %w", name, err)
+	}
+
+	primaryKeys := 0
+	for _, col := range details {
+		if col.IsPrimaryKey {
+			primaryKeys++
+		}
+	}
+
+	t := &Table{Name: name, Rows: rows, keys: map[string][]string{}}
+	for _, col := range details {
+		// SQLite flags every INTEGER key column, but only a lone one is
+		// the rowid
+		if db.IsAutoIncrement(col) && (p.dialect != "sqlite" || primaryKeys == 1) {
+			t.auto = col.Name
+			continue
+		}
+		if computed(col) {
+			continue
+		}
+		c := &column{
+			name:     col.Name,
+			dataType: col.DataType,
+			nullable: !strings.EqualFold(col.Nullable, "NO") && !col.IsPrimaryKey,
+			gen: generator{
+				rng:  p.rng,
+				name: strings.ToLower(col.Name),
+				typ:  parseType(col.DataType),
+			},
+		}
+		for i := range fks {
+			if strings.EqualFold(fks[i].Column, col.Name) {
+				c.ref = &fks[i]
+				break
+			}
+		}
+		t.columns = append(t.columns, c)
+	}
+	if len(t.columns) == 0 {
+		return nil, fmt.Errorf("%s has no columns to fill", name)
+	}
+
+	t.uniques = p.uniqueSets(t, details, uniqueCols)
+	for _, set := range t.uniques {
+		if len(set) != 1 {
+			continue
+		}
+		c := t.columns[set[0]]
+		c.unique = true
+		c.nullable = false
+		c.seq = c.ref == nil && c.gen.typ.kind == kindInt
+	}
+	for _, set := range t.uniques {
+		for _, i := range set {
+			t.columns[i].nullable = false
+		}
+	}
+	return t, nil
+}
+
+// computed reports whether a column's value is derived from others, so it
+// can't be written
+func computed(col db.ColumnInfo) bool {
+	extra := strings.ToLower(col.Extra)
+	return strings.Contains(extra, "computed") ||
+		(strings.Contains(extra, "generated") && !strings.Contains(extra, "default_generated"))
+}
+
+// uniqueSets lists the sets of columns whose values must not repeat, as
+// indexes into t.columns. Sets holding a column the database numbers are
+// unique already and left out.
+func (p *Plan) uniqueSets(t *Table, details []db.ColumnInfo, uniqueCols []string) [][]int {
+	var named [][]string
+	var pk []string
+	for _, col := range details {
+		if col.IsPrimaryKey {
+			pk = append(pk, col.Name)
+		}
+	}
+	if len(pk) > 0 {
+		named = append(named, pk)
+	}
+	if indexes, err := p.conn.GetIndexes(t.Name); err == nil {
+		for _, index := range indexes {
+			if index.Unique || index.Primary {
+				named = append(named, index.Columns)
+			}
+		}
+	}
+	// Unique constraints come as a flat list; ones no index covered are
+	// taken to stand alone
+	for _, name := range uniqueCols {
+		covered := false
+		for _, set := range named {
+			covered = covered || slices.ContainsFunc(set, func(s string) bool {
+				return strings.EqualFold(s, name)
+			})
+		}
+		if !covered {
+			named = append(named, []string{name})
+		}
+	}
+
+	var sets [][]int
+	seen := map[string]bool{}
+	for _, names := range named {
+		var set []int
+		for _, name := range names {
+			i := slices.IndexFunc(t.columns, func(c *column) bool {
+				return strings.EqualFold(c.name, name)
+			})
+			if i < 0 {
+				set = nil
+				break
+			}
+			set = append(set, i)
+		}
+		if len(set) == 0 {
+			continue
+		}
+		slices.Sort(set)
+		key := fmt.Sprint(set)
+		if !seen[key] {
+			seen[key] = true
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+func (p *Plan) quote(name string) string {
+	return db.QuoteIdentifierFor(p.dialect, name)
+}
+
+func (p *Plan) query(sql string) ([][]string, error) {
+	rows, err := p.conn.ExecQuery(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	_, data, err := db.FormatTableData(rows)
+	return data, err
+}
+
+func (p *Plan) count(table string) (int64, error) {
+	data, err := p.query("SELECT COUNT(*) FROM " + p.quote(table))
+	if err != nil {
+		return 0, fmt.Errorf("could not count rows of %s: %w", table, err)
+	}
+	if len(data) == 0 || len(data[0]) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(strings.TrimSpace(data[0][0]), 10, 64)
+}
+
+// prepare reads the state of the database a table's rows must fit: the
+// numbers in use, the unique values taken and the parent keys to draw from
+func (p *Plan) prepare(t *Table) error {
+	for _, c := range t.columns {
+		if !c.seq {
+			continue
+		}
+		data, err := p.query(fmt.Sprintf("SELECT MAX(%s) FROM %s", p.quote(c.name), p.quote(t.Name)))
+		if err != nil {
+			return fmt.Errorf("could not read %s.%s: %w", t.Name, c.name, err)
+		}
+		c.next = 1
+		if len(data) > 0 && len(data[0]) > 0 {
+			if n, err := strconv.ParseInt(strings.TrimSpace(data[0][0]), 10, 64); err == nil {
+				c.next = n + 1
+			}
+		}
+	}
+
+	t.taken = make([]map[string]bool, len(t.uniques))
+	for i, set := range t.uniques {
+		t.taken[i] = map[string]bool{}
+		if t.numbered(set) {
+			continue
+		}
+		cols := make([]string, len(set))
+		for j, ci := range set {
+			cols[j] = p.quote(t.columns[ci].name)
+		}
+		data, err := p.query(fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), p.quote(t.Name)))
+		if err != nil {
+			return fmt.Errorf("could not read unique values of %s: %w", t.Name, err)
+		}
+		for _, row := range data {
+			t.taken[i][strings.Join(row, "\x00")] = true
+		}
+	}
+
+	for _, c := range t.columns {
+		if c.ref == nil {
+			continue
+		}
+		pool, err := p.parentKeys(c)
+		if err != nil {
+			return fmt.Errorf("could not read keys of %s: %w", c.ref.ReferencedTable, err)
+		}
+		if c.unique {
+			// Each parent is used once, so skip the ones taken and draw
+			// the rest in a shuffled order
+			set := slices.IndexFunc(t.uniques, func(s []int) bool {
+				return len(s) == 1 && t.columns[s[0]] == c
+			})
+			pool = slices.DeleteFunc(pool, func(v string) bool { return t.taken[set][v] })
+			p.rng.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+			if len(pool) < t.Rows && !strings.EqualFold(c.ref.ReferencedTable, t.Name) {
+				return fmt.Errorf(
+					"%s.%s is unique but %s has only %d unused key(s) for %d row(s)",
+					t.Name, c.name, c.ref.ReferencedTable, len(pool), t.Rows,
+				)
+			}
+		}
+		if len(pool) == 0 && !c.nullable && !strings.EqualFold(c.ref.ReferencedTable, t.Name) {
+			return fmt.Errorf(
+				"%s.%s needs rows in %s, which is empty",
+				t.Name, c.name, c.ref.ReferencedTable,
+			)
+		}
+		c.pool = pool
+	}
+	return nil
+}
+
+// parentKeys lists the parent keys a foreign key column can hold: rows in
+// the database, plus rows seeded in this run that weren't inserted
+func (p *Plan) parentKeys(c *column) ([]string, error) {
+	sql := fmt.Sprintf(
+		"SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL ORDER BY %s",
+		p.quote(c.ref.ReferencedColumn),
+		p.quote(c.ref.ReferencedTable),
+		p.quote(c.ref.ReferencedColumn),
+		p.quote(c.ref.ReferencedColumn),
+	)
+	if !c.unique {
+		sql = p.conn.ApplyRowLimit(sql, poolLimit)
+	}
+	data, err := p.query(sql)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(data))
+	for _, row := range data {
+		keys = append(keys, row[0])
+	}
+	if parent := p.table(c.ref.ReferencedTable); parent != nil && !p.inserted {
+		keys = append(keys, parent.generatedKeys(c.ref.ReferencedColumn)...)
+	}
+	return keys, nil
+}
+
+// generatedKeys returns the values generated so far for column. A column
+// the database numbers is assumed to count from 1, as it does in a table
+// that was empty.
+func (t *Table) generatedKeys(column string) []string {
+	if strings.EqualFold(column, t.auto) {
+		keys := make([]string, t.done)
+		for i := range keys {
+			keys[i] = strconv.Itoa(i + 1)
+		}
+		return keys
+	}
+	return t.keys[strings.ToLower(column)]
+}
+
+// row generates the next row of t
+func (p *Plan) row(t *Table) ([]string, error) {
+	row := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		v, err := p.value(t, c)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = v
+	}
+
+	for s, set := range t.uniques {
+		if t.numbered(set) {
+			continue
+		}
+		key := uniqueKey(row, set)
+		for attempt := 1; t.taken[s][key]; attempt++ {
+			if attempt > maxAttempts {
+				return nil, fmt.Errorf(
+					"could not find unused values for %s (%s) after %d tries; try fewer rows",
+					t.Name, strings.Join(columnNames(t, set), ", "), maxAttempts,
+				)
+			}
+			for _, i := range set {
+				c := t.columns[i]
+				if c.ref != nil {
+					if !c.unique {
+						row[i] = c.pool[p.rng.IntN(len(c.pool))]
+					}
+					continue
+				}
+				if len(set) == 1 && c.gen.typ.kind == kindText {
+					row[i] = c.gen.unique(row[i], attempt)
+					continue
+				}
+				row[i] = c.gen.value()
+			}
+			key = uniqueKey(row, set)
+		}
+		t.taken[s][key] = true
+	}
+
+	for i, c := range t.columns {
+		if name := strings.ToLower(c.name); !p.inserted && p.referenced(t, c.name) {
+			t.keys[name] = append(t.keys[name], row[i])
+		}
+	}
+	// A table referencing itself can point at rows generated before
+	for _, c := range t.columns {
+		if c.ref == nil || !strings.EqualFold(c.ref.ReferencedTable, t.Name) {
+			continue
+		}
+		if j := slices.IndexFunc(t.columns, func(o *column) bool {
+			return strings.EqualFold(o.name, c.ref.ReferencedColumn)
+		}); j >= 0 {
+			c.pool = append(c.pool, row[j])
+		} else if !p.inserted && strings.EqualFold(c.ref.ReferencedColumn, t.auto) {
+			c.pool = append(c.pool, strconv.Itoa(t.done+1))
+		}
+	}
+	t.done++
+	return row, nil
+}
+
+// referenced reports whether another planned table has a foreign key to
+// column of t
+func (p *Plan) referenced(t *Table, column string) bool {
+	for _, other := range p.Tables {
+		for _, c := range other.columns {
+			if c.ref != nil && strings.EqualFold(c.ref.ReferencedTable, t.Name) &&
+				strings.EqualFold(c.ref.ReferencedColumn, column) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// value generates one value of column c
+func (p *Plan) value(t *Table, c *column) (string, error) {
+	switch {
+	case c.seq:
+		c.next++
+		return strconv.FormatInt(c.next-1, 10), nil
+	case c.ref != nil:
+		if c.nullable && (len(c.pool) == 0 || p.rng.Float64() < NullRate) {
+			return "NULL", nil
+		}
+		if len(c.pool) == 0 {
+			return "", fmt.Errorf("%s.%s has no %s row to reference", t.Name, c.name, c.ref.ReferencedTable)
+		}
+		if c.unique {
+			v := c.pool[0]
+			c.pool = c.pool[1:]
+			return v, nil
+		}
+		return c.pool[p.rng.IntN(len(c.pool))], nil
+	case c.nullable && p.rng.Float64() < NullRate:
+		return "NULL", nil
+	}
+	return c.gen.value(), nil
+}
+
+// numbered reports whether a unique set holds a numbered column, which
+// keeps every row distinct by itself
+func (t *Table) numbered(set []int) bool {
+	return slices.ContainsFunc(set, func(i int) bool { return t.columns[i].seq })
+}
+
+func uniqueKey(row []string, set []int) string {
+	values := make([]string, len(set))
+	for j, i := range set {
+		values[j] = row[i]
+	}
+	return strings.Join(values, "\x00")
+}
+
+func columnNames(t *Table, set []int) []string {
+	names := make([]string, len(set))
+	for j, i := range set {
+		names[j] = t.columns[i].name
+	}
+	return names
+}
+
+// rows generates up to n more rows of t
+func (p *Plan) rows(t *Table, n int) ([][]string, error) {
+	n = min(n, t.Rows-t.done)
+	out := make([][]string, 0, n)
+	for range n {
+		row, err := p.row(t)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// Insert writes the plan's rows into the database, batchSize rows to a
+// transaction. done is called as each table finishes.
+func (p *Plan) Insert(batchSize int, done func(t *Table)) error {
+	p.inserted = true
+	for _, t := range p.Tables {
+		if err := p.prepare(t); err != nil {
+			return err
+		}
+		for t.done < t.Rows {
+			batch, err := p.rows(t, batchSize)
+			if err != nil {
+				return err
+			}
+			statements := db.InsertStatements(p.conn, t.Name, t.Columns(), t.types(), batch, batchSize)
+			if err := db.ExecBatch(p.conn, statements); err != nil {
+				return fmt.Errorf("could not insert into %s: %w", t.Name, err)
+			}
+		}
+		if done != nil {
+			done(t)
+		}
+	}
+	return nil
+}
+
+// WriteSQL writes the plan's rows as INSERT statements, up to perStatement
+// rows each
+func (p *Plan) WriteSQL(w io.Writer, perStatement int) error {
+	for _, t := range p.Tables {
+		if err := p.prepare(t); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "-- %s: %d row(s)\n", t.Name, t.Rows)
+		for t.done < t.Rows {
+			batch, err := p.rows(t, perStatement)
+			if err != nil {
+				return err
+			}
+			for _, stmt := range db.InsertStatements(p.conn, t.Name, t.Columns(), t.types(), batch, perStatement) {
+				if _, err := fmt.Fprintf(w, "%s;\n", stmt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the rows of a plan seeding a single table as CSV, with a
+// header row. NULL is written as an empty field.
+func (p *Plan) WriteCSV(w io.Writer) error {
+	if len(p.Tables) > 1 {
+		var parents []string
+		for _, t := range p.Tables[:len(p.Tables)-1] {
+			parents = append(parents, t.Name)
+		}
+		return fmt.Errorf(
+			"CSV holds one table, but %s must be seeded first; write SQL instead",
+			strings.Join(parents, ", "),
+		)
+	}
+	t := p.Tables[0]
+	if err := p.prepare(t); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns()); err != nil {
+		return err
+	}
+	for t.done < t.Rows {
+		batch, err := p.rows(t, 1000)
+		if err != nil {
+			return err
+		}
+		for _, row := range batch {
+			record := make([]string, len(row))
+			for i, v := range row {
+				if v != "NULL" {
+					record[i] = v
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
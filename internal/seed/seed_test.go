@@ -0,0 +1,205 @@
+package seed
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		declared string
+		kind     kind
+		length   int
+	}{
+		{"varchar(40)", kindText, 40},
+		{"character varying(12)", kindText, 12},
+		{"TEXT", kindText, 0},
+		{"INTEGER", kindInt, 0},
+		{"tinyint(1)", kindBool, 0},
+		{"boolean", kindBool, 0},
+		{"numeric(10,2)", kindDecimal, 0},
+		{"numeric(6,0)", kindInt, 0},
+		{"timestamp without time zone", kindTimestamp, 0},
+		{"datetime", kindTimestamp, 0},
+		{"date", kindDate, 0},
+		{"time", kindTime, 0},
+		{"uuid", kindUUID, 0},
+		{"jsonb", kindJSON, 0},
+		{"enum('a','b')", kindEnum, 0},
+	}
+	for _, tt := range tests {
+		got := parseType(tt.declared)
+		if got.kind != tt.kind || got.length != tt.length {
+			t.Errorf("parseType(%q) = kind %d length %d, want %d %d",
+				tt.declared, got.kind, got.length, tt.kind, tt.length)
+		}
+	}
+	if got := parseType("enum('a','it''s')").values; len(got) != 2 || got[1] != "it's" {
+		t.Errorf("enum values = %q", got)
+	}
+}
+
+func TestGenerator_FitsColumn(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	email := generator{rng: rng, name: "email", typ: parseType("varchar(12)")}
+	small := generator{rng: rng, name: "qty", typ: parseType("tinyint")}
+	price := generator{rng: rng, name: "price", typ: parseType("decimal(5,2)")}
+	for range 200 {
+		if v := email.value(); len(v) > 12 {
+			t.Fatalf("%q is longer than varchar(12)", v)
+		}
+		if v := small.value(); len(v) > 3 {
+			t.Fatalf("%q doesn't fit a tinyint", v)
+		}
+		if v := price.value(); !strings.Contains(v, ".") || len(v) > 6 {
+			t.Fatalf("%q doesn't fit decimal(5,2)", v)
+		}
+	}
+	if v := email.unique("ab@example.c", 7); v != "ab@example.7" {
+		t.Errorf("unique = %q, want the suffix within the length", v)
+	}
+}
+
+func openSchema(t *testing.T, statements ...string) db.DatabaseConnection {
+	t.Helper()
+	conn, err := db.NewSQLiteConnection("seed", filepath.Join(t.TempDir(), "seed.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	for _, stmt := range statements {
+		if err := conn.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return conn
+}
+
+var shopSchema = []string{
+	`CREATE TABLE customers (
+		id INTEGER PRIMARY KEY,
+		email VARCHAR(40) NOT NULL UNIQUE,
+		name VARCHAR(60) NOT NULL,
+		city TEXT
+	)`,
+	`CREATE TABLE orders (
+		id INTEGER PRIMARY KEY,
+		customer_id INTEGER NOT NULL REFERENCES customers(id),
+		code VARCHAR(8) NOT NULL UNIQUE,
+		total DECIMAL(8,2) NOT NULL,
+		placed_at TIMESTAMP
+	)`,
+}
+
+func query(t *testing.T, conn db.DatabaseConnection, sql string) [][]string {
+	t.Helper()
+	rows, err := conn.ExecQuery(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	_, data, err := db.FormatTableData(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestInsert_SeedsEmptyParentsFirst(t *testing.T) {
+	conn := openSchema(t, shopSchema...)
+
+	plan, err := New(conn, "orders", 200, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Tables) != 2 || plan.Tables[0].Name != "customers" || plan.Tables[0].Rows != 20 {
+		t.Fatalf("plan = %+v, want customers(20) then orders", plan.Tables)
+	}
+	if err := plan.Insert(50, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := query(t, conn, `SELECT
+		(SELECT COUNT(*) FROM customers),
+		(SELECT COUNT(*) FROM orders),
+		(SELECT COUNT(DISTINCT code) FROM orders),
+		(SELECT COUNT(*) FROM orders WHERE customer_id NOT IN (SELECT id FROM customers))`)
+	if got := strings.Join(counts[0], " "); got != "20 200 200 0" {
+		t.Errorf("customers, orders, distinct codes, orphans = %s, want 20 200 200 0", got)
+	}
+
+	// A second run adds rows around the ones already there
+	plan, err = New(conn, "customers", 30, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Insert(500, nil); err != nil {
+		t.Fatal(err)
+	}
+	counts = query(t, conn, "SELECT COUNT(*), COUNT(DISTINCT email) FROM customers")
+	if got := strings.Join(counts[0], " "); got != "50 50" {
+		t.Errorf("customers, distinct emails = %s, want 50 50", got)
+	}
+}
+
+func TestWriteSQL_IsReproducible(t *testing.T) {
+	write := func() string {
+		conn := openSchema(t, shopSchema...)
+		plan, err := New(conn, "orders", 30, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err := plan.WriteSQL(&out, 10); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	first, second := write(), write()
+	if first != second {
+		t.Fatal("the same seed wrote different rows")
+	}
+
+	// The file loads into the empty schema with every key resolving
+	conn := openSchema(t, shopSchema...)
+	if _, err := db.ImportSQL(conn, strings.NewReader(first), db.ImportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	orphans := query(t, conn, "SELECT COUNT(*) FROM orders WHERE customer_id NOT IN (SELECT id FROM customers)")
+	if orphans[0][0] != "0" {
+		t.Errorf("%s orders reference missing customers", orphans[0][0])
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	conn := openSchema(t, shopSchema...)
+
+	plan, err := New(conn, "orders", 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.WriteCSV(&bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "customers") {
+		t.Errorf("err = %v, want customers to need seeding first", err)
+	}
+
+	plan, err = New(conn, "customers", 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := plan.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 || lines[0] != "email,name,city" {
+		t.Errorf("csv = %q", out.String())
+	}
+}
@@ -0,0 +1,77 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/chart"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// chartViewState holds the chart opened with 'C'. It is drawn from the
+// terminal size on every render, so it follows window resizes.
+type chartViewState struct {
+	active bool
+	kind   chart.Kind
+	data   chart.Data
+}
+
+// openChart plots the displayed rows: the selected column when it is
+// numeric, otherwise every numeric column, against the first date/time or
+// text column.
+func (m Model) openChart() (tea.Model, tea.Cmd) {
+	if m.numCols() == 0 || m.numRows() == 0 {
+		return m, nil
+	}
+
+	d, err := chart.Build(m.columns, m.columnTypes, m.data, "", []string{m.columns[m.selectedCol]})
+	if err != nil {
+		d, err = chart.Build(m.columns, m.columnTypes, m.data, "", nil)
+	}
+	if err != nil {
+		m.statusMessage = styles.Error.Render("✗ " + err.Error())
+		return m, m.blinkCmd()
+	}
+
+	m.chartView = chartViewState{active: true, kind: chart.Line, data: d}
+	return m, nil
+}
+
+func (m Model) handleChartView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "C":
+		m.chartView = chartViewState{}
+	case "tab", "t":
+		for i, k := range chart.Kinds {
+			if k == m.chartView.kind {
+				m.chartView.kind = chart.Kinds[(i+1)%len(chart.Kinds)]
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderChartView() string {
+	cv := m.chartView
+	var b strings.Builder
+
+	var names []string
+	for _, s := range cv.data.Series {
+		names = append(names, s.Name)
+	}
+	title := fmt.Sprintf("◆ %s chart: %s", cv.kind, strings.Join(names, ", "))
+	if cv.data.XName != "" {
+		title += " by " + cv.data.XName
+	}
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString("\n\n")
+
+	b.WriteString(chart.Render(cv.kind, cv.data, m.width-1, m.height-5))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Faint.Render("t/tab line • bar • sparkline • esc close"))
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/chart"
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_ChartView(t *testing.T) {
+	m := New(
+		[]string{"day", "total", "orders"},
+		[]string{"DATE", "NUMERIC", "INTEGER"},
+		[][]string{{"2026-01-01", "10", "1"}, {"2026-01-02", "40", "3"}},
+		time.Millisecond,
+		nil,
+		"",
+		"",
+		db.Query{Name: "sales"},
+		15,
+		config.UIVisibility{},
+	)
+	m = m.handleWindowResize(tea.WindowSizeMsg{Width: 60, Height: 20})
+	m.selectedCol = 2
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	m = model.(Model)
+	if !m.chartView.active || !m.capturingInput() {
+		t.Fatal("C should open the chart view")
+	}
+	if s := m.chartView.data.Series; len(s) != 1 || s[0].Name != "orders" {
+		t.Errorf("chart should plot the selected numeric column, got %+v", s)
+	}
+
+	axisWidth := func(view string) int {
+		for _, line := range strings.Split(view, "\n") {
+			if strings.Contains(line, "└") {
+				return strings.Count(line, "─")
+			}
+		}
+		return 0
+	}
+	narrow := axisWidth(m.View())
+	model, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if wide := axisWidth(model.View()); wide <= narrow {
+		t.Errorf("chart should widen with the window: %d → %d columns", narrow, wide)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if kind := model.(Model).chartView.kind; kind != chart.Bar {
+		t.Errorf("tab should switch to bar, got %s", kind)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(Model).chartView.active {
+		t.Error("esc should close the chart view")
+	}
+}
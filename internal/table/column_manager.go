@@ -0,0 +1,135 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// columnManagerState is the column list opened with 'c', where columns are
+// shown/hidden, reordered and pinned.
+type columnManagerState struct {
+	active bool
+	cursor int
+}
+
+func (m Model) openColumnManager() Model {
+	m.columnManager = columnManagerState{active: true}
+	if m.selectedCol < len(m.colMap) {
+		layout, _ := m.view.layout(m.srcColumns)
+		if i := indexOfInt(layout, m.colMap[m.selectedCol]); i >= 0 {
+			m.columnManager.cursor = i
+		}
+	}
+	return m
+}
+
+func (m Model) handleColumnManager(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	layout, _ := m.view.layout(m.srcColumns)
+	if len(layout) == 0 {
+		m.columnManager = columnManagerState{}
+		return m, nil
+	}
+	cursor := min(m.columnManager.cursor, len(layout)-1)
+	name := m.srcColumns[layout[cursor]]
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "c", "enter":
+		m.columnManager = columnManagerState{}
+		return m, nil
+
+	case "up", "k":
+		m.columnManager.cursor = max(cursor-1, 0)
+		return m, nil
+	case "down", "j":
+		m.columnManager.cursor = min(cursor+1, len(layout)-1)
+		return m, nil
+
+	case " ", "x":
+		if containsName(m.view.Hidden, name) {
+			m.view.Hidden = withoutName(m.view.Hidden, name)
+		} else if len(layout)-len(m.view.Hidden) > 1 {
+			m.view.Hidden = append(append([]string{}, m.view.Hidden...), name)
+			m.view.Pinned = withoutName(m.view.Pinned, name)
+		}
+		m = m.applyView()
+
+	case "p":
+		if containsName(m.view.Pinned, name) {
+			m.view.Pinned = withoutName(m.view.Pinned, name)
+		} else if !containsName(m.view.Hidden, name) {
+			m.view.Pinned = append(append([]string{}, m.view.Pinned...), name)
+		}
+		m = m.applyView()
+
+	case "K", "shift+up":
+		if cursor > 0 {
+			m.view = m.view.swapColumns(m.srcColumns, layout, cursor, cursor-1)
+			m = m.applyView()
+		}
+	case "J", "shift+down":
+		if cursor < len(layout)-1 {
+			m.view = m.view.swapColumns(m.srcColumns, layout, cursor, cursor+1)
+			m = m.applyView()
+		}
+
+	case "a":
+		m.view.Hidden = nil
+		m = m.applyView()
+	}
+
+	// Keep the cursor on the same column after it moved
+	layout, _ = m.view.layout(m.srcColumns)
+	for i, idx := range layout {
+		if m.srcColumns[idx] == name {
+			cursor = i
+			break
+		}
+	}
+	m.columnManager.cursor = cursor
+	return m, nil
+}
+
+func (m Model) renderColumnManager() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("◆ Columns"))
+	b.WriteString("\n")
+	b.WriteString(styles.Separator.Render(strings.Repeat("─", 40)))
+	b.WriteString("\n")
+
+	layout, _ := m.view.layout(m.srcColumns)
+	for i, idx := range layout {
+		name := m.srcColumns[idx]
+
+		visible := "[x]"
+		if containsName(m.view.Hidden, name) {
+			visible = "[ ]"
+		}
+		pin := "  "
+		if containsName(m.view.Pinned, name) &&
+			!containsName(m.view.Hidden, name) {
+			pin = "📌"
+		}
+		typ := ""
+		if idx < len(m.srcColumnTypes) && m.srcColumnTypes[idx] != "" {
+			typ = styles.Faint.Render(" " + m.srcColumnTypes[idx])
+		}
+
+		line := fmt.Sprintf("%s %s %s", visible, pin, name)
+		if i == m.columnManager.cursor {
+			line = styles.TableSelected.Render(line)
+		}
+		b.WriteString(line + typ + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.Faint.Render(
+		"j/k move • space show/hide • J/K reorder • p pin • a show all • esc close",
+	))
+	return b.String()
+}
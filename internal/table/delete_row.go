@@ -124,7 +124,7 @@ func (m Model) handleDeleteComplete(
 	}
 
 	// Successfully deleted - update the model data
-	m.data = append(m.data[:msg.rowIndex], m.data[msg.rowIndex+1:]...)
+	m = m.removeRow(msg.rowIndex)
 	if m.selectedRow >= m.numRows() && m.numRows() > 0 {
 		m.selectedRow = m.numRows() - 1
 	}
@@ -146,12 +146,7 @@ func (m Model) buildDeleteStatement() string {
 	var multipleMatches bool
 
 	if m.primaryKeyCol != "" {
-		for i, col := range m.columns {
-			if col == m.primaryKeyCol {
-				pkValue = m.data[m.selectedRow][i]
-				break
-			}
-		}
+		pkValue, _ = m.rowValue(m.selectedRow, m.primaryKeyCol)
 	}
 
 	if m.primaryKeyCol != "" && pkValue == "" {
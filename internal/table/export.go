@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/atotto/clipboard"
+	"github.com/caiolandgraf/pam/internal/mask"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -161,6 +162,8 @@ func (m Model) executeExportForFormat(key string) (Model, tea.Cmd) {
 		cellCount = (maxRow - minRow + 1) * (maxCol - minCol + 1)
 	}
 
+	rows = m.maskRows(headers, rows)
+
 	return m, func() tea.Msg {
 		content, err := m.formatExportContent(headers, rows, format)
 		if err != nil {
@@ -189,6 +192,11 @@ func (m Model) formatExportContent(headers []string, rows [][]string, format exp
 	return FormatExport(headers, rows, string(format), opts)
 }
 
+// maskRows applies the configured masking rules to rows leaving the view
+func (m Model) maskRows(headers []string, rows [][]string) [][]string {
+	return mask.Active.Rows(m.tableName, headers, rows)
+}
+
 // --- Standalone format functions ---
 
 func FormatCSV(headers []string, rows [][]string) (string, error) {
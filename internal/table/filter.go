@@ -0,0 +1,442 @@
+package table
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// rowFilter is a compiled filter bar expression, evaluated against the rows
+// already loaded in the table. The syntax is a small subset of SQL:
+//
+//	status = 'failed' and (amount > 100 or note like '%refund%')
+//	deleted_at is null and country in ('BR', 'PT')
+//
+// Values compare numerically when both sides are numbers and as text
+// otherwise. NULL cells only match IS NULL.
+type rowFilter interface {
+	match(row []string) bool
+}
+
+type andFilter struct{ left, right rowFilter }
+
+type orFilter struct{ left, right rowFilter }
+
+type notFilter struct{ inner rowFilter }
+
+type compareFilter struct {
+	col    int
+	op     string
+	values []string
+	like   *regexp.Regexp
+}
+
+func (f andFilter) match(row []string) bool {
+	return f.left.match(row) && f.right.match(row)
+}
+
+func (f orFilter) match(row []string) bool {
+	return f.left.match(row) || f.right.match(row)
+}
+
+func (f notFilter) match(row []string) bool {
+	return !f.inner.match(row)
+}
+
+func (f compareFilter) match(row []string) bool {
+	if f.col >= len(row) {
+		return false
+	}
+	cell := row[f.col]
+	isNull := cell == "NULL"
+
+	switch f.op {
+	case "is null":
+		return isNull
+	case "is not null":
+		return !isNull
+	}
+	if isNull {
+		return false
+	}
+
+	switch f.op {
+	case "like":
+		return f.like.MatchString(cell)
+	case "not like":
+		return !f.like.MatchString(cell)
+	case "in":
+		for _, v := range f.values {
+			if compareValues(cell, v) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	c := compareValues(cell, f.values[0])
+	switch f.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// compareValues orders two cell values, numerically when both parse as
+// numbers.
+func compareValues(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+type filterTokenKind int
+
+const (
+	tokIdent filterTokenKind = iota
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokEOF
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{tokLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokRParen, ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, filterToken{tokComma, ","})
+			i++
+		case r == '\'' || r == '"':
+			quote := r
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == quote {
+					if i+1 < len(runes) && runes[i+1] == quote {
+						sb.WriteRune(quote)
+						i += 2
+						continue
+					}
+					i++
+					closed = true
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated %c", quote)
+			}
+			kind := tokString
+			if quote == '"' {
+				kind = tokIdent
+			}
+			tokens = append(tokens, filterToken{kind, sb.String()})
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := op + string(runes[i+1]); two {
+				case "!=", "<>", "<=", ">=", "==":
+					op = two
+				}
+			}
+			i += len(op)
+			switch op {
+			case "!":
+				return nil, fmt.Errorf("unexpected !")
+			case "<>":
+				op = "!="
+			case "==":
+				op = "="
+			}
+			tokens = append(tokens, filterToken{tokOp, op})
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E') {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokNumber, string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{tokIdent, string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected %q", r)
+		}
+	}
+
+	return append(tokens, filterToken{kind: tokEOF}), nil
+}
+
+type filterParser struct {
+	tokens  []filterToken
+	pos     int
+	columns []string
+}
+
+// compileFilter parses a filter bar expression against the given columns.
+func compileFilter(expr string, columns []string) (rowFilter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokens: tokens, columns: columns}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	return f, nil
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (rowFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (rowFilter, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (rowFilter, error) {
+	if p.keyword("not") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notFilter{inner}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokRParen {
+			return nil, fmt.Errorf("missing )")
+		}
+		return f, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (rowFilter, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, fmt.Errorf("expected a column name, got %q", t.text)
+	}
+	col := -1
+	for i, c := range p.columns {
+		if strings.EqualFold(c, t.text) {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return nil, fmt.Errorf("unknown column %q", t.text)
+	}
+
+	f := compareFilter{col: col}
+
+	switch {
+	case p.keyword("is"):
+		f.op = "is null"
+		if p.keyword("not") {
+			f.op = "is not null"
+		}
+		if !p.keyword("null") {
+			return nil, fmt.Errorf("expected NULL after IS")
+		}
+		return f, nil
+
+	case p.keyword("not"):
+		if p.keyword("like") {
+			return p.finishLike(f, "not like")
+		}
+		if p.keyword("in") {
+			inner, err := p.finishIn(f)
+			if err != nil {
+				return nil, err
+			}
+			return notFilter{inner}, nil
+		}
+		return nil, fmt.Errorf("expected LIKE or IN after NOT")
+
+	case p.keyword("like"):
+		return p.finishLike(f, "like")
+
+	case p.keyword("in"):
+		return p.finishIn(f)
+	}
+
+	op := p.next()
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator after %s", t.text)
+	}
+	value, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	f.op = op.text
+	f.values = []string{value}
+	return f, nil
+}
+
+func (p *filterParser) finishLike(f compareFilter, op string) (rowFilter, error) {
+	pattern, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	f.op = op
+	f.like = likeToRegexp(pattern)
+	return f, nil
+}
+
+func (p *filterParser) finishIn(f compareFilter) (rowFilter, error) {
+	if p.next().kind != tokLParen {
+		return nil, fmt.Errorf("expected ( after IN")
+	}
+	for {
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		f.values = append(f.values, value)
+
+		t := p.next()
+		if t.kind == tokRParen {
+			break
+		}
+		if t.kind != tokComma {
+			return nil, fmt.Errorf("expected , or ) in IN list")
+		}
+	}
+	f.op = "in"
+	return f, nil
+}
+
+func (p *filterParser) literal() (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber:
+		return t.text, nil
+	case tokIdent:
+		if strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false") {
+			return strings.ToLower(t.text), nil
+		}
+	}
+	if t.kind == tokEOF {
+		return "", fmt.Errorf("expected a value")
+	}
+	return "", fmt.Errorf("expected a value, got %q (quote text with '')", t.text)
+}
+
+// likeToRegexp converts a SQL LIKE pattern to a case-insensitive regexp.
+func likeToRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,76 @@
+package table
+
+import "testing"
+
+func TestCompileFilter(t *testing.T) {
+	columns := []string{"id", "status", "amount", "note"}
+	rows := [][]string{
+		{"1", "failed", "150", "refund requested"},
+		{"2", "failed", "20", "NULL"},
+		{"3", "paid", "300", "ok"},
+		{"4", "pending", "99.5", "O'Brien"},
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"status = 'failed' and amount > 100", []string{"1"}},
+		{"status = 'failed' or amount >= 300", []string{"1", "2", "3"}},
+		{"amount < 100", []string{"2", "4"}},
+		{"amount != 20", []string{"1", "3", "4"}},
+		{"amount <> 20 and not status = 'paid'", []string{"1", "4"}},
+		{"note is null", []string{"2"}},
+		{"note is not null and note like '%REFUND%'", []string{"1"}},
+		{"note not like 'o%'", []string{"1"}},
+		{"status in ('paid', 'pending')", []string{"3", "4"}},
+		{"status not in ('paid', 'pending')", []string{"1", "2"}},
+		{"(status = 'paid' or status = 'pending') and amount > 100", []string{"3"}},
+		{"note = 'O''Brien'", []string{"4"}},
+		{"\"Amount\" = 150", []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		f, err := compileFilter(tt.expr, columns)
+		if err != nil {
+			t.Errorf("compileFilter(%q) error = %v", tt.expr, err)
+			continue
+		}
+
+		var got []string
+		for _, row := range rows {
+			if f.match(row) {
+				got = append(got, row[0])
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestCompileFilter_Errors(t *testing.T) {
+	columns := []string{"id", "status"}
+
+	for _, expr := range []string{
+		"missing = 1",
+		"status = failed",
+		"status = 'open",
+		"status =",
+		"status is 'x'",
+		"(status = 'a'",
+		"status = 'a' status",
+		"status ! 'a'",
+	} {
+		if _, err := compileFilter(expr, columns); err == nil {
+			t.Errorf("compileFilter(%q) should fail", expr)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// navRowLimit caps how many rows a foreign-key jump loads
+const navRowLimit = 1000
+
+// fkChooserState holds the pending choice when several foreign keys
+// reference the current table.
+type fkChooserState struct {
+	active  bool
+	choices []db.ForeignKey
+}
+
+// followForeignKey opens the row referenced by the selected FK cell.
+func (m Model) followForeignKey() (tea.Model, tea.Cmd) {
+	if m.dbConnection == nil || m.selectedRow >= m.numRows() {
+		return m, nil
+	}
+
+	col := m.selectedCol
+	if col >= len(m.columnFKs) || m.columnFKs[col] == "" {
+		return m.withNavStatus(
+			fmt.Sprintf("%s is not a foreign key", m.columns[col]),
+		)
+	}
+
+	ref := m.columnFKs[col]
+	dot := strings.LastIndex(ref, ".")
+	if dot < 0 {
+		return m, nil
+	}
+
+	return m.navigateTo(ref[:dot], ref[dot+1:], m.data[m.selectedRow][col])
+}
+
+// listChildRows opens the rows of other tables that reference the current
+// row. When several foreign keys point here, the user picks one first.
+func (m Model) listChildRows() (tea.Model, tea.Cmd) {
+	if m.dbConnection == nil || m.tableName == "" ||
+		m.selectedRow >= m.numRows() {
+		return m, nil
+	}
+
+	fks, err := m.dbConnection.GetForeignKeysReferencingTable(m.tableName)
+	if err != nil {
+		return m.withNavStatus(fmt.Sprintf("✗ %v", err))
+	}
+	if len(fks) == 0 {
+		return m.withNavStatus(
+			fmt.Sprintf("No tables reference %s", m.tableName),
+		)
+	}
+	if len(fks) == 1 {
+		return m.openChildRows(fks[0])
+	}
+
+	if len(fks) > 9 {
+		fks = fks[:9]
+	}
+	m.fkChooser = fkChooserState{active: true, choices: fks}
+	return m, nil
+}
+
+func (m Model) handleFKChooser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "esc" || key == "q" {
+		m.fkChooser = fkChooserState{}
+		return m, nil
+	}
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+		idx := int(key[0] - '1')
+		if idx < len(m.fkChooser.choices) {
+			fk := m.fkChooser.choices[idx]
+			m.fkChooser = fkChooserState{}
+			return m.openChildRows(fk)
+		}
+	}
+
+	return m, nil
+}
+
+// openChildRows lists rows of fk.ReferencedTable (the child table, as
+// returned by GetForeignKeysReferencingTable) pointing at the current row.
+func (m Model) openChildRows(fk db.ForeignKey) (tea.Model, tea.Cmd) {
+	col := indexOfName(m.srcColumns, fk.ReferencedColumn)
+	if col < 0 {
+		return m.withNavStatus(
+			fmt.Sprintf("Column %s is not in the result", fk.ReferencedColumn),
+		)
+	}
+	value, _ := m.rowValue(m.selectedRow, m.srcColumns[col])
+	return m.navigateTo(fk.ReferencedTable, fk.Column, value)
+}
+
+// navigateTo runs SELECT * FROM table WHERE column = value and stacks the
+// result on top of the current view.
+func (m Model) navigateTo(table, column, value string) (tea.Model, tea.Cmd) {
+	if value == "NULL" {
+		return m.withNavStatus("NULL reference, nothing to follow")
+	}
+
+	conn := m.dbConnection
+	// Reserved words such as order and mixed-case names need quoting
+	quotedTable, quotedColumn := db.QuoteName(conn, table), db.QuoteName(conn, column)
+	query := conn.ApplyRowLimit(
+		fmt.Sprintf(
+			"SELECT * FROM %s WHERE %s = %s",
+			quotedTable,
+			quotedColumn,
+			conn.GetPlaceholder(1),
+		),
+		navRowLimit,
+	)
+	displaySQL := fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s = '%s'",
+		quotedTable,
+		quotedColumn,
+		escapeSQLValue(value),
+	)
+
+	start := time.Now()
+	rows, err := conn.ExecQuery(query, value)
+	if err != nil {
+		return m.withNavStatus(fmt.Sprintf("✗ %v", err))
+	}
+	columns, columnTypes, data, err := db.FormatTableDataWithTypes(rows)
+	rows.Close()
+	if err != nil {
+		return m.withNavStatus(fmt.Sprintf("✗ %v", err))
+	}
+	elapsed := time.Since(start)
+
+	primaryKey := ""
+	if metadata, err := conn.GetTableMetadata(table); err == nil &&
+		metadata != nil && len(metadata.PrimaryKeys) > 0 {
+		primaryKey = metadata.PrimaryKeys[0]
+	}
+
+	label := fmt.Sprintf("%s %s=%s", table, column, value)
+	child := New(
+		columns,
+		columnTypes,
+		data,
+		elapsed,
+		conn,
+		table,
+		primaryKey,
+		db.Query{Name: label, SQL: displaySQL, TableName: table},
+		m.cellWidth,
+		m.uiVisibility,
+	)
+	child.saveQueryCallback = m.saveQueryCallback
+
+	return m.pushView(child, label), nil
+}
+
+// pushView stacks child on top of m, extending the breadcrumb with label.
+func (m Model) pushView(child Model, label string) Model {
+	path := m.navPath
+	if len(path) == 0 {
+		path = []string{m.navRootLabel()}
+	}
+
+	parent := m
+	parent.navStack = nil
+	parent.statusMessage = ""
+
+	child.navStack = append(append([]Model{}, m.navStack...), parent)
+	child.navPath = append(append([]string{}, path...), label)
+
+	return child.handleWindowResize(
+		tea.WindowSizeMsg{Width: m.width, Height: m.height},
+	)
+}
+
+// popView returns to the view below the current one in the navigation stack.
+func (m Model) popView() Model {
+	if len(m.navStack) == 0 {
+		return m
+	}
+
+	last := len(m.navStack) - 1
+	prev := m.navStack[last]
+	prev.navStack = m.navStack[:last]
+
+	return prev.handleWindowResize(
+		tea.WindowSizeMsg{Width: m.width, Height: m.height},
+	)
+}
+
+func (m Model) navRootLabel() string {
+	if m.currentQuery.Name != "" {
+		return m.currentQuery.Name
+	}
+	if m.tableName != "" {
+		return m.tableName
+	}
+	return "results"
+}
+
+func (m Model) renderBreadcrumb() string {
+	var parts []string
+	for i, p := range m.navPath {
+		if i == len(m.navPath)-1 {
+			parts = append(parts, styles.Title.Render(p))
+		} else {
+			parts = append(parts, styles.Faint.Render(p))
+		}
+	}
+	return strings.Join(parts, styles.Faint.Render(" › ")) +
+		styles.Faint.Render("  b back")
+}
+
+func (m Model) renderFKChooser() string {
+	var b strings.Builder
+	b.WriteString(
+		styles.Title.Render(
+			fmt.Sprintf("Rows referencing %s via:", m.tableName),
+		),
+	)
+	for i, fk := range m.fkChooser.choices {
+		b.WriteString(
+			fmt.Sprintf(
+				"\n  %s %s.%s",
+				styles.TableHeader.Render(fmt.Sprintf("%d", i+1)),
+				fk.ReferencedTable,
+				fk.Column,
+			),
+		)
+	}
+	b.WriteString("\n" + styles.Faint.Render("  1-9 choose • esc cancel"))
+	return b.String()
+}
+
+func (m Model) withNavStatus(msg string) (tea.Model, tea.Cmd) {
+	m.statusMessage = msg
+	return m, m.blinkCmd()
+}
@@ -0,0 +1,118 @@
+package table
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newNavModel(name string, rows [][]string) Model {
+	m := New(
+		[]string{"id", "customer_id"},
+		nil,
+		rows,
+		time.Millisecond,
+		nil,
+		"",
+		"",
+		db.Query{Name: name},
+		15,
+		config.UIVisibility{},
+	)
+	return m.handleWindowResize(tea.WindowSizeMsg{Width: 80, Height: 24})
+}
+
+func TestModel_NavigationStack(t *testing.T) {
+	orders := newNavModel("orders", [][]string{{"1", "42"}, {"2", "42"}})
+	orders.selectedRow = 1
+
+	customer := newNavModel("customers id=42", [][]string{{"42", "NULL"}})
+	customer = orders.pushView(customer, "customers id=42")
+
+	others := newNavModel("orders customer_id=42", [][]string{{"1", "42"}})
+	others = customer.pushView(others, "orders customer_id=42")
+
+	if len(others.navStack) != 2 {
+		t.Fatalf("navStack length = %d, want 2", len(others.navStack))
+	}
+	wantPath := []string{"orders", "customers id=42", "orders customer_id=42"}
+	if len(others.navPath) != len(wantPath) {
+		t.Fatalf("navPath = %v, want %v", others.navPath, wantPath)
+	}
+	for i := range wantPath {
+		if others.navPath[i] != wantPath[i] {
+			t.Errorf("navPath[%d] = %q, want %q", i, others.navPath[i], wantPath[i])
+		}
+	}
+	if others.width != 80 {
+		t.Errorf("pushed view width = %d, want 80", others.width)
+	}
+
+	back := others.popView()
+	if back.currentQuery.Name != "customers id=42" || len(back.navStack) != 1 {
+		t.Errorf("after one back: %q with stack %d", back.currentQuery.Name, len(back.navStack))
+	}
+
+	updated, _ := back.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	root := updated.(Model)
+	if root.currentQuery.Name != "orders" || len(root.navStack) != 0 {
+		t.Errorf("after two backs: %q with stack %d", root.currentQuery.Name, len(root.navStack))
+	}
+	if root.selectedRow != 1 {
+		t.Errorf("root selectedRow = %d, want cursor preserved at 1", root.selectedRow)
+	}
+	if root.popView().currentQuery.Name != "orders" {
+		t.Errorf("back on the root view should be a no-op")
+	}
+}
+
+func TestModel_FKChooserCapturesInput(t *testing.T) {
+	m := newNavModel("orders", [][]string{{"1", "42"}})
+	m.fkChooser = fkChooserState{
+		active: true,
+		choices: []db.ForeignKey{
+			{Column: "customer_id", ReferencedTable: "orders", ReferencedColumn: "id"},
+		},
+	}
+
+	if !m.capturingInput() {
+		t.Errorf("chooser should capture input so tab does not switch tabs")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).fkChooser.active {
+		t.Errorf("esc should close the chooser")
+	}
+}
+
+func TestModel_NavigateToQuotesNames(t *testing.T) {
+	conn, err := db.NewSQLiteConnection("nav", filepath.Join(t.TempDir(), "nav.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Exec(`CREATE TABLE "order" (id INTEGER PRIMARY KEY, "Group" TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec(`INSERT INTO "order" VALUES (7, 'a')`); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newNavModel("lines", [][]string{{"1", "7"}})
+	m.dbConnection = conn
+	for _, column := range []string{"id", "Group"} {
+		value := map[string]string{"id": "7", "Group": "a"}[column]
+		updated, _ := m.navigateTo("order", column, value)
+		child := updated.(Model)
+		if len(child.navStack) != 1 || len(child.data) != 1 || child.data[0][0] != "7" {
+			t.Errorf("navigating to order.%s: status %q, rows %v", column, child.statusMessage, child.data)
+		}
+	}
+}
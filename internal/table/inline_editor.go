@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/caiolandgraf/pam/internal/mask"
 	"github.com/caiolandgraf/pam/internal/styles"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -196,7 +197,7 @@ func (m Model) executeDeleteRows(rows []int) (tea.Model, tea.Cmd) {
 			})
 		}
 
-		m.data = append(m.data[:row], m.data[row+1:]...)
+		m = m.removeRow(row)
 		deleted++
 	}
 
@@ -246,7 +247,7 @@ func (m Model) openValueEditor(
 	if colIndex >= 0 && colIndex < len(m.columns) {
 		columnName = m.columns[colIndex]
 	}
-	masked := isSensitiveColumn(columnName)
+	masked := mask.IsSensitiveColumn(columnName)
 
 	input := textinput.New()
 	input.Prompt = "  New: "
@@ -361,22 +362,3 @@ func (m Model) renderValueEditorView() string {
 
 	return b.String()
 }
-
-func isSensitiveColumn(name string) bool {
-	n := strings.ToLower(strings.TrimSpace(name))
-	if n == "" {
-		return false
-	}
-
-	if strings.Contains(n, "password") ||
-		strings.Contains(n, "passwd") ||
-		strings.Contains(n, "pwd") ||
-		strings.Contains(n, "secret") ||
-		strings.Contains(n, "token") ||
-		strings.Contains(n, "apikey") ||
-		strings.Contains(n, "api_key") {
-		return true
-	}
-
-	return strings.HasSuffix(n, "_key")
-}
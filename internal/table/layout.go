@@ -0,0 +1,640 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewMetadataKey is the db.Query.Metadata key holding a saved table view
+const viewMetadataKey = "view"
+
+// tableView is the client-side layout applied over the loaded result. It
+// never re-queries the database, and is saved per query as JSON.
+type tableView struct {
+	Filter string    `json:"filter,omitempty"`
+	Sort   []sortKey `json:"sort,omitempty"`
+	Hidden []string  `json:"hidden,omitempty"`
+	Order  []string  `json:"order,omitempty"`
+	Pinned []string  `json:"pinned,omitempty"`
+}
+
+type sortKey struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc,omitempty"`
+}
+
+func (v tableView) isEmpty() bool {
+	return v.Filter == "" && len(v.Sort) == 0 && len(v.Hidden) == 0 &&
+		len(v.Order) == 0 && len(v.Pinned) == 0
+}
+
+// loadView reads a saved view from query metadata.
+func loadView(query db.Query) (tableView, bool) {
+	raw := query.Metadata[viewMetadataKey]
+	if raw == "" {
+		return tableView{}, false
+	}
+	var v tableView
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return tableView{}, false
+	}
+	return v, true
+}
+
+func containsName(names []string, name string) bool {
+	return indexOfName(names, name) >= 0
+}
+
+func indexOfName(names []string, name string) int {
+	for i, n := range names {
+		if strings.EqualFold(n, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func withoutName(names []string, name string) []string {
+	var result []string
+	for _, n := range names {
+		if !strings.EqualFold(n, name) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// layout returns every source column index in display order, hidden ones
+// included: pinned columns first, then the saved order, then the rest.
+func (v tableView) layout(columns []string) (cols []int, pinned int) {
+	seen := make([]bool, len(columns))
+
+	for _, name := range v.Pinned {
+		if i := indexOfName(columns, name); i >= 0 && !seen[i] &&
+			!containsName(v.Hidden, name) {
+			seen[i] = true
+			cols = append(cols, i)
+		}
+	}
+	pinned = len(cols)
+
+	for _, name := range v.Order {
+		if i := indexOfName(columns, name); i >= 0 && !seen[i] {
+			seen[i] = true
+			cols = append(cols, i)
+		}
+	}
+	for i := range columns {
+		if !seen[i] {
+			cols = append(cols, i)
+		}
+	}
+
+	return cols, pinned
+}
+
+// visibleColumns is layout without hidden columns.
+func (v tableView) visibleColumns(columns []string) ([]int, int) {
+	all, pinned := v.layout(columns)
+	var cols []int
+	for _, i := range all {
+		if !containsName(v.Hidden, columns[i]) {
+			cols = append(cols, i)
+		}
+	}
+	if len(cols) == 0 {
+		return all, pinned
+	}
+	return cols, pinned
+}
+
+// swapColumns exchanges two entries of layout. Pinned columns only move
+// among themselves.
+func (v tableView) swapColumns(columns []string, layout []int, i, j int) tableView {
+	a, b := columns[layout[i]], columns[layout[j]]
+	pinnedA, pinnedB := containsName(v.Pinned, a), containsName(v.Pinned, b)
+	if pinnedA != pinnedB {
+		return v
+	}
+
+	if pinnedA {
+		pins := append([]string{}, v.Pinned...)
+		x, y := indexOfName(pins, a), indexOfName(pins, b)
+		pins[x], pins[y] = pins[y], pins[x]
+		v.Pinned = pins
+		return v
+	}
+
+	order := make([]string, len(layout))
+	for k, idx := range layout {
+		order[k] = columns[idx]
+	}
+	order[i], order[j] = order[j], order[i]
+	v.Order = order
+	return v
+}
+
+// initView records the loaded result as the view source and applies any
+// view saved with the query.
+func (m Model) initView(query db.Query) Model {
+	m.srcColumns = m.columns
+	m.srcColumnTypes = m.columnTypes
+	m.srcColumnFKs = m.columnFKs
+	m.srcData = m.data
+	m.rowMap = identity(len(m.data))
+	m.colMap = identity(len(m.columns))
+
+	if v, ok := loadView(query); ok {
+		m.view = v
+		m = m.applyView()
+	}
+	return m
+}
+
+func identity(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+func isIdentity(idx []int, n int) bool {
+	if len(idx) != n {
+		return false
+	}
+	for i, v := range idx {
+		if v != i {
+			return false
+		}
+	}
+	return true
+}
+
+// applyView rebuilds the displayed rows and columns from the source using
+// the current filter, sort and column layout.
+func (m Model) applyView() Model {
+	selectedName := ""
+	if m.selectedCol >= 0 && m.selectedCol < m.numCols() {
+		selectedName = m.columns[m.selectedCol]
+	}
+	selectedSrcRow := m.sourceRow(m.selectedRow)
+
+	var filter rowFilter
+	m.filterError = ""
+	if m.view.Filter != "" {
+		f, err := compileFilter(m.view.Filter, m.srcColumns)
+		if err != nil {
+			m.filterError = err.Error()
+		} else {
+			filter = f
+		}
+	}
+
+	rows := make([]int, 0, len(m.srcData))
+	for i, row := range m.srcData {
+		if filter == nil || filter.match(row) {
+			rows = append(rows, i)
+		}
+	}
+
+	type resolvedKey struct {
+		col  int
+		desc bool
+	}
+	var keys []resolvedKey
+	for _, k := range m.view.Sort {
+		if i := indexOfName(m.srcColumns, k.Column); i >= 0 {
+			keys = append(keys, resolvedKey{i, k.Desc})
+		}
+	}
+	if len(keys) > 0 {
+		sort.SliceStable(rows, func(a, b int) bool {
+			ra, rb := m.srcData[rows[a]], m.srcData[rows[b]]
+			for _, k := range keys {
+				va, vb := ra[k.col], rb[k.col]
+				// NULLs sort last in both directions
+				if va == "NULL" || vb == "NULL" {
+					if va == vb {
+						continue
+					}
+					return vb == "NULL"
+				}
+				c := compareValues(va, vb)
+				if c == 0 {
+					continue
+				}
+				if k.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	cols, pinned := m.view.visibleColumns(m.srcColumns)
+
+	if isIdentity(cols, len(m.srcColumns)) {
+		m.columns = m.srcColumns
+		m.columnTypes = m.srcColumnTypes
+		m.columnFKs = m.srcColumnFKs
+	} else {
+		m.columns = project(m.srcColumns, cols)
+		m.columnTypes = project(m.srcColumnTypes, cols)
+		m.columnFKs = project(m.srcColumnFKs, cols)
+	}
+
+	if isIdentity(rows, len(m.srcData)) && isIdentity(cols, len(m.srcColumns)) {
+		m.data = m.srcData
+	} else {
+		m.data = make([][]string, len(rows))
+		for i, r := range rows {
+			m.data[i] = project(m.srcData[r], cols)
+		}
+	}
+
+	m.rowMap = rows
+	m.colMap = cols
+	m.pinnedCols = pinned
+
+	// Row indexes changed, so marks and search hits no longer apply
+	m.markedRows = map[int]bool{}
+	m.searchMatches = []CellPosition{}
+	m.searchColMatches = []int{}
+	m.visualMode = false
+
+	m.selectedRow = 0
+	for i, r := range rows {
+		if r == selectedSrcRow {
+			m.selectedRow = i
+			break
+		}
+	}
+	m.selectedCol = 0
+	if i := indexOfName(m.columns, selectedName); i >= 0 {
+		m.selectedCol = i
+	}
+	m.offsetY = 0
+	m.offsetX = 0
+
+	if m.width > 0 {
+		m = m.handleWindowResize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+	if m.selectedRow >= m.offsetY+m.visibleRows {
+		m.offsetY = m.selectedRow - m.visibleRows + 1
+	}
+	return m.keepColumnVisible()
+}
+
+func project(values []string, idx []int) []string {
+	result := make([]string, len(idx))
+	for i, j := range idx {
+		if j < len(values) {
+			result[i] = values[j]
+		}
+	}
+	return result
+}
+
+// sourceRow maps a displayed row to its index in the loaded result.
+func (m Model) sourceRow(row int) int {
+	if row >= 0 && row < len(m.rowMap) {
+		return m.rowMap[row]
+	}
+	return row
+}
+
+// rowValue returns a column of a displayed row from the loaded result, so
+// hidden columns such as the primary key stay reachable.
+func (m Model) rowValue(row int, column string) (string, bool) {
+	src := m.sourceRow(row)
+	if len(m.rowMap) == m.numRows() && src >= 0 && src < len(m.srcData) {
+		for i, c := range m.srcColumns {
+			if c == column {
+				return m.srcData[src][i], true
+			}
+		}
+	}
+
+	if row < 0 || row >= m.numRows() {
+		return "", false
+	}
+	for i, c := range m.columns {
+		if c == column {
+			return m.data[row][i], true
+		}
+	}
+	return "", false
+}
+
+// setCell updates a displayed cell and the loaded result behind it.
+func (m Model) setCell(row, col int, value string) Model {
+	m.data[row][col] = value
+	if len(m.rowMap) == m.numRows() && col < len(m.colMap) {
+		src := m.rowMap[row]
+		if src < len(m.srcData) && m.colMap[col] < len(m.srcData[src]) {
+			m.srcData[src][m.colMap[col]] = value
+		}
+	}
+	return m
+}
+
+// removeRow drops a displayed row and its source row.
+func (m Model) removeRow(row int) Model {
+	if row < 0 || row >= m.numRows() {
+		return m
+	}
+
+	if len(m.rowMap) == m.numRows() {
+		src := m.rowMap[row]
+		if src < len(m.srcData) {
+			m.srcData = append(m.srcData[:src:src], m.srcData[src+1:]...)
+		}
+		rowMap := append(m.rowMap[:row:row], m.rowMap[row+1:]...)
+		for i, r := range rowMap {
+			if r > src {
+				rowMap[i] = r - 1
+			}
+		}
+		m.rowMap = rowMap
+	}
+
+	m.data = append(m.data[:row:row], m.data[row+1:]...)
+	return m
+}
+
+// keepColumnVisible scrolls horizontally so the selected column is shown
+// next to any pinned columns.
+func (m Model) keepColumnVisible() Model {
+	if m.selectedCol < m.pinnedCols {
+		return m
+	}
+
+	span := m.visibleCols - m.pinnedCols
+	if span < 1 {
+		span = 1
+	}
+	first := max(m.offsetX, m.pinnedCols)
+	if m.selectedCol < first {
+		first = m.selectedCol
+	} else if m.selectedCol >= first+span {
+		first = m.selectedCol - span + 1
+	}
+	m.offsetX = first
+	return m
+}
+
+// displayedColumns lists the column indexes on screen: pinned columns,
+// then the horizontally scrolled ones.
+func (m Model) displayedColumns() []int {
+	var cols []int
+	for j := 0; j < m.pinnedCols && j < m.numCols() && len(cols) < m.visibleCols; j++ {
+		cols = append(cols, j)
+	}
+	for j := max(m.offsetX, m.pinnedCols); j < m.numCols() && len(cols) < m.visibleCols; j++ {
+		cols = append(cols, j)
+	}
+	return cols
+}
+
+func (m Model) startFilter() Model {
+	m.filterMode = true
+	m.filterInput = m.view.Filter
+	m.filterCursor = len(m.filterInput)
+	m.filterError = ""
+	return m
+}
+
+// handleFilterInput processes keystrokes in the filter bar
+func (m Model) handleFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		expr := strings.TrimSpace(m.filterInput)
+		if expr != "" {
+			if _, err := compileFilter(expr, m.srcColumns); err != nil {
+				m.filterError = err.Error()
+				return m, nil
+			}
+		}
+		m.filterMode = false
+		m.view.Filter = expr
+		return m.applyView(), nil
+	case "esc":
+		m.filterMode = false
+		m.filterError = ""
+	case "ctrl+c":
+		return m, tea.Quit
+	case "ctrl+u":
+		m.filterInput = ""
+		m.filterCursor = 0
+	case "backspace":
+		if m.filterCursor > 0 && len(m.filterInput) > 0 {
+			m.filterInput = m.filterInput[:m.filterCursor-1] + m.filterInput[m.filterCursor:]
+			m.filterCursor--
+		}
+	case "left":
+		if m.filterCursor > 0 {
+			m.filterCursor--
+		}
+	case "right":
+		if m.filterCursor < len(m.filterInput) {
+			m.filterCursor++
+		}
+	default:
+		if len(msg.String()) == 1 {
+			m.filterInput = m.filterInput[:m.filterCursor] + msg.String() + m.filterInput[m.filterCursor:]
+			m.filterCursor++
+		}
+	}
+	return m, nil
+}
+
+// toggleLocalSort cycles the selected column through ASC → DESC → unsorted
+// in the local sort. Other sorted columns are kept, so pressing it on
+// several columns builds a multi-column sort in that priority.
+func (m Model) toggleLocalSort() Model {
+	if m.selectedCol < 0 || m.selectedCol >= m.numCols() {
+		return m
+	}
+	name := m.columns[m.selectedCol]
+
+	keys := append([]sortKey{}, m.view.Sort...)
+	found := false
+	for i, k := range keys {
+		if !strings.EqualFold(k.Column, name) {
+			continue
+		}
+		found = true
+		if k.Desc {
+			keys = append(keys[:i], keys[i+1:]...)
+		} else {
+			keys[i].Desc = true
+		}
+		break
+	}
+	if !found {
+		keys = append(keys, sortKey{Column: name})
+	}
+
+	m.view.Sort = keys
+	return m.applyView()
+}
+
+func (m Model) hideColumn() (Model, tea.Cmd) {
+	if m.numCols() <= 1 {
+		m.statusMessage = "Cannot hide the last column"
+		return m, m.blinkCmd()
+	}
+	name := m.columns[m.selectedCol]
+	m.view.Hidden = append(append([]string{}, m.view.Hidden...), name)
+	m.view.Pinned = withoutName(m.view.Pinned, name)
+	col := m.selectedCol
+
+	m = m.applyView()
+	m.selectedCol = min(col, m.numCols()-1)
+	return m.keepColumnVisible(), nil
+}
+
+func (m Model) togglePinColumn() Model {
+	name := m.columns[m.selectedCol]
+	if containsName(m.view.Pinned, name) {
+		m.view.Pinned = withoutName(m.view.Pinned, name)
+	} else {
+		m.view.Pinned = append(append([]string{}, m.view.Pinned...), name)
+	}
+	return m.applyView()
+}
+
+// moveColumn swaps the selected column with its displayed neighbour.
+func (m Model) moveColumn(delta int) Model {
+	target := m.selectedCol + delta
+	if target < 0 || target >= m.numCols() {
+		return m
+	}
+
+	layout, _ := m.view.layout(m.srcColumns)
+	i := indexOfInt(layout, m.colMap[m.selectedCol])
+	j := indexOfInt(layout, m.colMap[target])
+	if i < 0 || j < 0 {
+		return m
+	}
+
+	m.view = m.view.swapColumns(m.srcColumns, layout, i, j)
+	return m.applyView()
+}
+
+func indexOfInt(values []int, v int) int {
+	for i, x := range values {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) resetView() Model {
+	m.view = tableView{}
+	return m.applyView()
+}
+
+// saveView stores the current view in the saved query's metadata. Only
+// Name, Id and Metadata are sent, so the stored SQL is left untouched.
+func (m Model) saveView() (tea.Model, tea.Cmd) {
+	if !m.isNamedQuery() || m.saveQueryCallback == nil {
+		m.statusMessage = "Save the query with s before saving its view"
+		return m, m.blinkCmd()
+	}
+
+	meta := map[string]string{}
+	for k, v := range m.currentQuery.Metadata {
+		meta[k] = v
+	}
+	if m.view.isEmpty() {
+		delete(meta, viewMetadataKey)
+	} else {
+		raw, err := json.Marshal(m.view)
+		if err != nil {
+			m.statusMessage = styles.Error.Render("✗ " + err.Error())
+			return m, m.blinkCmd()
+		}
+		meta[viewMetadataKey] = string(raw)
+	}
+
+	_, err := m.saveQueryCallback(db.Query{
+		Name:     m.currentQuery.Name,
+		Id:       m.currentQuery.Id,
+		Metadata: meta,
+	})
+	if err != nil {
+		m.statusMessage = styles.Error.Render("✗ Save failed: " + err.Error())
+		return m, m.blinkCmd()
+	}
+
+	m.currentQuery.Metadata = meta
+	m.statusMessage = styles.Success.Render(
+		"✓ View saved: " + m.currentQuery.Name,
+	)
+	return m, m.blinkCmd()
+}
+
+// viewSummary describes the active view in one line, or returns "".
+func (m Model) viewSummary() string {
+	var parts []string
+
+	if m.filterError != "" {
+		parts = append(parts, styles.Error.Render("filter error: "+m.filterError))
+	} else if m.view.Filter != "" {
+		parts = append(parts, "filter: "+m.view.Filter)
+	}
+	if len(m.view.Sort) > 0 {
+		var keys []string
+		for _, k := range m.view.Sort {
+			dir := "↑"
+			if k.Desc {
+				dir = "↓"
+			}
+			keys = append(keys, k.Column+" "+dir)
+		}
+		parts = append(parts, "sort: "+strings.Join(keys, ", "))
+	}
+	if hidden := len(m.srcColumns) - m.numCols(); hidden > 0 {
+		parts = append(parts, fmt.Sprintf("%d hidden", hidden))
+	}
+	if m.pinnedCols > 0 {
+		parts = append(parts, fmt.Sprintf("%d pinned", m.pinnedCols))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	if m.numRows() != len(m.srcData) {
+		parts = append(parts, fmt.Sprintf("%d/%d rows", m.numRows(), len(m.srcData)))
+	}
+
+	return styles.Faint.Render("◇ ") +
+		styles.Faint.Render(strings.Join(parts, " • "))
+}
+
+// localSortIcon returns the header marker for a locally sorted column.
+func (m Model) localSortIcon(column string) string {
+	for i, k := range m.view.Sort {
+		if !strings.EqualFold(k.Column, column) {
+			continue
+		}
+		icon := " ↑"
+		if k.Desc {
+			icon = " ↓"
+		}
+		if len(m.view.Sort) > 1 {
+			icon += fmt.Sprintf("%d", i+1)
+		}
+		return icon
+	}
+	return ""
+}
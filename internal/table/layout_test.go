@@ -0,0 +1,178 @@
+package table
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newLayoutModel(query db.Query) Model {
+	m := New(
+		[]string{"id", "status", "amount"},
+		nil,
+		[][]string{
+			{"1", "failed", "150"},
+			{"2", "paid", "20"},
+			{"3", "failed", "NULL"},
+			{"4", "paid", "300"},
+		},
+		time.Millisecond,
+		nil,
+		"orders",
+		"id",
+		query,
+		10,
+		config.UIVisibility{},
+	)
+	return m.handleWindowResize(tea.WindowSizeMsg{Width: 120, Height: 30})
+}
+
+func columnValues(m Model, col int) []string {
+	var values []string
+	for _, row := range m.data {
+		values = append(values, row[col])
+	}
+	return values
+}
+
+func assertStrings(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", what, got, want)
+			return
+		}
+	}
+}
+
+func TestModel_LocalSort(t *testing.T) {
+	m := newLayoutModel(db.Query{Name: "orders"})
+
+	m.selectedCol = 1
+	m = m.toggleLocalSort() // status ASC
+	m.selectedCol = indexOfName(m.columns, "amount")
+	m = m.toggleLocalSort() // amount ASC
+	m = m.toggleLocalSort() // amount DESC
+
+	assertStrings(t, "ids", columnValues(m, 0), []string{"1", "3", "4", "2"})
+	if m.localSortIcon("amount") != " ↓2" {
+		t.Errorf("amount sort icon = %q, want \" ↓2\"", m.localSortIcon("amount"))
+	}
+
+	m = m.toggleLocalSort() // amount removed
+	if len(m.view.Sort) != 1 || m.view.Sort[0].Column != "status" {
+		t.Errorf("sort keys = %+v, want only status", m.view.Sort)
+	}
+}
+
+func TestModel_FilterAndWriteThrough(t *testing.T) {
+	m := newLayoutModel(db.Query{Name: "orders"})
+	m.view.Filter = "status = 'paid'"
+	m = m.applyView()
+
+	assertStrings(t, "filtered ids", columnValues(m, 0), []string{"2", "4"})
+
+	m = m.setCell(1, 2, "350")
+	if m.srcData[3][2] != "350" {
+		t.Errorf("setCell did not reach the source row: %v", m.srcData[3])
+	}
+
+	m = m.removeRow(0)
+	if len(m.srcData) != 3 || m.rowMap[0] != 2 {
+		t.Errorf("after removeRow: src=%v rowMap=%v", m.srcData, m.rowMap)
+	}
+
+	m.view.Filter = ""
+	m = m.applyView()
+	assertStrings(t, "unfiltered ids", columnValues(m, 0), []string{"1", "3", "4"})
+}
+
+func TestModel_ColumnLayout(t *testing.T) {
+	m := newLayoutModel(db.Query{Name: "orders"})
+
+	// Hiding the primary key keeps it reachable for updates and deletes
+	m.selectedCol = 0
+	m, _ = m.hideColumn()
+	assertStrings(t, "columns", m.columns, []string{"status", "amount"})
+	if pk, ok := m.rowValue(0, "id"); !ok || pk != "1" {
+		t.Errorf("rowValue(id) = %q, %v; want 1, true", pk, ok)
+	}
+
+	m.selectedCol = 1
+	m = m.togglePinColumn()
+	assertStrings(t, "pinned first", m.columns, []string{"amount", "status"})
+	if m.pinnedCols != 1 {
+		t.Errorf("pinnedCols = %d, want 1", m.pinnedCols)
+	}
+
+	// Pinned and unpinned columns do not swap places
+	m.selectedCol = 0
+	if moved := m.moveColumn(1); moved.columns[0] != "amount" {
+		t.Errorf("moveColumn crossed the pin boundary: %v", moved.columns)
+	}
+
+	m = m.resetView()
+	assertStrings(t, "reset", m.columns, []string{"id", "status", "amount"})
+
+	m.selectedCol = 0
+	m = m.moveColumn(1)
+	assertStrings(t, "moved", m.columns, []string{"status", "id", "amount"})
+	if m.selectedCol != 1 {
+		t.Errorf("selection should follow the moved column, got %d", m.selectedCol)
+	}
+}
+
+func TestModel_PinnedColumnsStayVisible(t *testing.T) {
+	m := newLayoutModel(db.Query{Name: "orders"})
+	m.view.Pinned = []string{"id"}
+	m = m.applyView()
+	m.visibleCols = 2
+
+	m = m.jumpToLastCol()
+	cols := m.displayedColumns()
+	if len(cols) != 2 || cols[0] != 0 || cols[1] != 2 {
+		t.Errorf("displayedColumns = %v, want [0 2]", cols)
+	}
+}
+
+func TestModel_SavedViewRestored(t *testing.T) {
+	raw, _ := json.Marshal(tableView{
+		Filter: "amount is not null",
+		Sort:   []sortKey{{Column: "amount", Desc: true}},
+		Hidden: []string{"status"},
+	})
+	query := db.Query{
+		Name:     "orders",
+		Id:       3,
+		Metadata: map[string]string{viewMetadataKey: string(raw)},
+	}
+
+	m := newLayoutModel(query)
+	assertStrings(t, "columns", m.columns, []string{"id", "amount"})
+	assertStrings(t, "ids", columnValues(m, 0), []string{"4", "1", "2"})
+
+	var saved db.Query
+	m.saveQueryCallback = func(q db.Query) (db.Query, error) {
+		saved = q
+		return q, nil
+	}
+	m = m.resetView()
+	updated, _ := m.saveView()
+	if saved.SQL != "" || saved.Name != "orders" {
+		t.Errorf("saveView should only send name and metadata, got %+v", saved)
+	}
+	if _, ok := saved.Metadata[viewMetadataKey]; ok {
+		t.Errorf("an empty view should clear the saved one")
+	}
+	if _, ok := updated.(Model).currentQuery.Metadata[viewMetadataKey]; ok {
+		t.Errorf("model metadata should reflect the cleared view")
+	}
+}
@@ -110,6 +110,33 @@ type Model struct {
 	confirmActive  bool
 	confirmMessage string
 	confirmRows    []int
+
+	// Foreign-key navigation: views below this one and the breadcrumb path
+	navStack  []Model
+	navPath   []string
+	fkChooser fkChooserState
+
+	// Client-side view (filter, local sort, column layout). src* hold the
+	// result as loaded; rowMap/colMap map displayed rows/columns back to it.
+	srcColumns     []string
+	srcColumnTypes []string
+	srcColumnFKs   []string
+	srcData        [][]string
+	rowMap         []int
+	colMap         []int
+	view           tableView
+	pinnedCols     int
+	filterMode     bool
+	filterInput    string
+	filterCursor   int
+	filterError    string
+	columnManager  columnManagerState
+
+	statsPanel statsPanelState
+
+	summaryBuilder summaryBuilderState
+
+	chartView chartViewState
 }
 
 type blinkMsg struct{}
@@ -176,7 +203,7 @@ func New(
 	// Extract sort information from query if present
 	sortCol, sortDir := extractSortFromQuery(query.SQL)
 
-	return Model{
+	m := Model{
 		selectedRow:      0,
 		selectedCol:      0,
 		offsetX:          0,
@@ -205,6 +232,8 @@ func New(
 		sortColumn:       sortCol,
 		sortDirection:    sortDir,
 	}
+
+	return m.initView(query)
 }
 
 func (m Model) Init() tea.Cmd {
@@ -255,6 +284,14 @@ func (m Model) calculateHeaderLines() int {
 		headerLines += strings.Count(formattedSQL, "\n") + 1
 	}
 
+	if len(m.navPath) > 0 {
+		headerLines++
+	}
+
+	if m.viewSummary() != "" {
+		headerLines++
+	}
+
 	// Always add separator line
 	headerLines++
 
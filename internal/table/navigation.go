@@ -33,9 +33,7 @@ func (m Model) moveDown() Model {
 func (m Model) moveLeft() Model {
 	if m.selectedCol > 0 {
 		m.selectedCol--
-		if m.selectedCol < m.offsetX {
-			m.offsetX = m.selectedCol
-		}
+		m = m.keepColumnVisible()
 	}
 	return m
 }
@@ -43,9 +41,7 @@ func (m Model) moveLeft() Model {
 func (m Model) moveRight() Model {
 	if m.selectedCol < m.numCols()-1 {
 		m.selectedCol++
-		if m.selectedCol >= m.offsetX+m.visibleCols {
-			m.offsetX = m.selectedCol - m.visibleCols + 1
-		}
+		m = m.keepColumnVisible()
 	}
 	return m
 }
@@ -58,10 +54,7 @@ func (m Model) jumpToFirstCol() Model {
 
 func (m Model) jumpToLastCol() Model {
 	m.selectedCol = m.numCols() - 1
-	if m.visibleCols < m.numCols() {
-		m.offsetX = m.numCols() - m.visibleCols
-	}
-	return m
+	return m.keepColumnVisible()
 }
 
 func (m Model) jumpToFirstRow() Model {
@@ -164,21 +157,23 @@ func (m Model) copySelection() (Model, tea.Cmd) {
 
 	var allRows [][]string
 
+	headerRow := make([]string, 0)
+	for col := minCol; col <= maxCol; col++ {
+		headerRow = append(headerRow, m.columns[col])
+	}
 	if m.visualMode {
-		headerRow := make([]string, 0)
-		for col := minCol; col <= maxCol; col++ {
-			headerRow = append(headerRow, m.columns[col])
-		}
 		allRows = append(allRows, headerRow)
 	}
 
+	var dataRows [][]string
 	for row := minRow; row <= maxRow; row++ {
 		dataRow := make([]string, 0)
 		for col := minCol; col <= maxCol; col++ {
 			dataRow = append(dataRow, m.data[row][col])
 		}
-		allRows = append(allRows, dataRow)
+		dataRows = append(dataRows, dataRow)
 	}
+	allRows = append(allRows, m.maskRows(headerRow, dataRows)...)
 
 	numCols := maxCol - minCol + 1
 	colWidths := make([]int, numCols)
@@ -294,7 +289,7 @@ func (m Model) handleDetailViewEditComplete(
 	}
 
 	// Update local data
-	m.data[m.selectedRow][m.selectedCol] = newValue
+	m = m.setCell(m.selectedRow, m.selectedCol, newValue)
 
 	// Close detail view and return to table with highlighted cell
 	m.detailViewMode = false
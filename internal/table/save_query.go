@@ -32,8 +32,9 @@ func (m Model) saveQuery() (tea.Model, tea.Cmd) {
 		queryToSave := db.Query{
 			Name: m.currentQuery.Name,
 
-			SQL: sqlToSave,
-			Id:  m.currentQuery.Id,
+			SQL:      sqlToSave,
+			Id:       m.currentQuery.Id,
+			Metadata: m.currentQuery.Metadata,
 		}
 
 		if m.saveQueryCallback != nil {
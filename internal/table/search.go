@@ -148,12 +148,7 @@ func (m Model) searchCells() Model {
 		if m.selectedRow >= m.offsetY+m.visibleRows {
 			m.offsetY = m.selectedRow - m.visibleRows + 1
 		}
-		if m.selectedCol < m.offsetX {
-			m.offsetX = m.selectedCol
-		}
-		if m.selectedCol >= m.offsetX+m.visibleCols {
-			m.offsetX = m.selectedCol - m.visibleCols + 1
-		}
+		m = m.keepColumnVisible()
 	}
 
 	return m
@@ -191,12 +186,7 @@ func (m Model) searchColumnHeaders() Model {
 			m.selectedCol = m.searchColMatches[0]
 		}
 
-		if m.selectedCol < m.offsetX {
-			m.offsetX = m.selectedCol
-		}
-		if m.selectedCol >= m.offsetX+m.visibleCols {
-			m.offsetX = m.selectedCol - m.visibleCols + 1
-		}
+		m = m.keepColumnVisible()
 	}
 
 	return m
@@ -227,12 +217,7 @@ func (m Model) nextSearchMatch() Model {
 			if m.selectedRow >= m.offsetY+m.visibleRows {
 				m.offsetY = m.selectedRow - m.visibleRows + 1
 			}
-			if m.selectedCol < m.offsetX {
-				m.offsetX = m.selectedCol
-			}
-			if m.selectedCol >= m.offsetX+m.visibleCols {
-				m.offsetX = m.selectedCol - m.visibleCols + 1
-			}
+			m = m.keepColumnVisible()
 			return m
 		}
 	}
@@ -247,12 +232,7 @@ func (m Model) nextSearchMatch() Model {
 	if m.selectedRow >= m.offsetY+m.visibleRows {
 		m.offsetY = m.selectedRow - m.visibleRows + 1
 	}
-	if m.selectedCol < m.offsetX {
-		m.offsetX = m.selectedCol
-	}
-	if m.selectedCol >= m.offsetX+m.visibleCols {
-		m.offsetX = m.selectedCol - m.visibleCols + 1
-	}
+	m = m.keepColumnVisible()
 
 	return m
 }
@@ -274,12 +254,7 @@ func (m Model) prevSearchMatch() Model {
 			if m.selectedRow >= m.offsetY+m.visibleRows {
 				m.offsetY = m.selectedRow - m.visibleRows + 1
 			}
-			if m.selectedCol < m.offsetX {
-				m.offsetX = m.selectedCol
-			}
-			if m.selectedCol >= m.offsetX+m.visibleCols {
-				m.offsetX = m.selectedCol - m.visibleCols + 1
-			}
+			m = m.keepColumnVisible()
 			return m
 		}
 	}
@@ -294,12 +269,7 @@ func (m Model) prevSearchMatch() Model {
 	if m.selectedRow >= m.offsetY+m.visibleRows {
 		m.offsetY = m.selectedRow - m.visibleRows + 1
 	}
-	if m.selectedCol < m.offsetX {
-		m.offsetX = m.selectedCol
-	}
-	if m.selectedCol >= m.offsetX+m.visibleCols {
-		m.offsetX = m.selectedCol - m.visibleCols + 1
-	}
+	m = m.keepColumnVisible()
 
 	return m
 }
@@ -313,24 +283,14 @@ func (m Model) nextColumnMatch() Model {
 		if matchCol > m.selectedCol {
 			m.selectedCol = matchCol
 
-			if m.selectedCol < m.offsetX {
-				m.offsetX = m.selectedCol
-			}
-			if m.selectedCol >= m.offsetX+m.visibleCols {
-				m.offsetX = m.selectedCol - m.visibleCols + 1
-			}
+			m = m.keepColumnVisible()
 			return m
 		}
 	}
 
 	m.selectedCol = m.searchColMatches[0]
 
-	if m.selectedCol < m.offsetX {
-		m.offsetX = m.selectedCol
-	}
-	if m.selectedCol >= m.offsetX+m.visibleCols {
-		m.offsetX = m.selectedCol - m.visibleCols + 1
-	}
+	m = m.keepColumnVisible()
 
 	return m
 }
@@ -345,24 +305,14 @@ func (m Model) prevColumnMatch() Model {
 		if matchCol < m.selectedCol {
 			m.selectedCol = matchCol
 
-			if m.selectedCol < m.offsetX {
-				m.offsetX = m.selectedCol
-			}
-			if m.selectedCol >= m.offsetX+m.visibleCols {
-				m.offsetX = m.selectedCol - m.visibleCols + 1
-			}
+			m = m.keepColumnVisible()
 			return m
 		}
 	}
 
 	m.selectedCol = m.searchColMatches[len(m.searchColMatches)-1]
 
-	if m.selectedCol < m.offsetX {
-		m.offsetX = m.selectedCol
-	}
-	if m.selectedCol >= m.offsetX+m.visibleCols {
-		m.offsetX = m.selectedCol - m.visibleCols + 1
-	}
+	m = m.keepColumnVisible()
 
 	return m
 }
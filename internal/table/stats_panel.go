@@ -0,0 +1,115 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/profile"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statsPanelState holds the column statistics panel opened with 'i'.
+type statsPanelState struct {
+	active bool
+	col    int
+	stats  profile.ColumnStats
+	err    string
+}
+
+// openStatsPanel profiles the selected column over the loaded rows.
+func (m Model) openStatsPanel(col int) Model {
+	if col < 0 || col >= m.numCols() {
+		return m
+	}
+
+	values := make([]string, m.numRows())
+	for i, row := range m.data {
+		values[i] = row[col]
+	}
+	typeName := ""
+	if col < len(m.columnTypes) {
+		typeName = m.columnTypes[col]
+	}
+
+	m.statsPanel = statsPanelState{
+		active: true,
+		col:    col,
+		stats: profile.Compute(
+			m.columns[col],
+			typeName,
+			values,
+			profile.DefaultTopN,
+			profile.DefaultBuckets,
+		),
+	}
+	return m
+}
+
+// serverStats recomputes the panel with aggregate queries over the whole
+// table instead of the loaded rows.
+func (m Model) serverStats() Model {
+	if m.dbConnection == nil || m.tableName == "" {
+		m.statsPanel.err = "Server-side stats need a single-table query"
+		return m
+	}
+
+	stats, err := profile.Server(
+		m.dbConnection,
+		m.tableName,
+		m.statsPanel.stats.Column,
+		m.statsPanel.stats.Type,
+		profile.DefaultTopN,
+		profile.DefaultBuckets,
+	)
+	if err != nil {
+		m.statsPanel.err = err.Error()
+		return m
+	}
+	m.statsPanel.stats = stats
+	m.statsPanel.err = ""
+	return m
+}
+
+func (m Model) handleStatsPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "i", "enter":
+		m.statsPanel = statsPanelState{}
+	case "left", "h":
+		if m.statsPanel.col > 0 {
+			return m.openStatsPanel(m.statsPanel.col - 1), nil
+		}
+	case "right", "l":
+		if m.statsPanel.col < m.numCols()-1 {
+			return m.openStatsPanel(m.statsPanel.col + 1), nil
+		}
+	case "r":
+		return m.serverStats(), nil
+	}
+	return m, nil
+}
+
+func (m Model) renderStatsPanel() string {
+	s := m.statsPanel.stats
+	var b strings.Builder
+
+	source := fmt.Sprintf("%d loaded rows", s.Rows)
+	if s.Source == profile.SourceServer {
+		source = "whole table " + m.tableName
+	}
+	b.WriteString(profile.RenderColumn(s, source))
+
+	if m.statsPanel.err != "" {
+		b.WriteString("\n" + styles.Error.Render("✗ "+m.statsPanel.err) + "\n")
+	}
+
+	help := "h/l column • esc close"
+	if m.tableName != "" && m.dbConnection != nil &&
+		s.Source != profile.SourceServer {
+		help = "h/l column • r whole table (server-side) • esc close"
+	}
+	b.WriteString("\n" + styles.Faint.Render(help))
+	return b.String()
+}
@@ -0,0 +1,396 @@
+package table
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxPivotColumns caps how many distinct values a pivot turns into headers
+const maxPivotColumns = 100
+
+var summaryAggregates = []string{"count", "sum", "avg", "min", "max"}
+
+// summarySpec describes a group-by summary over the displayed rows. Pivot
+// and Value are column indexes, -1 when unset; count without a value column
+// counts rows.
+type summarySpec struct {
+	GroupBy []int
+	Pivot   int
+	Agg     string
+	Value   int
+}
+
+// summaryBuilderState is the column list opened with 'A', where group-by,
+// pivot and value columns are picked.
+type summaryBuilderState struct {
+	active bool
+	cursor int
+	spec   summarySpec
+	err    string
+}
+
+type aggregator struct {
+	count    int64
+	sum      float64
+	min, max string
+}
+
+func (a *aggregator) add(value string, agg string, column string) error {
+	if value == "NULL" {
+		return nil
+	}
+	switch agg {
+	case "sum", "avg":
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("%s has non-numeric value '%s'", column, value)
+		}
+		a.sum += f
+	case "min", "max":
+		if a.count == 0 || compareValues(value, a.min) < 0 {
+			a.min = value
+		}
+		if a.count == 0 || compareValues(value, a.max) > 0 {
+			a.max = value
+		}
+	}
+	a.count++
+	return nil
+}
+
+func (a *aggregator) result(agg string) string {
+	if a == nil {
+		if agg == "count" {
+			return "0"
+		}
+		return "NULL"
+	}
+	if agg == "count" {
+		return strconv.FormatInt(a.count, 10)
+	}
+	if a.count == 0 {
+		return "NULL"
+	}
+	switch agg {
+	case "sum":
+		return formatAggregate(a.sum)
+	case "avg":
+		return formatAggregate(a.sum / float64(a.count))
+	case "min":
+		return a.min
+	default:
+		return a.max
+	}
+}
+
+// formatAggregate rounds away float noise such as 0.30000000000000004.
+func formatAggregate(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e9)/1e9, 'f', -1, 64)
+}
+
+// compareNullsLast orders values like the local sort: NULLs after
+// everything else, numbers numerically.
+func compareNullsLast(a, b string) int {
+	if a == "NULL" || b == "NULL" {
+		switch {
+		case a == b:
+			return 0
+		case a == "NULL":
+			return 1
+		}
+		return -1
+	}
+	return compareValues(a, b)
+}
+
+func (s summarySpec) aggLabel(columns []string) string {
+	if s.Value < 0 {
+		return s.Agg
+	}
+	return fmt.Sprintf("%s(%s)", s.Agg, columns[s.Value])
+}
+
+// label describes the summary for the breadcrumb, e.g. "sum(amount) by day × status".
+func (s summarySpec) label(columns []string) string {
+	var by []string
+	for _, c := range s.GroupBy {
+		by = append(by, columns[c])
+	}
+	label := s.aggLabel(columns)
+	if len(by) > 0 {
+		label += " by " + strings.Join(by, ", ")
+	}
+	if s.Pivot >= 0 {
+		label += " × " + columns[s.Pivot]
+	}
+	return label
+}
+
+// summarize groups data by spec.GroupBy and aggregates spec.Value per group.
+// With a pivot column, each of its distinct values becomes a result column.
+func summarize(
+	columns, columnTypes []string,
+	data [][]string,
+	spec summarySpec,
+) ([]string, []string, [][]string, error) {
+	if spec.Value < 0 && spec.Agg != "count" {
+		return nil, nil, nil, fmt.Errorf("%s needs a value column", spec.Agg)
+	}
+
+	typeOf := func(col int) string {
+		if col < len(columnTypes) {
+			return columnTypes[col]
+		}
+		return ""
+	}
+
+	type group struct {
+		key   []string
+		cells map[string]*aggregator
+	}
+	groups := map[string]*group{}
+	var order []*group
+	pivotValues := map[string]bool{}
+
+	for _, row := range data {
+		key := make([]string, len(spec.GroupBy))
+		for i, c := range spec.GroupBy {
+			key[i] = row[c]
+		}
+		id := strings.Join(key, "\x00")
+		g, ok := groups[id]
+		if !ok {
+			g = &group{key: key, cells: map[string]*aggregator{}}
+			groups[id] = g
+			order = append(order, g)
+		}
+
+		pivot := ""
+		if spec.Pivot >= 0 {
+			pivot = row[spec.Pivot]
+			if !pivotValues[pivot] {
+				if len(pivotValues) == maxPivotColumns {
+					return nil, nil, nil, fmt.Errorf(
+						"%s has more than %d distinct values to pivot",
+						columns[spec.Pivot],
+						maxPivotColumns,
+					)
+				}
+				pivotValues[pivot] = true
+			}
+		}
+
+		a := g.cells[pivot]
+		if a == nil {
+			a = &aggregator{}
+			g.cells[pivot] = a
+		}
+		if spec.Value < 0 {
+			a.count++
+		} else if err := a.add(row[spec.Value], spec.Agg, columns[spec.Value]); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		for k := range order[i].key {
+			if c := compareNullsLast(order[i].key[k], order[j].key[k]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	aggType := "NUMERIC"
+	switch spec.Agg {
+	case "count":
+		aggType = "INTEGER"
+	case "min", "max":
+		aggType = typeOf(spec.Value)
+	}
+
+	var outCols, outTypes []string
+	for _, c := range spec.GroupBy {
+		outCols = append(outCols, columns[c])
+		outTypes = append(outTypes, typeOf(c))
+	}
+
+	cellKeys := []string{""}
+	if spec.Pivot >= 0 {
+		cellKeys = cellKeys[:0]
+		for v := range pivotValues {
+			cellKeys = append(cellKeys, v)
+		}
+		sort.Slice(cellKeys, func(i, j int) bool {
+			return compareNullsLast(cellKeys[i], cellKeys[j]) < 0
+		})
+		outCols = append(outCols, cellKeys...)
+	} else {
+		outCols = append(outCols, spec.aggLabel(columns))
+	}
+	for range cellKeys {
+		outTypes = append(outTypes, aggType)
+	}
+
+	out := make([][]string, 0, len(order))
+	for _, g := range order {
+		row := append([]string{}, g.key...)
+		for _, k := range cellKeys {
+			row = append(row, g.cells[k].result(spec.Agg))
+		}
+		out = append(out, row)
+	}
+
+	return outCols, outTypes, out, nil
+}
+
+func withoutInt(values []int, v int) []int {
+	var result []int
+	for _, x := range values {
+		if x != v {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+func (m Model) openSummaryBuilder() Model {
+	if m.numCols() == 0 {
+		return m
+	}
+	m.summaryBuilder = summaryBuilderState{
+		active: true,
+		cursor: m.selectedCol,
+		spec:   summarySpec{GroupBy: []int{m.selectedCol}, Pivot: -1, Agg: "count", Value: -1},
+	}
+	return m
+}
+
+func (m Model) handleSummaryBuilder(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	sb := &m.summaryBuilder
+	cursor := min(sb.cursor, m.numCols()-1)
+	sb.err = ""
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.summaryBuilder = summaryBuilderState{}
+	case "up", "k":
+		sb.cursor = max(cursor-1, 0)
+	case "down", "j":
+		sb.cursor = min(cursor+1, m.numCols()-1)
+
+	case " ", "g":
+		if indexOfInt(sb.spec.GroupBy, cursor) >= 0 {
+			sb.spec.GroupBy = withoutInt(sb.spec.GroupBy, cursor)
+		} else {
+			sb.spec.GroupBy = append(append([]int{}, sb.spec.GroupBy...), cursor)
+			if sb.spec.Pivot == cursor {
+				sb.spec.Pivot = -1
+			}
+		}
+	case "p":
+		if sb.spec.Pivot == cursor {
+			sb.spec.Pivot = -1
+		} else {
+			sb.spec.Pivot = cursor
+			sb.spec.GroupBy = withoutInt(sb.spec.GroupBy, cursor)
+		}
+	case "v":
+		if sb.spec.Value == cursor {
+			sb.spec.Value = -1
+		} else {
+			sb.spec.Value = cursor
+		}
+	case "a", "tab":
+		i := indexOfName(summaryAggregates, sb.spec.Agg)
+		sb.spec.Agg = summaryAggregates[(i+1)%len(summaryAggregates)]
+
+	case "enter":
+		return m.openSummary()
+	}
+	return m, nil
+}
+
+// openSummary computes the summary over the displayed rows and stacks it as
+// a new view, so 'b' returns to the rows and x/X export the summary.
+func (m Model) openSummary() (tea.Model, tea.Cmd) {
+	spec := m.summaryBuilder.spec
+	columns, columnTypes, data, err := summarize(m.columns, m.columnTypes, m.data, spec)
+	if err != nil {
+		m.summaryBuilder.err = err.Error()
+		return m, nil
+	}
+	m.summaryBuilder = summaryBuilderState{}
+
+	label := spec.label(m.columns)
+	child := New(
+		columns,
+		columnTypes,
+		data,
+		0,
+		m.dbConnection,
+		"",
+		"",
+		db.Query{Name: label},
+		m.cellWidth,
+		m.uiVisibility,
+	)
+	return m.pushView(child, label), nil
+}
+
+func (m Model) renderSummaryBuilder() string {
+	sb := m.summaryBuilder
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("◆ Summary"))
+	b.WriteString("\n")
+	b.WriteString(styles.Separator.Render(strings.Repeat("─", 40)))
+	b.WriteString("\n")
+
+	for i, name := range m.columns {
+		role := "     "
+		switch {
+		case indexOfInt(sb.spec.GroupBy, i) >= 0:
+			role = fmt.Sprintf("[G%d] ", indexOfInt(sb.spec.GroupBy, i)+1)
+		case sb.spec.Pivot == i:
+			role = "[P]  "
+		}
+		value := ""
+		if sb.spec.Value == i {
+			value = styles.Faint.Render("  ← value")
+		}
+		typ := ""
+		if i < len(m.columnTypes) && m.columnTypes[i] != "" {
+			typ = styles.Faint.Render(" " + m.columnTypes[i])
+		}
+
+		line := role + name
+		if i == sb.cursor {
+			line = styles.TableSelected.Render(line)
+		}
+		b.WriteString(line + typ + value + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(
+		"Result: " + styles.TableHeader.Render(sb.spec.label(m.columns)) +
+			styles.Faint.Render(fmt.Sprintf("  over %d rows", m.numRows())),
+	)
+	if sb.err != "" {
+		b.WriteString("\n" + styles.Error.Render("✗ "+sb.err))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(styles.Faint.Render(
+		"j/k move • space group by • p pivot • v value column • a aggregate (count/sum/avg/min/max) • enter show • esc cancel",
+	))
+	return b.String()
+}
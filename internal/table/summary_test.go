@@ -0,0 +1,142 @@
+package table
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var summaryColumns = []string{"day", "status", "amount"}
+
+var summaryRows = [][]string{
+	{"2026-01-02", "paid", "10.1"},
+	{"2026-01-01", "paid", "20.2"},
+	{"2026-01-01", "failed", "5"},
+	{"2026-01-02", "NULL", "NULL"},
+	{"2026-01-02", "paid", "2"},
+}
+
+func TestSummarize_GroupBy(t *testing.T) {
+	cols, types, data, err := summarize(
+		summaryColumns,
+		[]string{"DATE", "TEXT", "REAL"},
+		summaryRows,
+		summarySpec{GroupBy: []int{1}, Pivot: -1, Agg: "sum", Value: 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(cols, []string{"status", "sum(amount)"}) {
+		t.Errorf("columns = %v", cols)
+	}
+	if !reflect.DeepEqual(types, []string{"TEXT", "NUMERIC"}) {
+		t.Errorf("types = %v", types)
+	}
+	want := [][]string{
+		{"failed", "5"},
+		{"paid", "32.3"},
+		{"NULL", "NULL"},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestSummarize_Pivot(t *testing.T) {
+	cols, _, data, err := summarize(
+		summaryColumns,
+		nil,
+		summaryRows,
+		summarySpec{GroupBy: []int{0}, Pivot: 1, Agg: "count", Value: -1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(cols, []string{"day", "failed", "paid", "NULL"}) {
+		t.Errorf("columns = %v", cols)
+	}
+	want := [][]string{
+		{"2026-01-01", "1", "1", "0"},
+		{"2026-01-02", "0", "2", "1"},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+}
+
+func TestSummarize_MinMaxAndErrors(t *testing.T) {
+	_, _, data, err := summarize(
+		[]string{"n"},
+		nil,
+		[][]string{{"9"}, {"10"}, {"NULL"}},
+		summarySpec{Pivot: -1, Agg: "max", Value: 0},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, [][]string{{"10"}}) {
+		t.Errorf("max should compare numerically, got %v", data)
+	}
+
+	_, _, _, err = summarize(
+		summaryColumns,
+		nil,
+		summaryRows,
+		summarySpec{Pivot: -1, Agg: "avg", Value: 1},
+	)
+	if err == nil {
+		t.Error("avg over a text column should fail")
+	}
+
+	_, _, _, err = summarize(
+		summaryColumns,
+		nil,
+		summaryRows,
+		summarySpec{Pivot: -1, Agg: "sum", Value: -1},
+	)
+	if err == nil {
+		t.Error("sum without a value column should fail")
+	}
+}
+
+func TestModel_SummaryView(t *testing.T) {
+	m := New(
+		summaryColumns,
+		nil,
+		summaryRows,
+		time.Millisecond,
+		nil,
+		"",
+		"",
+		db.Query{Name: "orders"},
+		15,
+		config.UIVisibility{},
+	)
+	m = m.handleWindowResize(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.selectedCol = 1
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	summary := model.(Model)
+
+	if summary.summaryBuilder.active {
+		t.Fatal("builder should close after enter")
+	}
+	if !reflect.DeepEqual(summary.columns, []string{"status", "count"}) {
+		t.Fatalf("summary columns = %v", summary.columns)
+	}
+	if summary.numRows() != 3 || len(summary.navStack) != 1 {
+		t.Errorf("rows = %d, stack = %d", summary.numRows(), len(summary.navStack))
+	}
+
+	back := summary.popView()
+	if back.numRows() != len(summaryRows) {
+		t.Errorf("back to %d rows, want %d", back.numRows(), len(summaryRows))
+	}
+}
@@ -12,12 +12,32 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Table-view tabs, in the order they are shown
+const (
+	tvTabColumns = iota
+	tvTabIndexes
+	tvTabConstraints
+	tvTabTriggers
+	tvTabCount
+)
+
+var tvTabTitles = [tvTabCount]string{"Columns", "Indexes", "Constraints", "Triggers"}
+
+// tvTabItems names one row of each tab, for status messages
+var tvTabItems = [tvTabCount]string{"Column", "Index", "Constraint", "Trigger"}
+
 // TableViewModel is the bubbletea model for the table structure view
 type TableViewModel struct {
 	width        int
 	height       int
 	tableName    string
+	tab          int
 	columns      []db.ColumnInfo
+	indexes      []db.IndexInfo
+	constraints  []db.ConstraintInfo
+	triggers     []db.TriggerInfo
+	loaded       [tvTabCount]bool
+	loadErr      [tvTabCount]error
 	selectedRow  int
 	offsetY      int
 	visibleRows  int
@@ -46,6 +66,7 @@ func NewTableViewModel(
 	return TableViewModel{
 		tableName:    tableName,
 		columns:      columns,
+		loaded:       [tvTabCount]bool{tvTabColumns: true},
 		selectedRow:  0,
 		offsetY:      0,
 		conn:         conn,
@@ -72,7 +93,7 @@ func (m TableViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		reserved := 12 // header + footer + separators
+		reserved := 13 // header + tab bar + footer + separators
 		m.visibleRows = m.height - reserved
 		if m.visibleRows < 3 {
 			m.visibleRows = 3
@@ -111,7 +132,7 @@ func (m TableViewModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "down", "j":
-		if m.selectedRow < len(m.columns)-1 {
+		if m.selectedRow < m.rowCount()-1 {
 			m.selectedRow++
 			if m.selectedRow >= m.offsetY+m.visibleRows {
 				m.offsetY = m.selectedRow - m.visibleRows + 1
@@ -125,7 +146,7 @@ func (m TableViewModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "G":
-		m.selectedRow = len(m.columns) - 1
+		m.selectedRow = max(m.rowCount()-1, 0)
 		m.offsetY = m.selectedRow - m.visibleRows + 1
 		if m.offsetY < 0 {
 			m.offsetY = 0
@@ -142,28 +163,64 @@ func (m TableViewModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "pgdown", "ctrl+d":
 		m.selectedRow += m.visibleRows
-		if m.selectedRow >= len(m.columns) {
-			m.selectedRow = len(m.columns) - 1
+		if m.selectedRow >= m.rowCount() {
+			m.selectedRow = max(m.rowCount()-1, 0)
 		}
 		if m.selectedRow >= m.offsetY+m.visibleRows {
 			m.offsetY = m.selectedRow - m.visibleRows + 1
 		}
 		return m, nil
 
+	case "tab":
+		return m.switchTab((m.tab + 1) % tvTabCount), nil
+
+	case "shift+tab":
+		return m.switchTab((m.tab - 1 + tvTabCount) % tvTabCount), nil
+
+	case "1", "2", "3", "4":
+		return m.switchTab(int(msg.String()[0] - '1')), nil
+
 	case "a":
-		// Add column
+		// Add a column, or create an index, constraint or trigger
+		switch m.tab {
+		case tvTabIndexes:
+			return m.createIndex()
+		case tvTabConstraints:
+			return m.addConstraint()
+		case tvTabTriggers:
+			return m.createTrigger()
+		}
 		return m.addColumn()
 
 	case "e":
 		// Edit selected column (alter)
+		if m.tab != tvTabColumns {
+			return m, nil
+		}
 		return m.editColumn()
 
 	case "r":
-		// Rename selected column
+		// Rename the selected row
+		switch m.tab {
+		case tvTabIndexes:
+			return m.renameIndex()
+		case tvTabConstraints:
+			return m.renameConstraint()
+		case tvTabTriggers:
+			return m.renameTrigger()
+		}
 		return m.renameColumn()
 
 	case "D":
-		// Drop selected column
+		// Drop the selected row
+		switch m.tab {
+		case tvTabIndexes:
+			return m.dropIndex()
+		case tvTabConstraints:
+			return m.dropConstraint()
+		case tvTabTriggers:
+			return m.dropTrigger()
+		}
 		return m.dropColumn()
 
 	case "enter":
@@ -197,24 +254,71 @@ func (m TableViewModel) handleEditorResult(
 		return m, m.blinkCmd()
 	}
 
-	// Refresh column data
-	newCols, err := m.conn.GetColumnDetails(m.tableName)
-	if err != nil {
+	// Refresh the active tab; the others reload when next shown
+	m.loaded = [tvTabCount]bool{}
+	m = m.loadTab(m.tab)
+	if err := m.loadErr[m.tab]; err != nil {
 		m.message = fmt.Sprintf("✓ Executed, but refresh failed: %v", err)
 		m.messageStyle = styles.Success
 		return m, m.blinkCmd()
 	}
 
-	m.columns = newCols
-	if m.selectedRow >= len(m.columns) && len(m.columns) > 0 {
-		m.selectedRow = len(m.columns) - 1
+	if m.selectedRow >= m.rowCount() && m.rowCount() > 0 {
+		m.selectedRow = m.rowCount() - 1
 	}
 
-	m.message = "✓ Column updated successfully"
+	m.message = fmt.Sprintf("✓ %s updated successfully", tvTabItems[m.tab])
 	m.messageStyle = styles.Success
 	return m, tea.Batch(tea.ClearScreen, m.blinkCmd())
 }
 
+// switchTab shows tab, loading its rows the first time
+func (m TableViewModel) switchTab(tab int) TableViewModel {
+	if tab == m.tab {
+		return m
+	}
+	m.tab = tab
+	m.selectedRow = 0
+	m.offsetY = 0
+	if !m.loaded[tab] {
+		m = m.loadTab(tab)
+	}
+	return m
+}
+
+func (m TableViewModel) loadTab(tab int) TableViewModel {
+	var err error
+	switch tab {
+	case tvTabColumns:
+		var columns []db.ColumnInfo
+		if columns, err = m.conn.GetColumnDetails(m.tableName); err == nil {
+			m.columns = columns
+		}
+	case tvTabIndexes:
+		m.indexes, err = m.conn.GetIndexes(m.tableName)
+	case tvTabConstraints:
+		m.constraints, err = m.conn.GetConstraints(m.tableName)
+	case tvTabTriggers:
+		m.triggers, err = m.conn.GetTriggers(m.tableName)
+	}
+	m.loaded[tab] = true
+	m.loadErr[tab] = err
+	return m
+}
+
+// rowCount is the number of rows in the active tab
+func (m TableViewModel) rowCount() int {
+	switch m.tab {
+	case tvTabIndexes:
+		return len(m.indexes)
+	case tvTabConstraints:
+		return len(m.constraints)
+	case tvTabTriggers:
+		return len(m.triggers)
+	}
+	return len(m.columns)
+}
+
 func (m TableViewModel) blinkCmd() tea.Cmd {
 	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
 		return tableViewBlinkMsg{}
@@ -307,6 +411,174 @@ func (m TableViewModel) dropColumn() (tea.Model, tea.Cmd) {
 	return m.openEditor("Drop column", header+template)
 }
 
+func (m TableViewModel) createIndex() (tea.Model, tea.Cmd) {
+	column := "column_name"
+	if len(m.columns) > 0 {
+		column = m.columns[0].Name
+	}
+	template := m.conn.BuildCreateIndexSQL(
+		m.tableName,
+		fmt.Sprintf("idx_%s_%s", m.tableName, column),
+		[]string{column},
+		false,
+	)
+
+	header := fmt.Sprintf(
+		"-- CREATE INDEX on %s\n-- Edit the statement below and save to execute.\n-- To cancel, delete all content and save.\n\n",
+		m.tableName,
+	)
+
+	return m.openEditor("Create index", header+template)
+}
+
+func (m TableViewModel) renameIndex() (tea.Model, tea.Cmd) {
+	if len(m.indexes) == 0 {
+		return m, nil
+	}
+
+	index := m.indexes[m.selectedRow]
+	template := m.conn.BuildRenameIndexSQL(
+		m.tableName,
+		index,
+		index.Name+"_new",
+	)
+
+	header := fmt.Sprintf(
+		"-- RENAME INDEX '%s' on table '%s'\n-- Edit the new index name and save to execute.\n-- To cancel, delete all content and save.\n\n",
+		index.Name,
+		m.tableName,
+	)
+
+	return m.openEditor("Rename index", header+template)
+}
+
+func (m TableViewModel) dropIndex() (tea.Model, tea.Cmd) {
+	if len(m.indexes) == 0 {
+		return m, nil
+	}
+
+	index := m.indexes[m.selectedRow]
+	template := m.conn.BuildDropIndexSQL(m.tableName, index)
+
+	header := fmt.Sprintf(
+		"-- DROP INDEX '%s' from table '%s'\n-- WARNING: Queries relying on this index may slow down!\n-- To cancel, delete all content and save.\n\n",
+		index.Name,
+		m.tableName,
+	)
+
+	return m.openEditor("Drop index", header+template)
+}
+
+func (m TableViewModel) addConstraint() (tea.Model, tea.Cmd) {
+	column := "column_name"
+	if len(m.columns) > 0 {
+		column = m.columns[0].Name
+	}
+	template := m.conn.BuildAddConstraintSQL(
+		m.tableName,
+		fmt.Sprintf("%s_%s_check", m.tableName, column),
+		fmt.Sprintf("CHECK (%s IS NOT NULL)", column),
+	)
+
+	header := fmt.Sprintf(
+		"-- ADD CONSTRAINT to %s\n-- Edit the check or use FOREIGN KEY (col) REFERENCES other (col).\n-- To cancel, delete all content and save.\n\n",
+		m.tableName,
+	)
+
+	return m.openEditor("Add constraint", header+template)
+}
+
+func (m TableViewModel) renameConstraint() (tea.Model, tea.Cmd) {
+	if len(m.constraints) == 0 {
+		return m, nil
+	}
+
+	constraint := m.constraints[m.selectedRow]
+	template := m.conn.BuildRenameConstraintSQL(
+		m.tableName,
+		constraint,
+		constraint.Name+"_new",
+	)
+
+	header := fmt.Sprintf(
+		"-- RENAME CONSTRAINT '%s' on table '%s'\n-- Edit the new constraint name and save to execute.\n-- To cancel, delete all content and save.\n\n",
+		constraint.Name,
+		m.tableName,
+	)
+
+	return m.openEditor("Rename constraint", header+template)
+}
+
+func (m TableViewModel) dropConstraint() (tea.Model, tea.Cmd) {
+	if len(m.constraints) == 0 {
+		return m, nil
+	}
+
+	constraint := m.constraints[m.selectedRow]
+	template := m.conn.BuildDropConstraintSQL(m.tableName, constraint)
+
+	header := fmt.Sprintf(
+		"-- DROP CONSTRAINT '%s' from table '%s'\n-- WARNING: The table will no longer enforce %s!\n-- To cancel, delete all content and save.\n\n",
+		constraint.Name,
+		m.tableName,
+		constraint.Definition,
+	)
+
+	return m.openEditor("Drop constraint", header+template)
+}
+
+func (m TableViewModel) createTrigger() (tea.Model, tea.Cmd) {
+	template := m.conn.BuildCreateTriggerSQL(
+		m.tableName,
+		m.tableName+"_trigger",
+	)
+
+	header := fmt.Sprintf(
+		"-- CREATE TRIGGER on %s\n-- Edit the statement below and save to execute.\n-- To cancel, delete all content and save.\n\n",
+		m.tableName,
+	)
+
+	return m.openEditor("Create trigger", header+template)
+}
+
+func (m TableViewModel) renameTrigger() (tea.Model, tea.Cmd) {
+	if len(m.triggers) == 0 {
+		return m, nil
+	}
+
+	trigger := m.triggers[m.selectedRow]
+	template := m.conn.BuildRenameTriggerSQL(
+		m.tableName,
+		trigger,
+		trigger.Name+"_new",
+	)
+
+	header := fmt.Sprintf(
+		"-- RENAME TRIGGER '%s' on table '%s'\n-- Edit the new trigger name and save to execute.\n-- To cancel, delete all content and save.\n\n",
+		trigger.Name,
+		m.tableName,
+	)
+
+	return m.openEditor("Rename trigger", header+template)
+}
+
+func (m TableViewModel) dropTrigger() (tea.Model, tea.Cmd) {
+	if len(m.triggers) == 0 {
+		return m, nil
+	}
+
+	trigger := m.triggers[m.selectedRow]
+	template := m.conn.BuildDropTriggerSQL(m.tableName, trigger)
+
+	header := fmt.Sprintf(
+		"-- DROP TRIGGER '%s' from table '%s'\n-- WARNING: This will permanently remove the trigger!\n-- To cancel, delete all content and save.\n\n",
+		trigger.Name,
+		m.tableName,
+	)
+
+	return m.openEditor("Drop trigger", header+template)
+}
+
 func (m TableViewModel) openEditor(title, content string) (tea.Model, tea.Cmd) {
 	ta := textarea.New()
 	ta.SetValue(content)
@@ -429,6 +701,9 @@ func (m TableViewModel) View() string {
 	b.WriteString(styles.Faint.Render(connInfo))
 	b.WriteString("\n\n")
 
+	b.WriteString(m.renderTabBar())
+	b.WriteString("\n")
+
 	// Column headers
 	colWidths := m.computeColumnWidths()
 	headerCells := m.renderHeaderRow(colWidths)
@@ -447,13 +722,18 @@ func (m TableViewModel) View() string {
 	b.WriteString("\n")
 
 	// Data rows
-	if len(m.columns) == 0 {
-		b.WriteString(styles.Faint.Render("  No columns found"))
+	if err := m.loadErr[m.tab]; err != nil {
+		b.WriteString(styles.Error.Render(fmt.Sprintf("  %v", err)))
+		b.WriteString("\n")
+	} else if m.rowCount() == 0 {
+		b.WriteString(styles.Faint.Render(
+			fmt.Sprintf("  No %s found", strings.ToLower(tvTabTitles[m.tab])),
+		))
 		b.WriteString("\n")
 	} else {
 		endRow := m.offsetY + m.visibleRows
-		if endRow > len(m.columns) {
-			endRow = len(m.columns)
+		if endRow > m.rowCount() {
+			endRow = m.rowCount()
 		}
 
 		for i := m.offsetY; i < endRow; i++ {
@@ -475,9 +755,32 @@ func (m TableViewModel) View() string {
 	return b.String()
 }
 
-func (m TableViewModel) computeColumnWidths() []int {
+func (m TableViewModel) renderTabBar() string {
+	var parts []string
+	for i, title := range tvTabTitles {
+		label := fmt.Sprintf(" %d %s ", i+1, title)
+		if i == m.tab {
+			parts = append(parts, styles.TableSelected.Render(label))
+		} else {
+			parts = append(parts, styles.Faint.Render(label))
+		}
+	}
+	hint := styles.Faint.Render("  tab/shift+tab")
+	return strings.Join(parts, styles.Separator.Render("│")) + hint
+}
+
+// headers returns the column headers of the active tab
+func (m TableViewModel) headers() []string {
+	switch m.tab {
+	case tvTabIndexes:
+		return []string{"Name", "Columns", "Unique", "PK", "Method"}
+	case tvTabConstraints:
+		return []string{"Name", "Type", "Columns", "Definition"}
+	case tvTabTriggers:
+		return []string{"Name", "Timing", "Event"}
+	}
 	// Columns: #  | Name | Type | Nullable | Default | PK | Extra
-	headers := []string{
+	return []string{
 		"#",
 		"Name",
 		"Type",
@@ -486,14 +789,52 @@ func (m TableViewModel) computeColumnWidths() []int {
 		"PK",
 		"Extra",
 	}
+}
+
+// rowValues returns the cells of row i in the active tab
+func (m TableViewModel) rowValues(i int) []string {
+	switch m.tab {
+	case tvTabIndexes:
+		idx := m.indexes[i]
+		unique, pk := "", ""
+		if idx.Unique {
+			unique = "YES"
+		}
+		if idx.Primary {
+			pk = "⚿ PK"
+		}
+		return []string{
+			idx.Name,
+			strings.Join(idx.Columns, ", "),
+			unique,
+			pk,
+			idx.Method,
+		}
+	case tvTabConstraints:
+		c := m.constraints[i]
+		return []string{
+			c.Name,
+			c.Type,
+			strings.Join(c.Columns, ", "),
+			c.Definition,
+		}
+	case tvTabTriggers:
+		t := m.triggers[i]
+		return []string{t.Name, t.Timing, t.Event}
+	}
+	return m.columnToRow(m.columns[i])
+}
+
+func (m TableViewModel) computeColumnWidths() []int {
+	headers := m.headers()
 
 	widths := make([]int, len(headers))
 	for i, h := range headers {
 		widths[i] = len(h)
 	}
 
-	for _, col := range m.columns {
-		vals := m.columnToRow(col)
+	for row := 0; row < m.rowCount(); row++ {
+		vals := m.rowValues(row)
 		for i, v := range vals {
 			if len(v) > widths[i] {
 				widths[i] = len(v)
@@ -563,18 +904,8 @@ func (m TableViewModel) columnToRow(col db.ColumnInfo) []string {
 }
 
 func (m TableViewModel) renderHeaderRow(widths []int) string {
-	headers := []string{
-		"#",
-		"Name",
-		"Type",
-		"Nullable",
-		"Default",
-		"PK",
-		"Extra",
-	}
-
 	var cells []string
-	for i, h := range headers {
+	for i, h := range m.headers() {
 		w := widths[i]
 		content := tvFormatCell(h, w)
 		cells = append(cells, styles.TableHeader.Render(content))
@@ -584,8 +915,7 @@ func (m TableViewModel) renderHeaderRow(widths []int) string {
 }
 
 func (m TableViewModel) renderDataRow(rowIndex int, widths []int) string {
-	col := m.columns[rowIndex]
-	vals := m.columnToRow(col)
+	vals := m.rowValues(rowIndex)
 
 	var cells []string
 	for i, v := range vals {
@@ -598,7 +928,8 @@ func (m TableViewModel) renderDataRow(rowIndex int, widths []int) string {
 		}
 
 		// Highlight PK column name
-		if i == 5 && col.IsPrimaryKey && rowIndex != m.selectedRow {
+		if m.tab == tvTabColumns && i == 5 &&
+			m.columns[rowIndex].IsPrimaryKey && rowIndex != m.selectedRow {
 			style = styles.TableHeader
 		}
 
@@ -611,13 +942,17 @@ func (m TableViewModel) renderDataRow(rowIndex int, widths []int) string {
 func (m TableViewModel) renderFooter() string {
 	var b strings.Builder
 
-	// Column count and timing
+	// Row count and timing
 	info := fmt.Sprintf(
 		"\n%s %s | %s",
-		styles.Faint.Render(fmt.Sprintf("%d columns", len(m.columns))),
+		styles.Faint.Render(fmt.Sprintf(
+			"%d %s",
+			m.rowCount(),
+			strings.ToLower(tvTabTitles[m.tab]),
+		)),
 		styles.Faint.Render(fmt.Sprintf("In %.2fs", m.elapsed.Seconds())),
 		styles.Faint.Render(
-			fmt.Sprintf("[%d/%d]", m.selectedRow+1, len(m.columns)),
+			fmt.Sprintf("[%d/%d]", min(m.selectedRow+1, m.rowCount()), m.rowCount()),
 		),
 	)
 	b.WriteString(info)
@@ -630,17 +965,12 @@ func (m TableViewModel) renderFooter() string {
 	quit := styles.TableHeader.Render("q") + styles.Faint.Render("uit")
 	hjkl := styles.TableHeader.Render("jk") + styles.Faint.Render("↓↑")
 
-	b.WriteString(
-		fmt.Sprintf(
-			"  %s  %s  %s  %s  %s  %s",
-			add,
-			edit,
-			rename,
-			drop,
-			quit,
-			hjkl,
-		),
-	)
+	hints := []string{add, edit, rename, drop, quit, hjkl}
+	if m.tab != tvTabColumns {
+		// Only columns can be altered in place
+		hints = []string{add, rename, drop, quit, hjkl}
+	}
+	b.WriteString("  " + strings.Join(hints, "  "))
 
 	return b.String()
 }
@@ -0,0 +1,147 @@
+package table
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestTableView(t *testing.T) TableViewModel {
+	t.Helper()
+	conn, err := db.NewSQLiteConnection("tv", filepath.Join(t.TempDir(), "tv.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	schema := `
+		CREATE TABLE customers (id INTEGER PRIMARY KEY);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY,
+			customer_id INTEGER REFERENCES customers (id) ON DELETE CASCADE,
+			amount REAL CONSTRAINT positive_amount CHECK (amount > 0),
+			note TEXT CHECK (length(note) < 100)
+		);
+		CREATE UNIQUE INDEX idx_orders_note ON orders (note, amount);
+		CREATE TRIGGER orders_audit AFTER UPDATE OF amount ON orders
+		BEGIN
+			SELECT 1;
+		END;`
+	if err := conn.Exec(schema); err != nil {
+		t.Fatal(err)
+	}
+
+	columns, err := conn.GetColumnDetails("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewTableViewModel("orders", columns, conn, 0)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return updated.(TableViewModel)
+}
+
+func pressTableView(m TableViewModel, keys ...tea.KeyMsg) TableViewModel {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(TableViewModel)
+	}
+	return m
+}
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestTableViewTabs(t *testing.T) {
+	m := newTestTableView(t)
+
+	m = pressTableView(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.tab != tvTabIndexes || len(m.indexes) != 1 {
+		t.Fatalf("tab = %d, indexes = %+v", m.tab, m.indexes)
+	}
+	if idx := m.indexes[0]; idx.Name != "idx_orders_note" || !idx.Unique ||
+		strings.Join(idx.Columns, ",") != "note,amount" {
+		t.Errorf("index = %+v", idx)
+	}
+
+	m = pressTableView(m, runeKey('3'))
+	var got []string
+	for _, c := range m.constraints {
+		got = append(got, c.Name+"|"+c.Definition)
+	}
+	want := []string{
+		"positive_amount|CHECK (amount > 0)",
+		"|CHECK (length(note) < 100)",
+		"|FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("constraints =\n%s", strings.Join(got, "\n"))
+	}
+
+	m = pressTableView(m, tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyShiftTab}, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.tab != tvTabTriggers || len(m.triggers) != 1 {
+		t.Fatalf("tab = %d, triggers = %+v", m.tab, m.triggers)
+	}
+	if tr := m.triggers[0]; tr.Timing != "AFTER" || tr.Event != "UPDATE OF amount" {
+		t.Errorf("trigger = %+v", tr)
+	}
+	if view := m.View(); !strings.Contains(view, "orders_audit") || !strings.Contains(view, "1 triggers") {
+		t.Errorf("view does not list the trigger:\n%s", view)
+	}
+
+	m = pressTableView(m, runeKey('r'), tea.KeyMsg{Type: tea.KeyCtrlS})
+	if len(m.triggers) != 1 || m.triggers[0].Name != "orders_audit_new" {
+		t.Errorf("after rename: message %q, triggers %+v", m.message, m.triggers)
+	}
+}
+
+func TestTableViewIndexActions(t *testing.T) {
+	m := newTestTableView(t)
+	m = pressTableView(m, runeKey('2'))
+
+	// Renaming recreates the index under its new name
+	m = pressTableView(m, runeKey('r'))
+	if !m.editorActive || !strings.Contains(m.editor.Value(), "CREATE UNIQUE INDEX idx_orders_note_new ON orders") {
+		t.Fatalf("rename preview:\n%s", m.editor.Value())
+	}
+	m = pressTableView(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.editorActive || len(m.indexes) != 1 || m.indexes[0].Name != "idx_orders_note_new" {
+		t.Fatalf("after rename: message %q, indexes %+v", m.message, m.indexes)
+	}
+
+	m = pressTableView(m, runeKey('D'))
+	if !strings.Contains(m.editor.Value(), "DROP INDEX idx_orders_note_new;") {
+		t.Fatalf("drop preview:\n%s", m.editor.Value())
+	}
+	m = pressTableView(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if len(m.indexes) != 0 || m.message != "✓ Index updated successfully" {
+		t.Errorf("after drop: message %q, indexes %+v", m.message, m.indexes)
+	}
+
+	// Esc leaves the table untouched
+	m = pressTableView(m, runeKey('a'), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.editorActive || len(m.indexes) != 0 {
+		t.Errorf("cancelled create should not run, indexes %+v", m.indexes)
+	}
+}
+
+func TestTableViewConstraintsUnsupported(t *testing.T) {
+	m := newTestTableView(t)
+	m = pressTableView(m, runeKey('3'), runeKey('D'))
+	if !strings.HasPrefix(m.editor.Value(), "-- DROP CONSTRAINT") ||
+		!strings.Contains(m.editor.Value(), "-- SQLite can't drop constraints") {
+		t.Fatalf("preview:\n%s", m.editor.Value())
+	}
+
+	// Saving a preview that is only comments executes nothing
+	m = pressTableView(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if len(m.constraints) != 3 || m.message != "Cancelled (empty SQL)" {
+		t.Errorf("message %q, constraints %+v", m.message, m.constraints)
+	}
+}
@@ -0,0 +1,152 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tabBarLines is the number of terminal lines taken by the tab bar.
+const tabBarLines = 1
+
+// Tab is a single result set shown by TabsModel.
+type Tab struct {
+	Title string
+	Model Model
+}
+
+// TabsModel shows several result sets (e.g. from a multi-statement script),
+// one Model per tab. Tab/Shift+Tab switch between them; every other key is
+// handled by the active tab.
+type TabsModel struct {
+	tabs   []Tab
+	active int
+	width  int
+	height int
+}
+
+func NewTabs(tabs []Tab) TabsModel {
+	return TabsModel{tabs: tabs}
+}
+
+func (t TabsModel) Init() tea.Cmd {
+	return nil
+}
+
+// ActiveIndex returns the index of the tab that was focused last.
+func (t TabsModel) ActiveIndex() int {
+	return t.active
+}
+
+// Active returns the model of the focused tab.
+func (t TabsModel) Active() Model {
+	return t.tabs[t.active].Model
+}
+
+func (t TabsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if len(t.tabs) == 0 {
+		return t, tea.Quit
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+		t.height = msg.Height
+		inner := tea.WindowSizeMsg{Width: msg.Width, Height: msg.Height - tabBarLines}
+		for i := range t.tabs {
+			t.tabs[i].Model = t.tabs[i].Model.handleWindowResize(inner)
+		}
+		return t, nil
+
+	case tea.KeyMsg:
+		if !t.tabs[t.active].Model.capturingInput() {
+			switch msg.String() {
+			case "tab":
+				t.active = (t.active + 1) % len(t.tabs)
+				return t, nil
+			case "shift+tab":
+				t.active = (t.active - 1 + len(t.tabs)) % len(t.tabs)
+				return t, nil
+			}
+		}
+	}
+
+	updated, cmd := t.tabs[t.active].Model.Update(msg)
+	t.tabs[t.active].Model = updated.(Model)
+	return t, cmd
+}
+
+func (t TabsModel) View() string {
+	if len(t.tabs) == 0 {
+		return ""
+	}
+
+	active := t.tabs[t.active].Model
+	// Full-screen editors and the rerun handoff take over the whole view
+	if active.valueEditorActive || active.editorActive || active.shouldRerunQuery {
+		return active.View()
+	}
+
+	return t.renderTabBar() + "\n" + active.View()
+}
+
+func (t TabsModel) renderTabBar() string {
+	var parts []string
+	for i, tab := range t.tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, tab.Title)
+		if i == t.active {
+			parts = append(parts, styles.TableSelected.Render(label))
+		} else {
+			parts = append(parts, styles.Faint.Render(label))
+		}
+	}
+	hint := styles.Faint.Render("  tab/shift+tab")
+	return strings.Join(parts, styles.Separator.Render("│")) + hint
+}
+
+// capturingInput reports whether the model is in a mode where keys are text
+// input or a prompt answer rather than navigation.
+func (m Model) capturingInput() bool {
+	return m.valueEditorActive ||
+		m.editorActive ||
+		m.confirmActive ||
+		m.exportWaiting.active ||
+		m.fkChooser.active ||
+		m.filterMode ||
+		m.columnManager.active ||
+		m.statsPanel.active ||
+		m.summaryBuilder.active ||
+		m.chartView.active ||
+		m.searchMode ||
+		m.detailViewMode
+}
+
+// RenderTabs runs the tabbed viewer until the user quits and returns its
+// final state.
+func RenderTabs(
+	tabs []Tab,
+	saveCallback func(query db.Query) (db.Query, error),
+	initialStatus ...string,
+) (TabsModel, error) {
+	for i := range tabs {
+		tabs[i].Model.saveQueryCallback = saveCallback
+	}
+
+	model := NewTabs(tabs)
+	// A status (usually a script error) is shown on the last tab, which is
+	// the result closest to where the script stopped.
+	if len(tabs) > 0 && len(initialStatus) > 0 && initialStatus[0] != "" {
+		model.active = len(tabs) - 1
+		model.tabs[model.active].Model.statusMessage = initialStatus[0]
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		return model, err
+	}
+	return finalModel.(TabsModel), nil
+}
@@ -0,0 +1,77 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/config"
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestTabs(n int) TabsModel {
+	tabs := make([]Tab, n)
+	for i := range tabs {
+		model := New(
+			[]string{"id"},
+			nil,
+			[][]string{{"1"}, {"2"}},
+			0,
+			nil,
+			"",
+			"",
+			db.Query{Name: "script", SQL: "SELECT 1"},
+			15,
+			config.UIVisibility{},
+		)
+		tabs[i] = Tab{Title: "t", Model: model}
+	}
+	return NewTabs(tabs)
+}
+
+func TestTabsSwitching(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []tea.KeyMsg
+		want int
+	}{
+		{"next", []tea.KeyMsg{{Type: tea.KeyTab}}, 1},
+		{"wraps forward", []tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeyTab}, {Type: tea.KeyTab}}, 0},
+		{"previous wraps", []tea.KeyMsg{{Type: tea.KeyShiftTab}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m tea.Model = newTestTabs(3)
+			for _, k := range tt.keys {
+				m, _ = m.Update(k)
+			}
+			if got := m.(TabsModel).ActiveIndex(); got != tt.want {
+				t.Errorf("ActiveIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTabsKeysGoToActiveTab(t *testing.T) {
+	var m tea.Model = newTestTabs(2)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+
+	tabs := m.(TabsModel)
+	if tabs.tabs[0].Model.selectedRow != 1 {
+		t.Errorf("active tab selectedRow = %d, want 1", tabs.tabs[0].Model.selectedRow)
+	}
+	if tabs.tabs[1].Model.selectedRow != 0 {
+		t.Errorf("inactive tab selectedRow = %d, want 0", tabs.tabs[1].Model.selectedRow)
+	}
+}
+
+func TestTabsDoNotSwitchWhileSearching(t *testing.T) {
+	m := newTestTabs(2)
+	m.tabs[0].Model.searchMode = true
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := updated.(TabsModel).ActiveIndex(); got != 0 {
+		t.Errorf("ActiveIndex() = %d, want 0 while search input is active", got)
+	}
+}
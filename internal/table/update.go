@@ -49,6 +49,30 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.executeExportForFormat(msg.String())
 	}
 
+	if m.fkChooser.active {
+		return m.handleFKChooser(msg)
+	}
+
+	if m.filterMode {
+		return m.handleFilterInput(msg)
+	}
+
+	if m.columnManager.active {
+		return m.handleColumnManager(msg)
+	}
+
+	if m.statsPanel.active {
+		return m.handleStatsPanel(msg)
+	}
+
+	if m.summaryBuilder.active {
+		return m.handleSummaryBuilder(msg)
+	}
+
+	if m.chartView.active {
+		return m.handleChartView(msg)
+	}
+
 	// Handle search input mode
 	if m.searchMode {
 		return m.handleSearchInput(msg)
@@ -174,6 +198,39 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.prevColumnMatch(), nil
 	case ";":
 		return m.nextColumnMatch(), nil
+
+	case "o":
+		return m.followForeignKey()
+	case "O":
+		return m.listChildRows()
+	case "b", "backspace":
+		return m.popView(), nil
+
+	case "F":
+		return m.startFilter(), nil
+	case "S":
+		return m.toggleLocalSort(), nil
+	case "H":
+		return m.hideColumn()
+	case "c":
+		return m.openColumnManager(), nil
+	case "<":
+		return m.moveColumn(-1), nil
+	case ">":
+		return m.moveColumn(1), nil
+	case "P":
+		return m.togglePinColumn(), nil
+	case "R":
+		return m.resetView(), nil
+	case "W":
+		return m.saveView()
+
+	case "i":
+		return m.openStatsPanel(m.selectedCol), nil
+	case "A":
+		return m.openSummaryBuilder(), nil
+	case "C":
+		return m.openChart()
 	}
 
 	return m, nil
@@ -61,7 +61,7 @@ func (m Model) handleEditorComplete(
 		return m, nil
 	}
 
-	m.data[m.selectedRow][msg.colIndex] = newValue
+	m = m.setCell(m.selectedRow, msg.colIndex, newValue)
 
 	m.blinkUpdatedCell = true
 	m.updatedRow = m.selectedRow
@@ -91,12 +91,7 @@ func (m Model) buildUpdateStatementWithValue(newValue string) string {
 	var multipleMatches bool
 
 	if m.primaryKeyCol != "" {
-		for i, col := range m.columns {
-			if col == m.primaryKeyCol {
-				pkValue = m.data[m.selectedRow][i]
-				break
-			}
-		}
+		pkValue, _ = m.rowValue(m.selectedRow, m.primaryKeyCol)
 	}
 
 	// If PK not found in result set, try to fetch it
@@ -148,8 +143,8 @@ func (m Model) fetchPrimaryKeyValue() (string, bool) {
 
 	// Build WHERE clause from all columns in current row
 	var whereConditions []string
-	for i, col := range m.columns {
-		val := m.data[m.selectedRow][i]
+	for _, col := range m.srcColumns {
+		val, _ := m.rowValue(m.selectedRow, col)
 		whereConditions = append(
 			whereConditions,
 			fmt.Sprintf("%s = '%s'", col, escapeSQLValue(val)),
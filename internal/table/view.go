@@ -29,6 +29,22 @@ func (m Model) View() string {
 		return m.renderDetailView()
 	}
 
+	if m.columnManager.active {
+		return m.renderColumnManager()
+	}
+
+	if m.statsPanel.active {
+		return m.renderStatsPanel()
+	}
+
+	if m.summaryBuilder.active {
+		return m.renderSummaryBuilder()
+	}
+
+	if m.chartView.active {
+		return m.renderChartView()
+	}
+
 	// Don't render if we're about to rerun the query (prevents duplicate output)
 	if m.shouldRerunQuery {
 		return ""
@@ -58,12 +74,22 @@ func (m Model) View() string {
 		b.WriteString("\n")
 	}
 
+	if len(m.navPath) > 0 {
+		b.WriteString(m.renderBreadcrumb())
+		b.WriteString("\n")
+	}
+
+	if summary := m.viewSummary(); summary != "" {
+		b.WriteString(summary)
+		b.WriteString("\n")
+	}
+
 	// Add separator line
 	separatorWidth := 0
-	endCol := min(m.offsetX+m.visibleCols, m.numCols())
-	for j := m.offsetX; j < endCol; j++ {
+	displayed := m.displayedColumns()
+	for k := range displayed {
 		separatorWidth += m.cellWidth
-		if j < endCol-1 {
+		if k < len(displayed)-1 {
 			separatorWidth += 1
 		}
 	}
@@ -93,6 +119,11 @@ func (m Model) View() string {
 		b.WriteString(styles.SearchMatch.Render(m.statusMessage))
 	}
 
+	if m.fkChooser.active {
+		b.WriteString("\n")
+		b.WriteString(m.renderFKChooser())
+	}
+
 	// Confirm prompt (e.g., delete)
 	if m.confirmActive && m.confirmMessage != "" {
 		b.WriteString("\n")
@@ -104,9 +135,8 @@ func (m Model) View() string {
 
 func (m Model) renderHeader() string {
 	var cells []string
-	endCol := min(m.offsetX+m.visibleCols, m.numCols())
 
-	for j := m.offsetX; j < endCol; j++ {
+	for _, j := range m.displayedColumns() {
 		typeIcon := ""
 		if m.uiVisibility.TypeDisplay && j < len(m.columnTypes) &&
 			m.columnTypes[j] != "" {
@@ -126,8 +156,8 @@ func (m Model) renderHeader() string {
 			fkIcon = "🔗"
 		}
 
-		sortIcon := ""
-		if m.columns[j] == m.sortColumn {
+		sortIcon := m.localSortIcon(m.columns[j])
+		if sortIcon == "" && m.columns[j] == m.sortColumn {
 			if m.sortDirection == "ASC" {
 				sortIcon = " ↑"
 			} else if m.sortDirection == "DESC" {
@@ -156,9 +186,8 @@ func (m Model) renderHeader() string {
 
 func (m Model) renderDataRow(rowIndex int) string {
 	var cells []string
-	endCol := min(m.offsetX+m.visibleCols, m.numCols())
 
-	for j := m.offsetX; j < endCol; j++ {
+	for _, j := range m.displayedColumns() {
 		content := formatCell(m.data[rowIndex][j], m.cellWidth)
 		style := m.getCellStyle(rowIndex, j)
 		cells = append(cells, style.Render(content))
@@ -189,6 +218,20 @@ func (m Model) renderFooter() string {
 		return "\n" + input
 	}
 
+	// Show filter bar when active
+	if m.filterMode {
+		cursorAfter := m.filterInput[m.filterCursor:]
+		input := styles.SearchMatch.Render("F") + " " +
+			m.filterInput[:m.filterCursor] + "█" + cursorAfter + "\n"
+		if m.filterError != "" {
+			input += styles.Error.Render(m.filterError) + "  "
+		}
+		input += styles.Faint.Render(
+			"Enter: apply (empty clears)  Esc: cancel  e.g. status = 'failed' and amount > 100",
+		)
+		return "\n" + input
+	}
+
 	// Show export format prompt if active
 	if m.exportWaiting.active {
 		promptText := fmt.Sprintf(
@@ -226,7 +269,7 @@ func (m Model) renderFooter() string {
 
 		if m.selectedCol >= 0 && m.selectedCol < len(m.columnFKs) &&
 			m.columnFKs[m.selectedCol] != "" {
-			fkRef = fmt.Sprintf(" FK → %s", m.columnFKs[m.selectedCol])
+			fkRef = fmt.Sprintf(" FK → %s (o)", m.columnFKs[m.selectedCol])
 		}
 
 		maxPreviewWidth := m.width - len(columnType) - len(fkRef) - 10
@@ -0,0 +1,482 @@
+package tablewizard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	"github.com/caiolandgraf/pam/internal/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column fields, in the order they are shown
+const (
+	fieldName = iota
+	fieldType
+	fieldNullable
+	fieldDefault
+	fieldPK
+	fieldUnique
+	fieldReferences
+	fieldTotal
+)
+
+var fieldHeaders = [fieldTotal]string{
+	"Name", "Type", "Null", "Default", "PK", "Unique", "References",
+}
+
+type column struct {
+	name       string
+	dataType   string
+	nullable   bool
+	defaultVal string
+	pk         bool
+	unique     bool
+	references string // "table(column)"
+}
+
+// Model is the create-table form: one row per column, edited field by field,
+// then a review of the generated CREATE TABLE before it is confirmed.
+type Model struct {
+	tableName string
+	conn      db.DatabaseConnection
+	types     []string
+	tables    []string
+	refCols   map[string]string // primary key column of each referenced table
+	columns   []column
+	row       int
+	field     int
+	reviewing bool
+	message   string
+	aborted   bool
+}
+
+// New starts the form with an integer primary key column. tables are the
+// existing tables offered as foreign key targets.
+func New(tableName string, conn db.DatabaseConnection, tables []string) Model {
+	types := db.DataTypes(conn.GetDbType())
+	var targets []string
+	for _, t := range tables {
+		if t != tableName {
+			targets = append(targets, t)
+		}
+	}
+	return Model{
+		tableName: tableName,
+		conn:      conn,
+		types:     types,
+		tables:    targets,
+		refCols:   map[string]string{},
+		columns:   []column{{name: "id", dataType: types[0], pk: true}},
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.reviewing {
+		return m.updateReview(key)
+	}
+
+	m.message = ""
+	col := &m.columns[m.row]
+	switch key.String() {
+	case "ctrl+c", "esc":
+		m.aborted = true
+		return m, tea.Quit
+
+	case "enter":
+		if err := m.validate(); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		m.reviewing = true
+
+	case "up":
+		if m.row > 0 {
+			m.row--
+		}
+
+	case "down":
+		if m.row < len(m.columns)-1 {
+			m.row++
+		}
+
+	case "tab":
+		m.field++
+		if m.field == fieldTotal {
+			m.field = 0
+			m.row = (m.row + 1) % len(m.columns)
+		}
+
+	case "shift+tab":
+		m.field--
+		if m.field < 0 {
+			m.field = fieldTotal - 1
+			m.row = (m.row - 1 + len(m.columns)) % len(m.columns)
+		}
+
+	case "ctrl+n":
+		// New column below the current one
+		next := column{dataType: m.types[0], nullable: true}
+		if len(m.types) > 1 {
+			next.dataType = m.types[1]
+		}
+		m.columns = append(m.columns[:m.row+1], append([]column{next}, m.columns[m.row+1:]...)...)
+		m.row++
+		m.field = fieldName
+
+	case "ctrl+d":
+		if len(m.columns) > 1 {
+			m.columns = append(m.columns[:m.row], m.columns[m.row+1:]...)
+			if m.row == len(m.columns) {
+				m.row--
+			}
+		}
+
+	case "right", "left":
+		dir := 1
+		if key.String() == "left" {
+			dir = -1
+		}
+		switch m.field {
+		case fieldType:
+			col.dataType = cycle(m.types, col.dataType, dir)
+		case fieldReferences:
+			col.references = m.cycleReference(col.references, dir)
+		default:
+			m.toggle(col)
+		}
+
+	case " ":
+		if !m.toggle(col) {
+			m.appendText(col, " ")
+		}
+
+	case "backspace":
+		if text := m.text(col); text != nil && *text != "" {
+			runes := []rune(*text)
+			*text = string(runes[:len(runes)-1])
+		}
+
+	default:
+		if key.Type == tea.KeyRunes {
+			m.appendText(col, string(key.Runes))
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) updateReview(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.String() {
+	case "enter", "y":
+		return m, tea.Quit
+	case "esc", "n", "backspace":
+		m.reviewing = false
+	case "ctrl+c":
+		m.aborted = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// toggle flips the boolean field under the cursor, if it is one
+func (m *Model) toggle(col *column) bool {
+	switch m.field {
+	case fieldNullable:
+		col.nullable = !col.nullable
+		if col.nullable {
+			col.pk = false
+		}
+	case fieldPK:
+		col.pk = !col.pk
+		if col.pk {
+			col.nullable = false
+		}
+	case fieldUnique:
+		col.unique = !col.unique
+	default:
+		return false
+	}
+	return true
+}
+
+// text returns the text field under the cursor, or nil for other fields
+func (m *Model) text(col *column) *string {
+	switch m.field {
+	case fieldName:
+		return &col.name
+	case fieldType:
+		return &col.dataType
+	case fieldDefault:
+		return &col.defaultVal
+	case fieldReferences:
+		return &col.references
+	}
+	return nil
+}
+
+func (m *Model) appendText(col *column, s string) {
+	if text := m.text(col); text != nil {
+		*text += s
+	}
+}
+
+func cycle(options []string, current string, dir int) string {
+	if len(options) == 0 {
+		return current
+	}
+	idx := -1
+	for i, o := range options {
+		if strings.EqualFold(o, current) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 && dir < 0 {
+		idx = 0
+	}
+	return options[(idx+dir+len(options))%len(options)]
+}
+
+// cycleReference steps through "" and every table, pointing at the
+// table's primary key
+func (m *Model) cycleReference(current string, dir int) string {
+	table, _ := splitReference(current)
+	next := cycle(append([]string{""}, m.tables...), table, dir)
+	if next == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s(%s)", next, m.referencedColumn(next))
+}
+
+func (m *Model) referencedColumn(table string) string {
+	if col, ok := m.refCols[table]; ok {
+		return col
+	}
+	col := "id"
+	if meta, err := m.conn.GetTableMetadata(table); err == nil && len(meta.PrimaryKeys) > 0 {
+		col = meta.PrimaryKeys[0]
+	}
+	m.refCols[table] = col
+	return col
+}
+
+// splitReference parses "table(column)"; a bare table name references its
+// "id" column
+func splitReference(ref string) (table, column string) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", ""
+	}
+	open := strings.Index(ref, "(")
+	if open < 0 || !strings.HasSuffix(ref, ")") {
+		return ref, "id"
+	}
+	return strings.TrimSpace(ref[:open]), strings.TrimSpace(ref[open+1 : len(ref)-1])
+}
+
+func (m Model) validate() error {
+	seen := map[string]bool{}
+	for i, col := range m.columns {
+		name := strings.TrimSpace(col.name)
+		if name == "" {
+			return fmt.Errorf("column %d needs a name", i+1)
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("column %s is defined twice", name)
+		}
+		seen[strings.ToLower(name)] = true
+		if strings.TrimSpace(col.dataType) == "" {
+			return fmt.Errorf("column %s needs a type", name)
+		}
+		if table, ref := splitReference(col.references); table != "" && ref == "" {
+			return fmt.Errorf("column %s references %s without a column", name, table)
+		}
+	}
+	return nil
+}
+
+// Definition returns the table described by the form
+func (m Model) Definition() db.TableDefinition {
+	def := db.TableDefinition{Name: m.tableName}
+	for _, col := range m.columns {
+		table, ref := splitReference(col.references)
+		def.Columns = append(def.Columns, db.ColumnDefinition{
+			Name:             strings.TrimSpace(col.name),
+			DataType:         strings.TrimSpace(col.dataType),
+			Nullable:         col.nullable,
+			Default:          strings.TrimSpace(col.defaultVal),
+			PrimaryKey:       col.pk,
+			Unique:           col.unique,
+			ReferencedTable:  table,
+			ReferencedColumn: ref,
+		})
+	}
+	return def
+}
+
+// SQL is the CREATE TABLE statement for the form, in the connection's dialect
+func (m Model) SQL() string {
+	return m.conn.BuildCreateTableSQL(m.Definition())
+}
+
+func (m Model) WasAborted() bool {
+	return m.aborted
+}
+
+// ---- View ----
+
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render(fmt.Sprintf("Create table %s", m.tableName)))
+	b.WriteString(styles.Faint.Render(
+		fmt.Sprintf("  %s/%s", m.conn.GetDbType(), m.conn.GetName()),
+	))
+	b.WriteString("\n\n")
+
+	if m.reviewing {
+		b.WriteString(m.SQL())
+		b.WriteString("\n\n")
+		b.WriteString(styles.Faint.Render("Enter: create table  Esc: back to editing  Ctrl+C: cancel"))
+		return b.String()
+	}
+
+	widths := m.widths()
+	var header []string
+	for f, h := range fieldHeaders {
+		header = append(header, styles.TableHeader.Render(pad(h, widths[f])))
+	}
+	b.WriteString(strings.Join(header, " "))
+	b.WriteString("\n")
+
+	for r, col := range m.columns {
+		var cells []string
+		for f, value := range m.cells(col) {
+			style := styles.TableCell
+			if r == m.row && f == m.field {
+				style = styles.TableSelected
+				if m.text(&col) != nil {
+					value += "▏"
+				}
+			}
+			cells = append(cells, style.Render(pad(value, widths[f])))
+		}
+		b.WriteString(strings.Join(cells, " "))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.fieldHint())
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(styles.Error.Render("✗ " + m.message))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Faint.Render(
+		"↑/↓ column  Tab/Shift+Tab field  ←/→ cycle  Space toggle  Ctrl+N add column  Ctrl+D delete column  Enter review  Esc cancel",
+	))
+
+	return b.String()
+}
+
+func (m Model) cells(col column) [fieldTotal]string {
+	mark := func(on bool) string {
+		if on {
+			return "✓"
+		}
+		return ""
+	}
+	return [fieldTotal]string{
+		col.name,
+		col.dataType,
+		mark(col.nullable),
+		col.defaultVal,
+		mark(col.pk),
+		mark(col.unique),
+		col.references,
+	}
+}
+
+func (m Model) widths() [fieldTotal]int {
+	var widths [fieldTotal]int
+	for f, h := range fieldHeaders {
+		widths[f] = len(h)
+	}
+	for _, col := range m.columns {
+		for f, v := range m.cells(col) {
+			widths[f] = max(widths[f], lipgloss.Width(v)+1)
+		}
+	}
+	return widths
+}
+
+// fieldHint explains the focused field, listing the choices of a picker
+func (m Model) fieldHint() string {
+	col := m.columns[m.row]
+	switch m.field {
+	case fieldType:
+		var opts []string
+		for _, t := range m.types {
+			if strings.EqualFold(t, col.dataType) {
+				opts = append(opts, styles.Title.Render(t))
+			} else {
+				opts = append(opts, styles.Faint.Render(t))
+			}
+		}
+		return styles.Faint.Render("Type (←/→ or edit): ") + strings.Join(opts, styles.Faint.Render("  "))
+	case fieldReferences:
+		if len(m.tables) == 0 {
+			return styles.Faint.Render("Foreign key: no other tables; type table(column)")
+		}
+		return styles.Faint.Render(fmt.Sprintf(
+			"Foreign key (←/→ or type table(column)): %d tables",
+			len(m.tables),
+		))
+	case fieldDefault:
+		return styles.Faint.Render("Default: an SQL expression, e.g. 0, 'new' or CURRENT_TIMESTAMP")
+	case fieldNullable, fieldPK, fieldUnique:
+		return styles.Faint.Render(fieldHeaders[m.field] + ": Space or ←/→ to toggle")
+	}
+	return styles.Faint.Render("Column name")
+}
+
+func pad(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// ErrAborted is returned when the form is cancelled
+var ErrAborted = fmt.Errorf("create table aborted")
+
+// CollectTableDefinition runs the form and returns the confirmed table and
+// its CREATE TABLE statement.
+func CollectTableDefinition(
+	tableName string,
+	conn db.DatabaseConnection,
+	tables []string,
+) (db.TableDefinition, string, error) {
+	program := tea.NewProgram(New(tableName, conn, tables))
+	finalModel, err := program.Run()
+	if err != nil {
+		return db.TableDefinition{}, "", err
+	}
+
+	model := finalModel.(Model)
+	if model.WasAborted() {
+		return db.TableDefinition{}, "", ErrAborted
+	}
+	return model.Definition(), model.SQL(), nil
+}
@@ -0,0 +1,125 @@
+package tablewizard
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/caiolandgraf/pam/internal/db"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestConn(t *testing.T) db.DatabaseConnection {
+	t.Helper()
+	conn, err := db.NewSQLiteConnection("wizard", filepath.Join(t.TempDir(), "wizard.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if err := conn.Exec("CREATE TABLE customers (code TEXT PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func press(m Model, keys ...tea.KeyMsg) Model {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func typeText(m Model, s string) Model {
+	return press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+}
+
+func key(t tea.KeyType) tea.KeyMsg {
+	return tea.KeyMsg{Type: t}
+}
+
+func TestWizardCreatesTable(t *testing.T) {
+	conn := newTestConn(t)
+	m := New("orders", conn, []string{"customers", "orders"})
+
+	// Second column: customer TEXT NOT NULL UNIQUE REFERENCES customers
+	m = press(m, key(tea.KeyCtrlN))
+	m = typeText(m, "customer")
+	m = press(m, key(tea.KeyTab)) // new columns default to TEXT, nullable
+	m = press(m, key(tea.KeyTab), key(tea.KeySpace))
+	m = press(m, key(tea.KeyTab), key(tea.KeyTab), key(tea.KeyTab), key(tea.KeySpace))
+	m = press(m, key(tea.KeyTab), key(tea.KeyRight))
+
+	// Third column with a default
+	m = press(m, key(tea.KeyCtrlN))
+	m = typeText(m, "status")
+	m = press(m, key(tea.KeyTab), key(tea.KeyTab), key(tea.KeyTab))
+	m = typeText(m, "'new'")
+
+	def := m.Definition()
+	if len(def.Columns) != 3 {
+		t.Fatalf("expected 3 columns, got %+v", def.Columns)
+	}
+	customer := def.Columns[1]
+	if customer.DataType != "TEXT" || customer.Nullable || !customer.Unique {
+		t.Errorf("unexpected customer column: %+v", customer)
+	}
+	if customer.ReferencedTable != "customers" || customer.ReferencedColumn != "code" {
+		t.Errorf("expected reference to customers(code), got %+v", customer)
+	}
+	if def.Columns[2].Default != "'new'" || !def.Columns[2].Nullable {
+		t.Errorf("unexpected status column: %+v", def.Columns[2])
+	}
+
+	m = press(m, key(tea.KeyEnter))
+	if !m.reviewing {
+		t.Fatalf("expected review screen, got message %q", m.message)
+	}
+	if !strings.Contains(m.View(), "CREATE TABLE orders") {
+		t.Errorf("review should show the statement:\n%s", m.View())
+	}
+
+	if err := conn.Exec(m.SQL()); err != nil {
+		t.Fatalf("generated SQL failed: %v\n%s", err, m.SQL())
+	}
+	if err := conn.Exec("INSERT INTO orders (id, customer) VALUES (1, 'x')"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Exec("INSERT INTO orders (id, customer) VALUES (2, 'x')"); err == nil {
+		t.Error("expected unique constraint violation")
+	}
+}
+
+func TestWizardValidation(t *testing.T) {
+	conn := newTestConn(t)
+	m := New("things", conn, nil)
+
+	m = press(m, key(tea.KeyCtrlN), key(tea.KeyEnter))
+	if m.reviewing || !strings.Contains(m.message, "needs a name") {
+		t.Fatalf("expected a missing name error, got %q", m.message)
+	}
+
+	m = typeText(m, "ID")
+	m = press(m, key(tea.KeyEnter))
+	if m.reviewing || !strings.Contains(m.message, "twice") {
+		t.Fatalf("expected a duplicate column error, got %q", m.message)
+	}
+
+	m = press(m, key(tea.KeyCtrlD), key(tea.KeyEnter))
+	if !m.reviewing {
+		t.Fatalf("expected review after removing the duplicate, got %q", m.message)
+	}
+
+	m = press(m, key(tea.KeyEsc))
+	if m.reviewing || m.WasAborted() {
+		t.Fatal("esc on review should return to editing")
+	}
+	m = press(m, key(tea.KeyEsc))
+	if !m.WasAborted() {
+		t.Fatal("esc while editing should abort")
+	}
+}
@@ -0,0 +1,326 @@
+// Package workspace loads project-local queries from a .pam directory, so
+// they can be version-controlled next to the code that uses them.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caiolandgraf/pam/internal/db"
+)
+
+// DirName is the workspace directory looked up from the working directory
+const DirName = ".pam"
+
+// viewKey is the db.Query.Metadata key of the results table's saved view,
+// kept in a "-- view:" header line
+const viewKey = "view"
+
+// Query is a query read from a .sql file. Connection restricts it to one
+// connection; empty means every connection. Params are declared defaults,
+// applied to parameters the SQL leaves without one.
+type Query struct {
+	db.Query
+	Connection string
+	Params     []Param
+	Path       string
+}
+
+type Param struct {
+	Name    string
+	Default string
+}
+
+// Workspace is a discovered .pam directory and its queries.
+type Workspace struct {
+	Dir     string
+	Queries []Query
+}
+
+// Find walks up from start to the first directory containing .pam and
+// returns that .pam path, or "" when there is none.
+func Find(start string) string {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, DirName)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load reads every .sql file under dir. Files that fail to parse are
+// returned as errors alongside the queries that loaded.
+func Load(dir string) (*Workspace, []error) {
+	ws := &Workspace{Dir: dir}
+	var errs []error
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sql") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		q, err := Parse(string(content))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			return nil
+		}
+		if q.Name == "" {
+			q.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		if q.Folder == "" {
+			q.Folder = ws.folderOf(path)
+		}
+		q.Path = path
+		ws.Queries = append(ws.Queries, q)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	sort.Slice(ws.Queries, func(i, j int) bool {
+		return ws.Queries[i].Name < ws.Queries[j].Name
+	})
+	return ws, errs
+}
+
+var headerLine = regexp.MustCompile(`^--\s*([a-z_]+)\s*:\s*(.*?)\s*$`)
+
+var headerKeys = map[string]bool{
+	"name": true, "connection": true, "table": true, "params": true, "description": true,
+	"tags": true, "folder": true, "assert": true, "view": true,
+}
+
+// Parse reads a query file. Its front matter is the leading run of
+// "-- key: value" comment lines with the keys name, connection, table,
+// params, description, tags, folder, assert and view; the SQL follows.
+//
+//	-- name: active_users
+//	-- connection: prod
+//	-- params: status=active, limit=10
+//	-- tags: users, reporting
+//	SELECT * FROM users WHERE status = :status LIMIT :limit
+func Parse(content string) (Query, error) {
+	var q Query
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	body := 0
+	for ; body < len(lines); body++ {
+		line := strings.TrimSpace(lines[body])
+		if line == "" {
+			continue
+		}
+		m := headerLine.FindStringSubmatch(line)
+		if m == nil || !headerKeys[m[1]] {
+			break
+		}
+
+		switch m[1] {
+		case "name":
+			q.Name = m[2]
+		case "connection":
+			q.Connection = m[2]
+		case "table":
+			q.TableName = m[2]
+		case "description":
+			q.Description = m[2]
+		case "tags":
+			q.Tags = db.ParseTags(m[2])
+		case "folder":
+			q.Folder = strings.Trim(m[2], "/")
+		case "assert":
+			q.Assert = m[2]
+		case "view":
+			q.Metadata = map[string]string{viewKey: m[2]}
+		case "params":
+			params, err := parseParams(m[2])
+			if err != nil {
+				return q, err
+			}
+			q.Params = params
+		}
+	}
+
+	q.SQL = strings.TrimSpace(strings.Join(lines[body:], "\n"))
+	if q.SQL == "" {
+		return q, fmt.Errorf("no SQL after the header")
+	}
+	return q, nil
+}
+
+func parseParams(value string) ([]Param, error) {
+	var params []Param
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, def, _ := strings.Cut(part, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), ":")
+		if name == "" {
+			return nil, fmt.Errorf("invalid param %q", part)
+		}
+		params = append(params, Param{Name: name, Default: strings.TrimSpace(def)})
+	}
+	return params, nil
+}
+
+// Format renders q as a query file, the inverse of Parse.
+func Format(q Query) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- name: %s\n", q.Name)
+	if q.Connection != "" {
+		fmt.Fprintf(&b, "-- connection: %s\n", q.Connection)
+	}
+	if q.TableName != "" {
+		fmt.Fprintf(&b, "-- table: %s\n", q.TableName)
+	}
+	if len(q.Params) > 0 {
+		parts := make([]string, len(q.Params))
+		for i, p := range q.Params {
+			parts[i] = p.Name
+			if p.Default != "" {
+				parts[i] += "=" + p.Default
+			}
+		}
+		fmt.Fprintf(&b, "-- params: %s\n", strings.Join(parts, ", "))
+	}
+	if q.Description != "" {
+		fmt.Fprintf(&b, "-- description: %s\n", q.Description)
+	}
+	if len(q.Tags) > 0 {
+		fmt.Fprintf(&b, "-- tags: %s\n", strings.Join(q.Tags, ", "))
+	}
+	if q.Folder != "" {
+		fmt.Fprintf(&b, "-- folder: %s\n", q.Folder)
+	}
+	if q.Assert != "" {
+		fmt.Fprintf(&b, "-- assert: %s\n", q.Assert)
+	}
+	if view := q.Metadata[viewKey]; view != "" {
+		fmt.Fprintf(&b, "-- view: %s\n", view)
+	}
+	b.WriteString(strings.TrimSpace(q.SQL))
+	b.WriteString("\n")
+	return b.String()
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Save writes q to its file, or to <folder>/<name>.sql in the workspace
+// for a new query, and records it in the workspace.
+func (w *Workspace) Save(q Query) (Query, error) {
+	if q.Path == "" {
+		name := strings.Trim(unsafeFileChars.ReplaceAllString(q.Name, "_"), "_")
+		if name == "" {
+			return q, fmt.Errorf("invalid query name %q", q.Name)
+		}
+		dir := w.Dir
+		for _, part := range strings.Split(q.Folder, "/") {
+			if part = strings.Trim(unsafeFileChars.ReplaceAllString(part, "_"), "_."); part != "" {
+				dir = filepath.Join(dir, part)
+			}
+		}
+		q.Path = filepath.Join(dir, name+".sql")
+		if _, err := os.Stat(q.Path); err == nil {
+			return q, fmt.Errorf("%s already exists", w.Rel(q.Path))
+		}
+	}
+
+	// A folder that matches the file's directory goes without saying
+	file := q
+	if file.Folder == w.folderOf(q.Path) {
+		file.Folder = ""
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.Path), 0o755); err != nil {
+		return q, err
+	}
+	if err := os.WriteFile(q.Path, []byte(Format(file)), 0o644); err != nil {
+		return q, err
+	}
+
+	for i, existing := range w.Queries {
+		if existing.Path == q.Path {
+			w.Queries[i] = q
+			return q, nil
+		}
+	}
+	w.Queries = append(w.Queries, q)
+	return q, nil
+}
+
+// Remove deletes the query's file.
+func (w *Workspace) Remove(q Query) error {
+	if err := os.Remove(q.Path); err != nil {
+		return err
+	}
+	for i, existing := range w.Queries {
+		if existing.Path == q.Path {
+			w.Queries = append(w.Queries[:i], w.Queries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// folderOf is the directory of path inside the workspace, slash-separated.
+func (w *Workspace) folderOf(path string) string {
+	rel, err := filepath.Rel(w.Dir, filepath.Dir(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Rel shows path relative to the project root, e.g. .pam/users.sql.
+func (w *Workspace) Rel(path string) string {
+	if rel, err := filepath.Rel(filepath.Dir(w.Dir), path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// For returns the workspace queries available on connection conn.
+func (w *Workspace) For(conn string) []Query {
+	var result []Query
+	for _, q := range w.Queries {
+		if q.Connection == "" || q.Connection == conn {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// Lookup finds the workspace query named name for connection conn,
+// preferring one written for that connection over a shared one.
+func (w *Workspace) Lookup(conn, name string) (Query, bool) {
+	var found Query
+	ok := false
+	for _, q := range w.For(conn) {
+		if q.Name == name && (!ok || q.Connection != "") {
+			found, ok = q, true
+		}
+	}
+	return found, ok
+}
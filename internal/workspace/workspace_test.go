@@ -0,0 +1,142 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`-- name: active_users
+-- connection: prod
+-- params: status=active, :limit
+-- description: Users seen this month
+
+-- keeps this comment
+SELECT * FROM users WHERE status = :status LIMIT :limit
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Name != "active_users" || q.Connection != "prod" || q.Description != "Users seen this month" {
+		t.Errorf("header = %+v", q)
+	}
+	if want := []Param{{"status", "active"}, {"limit", ""}}; !reflect.DeepEqual(q.Params, want) {
+		t.Errorf("params = %+v, want %+v", q.Params, want)
+	}
+	if want := "-- keeps this comment\nSELECT * FROM users WHERE status = :status LIMIT :limit"; q.SQL != want {
+		t.Errorf("sql = %q", q.SQL)
+	}
+
+	if _, err := Parse("-- name: empty\n"); err == nil {
+		t.Error("expected an error for a file without SQL")
+	}
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	q := Query{Connection: "dev", Params: []Param{{"id", "1"}}}
+	q.Name, q.TableName, q.SQL = "by_id", "users", "SELECT * FROM users WHERE id = :id"
+	q.Description, q.Tags, q.Folder = "One user", []string{"users", "admin"}, "people/lookup"
+	q.Assert = "rows between 1 and 5; email != ''"
+	q.Metadata = map[string]string{"view": `{"filter":"a","hidden":["email"]}`}
+
+	got, err := Parse(Format(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, q) {
+		t.Errorf("round trip = %+v, want %+v", got, q)
+	}
+}
+
+func TestFindAndLoad(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, DirName)
+	nested := filepath.Join(root, "src", "app")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "reports"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"users.sql":         "SELECT * FROM users",
+		"reports/daily.sql": "-- name: daily\n-- connection: prod\nSELECT 1",
+		"shared_daily.sql":  "-- name: daily\nSELECT 2",
+		"broken.sql":        "-- name: broken\n",
+		"notes.txt":         "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := Find(nested); got != dir {
+		t.Fatalf("Find = %q, want %q", got, dir)
+	}
+
+	ws, errs := Load(dir)
+	if len(errs) != 1 {
+		t.Errorf("expected one error for broken.sql, got %v", errs)
+	}
+	if len(ws.Queries) != 3 {
+		t.Fatalf("loaded %d queries, want 3", len(ws.Queries))
+	}
+	if q, ok := ws.Lookup("dev", "users"); !ok || q.SQL != "SELECT * FROM users" {
+		t.Errorf("name should default to the file name, got %+v", q)
+	}
+	if q, _ := ws.Lookup("prod", "daily"); q.SQL != "SELECT 1" || q.Folder != "reports" {
+		t.Errorf("connection-specific query should win with its directory as folder, got %+v", q)
+	}
+	if q, _ := ws.Lookup("dev", "daily"); q.SQL != "SELECT 2" {
+		t.Errorf("other connections should get the shared query, got %q", q.SQL)
+	}
+	if rel := ws.Rel(filepath.Join(dir, "users.sql")); rel != filepath.Join(DirName, "users.sql") {
+		t.Errorf("Rel = %q", rel)
+	}
+}
+
+func TestSave(t *testing.T) {
+	ws := &Workspace{Dir: filepath.Join(t.TempDir(), DirName)}
+	q := Query{}
+	q.Name, q.SQL = "by id", "SELECT 1"
+
+	saved, err := ws.Save(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(saved.Path) != "by_id.sql" {
+		t.Errorf("path = %s", saved.Path)
+	}
+
+	nested := Query{}
+	nested.Name, nested.SQL, nested.Folder = "monthly", "SELECT 3", "billing/reports"
+	sub, err := ws.Save(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(ws.Dir, "billing", "reports", "monthly.sql"); sub.Path != want {
+		t.Errorf("path = %s, want %s", sub.Path, want)
+	}
+	if content, _ := os.ReadFile(sub.Path); strings.Contains(string(content), "-- folder:") {
+		t.Errorf("folder matching the directory should not be written:\n%s", content)
+	}
+	if err := ws.Remove(sub); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws.Save(q); err == nil {
+		t.Error("expected an error when the file already exists")
+	}
+
+	saved.SQL = "SELECT 2"
+	if _, err := ws.Save(saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := Load(ws.Dir)
+	if len(loaded.Queries) != 1 || loaded.Queries[0].SQL != "SELECT 2" {
+		t.Errorf("reloaded = %+v", loaded.Queries)
+	}
+}